@@ -1,9 +1,12 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -12,6 +15,7 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -19,7 +23,6 @@ import (
 	"time"
 	"unicode/utf8"
 
-	"github.com/go-mysql-org/go-mysql/dump"
 	_ "github.com/go-sql-driver/mysql"
 	mysqlDriver "github.com/go-sql-driver/mysql"
 	_ "github.com/sijms/go-ora/v2"
@@ -32,11 +35,25 @@ type App struct {
 	ctx           context.Context
 	db            *sql.DB
 	currentDBType string
+	currentCfg    DBConfig // 当前连接的配置，用于语句检查
+	currentSchema string   // 当前库，用于审计
+
+	jobs     *jobManager
+	cdc      *cdcManager
+	meta     *metadataCache
+	guard    *statementGuard
+	auditLog *auditLog
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{currentDBType: "mysql"}
+	a := &App{currentDBType: "mysql"}
+	a.jobs = newJobManager(defaultMaxConcurrentJobs, a.emitJobStatus)
+	a.cdc = newCDCManager(a)
+	a.meta = newMetadataCache()
+	a.guard = newStatementGuard()
+	a.auditLog = newAuditLog()
+	return a
 }
 
 // startup is called when the app starts. The context is saved
@@ -45,6 +62,10 @@ func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	_ = loadSavedConfigs()
 	_ = loadAppSettings()
+	_ = loadMaskingRules()
+	a.jobs.setConcurrency(appSettings.MaxConcurrentJobs)
+	_ = a.jobs.load()
+	a.pruneAuditLog()
 }
 
 // LogBridge 用于捕获 mysqldump 的 stderr 并转发到 Wails 前端
@@ -61,11 +82,40 @@ func (l *LogBridge) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-// ConnectDB 连接数据库
+// ConnectDB 连接数据库；按地址与用户匹配已保存的连接以沿用其环境标签与只读标记，
+// 无法匹配时按生产环境处理，避免绕过执行前检查
 func (a *App) ConnectDB(dsn string) error {
+	a.currentCfg = dsnConnection(dsn)
+	a.currentSchema = a.currentCfg.Database
 	return a.connectByDriver("mysql", dsn)
 }
 
+// dsnConnection 由 DSN 还原连接信息并匹配已保存的连接
+func dsnConnection(dsn string) DBConfig {
+	cfg := DBConfig{Type: "mysql", Environment: envProd}
+	parsed, err := mysqlDriver.ParseDSN(dsn)
+	if err != nil {
+		return cfg
+	}
+	cfg.User, cfg.Database = parsed.User, parsed.DBName
+	host, port, err := net.SplitHostPort(parsed.Addr)
+	if err != nil {
+		host = parsed.Addr
+	}
+	cfg.Host = host
+	cfg.Port, _ = strconv.Atoi(port)
+	if cfg.Port == 0 {
+		cfg.Port = 3306
+	}
+	for _, c := range savedConfigs {
+		if normalizeDBType(c.Type) == "mysql" && strings.EqualFold(c.Host, cfg.Host) && c.Port == cfg.Port && c.User == cfg.User {
+			c.Database = cfg.Database
+			return resolveConnection(c)
+		}
+	}
+	return cfg
+}
+
 func (a *App) connectByDriver(driver string, dsn string) error {
 	// 如果已有连接，先关闭
 	if a.db != nil {
@@ -94,7 +144,15 @@ func (a *App) ConnectDBConfig(cfg DBConfig) error {
 	if err != nil {
 		return err
 	}
-	return a.connectByDriver(driver, dsn)
+	if err := a.connectByDriver(driver, dsn); err != nil {
+		return err
+	}
+	a.currentCfg = resolveConnection(cfg)
+	a.currentSchema = ""
+	if normalizeDBType(cfg.Type) == "mysql" {
+		a.currentSchema = cfg.Database
+	}
+	return nil
 }
 
 // TestConnection 测试连接（不保留连接）
@@ -127,17 +185,25 @@ func (a *App) TestConnectionConfig(cfg DBConfig) error {
 	return nil
 }
 
-// ExecuteQuery 执行 SQL 并返回结果
-func (a *App) ExecuteQuery(query string) ([]map[string]interface{}, error) {
+// ExecuteQuery 执行 SQL 并返回结果；生产环境的危险语句需先经 CheckStatement 确认，此处不接受
+func (a *App) ExecuteQuery(query string) (result []map[string]interface{}, err error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("数据库未连接")
 	}
+	start := time.Now()
+	defer func() { a.auditStatement("query", query, start, len(result), err) }()
+	stmts, err := a.guardQuery(query, "")
+	if err != nil {
+		return nil, err
+	}
+	defer a.invalidateDDLMetadata(stmts, a.currentSchema)
 
 	rows, err := a.db.Query(query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+	a.trackSchema(query)
 
 	// 获取列名
 	columns, err := rows.Columns()
@@ -145,8 +211,6 @@ func (a *App) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 		return nil, err
 	}
 
-	var result []map[string]interface{}
-
 	for rows.Next() {
 		// 创建一个切片用来存储扫描出的数据
 		values := make([]interface{}, len(columns))
@@ -173,6 +237,7 @@ func (a *App) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 		result = append(result, rowMap)
 	}
 
+	a.maskQueryResult(query, columns, result)
 	return result, nil
 }
 
@@ -180,19 +245,28 @@ func (a *App) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 type QueryResult struct {
 	Columns []string                 `json:"columns"`
 	Rows    []map[string]interface{} `json:"rows"`
+	Masked  []string                 `json:"masked,omitempty"` // 按脱敏规则处理过的列
 }
 
-// ExecuteQueryWithColumns 执行 SQL 并返回列顺序与数据
-func (a *App) ExecuteQueryWithColumns(query string) (QueryResult, error) {
+// ExecuteQueryWithColumns 执行 SQL 并返回列顺序与数据；confirmToken 为 CheckStatement 返回的确认令牌
+func (a *App) ExecuteQueryWithColumns(query string, confirmToken string) (res QueryResult, err error) {
 	if a.db == nil {
 		return QueryResult{}, fmt.Errorf("数据库未连接")
 	}
+	start := time.Now()
+	defer func() { a.auditStatement("query", query, start, len(res.Rows), err) }()
+	stmts, err := a.guardQuery(query, confirmToken)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	defer a.invalidateDDLMetadata(stmts, a.currentSchema)
 
 	rows, err := a.db.Query(query)
 	if err != nil {
 		return QueryResult{}, err
 	}
 	defer rows.Close()
+	a.trackSchema(query)
 
 	columns, err := rows.Columns()
 	if err != nil {
@@ -221,7 +295,8 @@ func (a *App) ExecuteQueryWithColumns(query string) (QueryResult, error) {
 		result = append(result, rowMap)
 	}
 
-	return QueryResult{Columns: columns, Rows: result}, nil
+	masked := a.maskQueryResult(query, columns, result)
+	return QueryResult{Columns: columns, Rows: result, Masked: masked}, nil
 }
 
 // GetProcessList 获取会话列表
@@ -268,15 +343,21 @@ func (a *App) GetProcessList() ([]map[string]interface{}, error) {
 	return result, nil
 }
 
-// KillProcess 终止会话
-func (a *App) KillProcess(id int64) error {
+// KillProcess 终止会话；生产环境需带上 CheckStatement("KILL <id>") 返回的确认令牌
+func (a *App) KillProcess(id int64, confirmToken string) (err error) {
 	if a.currentDBType != "mysql" {
 		return fmt.Errorf("当前连接类型暂不支持终止会话")
 	}
 	if a.db == nil {
 		return fmt.Errorf("数据库未连接")
 	}
-	_, err := a.db.Exec(fmt.Sprintf("KILL %d", id))
+	stmt := fmt.Sprintf("KILL %d", id)
+	start := time.Now()
+	defer func() { a.auditStatement("kill", stmt, start, 0, err) }()
+	if _, err := a.guardQuery(stmt, confirmToken); err != nil {
+		return err
+	}
+	_, err = a.db.Exec(stmt)
 	return err
 }
 
@@ -289,10 +370,16 @@ type DBConfig struct {
 	User     string `json:"user"`
 	Password string `json:"password"`
 	Database string `json:"database"`
+
+	Environment string `json:"environment"` // dev / test / prod
+	ReadOnly    bool   `json:"readOnly"`    // 只读连接禁止执行写入语句
 }
 
 type AppSettings struct {
-	MysqldumpPath string `json:"mysqldumpPath"`
+	MysqldumpPath     string `json:"mysqldumpPath"`
+	MaxConcurrentJobs int    `json:"maxConcurrentJobs"`
+
+	AuditRetentionDays int `json:"auditRetentionDays"` // 审计日志保留天数，0 表示永久保留
 }
 
 type TableMeta struct {
@@ -306,6 +393,22 @@ type MigrationCheckRow struct {
 	SourceRows int64  `json:"sourceRows"`
 	TargetRows int64  `json:"targetRows"`
 	Status     string `json:"status"`
+	// 断点续传信息
+	Resumed     bool   `json:"resumed,omitempty"`
+	ResumedRows int64  `json:"resumedRows,omitempty"`
+	Note        string `json:"note,omitempty"`
+	// 冲突处理策略与写入统计
+	TablePolicy string `json:"tablePolicy,omitempty"`
+	DataPolicy  string `json:"dataPolicy,omitempty"`
+	Inserted    int64  `json:"inserted"`
+	Updated     int64  `json:"updated"`
+	Skipped     int64  `json:"skipped"`
+	Checksum    string `json:"checksum,omitempty"`
+	// 映射后的目标表名（与源表名不同时）
+	Target string `json:"target,omitempty"`
+	// 对目标库执行的操作与语句（演练模式下为计划）
+	Actions []string `json:"actions,omitempty"`
+	DDL     []string `json:"ddl,omitempty"`
 }
 
 type ViewMeta struct {
@@ -431,7 +534,12 @@ func (a *App) GetAppSettings() AppSettings {
 // SaveAppSettings 保存应用设置
 func (a *App) SaveAppSettings(s AppSettings) error {
 	appSettings = s
-	return persistAppSettings()
+	a.jobs.setConcurrency(s.MaxConcurrentJobs)
+	if err := persistAppSettings(); err != nil {
+		return err
+	}
+	a.pruneAuditLog()
+	return nil
 }
 
 // SaveConnection 保存新连接
@@ -441,13 +549,16 @@ func (a *App) SaveConnection(cfg DBConfig) error {
 		return fmt.Errorf("名称、主机、端口、用户名不能为空")
 	}
 	cfg.Type = normalizeDBType(cfg.Type)
+	cfg.Environment = normalizeEnvironment(cfg.Environment)
 	for _, c := range savedConfigs {
 		if c.Name == cfg.Name {
 			return fmt.Errorf("连接名称已存在")
 		}
 	}
 	savedConfigs = append(savedConfigs, cfg)
-	return persistSavedConfigs()
+	err := persistSavedConfigs()
+	a.auditConnChange("connection.create", DBConfig{}, cfg, err)
+	return err
 }
 
 // UpdateConnection 更新连接
@@ -459,6 +570,7 @@ func (a *App) UpdateConnection(cfg DBConfig) error {
 		return fmt.Errorf("名称、主机、端口、用户名不能为空")
 	}
 	cfg.Type = normalizeDBType(cfg.Type)
+	cfg.Environment = normalizeEnvironment(cfg.Environment)
 	for _, c := range savedConfigs {
 		if c.Name == cfg.Name && c.ID != cfg.ID {
 			return fmt.Errorf("连接名称已存在")
@@ -467,7 +579,9 @@ func (a *App) UpdateConnection(cfg DBConfig) error {
 	for i, c := range savedConfigs {
 		if c.ID == cfg.ID {
 			savedConfigs[i] = cfg
-			return persistSavedConfigs()
+			err := persistSavedConfigs()
+			a.auditConnChange("connection.update", c, cfg, err)
+			return err
 		}
 	}
 	return fmt.Errorf("未找到需要更新的连接")
@@ -488,8 +602,11 @@ func (a *App) DeleteConnection(id string) error {
 	if idx == -1 {
 		return fmt.Errorf("未找到需要删除的连接")
 	}
+	removed := savedConfigs[idx]
 	savedConfigs = append(savedConfigs[:idx], savedConfigs[idx+1:]...)
-	return persistSavedConfigs()
+	err := persistSavedConfigs()
+	a.auditConnChange("connection.delete", removed, removed, err)
+	return err
 }
 
 // GetDatabases 获取当前连接的所有数据库
@@ -872,17 +989,9 @@ func (a *App) SaveExcelFromJSON(suggestedName string, jsonData string) (string,
 		suggestedName = "results.xlsx"
 	}
 
-	var payload struct {
-		Headers []string                 `json:"headers"`
-		Rows    []map[string]interface{} `json:"rows"`
-	}
-	var rows []map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonData), &payload); err == nil && payload.Rows != nil {
-		rows = payload.Rows
-	} else {
-		if err := json.Unmarshal([]byte(jsonData), &rows); err != nil {
-			return "", fmt.Errorf("JSON解析失败: %v", err)
-		}
+	headers, rows, err := a.decodeExportRows(jsonData)
+	if err != nil {
+		return "", err
 	}
 
 	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
@@ -901,23 +1010,6 @@ func (a *App) SaveExcelFromJSON(suggestedName string, jsonData string) (string,
 	f := excelize.NewFile()
 	sheetName := f.GetSheetName(0)
 
-	// Build header order
-	headers := make([]string, 0)
-	if len(payload.Headers) > 0 {
-		headers = append(headers, payload.Headers...)
-	} else {
-		headerSet := make(map[string]struct{})
-		for _, row := range rows {
-			for k := range row {
-				if _, ok := headerSet[k]; ok {
-					continue
-				}
-				headerSet[k] = struct{}{}
-				headers = append(headers, k)
-			}
-		}
-	}
-
 	cleaner := regexp.MustCompile(`[\x00-\x08\x0B\x0C\x0E-\x1F]`)
 	stringify := func(v interface{}) interface{} {
 		switch t := v.(type) {
@@ -954,6 +1046,104 @@ func (a *App) SaveExcelFromJSON(suggestedName string, jsonData string) (string,
 	return path, nil
 }
 
+// decodeExportRows 解析前端传入的导出数据并按脱敏规则处理。
+// 数据为 {headers, rows, sql, masked} 或行数组；sql 为结果集的来源语句，masked 为查询时已脱敏的列
+func (a *App) decodeExportRows(jsonData string) ([]string, []map[string]interface{}, error) {
+	var payload struct {
+		Headers []string                 `json:"headers"`
+		Rows    []map[string]interface{} `json:"rows"`
+		SQL     string                   `json:"sql"`
+		Masked  []string                 `json:"masked"`
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonData), &payload); err == nil && payload.Rows != nil {
+		rows = payload.Rows
+	} else {
+		if err := json.Unmarshal([]byte(jsonData), &rows); err != nil {
+			return nil, nil, fmt.Errorf("JSON解析失败: %v", err)
+		}
+	}
+
+	// Build header order
+	headers := make([]string, 0)
+	if len(payload.Headers) > 0 {
+		headers = append(headers, payload.Headers...)
+	} else {
+		headerSet := make(map[string]struct{})
+		for _, row := range rows {
+			for k := range row {
+				if _, ok := headerSet[k]; ok {
+					continue
+				}
+				headerSet[k] = struct{}{}
+				headers = append(headers, k)
+			}
+		}
+	}
+	a.maskExportRows(payload.SQL, headers, payload.Masked, rows)
+	return headers, rows, nil
+}
+
+// SaveCSVFromJSON 弹出保存对话框并将结果集保存为 CSV（UTF-8 带 BOM）
+func (a *App) SaveCSVFromJSON(suggestedName string, jsonData string) (string, error) {
+	if a.ctx == nil {
+		return "", fmt.Errorf("应用未初始化")
+	}
+	if suggestedName == "" {
+		suggestedName = "results.csv"
+	}
+	headers, rows, err := a.decodeExportRows(jsonData)
+	if err != nil {
+		return "", err
+	}
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		DefaultFilename: suggestedName,
+		Filters: []runtime.FileFilter{
+			{DisplayName: "CSV", Pattern: "*.csv"},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("创建文件失败: %v", err)
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	w.WriteString("\xEF\xBB\xBF")
+	cw := csv.NewWriter(w)
+	_ = cw.Write(headers)
+	record := make([]string, len(headers))
+	for _, row := range rows {
+		for i, h := range headers {
+			switch v := row[h].(type) {
+			case nil:
+				record[i] = ""
+			case string:
+				record[i] = v
+			case map[string]interface{}, []interface{}:
+				b, _ := json.Marshal(v)
+				record[i] = string(b)
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		_ = cw.Write(record)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return "", err
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
 // SaveTextFile 弹出保存对话框并保存文本文件
 func (a *App) SaveTextFile(suggestedName string, content string) (string, error) {
 	if a.ctx == nil {
@@ -1016,29 +1206,70 @@ func writeSchemaTo(w io.Writer, db *sql.DB, database string, tables []string) er
 	return nil
 }
 
-func (a *App) newGoMysqlDumper(cfg DBConfig, executionPath string) (*dump.Dumper, error) {
-	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
-	d, err := dump.NewDumper(executionPath, addr, cfg.User, cfg.Password)
+// mysqldumpCommand 构造导出表数据的 mysqldump 命令，参数与 go-mysql 的 Dumper 一致；
+// 命令绑定 ctx，任务取消时进程随之结束
+func mysqldumpCommand(ctx context.Context, cfg DBConfig, executionPath string, tables []string) (*exec.Cmd, error) {
+	var path string
+	var err error
+	if executionPath == "" {
+		if path, err = exec.LookPath("mysqldump"); err != nil {
+			if path, err = exec.LookPath("mariadb-dump"); err != nil {
+				return nil, errors.New("未找到 mysqldump 或 mariadb-dump")
+			}
+		}
+	} else if path, err = exec.LookPath(executionPath); err != nil {
+		return nil, err
+	}
+	help, err := exec.CommandContext(ctx, path, "--help").CombinedOutput()
 	if err != nil {
 		return nil, err
 	}
-	d.ExtraOptions = append(d.ExtraOptions, "--extended-insert", "--verbose", "--set-gtid-purged=OFF", "--no-create-db")
-	d.SkipMasterData(true)
-	d.SetErrOut(&LogBridge{ctx: a.ctx, eventName: "export-log"})
-	return d, nil
+	args := []string{
+		"--host=" + cfg.Host,
+		"--port=" + strconv.Itoa(cfg.Port),
+		"--user=" + cfg.User,
+		"--password=" + cfg.Password,
+		"--single-transaction",
+		"--skip-lock-tables",
+		"--compact",
+		"--skip-opt",
+		"--quick",
+		"--no-create-info",
+		"--extended-insert",
+		"--skip-tz-utc",
+		"--default-character-set=utf8mb4",
+		"--verbose",
+		"--set-gtid-purged=OFF",
+		"--no-create-db",
+	}
+	// 新版 mysqldump 会查询旧版服务器上不存在的 COLUMN_STATISTICS
+	if bytes.Contains(help, []byte("--column-statistics")) {
+		args = append(args, "--column-statistics=0")
+	}
+	if len(tables) > 0 {
+		args = append(args, cfg.Database)
+		args = append(args, tables...)
+	} else {
+		args = append(args, "--databases", cfg.Database)
+	}
+	return exec.CommandContext(ctx, path, args...), nil
 }
 
-func (a *App) dumpDataTo(w io.Writer, cfg DBConfig, tables []string, executionPath string) error {
-	d, err := a.newGoMysqlDumper(cfg, executionPath)
+// dumpDataTo 调用 mysqldump 导出表数据；ctx 取消时结束 mysqldump 进程，返回前进程已退出
+func (a *App) dumpDataTo(ctx context.Context, w io.Writer, cfg DBConfig, tables []string, executionPath string) error {
+	cmd, err := mysqldumpCommand(ctx, cfg, executionPath, tables)
 	if err != nil {
 		return err
 	}
 	if len(tables) > 0 {
-		d.AddTables(cfg.Database, tables...)
-	} else {
-		d.AddDatabases(cfg.Database)
+		// 只导出部分表时输出中不带库名，补上 USE 语句
+		if _, err := fmt.Fprintf(w, "USE `%s`;\n", cfg.Database); err != nil {
+			return err
+		}
 	}
-	return d.Dump(w)
+	cmd.Stdout = w
+	cmd.Stderr = &LogBridge{ctx: a.ctx, eventName: "export-log"}
+	return cmd.Run()
 }
 
 func escapeSQLString(s string) string {
@@ -1203,89 +1434,28 @@ func showCreateTable(db *sql.DB, database string, table string) (string, error)
 	return createSQL, nil
 }
 
-func copyTableDataDirect(srcDB *sql.DB, sourceDB string, tgtDB *sql.DB, targetDB string, table string, batchSize int) (int64, error) {
-	rows, err := srcDB.Query(fmt.Sprintf("SELECT * FROM `%s`.`%s`", sourceDB, table))
-	if err != nil {
-		return 0, err
+// ExportSqlDump 使用开源库导出SQL（结构/数据/结构+数据），支持压缩、分卷与校验文件
+// 导出作为后台任务执行并等待其结束；导出过程中通过 export-progress 事件推送进度，可用 CancelExport 取消
+func (a *App) ExportSqlDump(cfg DBConfig, tables []string, mode string, opts ExportOptions) (string, error) {
+	job, err := a.SubmitExportJob(cfg, tables, mode, opts)
+	if err != nil || job.ID == "" {
+		return "", err
 	}
-	defer rows.Close()
-
-	cols, err := rows.Columns()
+	res, err := a.jobs.wait(job.ID)
 	if err != nil {
-		return 0, err
-	}
-	colList := make([]string, len(cols))
-	placeholdersOne := make([]string, len(cols))
-	for i, c := range cols {
-		colList[i] = fmt.Sprintf("`%s`", c)
-		placeholdersOne[i] = "?"
-	}
-	colSQL := strings.Join(colList, ", ")
-	oneRowPH := "(" + strings.Join(placeholdersOne, ", ") + ")"
-
-	values := make([]interface{}, len(cols))
-	valuePtrs := make([]interface{}, len(cols))
-	for i := range values {
-		valuePtrs[i] = &values[i]
-	}
-
-	var (
-		totalInserted int64
-		batchPH       []string
-		batchArgs     []interface{}
-	)
-
-	flush := func() error {
-		if len(batchPH) == 0 {
-			return nil
-		}
-		sqlText := fmt.Sprintf("INSERT INTO `%s`.`%s` (%s) VALUES %s", targetDB, table, colSQL, strings.Join(batchPH, ","))
-		if _, err := tgtDB.Exec(sqlText, batchArgs...); err != nil {
-			return err
-		}
-		totalInserted += int64(len(batchPH))
-		batchPH = batchPH[:0]
-		batchArgs = batchArgs[:0]
-		return nil
-	}
-
-	for rows.Next() {
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return totalInserted, err
-		}
-		for _, v := range values {
-			switch b := v.(type) {
-			case []byte:
-				copied := make([]byte, len(b))
-				copy(copied, b)
-				batchArgs = append(batchArgs, copied)
-			default:
-				batchArgs = append(batchArgs, v)
-			}
-		}
-		batchPH = append(batchPH, oneRowPH)
-		if len(batchPH) >= batchSize {
-			if err := flush(); err != nil {
-				return totalInserted, err
-			}
-		}
-	}
-	if err := rows.Err(); err != nil {
-		return totalInserted, err
-	}
-	if err := flush(); err != nil {
-		return totalInserted, err
+		return "", err
 	}
-	return totalInserted, nil
+	path, _ := res.(string)
+	return path, nil
 }
 
-// ExportSqlDump 使用开源库导出SQL（结构/数据/结构+数据）
-func (a *App) ExportSqlDump(cfg DBConfig, tables []string, mode string) (string, error) {
+// SubmitExportJob 选择保存路径后提交导出任务，立即返回任务信息；取消保存时返回空任务
+func (a *App) SubmitExportJob(cfg DBConfig, tables []string, mode string, opts ExportOptions) (Job, error) {
 	if normalizeDBType(cfg.Type) != "mysql" {
-		return "", fmt.Errorf("当前连接类型暂不支持SQL导出")
+		return Job{}, fmt.Errorf("当前连接类型暂不支持SQL导出")
 	}
 	if a.ctx == nil {
-		return "", fmt.Errorf("应用未初始化")
+		return Job{}, fmt.Errorf("应用未初始化")
 	}
 	log := func(format string, args ...interface{}) {
 		ts := time.Now().Format("15:04:05")
@@ -1293,256 +1463,190 @@ func (a *App) ExportSqlDump(cfg DBConfig, tables []string, mode string) (string,
 	}
 	if cfg.Host == "" || cfg.User == "" || cfg.Port == 0 || cfg.Database == "" {
 		log("导出失败：连接信息不完整")
-		return "", fmt.Errorf("连接信息不完整")
+		return Job{}, fmt.Errorf("连接信息不完整")
 	}
 	if len(tables) == 0 {
 		log("导出失败：未选择任何表")
-		return "", fmt.Errorf("请选择至少一个表")
+		return Job{}, fmt.Errorf("请选择至少一个表")
 	}
+	opts = normalizeExportOptions(opts)
 
 	log("开始导出数据库：%s", cfg.Database)
-	dsn, err := buildDSN(cfg)
-	if err != nil {
-		log("构建连接信息失败：%v", err)
-		return "", err
-	}
-	log("连接数据库 %s:%d", cfg.Host, cfg.Port)
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		log("连接数据库失败：%v", err)
-		return "", err
-	}
-	defer db.Close()
-
 	log("已选择导出 %d 张表", len(tables))
-	fileName := fmt.Sprintf("%s.sql", cfg.Database)
+	ext := compressionExt(opts.Compression)
+	fileName := fmt.Sprintf("%s.sql%s", cfg.Database, ext)
 	log("准备保存文件：%s", fileName)
 	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
 		DefaultFilename: fileName,
 		Filters: []runtime.FileFilter{
-			{DisplayName: "SQL", Pattern: "*.sql"},
+			{DisplayName: "SQL", Pattern: "*.sql" + ext},
 		},
 	})
 	if err != nil {
 		log("选择保存路径失败：%v", err)
-		return "", err
+		return Job{}, err
 	}
 	if path == "" {
 		log("已取消保存")
-		return "", nil
+		return Job{}, nil
 	}
-
-	file, err := os.Create(path)
-	if err != nil {
-		log("创建文件失败：%v", err)
-		return "", err
+	switch opts.SplitMode {
+	case "size":
+		log("输出方式：按大小分卷（每卷 %d MB），压缩：%s", opts.SplitSizeMB, opts.Compression)
+	case "table":
+		log("输出方式：按表分文件，压缩：%s", opts.Compression)
+	default:
+		log("输出方式：单文件，压缩：%s", opts.Compression)
 	}
-	defer file.Close()
 
-	if mode == "schema" || mode == "both" {
-		log("导出表结构")
-		if err := writeSchemaTo(file, db, cfg.Database, tables); err != nil {
-			log("导出表结构失败：%v", err)
-			return "", err
-		}
-	}
-	if mode == "data" || mode == "both" {
-		log("导出表数据")
-		if appSettings.MysqldumpPath == "" {
-			log("mysqldump 路径：自动查找")
-		} else {
-			log("mysqldump 路径：%s", appSettings.MysqldumpPath)
-		}
-		if mode == "both" {
-			_, _ = io.WriteString(file, "\n")
-		}
+	title := fmt.Sprintf("导出 %s（%d 张表）", cfg.Database, len(tables))
+	job := a.jobs.submit("export", title, func(ctx context.Context, r *jobReporter) (interface{}, error) {
 		start := time.Now()
-		if err := a.dumpDataTo(file, cfg, tables, appSettings.MysqldumpPath); err != nil {
-			log("导出表数据失败：%v", err)
-			return "", err
-		}
-		log("数据导出完成，用时 %s", time.Since(start).Truncate(time.Millisecond))
-	}
-
-	log("保存完成：%s", path)
-	return path, nil
+		res, err := a.runExportDump(ctx, r, cfg, tables, mode, opts, path, log)
+		a.auditJob("export", cfg, cfg.Database, fmt.Sprintf("导出 %s 模式=%s 表=%s → %s", cfg.Database, mode, auditTableList(tables), path), start, err)
+		return res, err
+	})
+	return job, nil
 }
 
-// SyncDatabase 同步数据库（结构/数据/结构+数据）
-func (a *App) SyncDatabase(source DBConfig, sourceDB string, target DBConfig, targetDB string, mode string, tables []string) ([]MigrationCheckRow, error) {
-	if normalizeDBType(source.Type) != "mysql" || normalizeDBType(target.Type) != "mysql" {
-		return nil, fmt.Errorf("当前仅支持MySQL之间同步")
-	}
-	if sourceDB == "" || targetDB == "" {
-		return nil, fmt.Errorf("源库和目标库不能为空")
-	}
-	source.Database = sourceDB
-	target.Database = targetDB
-
-	sourceDSN, err := buildDSN(source)
-	if err != nil {
-		return nil, err
-	}
-	targetDSN, err := buildDSN(target)
-	if err != nil {
-		return nil, err
-	}
-	srcDB, err := sql.Open("mysql", sourceDSN)
+// runExportDump 导出任务执行体
+func (a *App) runExportDump(ctx context.Context, r *jobReporter, cfg DBConfig, tables []string, mode string, opts ExportOptions, path string, log func(format string, args ...interface{})) (string, error) {
+	jobID := r.ID()
+	dsn, err := buildDSN(cfg)
 	if err != nil {
-		return nil, err
+		log("构建连接信息失败：%v", err)
+		return "", err
 	}
-	defer srcDB.Close()
-
-	tgtDB, err := sql.Open("mysql", targetDSN)
+	log("连接数据库 %s:%d", cfg.Host, cfg.Port)
+	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		return nil, err
+		log("连接数据库失败：%v", err)
+		return "", err
 	}
-	defer tgtDB.Close()
+	defer db.Close()
 
-	if len(tables) == 0 {
-		allTables, err := fetchAllTableNames(srcDB, sourceDB)
-		if err != nil {
-			return nil, err
+	var estimated int64
+	if mode == "data" || mode == "both" {
+		if stats, err := a.GetTableStats(cfg, cfg.Database); err == nil {
+			selected := make(map[string]struct{}, len(tables))
+			for _, t := range tables {
+				selected[t] = struct{}{}
+			}
+			for _, st := range stats {
+				if _, ok := selected[st.Name]; ok {
+					estimated += st.Rows
+				}
+			}
 		}
-		tables = allTables
-	}
-	if len(tables) == 0 {
-		return []MigrationCheckRow{}, nil
-	}
-
-	// 确保目标库存在
-	if _, err := tgtDB.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`", targetDB)); err != nil {
-		return nil, err
 	}
-	_, _ = tgtDB.Exec("SET FOREIGN_KEY_CHECKS = 0")
-	defer func() { _, _ = tgtDB.Exec("SET FOREIGN_KEY_CHECKS = 1") }()
-
-	var results []MigrationCheckRow
-	for _, table := range tables {
-		row := MigrationCheckRow{Name: table, SourceRows: 0, TargetRows: 0, Status: "pending"}
-
-		// 源表行数
-		if c, err := countRows(srcDB, sourceDB, table); err == nil {
-			row.SourceRows = c
+	tracker := newExportTracker(jobID, estimated, func(p ExportProgress) {
+		runtime.EventsEmit(a.ctx, "export-progress", p)
+		pct := float64(-1)
+		if p.RowsEstimated > 0 {
+			pct = float64(p.RowsWritten) * 100 / float64(p.RowsEstimated)
+			if pct > 99 {
+				pct = 99
+			}
 		}
-
-		// 目标表是否存在 + 行数
-		exists, err := tableExists(tgtDB, targetDB, table)
-		if err != nil {
-			row.Status = "failed: 读取目标表信息失败"
-			results = append(results, row)
-			continue
+		msg := p.Table
+		if msg != "" {
+			msg = "当前表：" + msg
 		}
-		if exists {
-			if c, err := countRows(tgtDB, targetDB, table); err == nil {
-				row.TargetRows = c
+		r.Progress(pct, msg)
+	})
+	log("导出任务ID：%s，预计 %d 行", jobID, estimated)
+	tracker.finish("running", "")
+
+	sink := newExportSink(path, opts, log)
+	out := newExportProgressWriter(ctx, sink, tracker)
+	fail := func(err error) (string, error) {
+		if ctx.Err() != nil {
+			sink.Cleanup()
+			log("导出已取消，已删除未完成的文件")
+			tracker.finish("cancelled", "导出已取消")
+			return "", fmt.Errorf("导出已取消")
+		}
+		sink.Abort()
+		tracker.finish("failed", err.Error())
+		return "", err
+	}
+	writeAll := func(w io.Writer, tables []string) error {
+		if mode == "schema" || mode == "both" {
+			log("导出表结构")
+			if err := writeSchemaTo(w, db, cfg.Database, tables); err != nil {
+				log("导出表结构失败：%v", err)
+				return err
 			}
 		}
-
-		// 结构迁移
-		if mode == "schema" || mode == "both" || (mode == "data" && !exists) {
-			if exists && (mode == "schema" || mode == "both") {
-				row.Status = "failed: 目标已存在同名表"
-				results = append(results, row)
-				continue
-			}
-			createSQL, err := showCreateTable(srcDB, sourceDB, table)
-			if err != nil {
-				row.Status = "failed: 获取源表结构失败"
-				results = append(results, row)
-				continue
+		if mode == "data" || mode == "both" {
+			log("导出表数据")
+			if appSettings.MysqldumpPath == "" {
+				log("mysqldump 路径：自动查找")
+			} else {
+				log("mysqldump 路径：%s", appSettings.MysqldumpPath)
 			}
-			if _, err := tgtDB.Exec(fmt.Sprintf("USE `%s`", targetDB)); err != nil {
-				row.Status = "failed: 切换目标库失败"
-				results = append(results, row)
-				continue
+			if mode == "both" {
+				_, _ = io.WriteString(w, "\n")
 			}
-			if _, err := tgtDB.Exec(createSQL); err != nil {
-				row.Status = "failed: 创建目标表失败"
-				results = append(results, row)
-				continue
+			start := time.Now()
+			if err := a.dumpDataTo(ctx, w, cfg, tables, appSettings.MysqldumpPath); err != nil {
+				log("导出表数据失败：%v", err)
+				return err
 			}
-			exists = true
+			log("数据导出完成，用时 %s", time.Since(start).Truncate(time.Millisecond))
 		}
+		return nil
+	}
 
-		// 数据迁移
-		if mode == "data" || mode == "both" {
-			if !exists {
-				row.Status = "failed: 目标不存在表结构"
-				results = append(results, row)
-				continue
-			}
-			if row.TargetRows > 0 {
-				row.Status = "failed: 目标表已有数据"
-				results = append(results, row)
-				continue
-			}
-
-			// 分批拷贝数据
-			if _, err := tgtDB.Exec(fmt.Sprintf("USE `%s`", targetDB)); err != nil {
-				row.Status = "failed: 切换目标库失败"
-				results = append(results, row)
-				continue
+	if opts.SplitMode == "table" {
+		for _, table := range tables {
+			if ctx.Err() != nil {
+				return fail(ctx.Err())
 			}
-
-			_, err := copyTableDataDirect(srcDB, sourceDB, tgtDB, targetDB, table, 200)
-			if err != nil {
-				row.Status = "failed: 写入数据失败"
-				results = append(results, row)
-				continue
+			if err := sink.BeginTable(table); err != nil {
+				log("创建文件失败：%v", err)
+				return fail(err)
 			}
-			if c, err := countRows(tgtDB, targetDB, table); err == nil {
-				row.TargetRows = c
+			log("导出表：%s", table)
+			if err := writeAll(out, []string{table}); err != nil {
+				return fail(err)
 			}
 		}
-
-		row.Status = "success"
-		results = append(results, row)
+	} else if err := writeAll(out, tables); err != nil {
+		return fail(err)
+	}
+	if ctx.Err() != nil {
+		return fail(ctx.Err())
 	}
 
-	return results, nil
+	resultPath, err := sink.Finish(cfg.Database, mode, tables)
+	if err != nil {
+		log("写入文件失败：%v", err)
+		return fail(err)
+	}
+	tracker.finish("success", resultPath)
+	log("保存完成：%s", resultPath)
+	return resultPath, nil
 }
 
-func extractTableName(stmt string) string {
-	re := regexp.MustCompile("(?i)^(CREATE\\s+TABLE|INSERT\\s+INTO|DROP\\s+TABLE|ALTER\\s+TABLE)\\s+`?([a-zA-Z0-9_\\-\\.]+)`?")
-	match := re.FindStringSubmatch(stmt)
-	if len(match) >= 3 {
-		return match[2]
-	}
-	return ""
-}
-
-func filterDump(input string, keepSchema bool, keepData bool) string {
-	lines := strings.Split(input, "\n")
-	var out []string
-	inInsert := false
-	for _, line := range lines {
-		trim := strings.TrimSpace(line)
-		if strings.HasPrefix(trim, "INSERT INTO") {
-			inInsert = true
-		}
-		if inInsert {
-			if keepData {
-				out = append(out, line)
-			}
-			if strings.Contains(trim, ";") {
-				inInsert = false
-			}
-			continue
-		}
+var tableStmtRe = regexp.MustCompile("(?i)^(?:CREATE\\s+TABLE(?:\\s+IF\\s+NOT\\s+EXISTS)?|INSERT\\s+(?:IGNORE\\s+)?INTO|REPLACE\\s+INTO|DROP\\s+TABLE(?:\\s+IF\\s+EXISTS)?|ALTER\\s+TABLE|LOCK\\s+TABLES)\\s+((?:`[^`]+`|[a-zA-Z0-9_\\-$]+)(?:\\.(?:`[^`]+`|[a-zA-Z0-9_\\-$]+))?)")
 
-		if !keepSchema {
-			continue
-		}
-		if strings.HasPrefix(trim, "LOCK TABLES") || strings.HasPrefix(trim, "UNLOCK TABLES") {
-			continue
-		}
-		if strings.HasPrefix(trim, "INSERT INTO") {
-			continue
+// extractTableName 识别建表/插入/删表/改表语句中的表名（带库名前缀时只返回表名）
+func extractTableName(stmt string) string {
+	match := tableStmtRe.FindStringSubmatch(stmt)
+	if len(match) < 2 {
+		return ""
+	}
+	name := match[1]
+	if strings.HasSuffix(name, "`") {
+		if idx := strings.LastIndex(name[:len(name)-1], "`"); idx >= 0 {
+			return name[idx+1 : len(name)-1]
 		}
-		out = append(out, line)
 	}
-	return strings.Join(out, "\n")
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return strings.Trim(name, "`")
 }
 
 func buildDriverAndDSN(cfg DBConfig) (string, string, error) {
@@ -1578,6 +1682,21 @@ func buildDSN(cfg DBConfig) (string, error) {
 	return dsn, err
 }
 
+// buildDSNWithParams 构造带会话变量的MySQL连接串，变量会在每个新连接上生效
+func buildDSNWithParams(cfg DBConfig, params map[string]string) (string, error) {
+	if cfg.Host == "" || cfg.User == "" || cfg.Port == 0 {
+		return "", fmt.Errorf("连接信息不完整")
+	}
+	conf := mysqlDriver.NewConfig()
+	conf.User = cfg.User
+	conf.Passwd = cfg.Password
+	conf.Net = "tcp"
+	conf.Addr = fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	conf.DBName = cfg.Database
+	conf.Params = params
+	return conf.FormatDSN(), nil
+}
+
 func normalizeDBType(t string) string {
 	switch strings.ToLower(strings.TrimSpace(t)) {
 	case "", "mysql":
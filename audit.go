@@ -0,0 +1,788 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// 审计结果
+const (
+	auditSuccess  = "success"
+	auditError    = "error"
+	auditBlocked  = "blocked"
+	auditCanceled = "canceled"
+)
+
+// AuditEntry 审计记录；Hash = sha256(PrevHash + 记录内容)，逐条串联，删改任一条都会使后续校验失败
+type AuditEntry struct {
+	Seq         int64  `json:"seq"`
+	Time        string `json:"time"`
+	OSUser      string `json:"osUser"`
+	Hostname    string `json:"hostname"`
+	Action      string `json:"action"` // query / kill / sync / export / connection.create / connection.update / connection.delete / audit.prune
+	ConnID      string `json:"connId,omitempty"`
+	ConnName    string `json:"connName,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Database    string `json:"database,omitempty"`
+	Statement   string `json:"statement"`
+	Outcome     string `json:"outcome"` // success / error / blocked / canceled
+	Error       string `json:"error,omitempty"`
+	Rows        int64  `json:"rows,omitempty"` // 返回的行数
+	DurationMs  int64  `json:"durationMs"`
+	PrevHash    string `json:"prevHash"`
+	Hash        string `json:"hash"`
+}
+
+// AuditFilter 审计日志查询条件；日期格式 2006-01-02，包含首尾两天
+type AuditFilter struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Action  string `json:"action"` // 前缀匹配，如 connection
+	Outcome string `json:"outcome"`
+	Keyword string `json:"keyword"` // 匹配语句、连接名、库名、用户
+	Limit   int    `json:"limit"`
+	Offset  int    `json:"offset"`
+}
+
+// AuditQueryResult 审计日志查询结果（最新在前）
+type AuditQueryResult struct {
+	Entries []AuditEntry `json:"entries"`
+	Total   int          `json:"total"`
+}
+
+// AuditVerifyResult 哈希链校验结果
+type AuditVerifyResult struct {
+	OK         bool   `json:"ok"`
+	Files      int    `json:"files"`
+	Checked    int    `json:"checked"`
+	FirstSeq   int64  `json:"firstSeq"`
+	LastSeq    int64  `json:"lastSeq"`
+	BrokenFile string `json:"brokenFile,omitempty"`
+	BrokenLine int    `json:"brokenLine,omitempty"`
+	Message    string `json:"message"`
+}
+
+// auditLog 追加写入的审计日志，按月分文件：audit/2006-01.jsonl
+type auditLog struct {
+	mu        sync.Mutex
+	loaded    bool
+	seq       int64
+	lastHash  string
+	anchor    auditAnchor
+	anchorErr error // 锚点缺失或被修改，此后不再更新锚点，校验始终失败
+}
+
+// auditAnchor 链首尾锚点，保存在审计目录之外并带 HMAC。
+// 哈希链本身无密钥，能改写日志文件的人可以整体重写链；锚点记录链尾和保留的第一条记录，
+// 删除首尾记录或重写链都会与锚点不符
+type auditAnchor struct {
+	FirstSeq      int64  `json:"firstSeq"`      // 最早保留记录的序号，只在首次写入和保留期清理时更新
+	FirstPrevHash string `json:"firstPrevHash"` // 最早保留记录的 PrevHash
+	LastSeq       int64  `json:"lastSeq"`
+	LastHash      string `json:"lastHash"`
+	MAC           string `json:"mac"`
+}
+
+func newAuditLog() *auditLog {
+	return &auditLog{}
+}
+
+func auditDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dms-new", "audit"), nil
+}
+
+// auditConfigPath 审计目录之外的配置文件路径（锚点、密钥）
+func auditConfigPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dms-new", name), nil
+}
+
+// auditKey 读取锚点的 HMAC 密钥，create 为 true 且不存在时随机生成
+func auditKey(create bool) ([]byte, error) {
+	path, err := auditConfigPath("audit.key")
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return hex.DecodeString(strings.TrimSpace(string(data)))
+	}
+	if !errors.Is(err, os.ErrNotExist) || !create {
+		return nil, err
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)), 0o600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// anchorMAC 计算锚点的 HMAC（MAC 字段置空后序列化）
+func anchorMAC(a auditAnchor, key []byte) string {
+	a.MAC = ""
+	data, _ := json.Marshal(a)
+	m := hmac.New(sha256.New, key)
+	m.Write(data)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// loadAuditAnchor 读取并校验锚点；ok 为 false 表示尚未建立
+func loadAuditAnchor() (a auditAnchor, ok bool, err error) {
+	path, err := auditConfigPath("audit.anchor.json")
+	if err != nil {
+		return a, false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// 密钥与锚点一同创建，密钥存在而锚点缺失说明锚点被删除
+		if _, kerr := auditKey(false); kerr == nil {
+			return a, false, fmt.Errorf("审计校验锚点缺失，可能已被删除")
+		}
+		return a, false, nil
+	}
+	if err != nil {
+		return a, false, err
+	}
+	key, err := auditKey(false)
+	if err != nil {
+		return a, false, fmt.Errorf("读取审计校验密钥失败: %v", err)
+	}
+	if err := json.Unmarshal(data, &a); err != nil || !hmac.Equal([]byte(anchorMAC(a, key)), []byte(a.MAC)) {
+		return auditAnchor{}, false, fmt.Errorf("审计校验锚点无效，可能已被修改")
+	}
+	return a, true, nil
+}
+
+// saveAuditAnchor 写入锚点，先写临时文件再替换，避免中断时留下半个文件
+func saveAuditAnchor(a auditAnchor) error {
+	key, err := auditKey(true)
+	if err != nil {
+		return err
+	}
+	a.MAC = anchorMAC(a, key)
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	path, err := auditConfigPath("audit.anchor.json")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// auditFiles 按时间顺序列出审计文件
+func auditFiles() ([]string, error) {
+	dir, err := auditDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".jsonl") {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readAuditFile 逐行读取审计文件，fn 返回 false 时停止
+func readAuditFile(path string, fn func(line int, raw []byte, e AuditEntry, err error) bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		raw := sc.Bytes()
+		if len(strings.TrimSpace(string(raw))) == 0 {
+			continue
+		}
+		var e AuditEntry
+		err := json.Unmarshal(raw, &e)
+		if !fn(line, raw, e, err) {
+			return nil
+		}
+	}
+	return sc.Err()
+}
+
+// auditHash 计算记录哈希：记录内容按字段顺序序列化（Hash 置空）后与上一条哈希串联
+func auditHash(e AuditEntry) string {
+	e.Hash = ""
+	data, _ := json.Marshal(e)
+	h := sha256.New()
+	h.Write([]byte(e.PrevHash))
+	h.Write([]byte{'\n'})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// firstAuditEntry 读取现存最早的一条记录
+func firstAuditEntry(files []string) (first AuditEntry, found bool, err error) {
+	for _, path := range files {
+		err := readAuditFile(path, func(_ int, _ []byte, e AuditEntry, err error) bool {
+			if err == nil {
+				first, found = e, true
+				return false
+			}
+			return true
+		})
+		if err != nil || found {
+			return first, found, err
+		}
+	}
+	return first, false, nil
+}
+
+// load 以锚点记录的链尾为准继续追加；日志末尾被截断时新记录与现存记录不衔接，校验可以发现。
+// 尚无锚点（首次使用或旧版本的日志）时按现存记录建立
+func (l *auditLog) load() error {
+	files, err := auditFiles()
+	if err != nil {
+		return err
+	}
+	anchor, ok, err := loadAuditAnchor()
+	var last AuditEntry
+	linked := false // 最新文件中包含锚点记录的链尾
+	if len(files) > 0 {
+		err := readAuditFile(files[len(files)-1], func(_ int, _ []byte, e AuditEntry, err error) bool {
+			if err == nil {
+				last = e
+				if ok && e.Seq == anchor.LastSeq && e.Hash == anchor.LastHash {
+					linked = true
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	l.seq, l.lastHash = last.Seq, last.Hash
+	switch {
+	case err != nil:
+		l.anchorErr = err
+	case ok && linked && last.Seq > anchor.LastSeq:
+		// 上次写入记录后未及更新锚点就退出，从锚点链尾接续的记录补记入锚点
+		l.anchor = anchor
+		l.anchor.LastSeq, l.anchor.LastHash = last.Seq, last.Hash
+		if err := saveAuditAnchor(l.anchor); err != nil {
+			return err
+		}
+	case ok:
+		l.anchor = anchor
+		l.seq, l.lastHash = anchor.LastSeq, anchor.LastHash
+	case last.Seq > 0:
+		first, _, err := firstAuditEntry(files)
+		if err != nil {
+			return err
+		}
+		l.anchor = auditAnchor{FirstSeq: first.Seq, FirstPrevHash: first.PrevHash, LastSeq: last.Seq, LastHash: last.Hash}
+		if err := saveAuditAnchor(l.anchor); err != nil {
+			return err
+		}
+	}
+	l.loaded = true
+	return nil
+}
+
+func (l *auditLog) append(e AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.loaded {
+		if err := l.load(); err != nil {
+			return err
+		}
+	}
+	dir, err := auditDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	now := time.Now()
+	e.Seq = l.seq + 1
+	e.Time = now.Format(time.RFC3339Nano)
+	e.OSUser = osUserName()
+	e.Hostname, _ = os.Hostname()
+	e.PrevHash = l.lastHash
+	e.Hash = auditHash(e)
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, now.Format("2006-01")+".jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	l.seq, l.lastHash = e.Seq, e.Hash
+	if l.anchorErr != nil {
+		return nil
+	}
+	if l.anchor.FirstSeq == 0 {
+		l.anchor.FirstSeq, l.anchor.FirstPrevHash = e.Seq, e.PrevHash
+	}
+	l.anchor.LastSeq, l.anchor.LastHash = e.Seq, e.Hash
+	return saveAuditAnchor(l.anchor)
+}
+
+// prune 删除整月早于保留期限的审计文件，返回被删除的文件名
+func (l *auditLog) prune(days int) ([]string, error) {
+	if days <= 0 {
+		return nil, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.loaded {
+		if err := l.load(); err != nil {
+			return nil, err
+		}
+	}
+	files, err := auditFiles()
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	var removed []string
+	// 保留最新文件，保证链尾可读
+	for i, path := range files {
+		if i == len(files)-1 {
+			break
+		}
+		name := filepath.Base(path)
+		month, err := time.ParseInLocation("2006-01", strings.TrimSuffix(name, ".jsonl"), time.Local)
+		if err != nil || !month.AddDate(0, 1, 0).Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, name)
+	}
+	if len(removed) > 0 && l.anchorErr == nil {
+		// 清理后最早保留的记录记入锚点，校验时据此区分保留期清理与人为删除
+		files, err := auditFiles()
+		if err != nil {
+			return removed, err
+		}
+		first, found, err := firstAuditEntry(files)
+		if err != nil {
+			return removed, err
+		}
+		if found {
+			l.anchor.FirstSeq, l.anchor.FirstPrevHash = first.Seq, first.PrevHash
+			if err := saveAuditAnchor(l.anchor); err != nil {
+				return removed, err
+			}
+		}
+	}
+	return removed, nil
+}
+
+func osUserName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// auditOutcome 根据错误确定审计结果
+func auditOutcome(err error) (string, string) {
+	var ge *guardError
+	switch {
+	case err == nil:
+		return auditSuccess, ""
+	case errors.As(err, &ge):
+		return auditBlocked, err.Error()
+	case errors.Is(err, context.Canceled):
+		return auditCanceled, err.Error()
+	default:
+		return auditError, err.Error()
+	}
+}
+
+// audit 写入审计记录；写入失败不影响业务操作，只记录到应用日志
+func (a *App) audit(e AuditEntry) {
+	if err := a.auditLog.append(e); err != nil && a.ctx != nil {
+		runtime.LogErrorf(a.ctx, "写入审计日志失败: %v", err)
+	}
+}
+
+// auditConn 填充审计记录中的连接信息
+func auditConn(e AuditEntry, cfg DBConfig) AuditEntry {
+	cfg = resolveConnection(cfg)
+	e.ConnID = cfg.ID
+	e.ConnName = connDisplayName(cfg)
+	e.Environment = cfg.Environment
+	return e
+}
+
+// auditStatement 记录当前连接上执行的语句
+func (a *App) auditStatement(action, stmt string, start time.Time, rows int, err error) {
+	e := auditConn(AuditEntry{Action: action, Database: a.currentSchema, Statement: stmt}, a.currentCfg)
+	e.Outcome, e.Error = auditOutcome(err)
+	e.Rows = int64(rows)
+	e.DurationMs = time.Since(start).Milliseconds()
+	a.audit(e)
+}
+
+// auditJob 记录后台任务（同步、导出）的执行结果
+func (a *App) auditJob(action string, cfg DBConfig, database, stmt string, start time.Time, err error) {
+	e := auditConn(AuditEntry{Action: action, Database: database, Statement: stmt}, cfg)
+	e.Outcome, e.Error = auditOutcome(err)
+	e.DurationMs = time.Since(start).Milliseconds()
+	a.audit(e)
+}
+
+// auditTableList 表清单过长时只记录前若干个
+func auditTableList(tables []string) string {
+	const max = 20
+	if len(tables) == 0 {
+		return "全部"
+	}
+	if len(tables) <= max {
+		return strings.Join(tables, ",")
+	}
+	return fmt.Sprintf("%s 等 %d 张", strings.Join(tables[:max], ","), len(tables))
+}
+
+// syncAuditStatement 同步任务的审计描述
+func syncAuditStatement(source DBConfig, sourceDB, targetDB, mode string, tables []string) string {
+	return fmt.Sprintf("同步 %s/%s → %s 模式=%s 表=%s", connDisplayName(resolveConnection(source)), sourceDB, targetDB, mode, auditTableList(tables))
+}
+
+// auditConnChange 记录连接的新增、修改与删除；密码只记录是否变更
+func (a *App) auditConnChange(action string, old, cfg DBConfig, err error) {
+	var stmt string
+	switch action {
+	case "connection.update":
+		var changes []string
+		field := func(name, before, after string) {
+			if before != after {
+				changes = append(changes, fmt.Sprintf("%s: %s → %s", name, before, after))
+			}
+		}
+		field("name", old.Name, cfg.Name)
+		field("type", old.Type, cfg.Type)
+		field("host", old.Host, cfg.Host)
+		field("port", strconv.Itoa(old.Port), strconv.Itoa(cfg.Port))
+		field("user", old.User, cfg.User)
+		field("database", old.Database, cfg.Database)
+		field("environment", normalizeEnvironment(old.Environment), normalizeEnvironment(cfg.Environment))
+		field("readOnly", strconv.FormatBool(old.ReadOnly), strconv.FormatBool(cfg.ReadOnly))
+		if old.Password != cfg.Password {
+			changes = append(changes, "password: 已修改")
+		}
+		stmt = "修改连接 " + strings.Join(changes, "; ")
+		if len(changes) == 0 {
+			stmt = "修改连接（无变化）"
+		}
+	case "connection.delete":
+		stmt = fmt.Sprintf("删除连接 %s %s@%s:%d/%s", cfg.Type, cfg.User, cfg.Host, cfg.Port, cfg.Database)
+	default:
+		stmt = fmt.Sprintf("新增连接 %s %s@%s:%d/%s env=%s readOnly=%v", cfg.Type, cfg.User, cfg.Host, cfg.Port, cfg.Database,
+			normalizeEnvironment(cfg.Environment), cfg.ReadOnly)
+	}
+	e := AuditEntry{Action: action, ConnID: cfg.ID, ConnName: cfg.Name, Environment: normalizeEnvironment(cfg.Environment), Statement: stmt}
+	e.Outcome, e.Error = auditOutcome(err)
+	a.audit(e)
+}
+
+// pruneAuditLog 按设置的保留天数清理审计文件，清理动作本身也记入审计
+func (a *App) pruneAuditLog() {
+	removed, err := a.auditLog.prune(appSettings.AuditRetentionDays)
+	if len(removed) == 0 && err == nil {
+		return
+	}
+	e := AuditEntry{Action: "audit.prune", Statement: fmt.Sprintf("保留 %d 天，删除 %s", appSettings.AuditRetentionDays, strings.Join(removed, ", "))}
+	e.Outcome, e.Error = auditOutcome(err)
+	a.audit(e)
+}
+
+// trackSchema 跟踪 USE / ALTER SESSION SET CURRENT_SCHEMA 切换后的当前库，供审计记录使用
+func (a *App) trackSchema(query string) {
+	tokens, err := tokenizeSQL(query, a.currentDBType)
+	if err != nil {
+		return
+	}
+	sig := significantTokens(tokens)
+	switch {
+	case len(sig) >= 2 && tokenIs(sig[0], "USE"):
+		_, a.currentSchema, _ = readObjectName(sig, 1)
+	case len(sig) >= 6 && tokenIs(sig[0], "ALTER") && tokenIs(sig[1], "SESSION") && tokenIs(sig[2], "SET") &&
+		tokenIs(sig[3], "CURRENT_SCHEMA") && sig[4].text == "=":
+		_, a.currentSchema, _ = readObjectName(sig, 5)
+	}
+}
+
+func parseAuditDate(s string, endOfDay bool) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, true
+}
+
+// matchAudit 筛选审计记录（不含分页）
+func matchAudit(f AuditFilter) ([]AuditEntry, error) {
+	files, err := auditFiles()
+	if err != nil {
+		return nil, err
+	}
+	from, hasFrom := parseAuditDate(f.From, false)
+	to, hasTo := parseAuditDate(f.To, true)
+	keyword := strings.ToLower(strings.TrimSpace(f.Keyword))
+	var out []AuditEntry
+	for _, path := range files {
+		month, err := time.ParseInLocation("2006-01", strings.TrimSuffix(filepath.Base(path), ".jsonl"), time.Local)
+		if err == nil && ((hasFrom && !month.AddDate(0, 1, 0).After(from)) || (hasTo && !month.Before(to))) {
+			continue
+		}
+		err = readAuditFile(path, func(_ int, _ []byte, e AuditEntry, err error) bool {
+			if err != nil {
+				return true
+			}
+			t, perr := time.Parse(time.RFC3339Nano, e.Time)
+			if perr == nil && ((hasFrom && t.Before(from)) || (hasTo && !t.Before(to))) {
+				return true
+			}
+			if f.Action != "" && !strings.HasPrefix(e.Action, f.Action) {
+				return true
+			}
+			if f.Outcome != "" && e.Outcome != f.Outcome {
+				return true
+			}
+			if keyword != "" {
+				text := strings.ToLower(strings.Join([]string{e.Statement, e.ConnName, e.Database, e.OSUser, e.Error}, "\n"))
+				if !strings.Contains(text, keyword) {
+					return true
+				}
+			}
+			out = append(out, e)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// QueryAuditLog 查询审计日志，最新记录在前
+func (a *App) QueryAuditLog(filter AuditFilter) (AuditQueryResult, error) {
+	list, err := matchAudit(filter)
+	if err != nil {
+		return AuditQueryResult{}, err
+	}
+	res := AuditQueryResult{Total: len(list), Entries: []AuditEntry{}}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 200
+	}
+	for i := len(list) - 1 - filter.Offset; i >= 0 && len(res.Entries) < limit; i-- {
+		res.Entries = append(res.Entries, list[i])
+	}
+	return res, nil
+}
+
+// VerifyAuditLog 校验审计日志哈希链，并核对链首尾与锚点一致：
+// 最早的记录只能是保留期清理后记入锚点的那条，最后的记录必须是锚点记录的链尾
+func (a *App) VerifyAuditLog() (AuditVerifyResult, error) {
+	return a.auditLog.verify()
+}
+
+func (l *auditLog) verify() (AuditVerifyResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.loaded {
+		if err := l.load(); err != nil {
+			return AuditVerifyResult{}, err
+		}
+	}
+	files, err := auditFiles()
+	if err != nil {
+		return AuditVerifyResult{}, err
+	}
+	res := AuditVerifyResult{OK: true, Files: len(files)}
+	var prevHash, firstPrevHash string
+	var prevSeq int64
+	for _, path := range files {
+		name := filepath.Base(path)
+		err := readAuditFile(path, func(line int, _ []byte, e AuditEntry, err error) bool {
+			fail := func(msg string) bool {
+				res.OK, res.BrokenFile, res.BrokenLine, res.Message = false, name, line, msg
+				return false
+			}
+			if err != nil {
+				return fail(fmt.Sprintf("记录无法解析: %v", err))
+			}
+			if res.Checked > 0 {
+				if e.Seq != prevSeq+1 {
+					return fail(fmt.Sprintf("序号不连续：%d 之后为 %d", prevSeq, e.Seq))
+				}
+				if e.PrevHash != prevHash {
+					return fail(fmt.Sprintf("记录 %d 与上一条记录的哈希不衔接", e.Seq))
+				}
+			} else {
+				res.FirstSeq, firstPrevHash = e.Seq, e.PrevHash
+			}
+			if auditHash(e) != e.Hash {
+				return fail(fmt.Sprintf("记录 %d 内容与哈希不符，可能已被修改", e.Seq))
+			}
+			prevHash, prevSeq = e.Hash, e.Seq
+			res.Checked++
+			res.LastSeq = e.Seq
+			return true
+		})
+		if err != nil {
+			return AuditVerifyResult{}, err
+		}
+		if !res.OK {
+			return res, nil
+		}
+	}
+	anchor := l.anchor
+	switch {
+	case l.anchorErr != nil:
+		res.OK, res.Message = false, l.anchorErr.Error()
+		return res, nil
+	case res.Checked == 0 && anchor.LastSeq > 0:
+		res.OK, res.Message = false, fmt.Sprintf("审计记录全部缺失（应至记录 %d）", anchor.LastSeq)
+		return res, nil
+	case res.Checked > 0 && (res.FirstSeq != anchor.FirstSeq || firstPrevHash != anchor.FirstPrevHash):
+		res.OK = false
+		res.Message = fmt.Sprintf("最早的记录为 %d，与保留期清理记录不符（应为 %d），记录可能被删除或改写", res.FirstSeq, anchor.FirstSeq)
+		return res, nil
+	case res.Checked > 0 && (prevSeq != anchor.LastSeq || prevHash != anchor.LastHash):
+		res.OK = false
+		res.Message = fmt.Sprintf("日志末尾的记录缺失或被改写：现存记录至 %d，应至 %d", prevSeq, anchor.LastSeq)
+		return res, nil
+	}
+	switch {
+	case res.Checked == 0:
+		res.Message = "暂无审计记录"
+	case res.FirstSeq > 1:
+		res.Message = fmt.Sprintf("校验通过：记录 %d - %d（更早的记录已按保留期清理）", res.FirstSeq, res.LastSeq)
+	default:
+		res.Message = fmt.Sprintf("校验通过：共 %d 条记录", res.Checked)
+	}
+	return res, nil
+}
+
+// ExportAuditLog 按条件导出审计日志，format 为 csv 或 jsonl（原始记录，可独立校验哈希链）
+func (a *App) ExportAuditLog(filter AuditFilter, format string) (string, error) {
+	if a.ctx == nil {
+		return "", fmt.Errorf("应用未初始化")
+	}
+	if format != "jsonl" {
+		format = "csv"
+	}
+	list, err := matchAudit(filter)
+	if err != nil {
+		return "", err
+	}
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		DefaultFilename: fmt.Sprintf("audit-%s.%s", time.Now().Format("20060102"), format),
+		Filters: []runtime.FileFilter{
+			{DisplayName: strings.ToUpper(format), Pattern: "*." + format},
+		},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("创建文件失败: %v", err)
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	if format == "jsonl" {
+		for _, e := range list {
+			line, _ := json.Marshal(e)
+			w.Write(line)
+			w.WriteByte('\n')
+		}
+	} else {
+		// 带 BOM 便于 Excel 识别 UTF-8
+		w.WriteString("\xEF\xBB\xBF")
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"seq", "time", "osUser", "hostname", "action", "connection", "environment", "database", "statement", "outcome", "error", "rows", "durationMs", "hash"})
+		for _, e := range list {
+			_ = cw.Write([]string{strconv.FormatInt(e.Seq, 10), e.Time, e.OSUser, e.Hostname, e.Action, e.ConnName, e.Environment, e.Database,
+				e.Statement, e.Outcome, e.Error, strconv.FormatInt(e.Rows, 10), strconv.FormatInt(e.DurationMs, 10), e.Hash})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
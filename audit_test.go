@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupAuditLog 在临时配置目录中写入 n 条审计记录
+func setupAuditLog(t *testing.T, n int) (*auditLog, string) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	l := newAuditLog()
+	for i := 0; i < n; i++ {
+		if err := l.append(AuditEntry{Action: "query", Statement: "SELECT 1", Outcome: auditSuccess}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := auditFiles()
+	if err != nil || len(files) != 1 {
+		t.Fatalf("audit files = %v, %v", files, err)
+	}
+	return l, files[0]
+}
+
+// rewriteAuditFile 按行号（从 0 开始）保留审计文件中的记录
+func rewriteAuditFile(t *testing.T, path string, keep func(i int) bool) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []string
+	for i, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		if keep(i) {
+			kept = append(kept, line+"\n")
+		}
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(kept, "")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuditVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, path string)
+		ok     bool
+		msg    string
+	}{
+		{"intact", func(*testing.T, string) {}, true, "共 5 条"},
+		{"leading entries deleted", func(t *testing.T, path string) {
+			rewriteAuditFile(t, path, func(i int) bool { return i >= 2 })
+		}, false, "与保留期清理记录不符"},
+		{"tail truncated", func(t *testing.T, path string) {
+			rewriteAuditFile(t, path, func(i int) bool { return i < 3 })
+		}, false, "日志末尾的记录缺失"},
+		{"all entries deleted", func(t *testing.T, path string) {
+			os.Remove(path)
+		}, false, "全部缺失"},
+		{"anchor edited", func(t *testing.T, path string) {
+			anchor, _ := auditConfigPath("audit.anchor.json")
+			data, _ := os.ReadFile(anchor)
+			os.WriteFile(anchor, []byte(strings.Replace(string(data), `"lastSeq":5`, `"lastSeq":3`, 1)), 0o600)
+		}, false, "锚点无效"},
+		{"anchor deleted", func(t *testing.T, path string) {
+			anchor, _ := auditConfigPath("audit.anchor.json")
+			os.Remove(anchor)
+		}, false, "锚点缺失"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, path := setupAuditLog(t, 5)
+			tt.tamper(t, path)
+			// 模拟重新启动后校验
+			res, err := newAuditLog().verify()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.OK != tt.ok || !strings.Contains(res.Message, tt.msg) {
+				t.Fatalf("verify = %v %q, want %v containing %q", res.OK, res.Message, tt.ok, tt.msg)
+			}
+		})
+	}
+}
+
+func TestAuditVerifyAfterPrune(t *testing.T) {
+	l, path := setupAuditLog(t, 3)
+	// 已写入的记录挪到很早的月份，之后的记录写入当月文件
+	if err := os.Rename(path, filepath.Join(filepath.Dir(path), "2000-01.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := l.append(AuditEntry{Action: "query", Statement: "SELECT 2", Outcome: auditSuccess}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removed, err := l.prune(30)
+	if err != nil || len(removed) != 1 {
+		t.Fatalf("prune = %v, %v", removed, err)
+	}
+	res, err := newAuditLog().verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.OK || res.FirstSeq != 4 || res.LastSeq != 5 {
+		t.Fatalf("verify = %+v", res)
+	}
+}
+
+func TestAuditLoadContinuesFromAnchor(t *testing.T) {
+	_, path := setupAuditLog(t, 4)
+	rewriteAuditFile(t, path, func(i int) bool { return i < 2 })
+	// 截断后继续写入：序号从锚点接续，校验仍能发现缺失的记录
+	l := newAuditLog()
+	if err := l.append(AuditEntry{Action: "query", Statement: "SELECT 3", Outcome: auditSuccess}); err != nil {
+		t.Fatal(err)
+	}
+	if l.seq != 5 {
+		t.Fatalf("seq = %d, want 5", l.seq)
+	}
+	res, err := newAuditLog().verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.OK || !strings.Contains(res.Message, "序号不连续") {
+		t.Fatalf("verify = %v %q", res.OK, res.Message)
+	}
+}
+
+func TestAuditLoadAdoptsUnanchoredTail(t *testing.T) {
+	// 写入第 4 条记录后未及更新锚点：锚点停在第 3 条
+	l, _ := setupAuditLog(t, 4)
+	files, _ := auditFiles()
+	var third AuditEntry
+	readAuditFile(files[0], func(_ int, _ []byte, e AuditEntry, _ error) bool {
+		if e.Seq == 3 {
+			third = e
+		}
+		return true
+	})
+	anchor := l.anchor
+	anchor.LastSeq, anchor.LastHash = third.Seq, third.Hash
+	if err := saveAuditAnchor(anchor); err != nil {
+		t.Fatal(err)
+	}
+	res, err := newAuditLog().verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.OK || res.LastSeq != 4 {
+		t.Fatalf("verify = %v %q", res.OK, res.Message)
+	}
+}
@@ -0,0 +1,764 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// BinlogQuery binlog 浏览条件
+type BinlogQuery struct {
+	Source     string   `json:"source"`     // server：从实例读取；file：解析本地 binlog 文件
+	Files      []string `json:"files"`      // Source 为 file 时的本地文件，按顺序解析
+	StartFile  string   `json:"startFile"`  // Source 为 server 时的起始 binlog，为空时取当前 binlog
+	StartPos   uint32   `json:"startPos"`   // 起始位置，为 0 时从文件头开始
+	EndFile    string   `json:"endFile"`    // 读到该文件末尾为止，为空时读到当前位点
+	StartTime  string   `json:"startTime"`  // 2006-01-02 15:04:05，按实例默认时区（见 BinlogBrowseResult.TimeZone）
+	EndTime    string   `json:"endTime"`    // 晚于该时间的事件不再读取
+	Databases  []string `json:"databases"`  // 为空表示全部
+	Tables     []string `json:"tables"`     // 表名或 库名.表名，为空表示全部
+	EventTypes []string `json:"eventTypes"` // insert / update / delete，为空表示全部
+	MaxEvents  int      `json:"maxEvents"`  // 返回的最大行变更数，默认 1000
+}
+
+// BinlogRowChange 一行变更及其正向、回滚语句
+type BinlogRowChange struct {
+	File         string        `json:"file"`
+	Pos          uint32        `json:"pos"` // 事件结束位置
+	Time         string        `json:"time"`
+	GTID         string        `json:"gtid,omitempty"`
+	Database     string        `json:"database"`
+	Table        string        `json:"table"`
+	Type         string        `json:"type"` // insert / update / delete
+	Columns      []string      `json:"columns"`
+	Before       []interface{} `json:"before,omitempty"`
+	After        []interface{} `json:"after,omitempty"`
+	Changed      []string      `json:"changed,omitempty"` // update 时值发生变化的列
+	ForwardSQL   string        `json:"forwardSql"`
+	FlashbackSQL string        `json:"flashbackSql"`
+}
+
+// BinlogBrowseResult binlog 浏览结果；回滚脚本按变更的逆序排列
+type BinlogBrowseResult struct {
+	Changes         []BinlogRowChange `json:"changes"`
+	Truncated       bool              `json:"truncated"`
+	ScannedEvents   int64             `json:"scannedEvents"`
+	EndFile         string            `json:"endFile"`
+	EndPos          uint32            `json:"endPos"`
+	ForwardScript   string            `json:"forwardScript"`
+	FlashbackScript string            `json:"flashbackScript"`
+	Warnings        []string          `json:"warnings,omitempty"`
+	TimeZone        string            `json:"timeZone"` // 事件时间所用的时区；TIMESTAMP 列按其当前偏移换算，与脚本中的 SET time_zone 一致
+	ElapsedSec      float64           `json:"elapsedSec"`
+}
+
+// BinlogFile 实例上的 binlog 文件
+type BinlogFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+const defaultBinlogMaxEvents = 1000
+
+var errBinlogStop = errors.New("binlog stop")
+
+// ListBinlogFiles 列出实例上的 binlog 文件
+func (a *App) ListBinlogFiles(cfg DBConfig) ([]BinlogFile, error) {
+	if normalizeDBType(cfg.Type) != "mysql" {
+		return nil, fmt.Errorf("仅支持MySQL")
+	}
+	cfg.Database = ""
+	dsn, err := buildDSN(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.Query("SHOW BINARY LOGS")
+	if err != nil {
+		return nil, fmt.Errorf("读取 binlog 列表失败: %v", err)
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	files := []BinlogFile{}
+	for rows.Next() {
+		values := make([]sql.NullString, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		f := BinlogFile{Name: values[0].String}
+		if len(values) > 1 {
+			fmt.Sscan(values[1].String, &f.Size)
+		}
+		files = append(files, f)
+	}
+	return files, rows.Err()
+}
+
+// SelectBinlogFiles 弹出打开文件对话框选择本地 binlog 文件（可多选）
+func (a *App) SelectBinlogFiles() ([]string, error) {
+	if a.ctx == nil {
+		return nil, fmt.Errorf("应用未初始化")
+	}
+	return runtime.OpenMultipleFilesDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "选择 binlog 文件",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "All Files", Pattern: "*"},
+		},
+	})
+}
+
+// BrowseBinlog 读取 binlog 中的行变更（需 binlog_format=ROW），生成正向 SQL 与回滚 SQL。
+// cfg 用于从实例读取，解析本地文件时用于补全列名与主键（binlog_row_metadata 非 FULL 时）
+func (a *App) BrowseBinlog(cfg DBConfig, query BinlogQuery) (BinlogBrowseResult, error) {
+	job, err := a.SubmitBinlogJob(cfg, query)
+	if err != nil {
+		return BinlogBrowseResult{}, err
+	}
+	res, err := a.jobs.wait(job.ID)
+	result, _ := res.(BinlogBrowseResult)
+	return result, err
+}
+
+// SubmitBinlogJob 提交 binlog 浏览任务，立即返回任务信息
+func (a *App) SubmitBinlogJob(cfg DBConfig, query BinlogQuery) (Job, error) {
+	switch query.Source {
+	case "server":
+		if normalizeDBType(cfg.Type) != "mysql" {
+			return Job{}, fmt.Errorf("仅支持MySQL")
+		}
+	case "file":
+		if len(query.Files) == 0 {
+			return Job{}, fmt.Errorf("请选择 binlog 文件")
+		}
+	default:
+		return Job{}, fmt.Errorf("不支持的来源: %s", query.Source)
+	}
+	f, err := newBinlogFilter(query)
+	if err != nil {
+		return Job{}, err
+	}
+	title := "浏览 binlog"
+	if query.Source == "file" {
+		title += " " + filepath.Base(query.Files[0])
+	} else if query.StartFile != "" {
+		title += " " + query.StartFile
+	}
+	job := a.jobs.submit("binlog", title, func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		return runBrowseBinlog(ctx, r, cfg, query, f)
+	})
+	return job, nil
+}
+
+// binlogFilter 过滤条件
+type binlogFilter struct {
+	start, end time.Time
+	databases  map[string]bool
+	tables     map[string]bool
+	types      map[string]bool
+	maxEvents  int
+}
+
+func newBinlogFilter(q BinlogQuery) (*binlogFilter, error) {
+	f := &binlogFilter{databases: map[string]bool{}, tables: map[string]bool{}, types: map[string]bool{}, maxEvents: q.MaxEvents}
+	if f.maxEvents <= 0 {
+		f.maxEvents = defaultBinlogMaxEvents
+	}
+	var err error
+	if q.StartTime != "" {
+		if f.start, err = time.ParseInLocation("2006-01-02 15:04:05", q.StartTime, time.UTC); err != nil {
+			return nil, fmt.Errorf("开始时间格式错误: %v", err)
+		}
+	}
+	if q.EndTime != "" {
+		if f.end, err = time.ParseInLocation("2006-01-02 15:04:05", q.EndTime, time.UTC); err != nil {
+			return nil, fmt.Errorf("结束时间格式错误: %v", err)
+		}
+	}
+	for _, d := range q.Databases {
+		f.databases[strings.ToLower(d)] = true
+	}
+	for _, t := range q.Tables {
+		f.tables[strings.ToLower(t)] = true
+	}
+	for _, t := range q.EventTypes {
+		switch t {
+		case "insert", "update", "delete":
+			f.types[t] = true
+		default:
+			return nil, fmt.Errorf("不支持的事件类型: %s", t)
+		}
+	}
+	return f, nil
+}
+
+// inLocation 把起止时间解释为指定时区的时间（解析时按 UTC 暂存）
+func (f *binlogFilter) inLocation(loc *time.Location) {
+	rebase := func(t time.Time) time.Time {
+		if t.IsZero() {
+			return t
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+	}
+	f.start, f.end = rebase(f.start), rebase(f.end)
+}
+
+func (f *binlogFilter) match(database, table, kind string) bool {
+	if len(f.databases) > 0 && !f.databases[strings.ToLower(database)] {
+		return false
+	}
+	if len(f.tables) > 0 && !f.tables[strings.ToLower(table)] && !f.tables[strings.ToLower(database+"."+table)] {
+		return false
+	}
+	return len(f.types) == 0 || f.types[kind]
+}
+
+// binlogTableMeta 表的列名与主键
+type binlogTableMeta struct {
+	columns []string
+	pk      []int
+}
+
+// binlogReader 逐个事件处理并汇总结果
+type binlogReader struct {
+	filter   *binlogFilter
+	db       *sql.DB        // 补全列名用，可为空
+	loc      *time.Location // 事件时间与起止时间
+	tsLoc    *time.Location // TIMESTAMP 列取值，固定偏移
+	meta     map[string]*binlogTableMeta
+	warned   map[string]bool
+	file     string
+	gtid     string
+	endFile  string
+	endPos   uint32
+	result   BinlogBrowseResult
+	forward  []string
+	backward []string
+}
+
+// handle 处理一个事件；返回 errBinlogStop 表示已满足结束条件
+func (br *binlogReader) handle(e *replication.BinlogEvent) error {
+	br.result.ScannedEvents++
+	if rot, ok := e.Event.(*replication.RotateEvent); ok {
+		br.file = string(rot.NextLogName)
+		return nil
+	}
+	if e.Header.Timestamp == 0 {
+		return nil
+	}
+	if br.endFile != "" && br.file != "" {
+		cur := gomysql.Position{Name: br.file, Pos: e.Header.LogPos}
+		if cur.Compare(gomysql.Position{Name: br.endFile, Pos: br.endPos}) > 0 {
+			return errBinlogStop
+		}
+	}
+	br.result.EndFile, br.result.EndPos = br.file, e.Header.LogPos
+	at := time.Unix(int64(e.Header.Timestamp), 0).In(br.loc)
+	if !br.filter.end.IsZero() && at.After(br.filter.end) {
+		return errBinlogStop
+	}
+	switch ev := e.Event.(type) {
+	case *replication.GTIDEvent:
+		br.gtid = formatBinlogGTID(ev.SID, ev.GNO)
+		return nil
+	case *replication.RowsEvent:
+		if !br.filter.start.IsZero() && at.Before(br.filter.start) {
+			return nil
+		}
+		return br.rows(e.Header, ev, at)
+	}
+	return nil
+}
+
+func (br *binlogReader) rows(h *replication.EventHeader, ev *replication.RowsEvent, at time.Time) error {
+	var kind string
+	switch h.EventType {
+	case replication.WRITE_ROWS_EVENTv0, replication.WRITE_ROWS_EVENTv1, replication.WRITE_ROWS_EVENTv2, replication.MARIADB_WRITE_ROWS_COMPRESSED_EVENT_V1:
+		kind = "insert"
+	case replication.UPDATE_ROWS_EVENTv0, replication.UPDATE_ROWS_EVENTv1, replication.UPDATE_ROWS_EVENTv2, replication.MARIADB_UPDATE_ROWS_COMPRESSED_EVENT_V1:
+		kind = "update"
+	case replication.DELETE_ROWS_EVENTv0, replication.DELETE_ROWS_EVENTv1, replication.DELETE_ROWS_EVENTv2, replication.MARIADB_DELETE_ROWS_COMPRESSED_EVENT_V1:
+		kind = "delete"
+	default:
+		// PARTIAL_UPDATE_ROWS_EVENT 只含 JSON 差量，无法生成回滚语句
+		br.warn("partial", "存在 JSON 部分更新事件（binlog_row_value_options=PARTIAL_JSON），已跳过")
+		return nil
+	}
+	database, table := string(ev.Table.Schema), string(ev.Table.Table)
+	if !br.filter.match(database, table, kind) {
+		return nil
+	}
+	meta := br.tableMeta(ev.Table)
+	unsigned := ev.Table.UnsignedMap()
+	step := 1
+	if kind == "update" {
+		step = 2
+	}
+	for i := 0; i+step-1 < len(ev.Rows); i += step {
+		if len(br.result.Changes) >= br.filter.maxEvents {
+			br.result.Truncated = true
+			return errBinlogStop
+		}
+		var skipped []int
+		if i < len(ev.SkippedColumns) {
+			skipped = ev.SkippedColumns[i]
+		}
+		if len(skipped) > 0 {
+			br.warn("minimal", "存在不完整的行镜像（binlog_row_image 非 FULL），相关回滚语句可能无法准确定位行")
+		}
+		c := BinlogRowChange{
+			File:     br.file,
+			Pos:      h.LogPos,
+			Time:     at.Format("2006-01-02 15:04:05"),
+			GTID:     br.gtid,
+			Database: database,
+			Table:    table,
+			Type:     kind,
+			Columns:  meta.columns,
+		}
+		var before, after []interface{}
+		switch kind {
+		case "insert":
+			after = binlogRowValues(ev.Rows[i], unsigned)
+		case "delete":
+			before = binlogRowValues(ev.Rows[i], unsigned)
+		case "update":
+			before = binlogRowValues(ev.Rows[i], unsigned)
+			after = binlogRowValues(ev.Rows[i+1], unsigned)
+			if i+1 < len(ev.SkippedColumns) {
+				skipped = append(skipped, ev.SkippedColumns[i+1]...)
+			}
+			for j := range after {
+				if j < len(before) && j < len(meta.columns) && fmt.Sprint(before[j]) != fmt.Sprint(after[j]) {
+					c.Changed = append(c.Changed, meta.columns[j])
+				}
+			}
+		}
+		skip := map[int]bool{}
+		for _, j := range skipped {
+			skip[j] = true
+		}
+		c.ForwardSQL, c.FlashbackSQL = binlogRowSQL(database, table, meta, kind, before, after, skip)
+		c.Before, c.After = binlogDisplayValues(before), binlogDisplayValues(after)
+		br.result.Changes = append(br.result.Changes, c)
+		br.forward = append(br.forward, c.ForwardSQL)
+		br.backward = append(br.backward, c.FlashbackSQL)
+	}
+	return nil
+}
+
+func (br *binlogReader) warn(key, msg string) {
+	if br.warned[key] {
+		return
+	}
+	br.warned[key] = true
+	br.result.Warnings = append(br.result.Warnings, msg)
+}
+
+// tableMeta 列名与主键优先取 binlog 中的元数据（binlog_row_metadata=FULL），否则查询当前表结构
+func (br *binlogReader) tableMeta(t *replication.TableMapEvent) *binlogTableMeta {
+	key := string(t.Schema) + "." + string(t.Table)
+	if m, ok := br.meta[key]; ok {
+		return m
+	}
+	m := &binlogTableMeta{}
+	if names := t.ColumnNameString(); len(names) == int(t.ColumnCount) {
+		m.columns = names
+		for _, idx := range t.PrimaryKey {
+			m.pk = append(m.pk, int(idx))
+		}
+	} else if br.db != nil {
+		if cols, pk, err := binlogTableColumns(br.db, string(t.Schema), string(t.Table)); err == nil && len(cols) == int(t.ColumnCount) {
+			m.columns, m.pk = cols, pk
+		} else {
+			br.warn("columns:"+key, fmt.Sprintf("表 %s 的当前结构与 binlog 不一致，列名以序号表示", key))
+		}
+	}
+	if m.columns == nil {
+		m.columns = make([]string, t.ColumnCount)
+		for i := range m.columns {
+			m.columns[i] = fmt.Sprintf("@%d", i+1)
+		}
+		br.warn("unnamed", "缺少列名信息，生成的语句需人工确认列名后执行")
+	}
+	br.meta[key] = m
+	return m
+}
+
+// binlogTableColumns 查询表的列（按定义顺序）与主键列序号
+func binlogTableColumns(db *sql.DB, database, table string) ([]string, []int, error) {
+	rows, err := db.Query("SELECT COLUMN_NAME, COLUMN_KEY FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION", database, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var cols []string
+	var pk []int
+	for rows.Next() {
+		var name, key string
+		if err := rows.Scan(&name, &key); err != nil {
+			return nil, nil, err
+		}
+		if key == "PRI" {
+			pk = append(pk, len(cols))
+		}
+		cols = append(cols, name)
+	}
+	return cols, pk, rows.Err()
+}
+
+// binlogRowValues 还原无符号整数（binlog 中按有符号解码）
+func binlogRowValues(row []interface{}, unsigned map[int]bool) []interface{} {
+	out := make([]interface{}, len(row))
+	for i, v := range row {
+		if !unsigned[i] {
+			out[i] = v
+			continue
+		}
+		switch t := v.(type) {
+		case int8:
+			out[i] = uint8(t)
+		case int16:
+			out[i] = uint16(t)
+		case int32:
+			if t < 0 {
+				// MEDIUMINT 以 int32 解码
+				out[i] = uint32(t) & 0xFFFFFF
+			} else {
+				out[i] = uint32(t)
+			}
+		case int64:
+			out[i] = uint64(t)
+		default:
+			out[i] = v
+		}
+	}
+	return out
+}
+
+// binlogDisplayValues 转成便于前端展示的值：文本转字符串，二进制转十六进制
+func binlogDisplayValues(row []interface{}) []interface{} {
+	if row == nil {
+		return nil
+	}
+	out := make([]interface{}, len(row))
+	for i, v := range row {
+		switch t := v.(type) {
+		case []byte:
+			if utf8.Valid(t) {
+				out[i] = string(t)
+			} else {
+				out[i] = "0x" + hex.EncodeToString(t)
+			}
+		case nil, string, bool, float32, float64, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+			out[i] = t
+		default:
+			out[i] = fmt.Sprint(t)
+		}
+	}
+	return out
+}
+
+// binlogRowSQL 生成正向与回滚语句：insert ↔ delete，update 交换前后镜像；
+// 定位行时有主键用主键，否则用全部列并限制一行
+func binlogRowSQL(database, table string, meta *binlogTableMeta, kind string, before, after []interface{}, skip map[int]bool) (string, string) {
+	name := fmt.Sprintf("`%s`.`%s`", database, table)
+	insert := func(row []interface{}) string {
+		var cols, vals []string
+		for i, v := range row {
+			if skip[i] || i >= len(meta.columns) {
+				continue
+			}
+			cols = append(cols, fmt.Sprintf("`%s`", meta.columns[i]))
+			vals = append(vals, valueToSQL(v))
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", name, strings.Join(cols, ", "), strings.Join(vals, ", "))
+	}
+	where := func(row []interface{}) (string, bool) {
+		idxs := meta.pk
+		keyed := len(idxs) > 0
+		for _, i := range idxs {
+			if skip[i] {
+				keyed = false
+			}
+		}
+		if !keyed {
+			idxs = nil
+			for i := range row {
+				idxs = append(idxs, i)
+			}
+		}
+		var conds []string
+		for _, i := range idxs {
+			if skip[i] || i >= len(row) || i >= len(meta.columns) {
+				continue
+			}
+			if row[i] == nil {
+				conds = append(conds, fmt.Sprintf("`%s` IS NULL", meta.columns[i]))
+			} else {
+				conds = append(conds, fmt.Sprintf("`%s` = %s", meta.columns[i], valueToSQL(row[i])))
+			}
+		}
+		return strings.Join(conds, " AND "), keyed
+	}
+	del := func(row []interface{}) string {
+		w, keyed := where(row)
+		stmt := fmt.Sprintf("DELETE FROM %s WHERE %s", name, w)
+		if !keyed {
+			stmt += " LIMIT 1"
+		}
+		return stmt
+	}
+	update := func(from, to []interface{}) string {
+		var sets []string
+		for i, v := range to {
+			if skip[i] || i >= len(meta.columns) {
+				continue
+			}
+			sets = append(sets, fmt.Sprintf("`%s` = %s", meta.columns[i], valueToSQL(v)))
+		}
+		w, keyed := where(from)
+		stmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s", name, strings.Join(sets, ", "), w)
+		if !keyed {
+			stmt += " LIMIT 1"
+		}
+		return stmt
+	}
+	switch kind {
+	case "insert":
+		return insert(after), del(after)
+	case "delete":
+		return del(before), insert(before)
+	default:
+		return update(before, after), update(after, before)
+	}
+}
+
+func formatBinlogGTID(sid []byte, gno int64) string {
+	if len(sid) != 16 {
+		return ""
+	}
+	h := hex.EncodeToString(sid)
+	return fmt.Sprintf("%s-%s-%s-%s-%s:%d", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32], gno)
+}
+
+// runBrowseBinlog 从实例或本地文件读取 binlog
+func runBrowseBinlog(ctx context.Context, r *jobReporter, cfg DBConfig, q BinlogQuery, f *binlogFilter) (BinlogBrowseResult, error) {
+	started := time.Now()
+	br := &binlogReader{filter: f, meta: map[string]*binlogTableMeta{}, warned: map[string]bool{}}
+	if normalizeDBType(cfg.Type) == "mysql" && cfg.Host != "" {
+		cfg.Database = ""
+		if dsn, err := buildDSN(cfg); err == nil {
+			if db, err := sql.Open("mysql", dsn); err == nil {
+				defer db.Close()
+				if q.Source == "server" || db.PingContext(ctx) == nil {
+					br.db = db
+				}
+			}
+		}
+	}
+
+	loc, tsLoc := binlogTimeZone(ctx, br.db)
+	br.loc, br.tsLoc = loc, tsLoc
+	br.result.TimeZone = loc.String()
+	f.inLocation(loc)
+
+	var err error
+	if q.Source == "file" {
+		err = browseBinlogFiles(ctx, r, br, q.Files)
+	} else {
+		err = browseBinlogServer(ctx, r, br, cfg, q)
+	}
+	if err != nil && !errors.Is(err, errBinlogStop) {
+		return BinlogBrowseResult{}, err
+	}
+
+	result := br.result
+	if result.Changes == nil {
+		result.Changes = []BinlogRowChange{}
+	}
+	// TIMESTAMP 取值按固定偏移生成，脚本中把会话时区设为同一偏移；数字偏移不依赖实例加载时区表
+	header := fmt.Sprintf("-- binlog %s:%d 至 %s:%d，共 %d 行变更\nSET time_zone = '%s';\n", firstChangeFile(result.Changes), firstChangePos(result.Changes), result.EndFile, result.EndPos, len(result.Changes), tsLoc)
+	var fw, bw strings.Builder
+	fw.WriteString(header)
+	for _, s := range br.forward {
+		fw.WriteString(s + ";\n")
+	}
+	bw.WriteString(header)
+	bw.WriteString("-- 回滚语句按变更逆序排列，请在事务中执行并核对结果\n")
+	for i := len(br.backward) - 1; i >= 0; i-- {
+		bw.WriteString(br.backward[i] + ";\n")
+	}
+	result.ForwardScript, result.FlashbackScript = fw.String(), bw.String()
+	result.ElapsedSec = time.Since(started).Seconds()
+	r.Progress(100, fmt.Sprintf("完成，共 %d 行变更", len(result.Changes)))
+	return result, nil
+}
+
+// binlogTimeZone 事件时间按实例默认时区显示，TIMESTAMP 列按该时区的当前偏移换算，与在实例上查询到的值一致；
+// 无法连接实例（仅解析本地文件）时使用 UTC
+func binlogTimeZone(ctx context.Context, db *sql.DB) (loc, tsLoc *time.Location) {
+	if db == nil {
+		return binlogZones("+00:00", 0)
+	}
+	var tz string
+	var offset int
+	if err := db.QueryRowContext(ctx, "SELECT @@global.time_zone, TIMESTAMPDIFF(SECOND, UTC_TIMESTAMP(), NOW())").Scan(&tz, &offset); err != nil {
+		return binlogZones("+00:00", 0)
+	}
+	return binlogZones(tz, offset)
+}
+
+// binlogZones 由 @@time_zone 与当前偏移（秒）得到事件时间所用的时区和 TIMESTAMP 列所用的固定偏移；
+// 固定偏移的名称形如 +08:00，可直接用于 SET time_zone，不要求实例加载了时区表
+func binlogZones(tz string, offset int) (loc, tsLoc *time.Location) {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	// NOW() 与 UTC_TIMESTAMP() 可能跨秒，偏移取整到分钟
+	offset = (offset + 30) / 60 * 60
+	name := fmt.Sprintf("%s%02d:%02d", sign, offset/3600, offset%3600/60)
+	secs := offset
+	if sign == "-" {
+		secs = -offset
+	}
+	tsLoc = time.FixedZone(name, secs)
+	// 命名时区（如 Asia/Shanghai）能加载时事件时间按其规则处理夏令时，否则按当前偏移
+	if tz != "SYSTEM" && !strings.HasPrefix(tz, "+") && !strings.HasPrefix(tz, "-") {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc, tsLoc
+		}
+	}
+	return tsLoc, tsLoc
+}
+
+func firstChangeFile(changes []BinlogRowChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	return changes[0].File
+}
+
+func firstChangePos(changes []BinlogRowChange) uint32 {
+	if len(changes) == 0 {
+		return 0
+	}
+	return changes[0].Pos
+}
+
+// browseBinlogFiles 依次解析本地 binlog 文件
+func browseBinlogFiles(ctx context.Context, r *jobReporter, br *binlogReader, files []string) error {
+	p := replication.NewBinlogParser()
+	p.SetTimestampStringLocation(br.tsLoc)
+	for i, path := range files {
+		br.file = filepath.Base(path)
+		r.Progress(float64(i)*100/float64(len(files)), "解析 "+br.file)
+		err := p.ParseFile(path, 0, func(e *replication.BinlogEvent) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if _, ok := e.Event.(*replication.RotateEvent); ok {
+				// 文件末尾的 rotate 指向下一个文件，本地解析时以实际文件为准
+				br.result.ScannedEvents++
+				return nil
+			}
+			return br.handle(e)
+		})
+		if err != nil {
+			if errors.Is(err, errBinlogStop) {
+				return err
+			}
+			return fmt.Errorf("解析 %s 失败: %v", br.file, err)
+		}
+	}
+	return nil
+}
+
+// browseBinlogServer 以从库身份从实例拉取 binlog，读到结束文件末尾或当前位点为止
+func browseBinlogServer(ctx context.Context, r *jobReporter, br *binlogReader, cfg DBConfig, q BinlogQuery) error {
+	if br.db == nil {
+		return fmt.Errorf("连接实例失败")
+	}
+	curFile, curPos, _, err := captureBinlogPosition(cfg)
+	if err != nil {
+		return err
+	}
+	start := gomysql.Position{Name: q.StartFile, Pos: q.StartPos}
+	if start.Name == "" {
+		start.Name = curFile
+	}
+	if start.Pos < 4 {
+		start.Pos = 4
+	}
+	br.file = start.Name
+	br.endFile, br.endPos = curFile, curPos
+	if q.EndFile != "" && q.EndFile < curFile {
+		br.endFile, br.endPos = q.EndFile, ^uint32(0)
+	}
+
+	syncer := replication.NewBinlogSyncer(replication.BinlogSyncerConfig{
+		ServerID: uint32(time.Now().UnixNano()%100000) + 200000,
+		Flavor:   gomysql.MySQLFlavor,
+		Host:     cfg.Host,
+		Port:     uint16(cfg.Port),
+		User:     cfg.User,
+		Password: cfg.Password,
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+
+		TimestampStringLocation: br.tsLoc,
+	})
+	defer syncer.Close()
+	streamer, err := syncer.StartSync(start)
+	if err != nil {
+		return fmt.Errorf("读取 binlog 失败: %v", err)
+	}
+	lastReport := time.Now()
+	for {
+		if (gomysql.Position{Name: br.file, Pos: br.result.EndPos}).Compare(gomysql.Position{Name: br.endFile, Pos: br.endPos}) >= 0 && br.result.EndPos > 0 {
+			return nil
+		}
+		// 读到末尾后服务端不再发送事件，空闲超时视为结束
+		evCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		e, err := streamer.GetEvent(evCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return fmt.Errorf("读取 binlog 失败: %v", err)
+		}
+		if err := br.handle(e); err != nil {
+			return err
+		}
+		if time.Since(lastReport) > time.Second {
+			lastReport = time.Now()
+			r.Progress(-1, fmt.Sprintf("%s:%d，已找到 %d 行变更", br.file, br.result.EndPos, len(br.result.Changes)))
+		}
+	}
+}
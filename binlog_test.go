@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestBinlogZones(t *testing.T) {
+	tests := []struct {
+		tz      string
+		offset  int
+		wantLoc string
+		wantTS  string
+	}{
+		{"Asia/Shanghai", 8 * 3600, "Asia/Shanghai", "+08:00"},
+		{"America/New_York", -4*3600 + 1, "America/New_York", "-04:00"},
+		{"SYSTEM", 19800 - 29, "+05:30", "+05:30"},
+		{"+02:00", 7200, "+02:00", "+02:00"},
+		{"No/Such_Zone", 0, "+00:00", "+00:00"},
+	}
+	for _, tt := range tests {
+		loc, tsLoc := binlogZones(tt.tz, tt.offset)
+		if loc.String() != tt.wantLoc || tsLoc.String() != tt.wantTS {
+			t.Errorf("binlogZones(%q, %d) = %s, %s; want %s, %s", tt.tz, tt.offset, loc, tsLoc, tt.wantLoc, tt.wantTS)
+		}
+	}
+}
@@ -0,0 +1,935 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/canal"
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// CDCOptions 增量同步选项
+type CDCOptions struct {
+	Sync            SyncOptions `json:"sync"`            // 全量复制选项（映射同样作用于增量变更）
+	SkipInitialCopy bool        `json:"skipInitialCopy"` // 跳过全量复制，从指定位点开始
+	StartFile       string      `json:"startFile"`       // 起始 binlog 文件，为空时取源库当前位点
+	StartPos        uint32      `json:"startPos"`
+	StartGTID       string      `json:"startGtid"` // 起始 GTID 集合，优先于文件位点
+	ServerID        uint32      `json:"serverId"`  // 作为从库连接时使用的 server_id，为 0 时随机
+}
+
+// CDCStatus 增量同步任务状态
+type CDCStatus struct {
+	ID          string              `json:"id"`
+	TargetID    string              `json:"targetId"` // 目标连接ID，续传全量复制前用于确认生产环境
+	SourceDB    string              `json:"sourceDb"`
+	TargetDB    string              `json:"targetDb"`
+	State       string              `json:"state"` // copying / running / paused / stopping / stopped / failed
+	CopyJobID   string              `json:"copyJobId,omitempty"`
+	CopyDone    bool                `json:"copyDone"`
+	InitialCopy []MigrationCheckRow `json:"initialCopy,omitempty"`
+	File        string              `json:"file"` // 已应用到目标库的位点
+	Pos         uint32              `json:"pos"`
+	GTID        string              `json:"gtid,omitempty"`
+	LagSeconds  uint32              `json:"lagSeconds"`
+	Inserted    int64               `json:"inserted"`
+	Updated     int64               `json:"updated"`
+	Deleted     int64               `json:"deleted"`
+	SkippedDDL  int64               `json:"skippedDdl"`
+	LastDDL     string              `json:"lastDdl,omitempty"`
+	LastEventAt string              `json:"lastEventAt,omitempty"`
+	Error       string              `json:"error,omitempty"`
+	StartedAt   string              `json:"startedAt"`
+	UpdatedAt   string              `json:"updatedAt"`
+}
+
+// cdcState 持久化到 dms-new/cdc/<任务ID>.json 的任务信息
+type cdcState struct {
+	Status   CDCStatus  `json:"status"`
+	Source   DBConfig   `json:"source"`
+	Target   DBConfig   `json:"target"`
+	Tables   []string   `json:"tables"`
+	Options  CDCOptions `json:"options"`
+	Mode     string     `json:"mode"`
+	ServerID uint32     `json:"serverId"`
+}
+
+// cdcTask 运行中的增量同步任务
+type cdcTask struct {
+	mu       sync.Mutex
+	state    cdcState
+	canal    *canal.Canal
+	done     chan struct{} // 本轮复制结束（暂停、停止或出错）时关闭
+	request  string        // pause / stop：由用户发起的结束
+	lastSave time.Time
+}
+
+// cdcManager 管理增量同步任务：状态推送、位点持久化与暂停/继续/停止
+type cdcManager struct {
+	app   *App
+	mu    sync.Mutex
+	tasks map[string]*cdcTask
+}
+
+func newCDCManager(app *App) *cdcManager {
+	return &cdcManager{app: app, tasks: map[string]*cdcTask{}}
+}
+
+func cdcDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dms-new", "cdc"), nil
+}
+
+func cdcStatePath(id string) (string, error) {
+	dir, err := cdcDir()
+	if err != nil {
+		return "", err
+	}
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", fmt.Errorf("无效的任务ID: %s", id)
+	}
+	return filepath.Join(dir, id+".json"), nil
+}
+
+func loadCDCState(id string) (*cdcState, error) {
+	path, err := cdcStatePath(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("未找到增量同步任务: %s", id)
+		}
+		return nil, err
+	}
+	var st cdcState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("任务文件损坏: %v", err)
+	}
+	return &st, nil
+}
+
+// saveLocked 写入任务文件并推送状态；force 为 false 时按间隔节流
+func (t *cdcTask) saveLocked(m *cdcManager, force bool) {
+	if !force && time.Since(t.lastSave) < checkpointSaveInterval {
+		return
+	}
+	t.lastSave = time.Now()
+	t.state.Status.UpdatedAt = time.Now().Format(time.RFC3339)
+	if path, err := cdcStatePath(t.state.Status.ID); err == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			if data, err := json.MarshalIndent(t.state, "", "  "); err == nil {
+				tmp := path + ".tmp"
+				if os.WriteFile(tmp, data, 0o600) == nil {
+					_ = os.Rename(tmp, path)
+				}
+			}
+		}
+	}
+	if m.app.ctx != nil {
+		runtime.EventsEmit(m.app.ctx, "cdc-status", t.state.Status)
+	}
+}
+
+func (t *cdcTask) update(m *cdcManager, force bool, fn func(s *CDCStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn(&t.state.Status)
+	t.saveLocked(m, force)
+}
+
+func (t *cdcTask) status() CDCStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state.Status
+}
+
+// StartCDC 启动增量同步：全量复制在源库的一致性快照中读取，并记录该快照对应的 binlog 位点（或 GTID），
+// 复制完成后从该位点持续应用行变更（按主键覆盖写入与删除）。无主键的表无法按行定位，拒绝同步
+func (a *App) StartCDC(source DBConfig, sourceDB string, target DBConfig, targetDB string, tables []string, opts CDCOptions) (CDCStatus, error) {
+	if normalizeDBType(source.Type) != "mysql" || normalizeDBType(target.Type) != "mysql" {
+		return CDCStatus{}, fmt.Errorf("增量同步仅支持MySQL之间")
+	}
+	if sourceDB == "" || targetDB == "" {
+		return CDCStatus{}, fmt.Errorf("源库和目标库不能为空")
+	}
+	if err := checkWritable(target); err != nil {
+		return CDCStatus{}, err
+	}
+	if err := validateSyncPolicies(opts.Sync); err != nil {
+		return CDCStatus{}, err
+	}
+	if err := validateSyncMapping(opts.Sync.Mapping); err != nil {
+		return CDCStatus{}, err
+	}
+	if !opts.SkipInitialCopy {
+		if err := a.checkDestructiveTask(target, "cdc", targetDB, opts.Sync.destructiveReason(), opts.Sync.ConfirmToken); err != nil {
+			return CDCStatus{}, err
+		}
+	}
+	opts.Sync.ConfirmToken = ""
+	keyless, err := cdcKeylessTables(source, sourceDB, tables)
+	if err != nil {
+		return CDCStatus{}, err
+	}
+	if len(keyless) > 0 {
+		return CDCStatus{}, fmt.Errorf("以下表没有主键，增量变更无法按行定位: %s", strings.Join(keyless, ", "))
+	}
+	serverID := opts.ServerID
+	if serverID == 0 {
+		serverID = uint32(time.Now().UnixNano()%100000) + 100000
+	}
+	t := &cdcTask{state: cdcState{
+		Status: CDCStatus{
+			ID:        newJobID(),
+			TargetID:  target.ID,
+			SourceDB:  sourceDB,
+			TargetDB:  targetDB,
+			State:     "copying",
+			CopyDone:  opts.SkipInitialCopy,
+			StartedAt: time.Now().Format(time.RFC3339),
+		},
+		Source:   source,
+		Target:   target,
+		Tables:   tables,
+		Options:  opts,
+		Mode:     "both",
+		ServerID: serverID,
+	}}
+
+	// 未指定起始位点时：跳过全量复制则取当前位点，否则由全量复制的快照给出位点（此处只确认源库已开启 binlog）
+	switch {
+	case opts.StartGTID != "":
+		t.state.Status.GTID = opts.StartGTID
+	case opts.StartFile != "":
+		t.state.Status.File, t.state.Status.Pos = opts.StartFile, opts.StartPos
+	default:
+		file, pos, gtid, err := captureBinlogPosition(source)
+		if err != nil {
+			return CDCStatus{}, err
+		}
+		if opts.SkipInitialCopy {
+			t.state.Status.File, t.state.Status.Pos, t.state.Status.GTID = file, pos, gtid
+		}
+	}
+	if opts.SkipInitialCopy {
+		t.state.Status.State = "running"
+	}
+
+	a.cdc.mu.Lock()
+	a.cdc.tasks[t.state.Status.ID] = t
+	a.cdc.mu.Unlock()
+	t.update(a.cdc, true, func(s *CDCStatus) {})
+	go a.cdc.run(t)
+	return t.status(), nil
+}
+
+// PauseCDC 暂停增量同步：断开 binlog 连接并保存已应用的位点
+func (a *App) PauseCDC(id string) error {
+	t, err := a.cdc.running(id)
+	if err != nil {
+		return err
+	}
+	return a.cdc.halt(t, "pause", false)
+}
+
+// StopCDC 停止增量同步（切换时使用）；waitCatchUp 为 true 时先等待应用到源库当前位点再停止
+func (a *App) StopCDC(id string, waitCatchUp bool) error {
+	t, err := a.cdc.running(id)
+	if err != nil {
+		// 已暂停或出错的任务直接标记为停止
+		st, loadErr := loadCDCState(id)
+		if loadErr != nil {
+			return err
+		}
+		t = &cdcTask{state: *st}
+		t.update(a.cdc, true, func(s *CDCStatus) { s.State = "stopped" })
+		return nil
+	}
+	return a.cdc.halt(t, "stop", waitCatchUp)
+}
+
+// ResumeCDC 从保存的位点继续已暂停或出错的增量同步；全量复制未完成时先续传全量复制，
+// 此时目标为生产环境且策略会删表或清空数据须带确认令牌
+func (a *App) ResumeCDC(id string, confirmToken string) (CDCStatus, error) {
+	if t, err := a.cdc.running(id); err == nil {
+		return t.status(), fmt.Errorf("任务仍在执行中")
+	}
+	st, err := loadCDCState(id)
+	if err != nil {
+		return CDCStatus{}, err
+	}
+	if st.Status.State == "stopped" {
+		return CDCStatus{}, fmt.Errorf("任务已停止，无法继续")
+	}
+	if !st.Status.CopyDone {
+		if err := a.checkDestructiveTask(st.Target, "cdc", st.Status.TargetDB, st.Options.Sync.destructiveReason(), confirmToken); err != nil {
+			return CDCStatus{}, err
+		}
+	}
+	t := &cdcTask{state: *st}
+	t.state.Status.Error = ""
+	t.state.Status.State = "running"
+	if !t.state.Status.CopyDone {
+		t.state.Status.State = "copying"
+	}
+	a.cdc.mu.Lock()
+	a.cdc.tasks[id] = t
+	a.cdc.mu.Unlock()
+	t.update(a.cdc, true, func(s *CDCStatus) {})
+	go a.cdc.run(t)
+	return t.status(), nil
+}
+
+// ListCDC 列出增量同步任务（最近更新在前）；程序退出时仍在运行的任务显示为已暂停
+func (a *App) ListCDC() ([]CDCStatus, error) {
+	dir, err := cdcDir()
+	if err != nil {
+		return nil, err
+	}
+	list := []CDCStatus{}
+	seen := map[string]bool{}
+	a.cdc.mu.Lock()
+	for id, t := range a.cdc.tasks {
+		list = append(list, t.status())
+		seen[id] = true
+	}
+	a.cdc.mu.Unlock()
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		id := strings.TrimSuffix(e.Name(), ".json")
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") || seen[id] {
+			continue
+		}
+		st, err := loadCDCState(id)
+		if err != nil {
+			continue
+		}
+		if st.Status.State == "running" || st.Status.State == "copying" || st.Status.State == "stopping" {
+			st.Status.State = "paused"
+		}
+		list = append(list, st.Status)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].UpdatedAt > list[j].UpdatedAt })
+	return list, nil
+}
+
+// DeleteCDC 删除已停止或暂停的增量同步任务记录
+func (a *App) DeleteCDC(id string) error {
+	if _, err := a.cdc.running(id); err == nil {
+		return fmt.Errorf("任务仍在执行中")
+	}
+	path, err := cdcStatePath(id)
+	if err != nil {
+		return err
+	}
+	a.cdc.mu.Lock()
+	delete(a.cdc.tasks, id)
+	a.cdc.mu.Unlock()
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// running 返回正在执行的任务
+func (m *cdcManager) running(id string) (*cdcTask, error) {
+	m.mu.Lock()
+	t, ok := m.tasks[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("任务未在执行: %s", id)
+	}
+	s := t.status()
+	if s.State != "running" && s.State != "copying" && s.State != "stopping" {
+		return nil, fmt.Errorf("任务未在执行: %s", id)
+	}
+	return t, nil
+}
+
+// halt 结束本轮复制；全量复制阶段只能停止（取消复制任务），不能暂停
+func (m *cdcManager) halt(t *cdcTask, request string, waitCatchUp bool) error {
+	t.mu.Lock()
+	s := t.state.Status
+	c, done := t.canal, t.done
+	t.mu.Unlock()
+	if s.State == "copying" {
+		if request == "pause" {
+			return fmt.Errorf("全量复制阶段无法暂停，可停止后通过续传继续")
+		}
+		t.mu.Lock()
+		t.request = request
+		t.mu.Unlock()
+		if s.CopyJobID != "" {
+			return m.app.jobs.cancel(s.CopyJobID)
+		}
+		return nil
+	}
+	if c == nil {
+		return fmt.Errorf("任务尚未开始复制")
+	}
+	if waitCatchUp {
+		t.update(m, true, func(s *CDCStatus) { s.State = "stopping" })
+		if err := waitCDCCatchUp(t, c, 5*time.Minute); err != nil {
+			t.update(m, true, func(s *CDCStatus) { s.State = "running" })
+			return err
+		}
+	}
+	t.mu.Lock()
+	t.request = request
+	t.mu.Unlock()
+	c.Close()
+	<-done
+	return nil
+}
+
+// waitCDCCatchUp 等待已应用的位点追上源库当前位点
+func waitCDCCatchUp(t *cdcTask, c *canal.Canal, timeout time.Duration) error {
+	s := t.status()
+	deadline := time.Now().Add(timeout)
+	if s.GTID != "" {
+		target, err := c.GetMasterGTIDSet()
+		if err != nil {
+			return fmt.Errorf("读取源库 GTID 失败: %v", err)
+		}
+		for time.Now().Before(deadline) {
+			if synced := c.SyncedGTIDSet(); synced != nil && synced.Contain(target) {
+				return nil
+			}
+			time.Sleep(200 * time.Millisecond)
+		}
+		return fmt.Errorf("等待追平源库超时")
+	}
+	target, err := c.GetMasterPos()
+	if err != nil {
+		return fmt.Errorf("读取源库位点失败: %v", err)
+	}
+	for time.Now().Before(deadline) {
+		if c.SyncedPosition().Compare(target) >= 0 {
+			return nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return fmt.Errorf("等待追平源库超时")
+}
+
+// run 任务主流程：全量复制（如需要）后持续应用 binlog 行变更
+func (m *cdcManager) run(t *cdcTask) {
+	if !t.status().CopyDone {
+		if err := m.initialCopy(t); err != nil {
+			t.mu.Lock()
+			stopped := t.request == "stop"
+			t.mu.Unlock()
+			t.update(m, true, func(s *CDCStatus) {
+				if stopped {
+					s.State = "stopped"
+					return
+				}
+				s.State = "failed"
+				s.Error = err.Error()
+			})
+			return
+		}
+		t.update(m, true, func(s *CDCStatus) {
+			s.CopyDone = true
+			s.State = "running"
+		})
+	}
+	err := m.stream(t)
+	t.mu.Lock()
+	request := t.request
+	t.request = ""
+	t.mu.Unlock()
+	t.update(m, true, func(s *CDCStatus) {
+		s.LagSeconds = 0
+		switch {
+		case request == "stop":
+			s.State = "stopped"
+		case request == "pause":
+			s.State = "paused"
+		case err != nil:
+			s.State = "failed"
+			s.Error = err.Error()
+		default:
+			s.State = "paused"
+		}
+	})
+}
+
+// initialCopy 作为普通同步任务在源库一致性快照中执行全量复制，中断后通过断点续传继续。
+// 增量起点取首次快照的位点：续传时新快照晚于该位点，其间的变更会被重放，按主键覆盖写入不会产生重复数据
+func (m *cdcManager) initialCopy(t *cdcTask) error {
+	st := t.state
+	onSnapshot := func(pos binlogPosition) {
+		t.update(m, true, func(s *CDCStatus) {
+			if s.File == "" && s.GTID == "" {
+				s.File, s.Pos, s.GTID = pos.File, pos.Pos, pos.GTID
+			}
+		})
+	}
+	var job Job
+	var err error
+	if st.Status.CopyJobID != "" {
+		if cp, loadErr := loadSyncCheckpoint(st.Status.CopyJobID); loadErr == nil {
+			cp.Options.onSnapshot = onSnapshot
+			job, err = m.app.submitResumeSyncJob(cp)
+		}
+	}
+	if job.ID == "" && err == nil {
+		opts := st.Options.Sync
+		opts.onSnapshot = onSnapshot
+		job = m.app.submitSyncJob(st.Source, st.Status.SourceDB, st.Target, st.Status.TargetDB, st.Mode, st.Tables, opts)
+	}
+	if err != nil {
+		return err
+	}
+	t.update(m, true, func(s *CDCStatus) { s.CopyJobID = job.ID })
+	res, err := m.app.jobs.wait(job.ID)
+	rows, _ := res.([]MigrationCheckRow)
+	t.update(m, true, func(s *CDCStatus) { s.InitialCopy = rows })
+	if err != nil {
+		return fmt.Errorf("全量复制失败: %v", err)
+	}
+	var failed []string
+	for _, row := range rows {
+		if strings.HasPrefix(row.Status, "failed") {
+			failed = append(failed, row.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("全量复制有失败的表: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// cdcCanalConfig 构造 binlog 订阅配置。TIMESTAMP 按 UTC 格式化，与目标库会话时区 syncSessionTimeZone 一致
+func cdcCanalConfig(source DBConfig, serverID uint32, tableRegex string) *canal.Config {
+	cfg := canal.NewDefaultConfig()
+	cfg.Addr = fmt.Sprintf("%s:%d", source.Host, source.Port)
+	cfg.User = source.User
+	cfg.Password = source.Password
+	cfg.ServerID = serverID
+	cfg.Dump.ExecutionPath = ""
+	cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg.IncludeTableRegex = []string{tableRegex}
+	cfg.DiscardNoMetaRowEvent = true
+	cfg.TimestampStringLocation = time.UTC
+	return cfg
+}
+
+// stream 建立 binlog 连接并应用行变更，直到连接关闭或出错
+func (m *cdcManager) stream(t *cdcTask) error {
+	st := t.status()
+	t.mu.Lock()
+	source, target, tables, opts, serverID := t.state.Source, t.state.Target, t.state.Tables, t.state.Options, t.state.ServerID
+	t.mu.Unlock()
+
+	target.Database = st.TargetDB
+	targetDSN, err := syncTargetDSN(target)
+	if err != nil {
+		return err
+	}
+	tgtDB, err := sql.Open("mysql", targetDSN)
+	if err != nil {
+		return err
+	}
+	defer tgtDB.Close()
+
+	cfg := cdcCanalConfig(source, serverID, cdcTableRegex(st.SourceDB, tables))
+	c, err := canal.NewCanal(cfg)
+	if err != nil {
+		return fmt.Errorf("连接源库 binlog 失败: %v", err)
+	}
+	h := &cdcHandler{m: m, t: t, db: tgtDB, targetDB: st.TargetDB, mapping: opts.Sync.Mapping, source: source, masking: opts.Sync.ApplyMasking}
+	c.SetEventHandler(h)
+
+	done := make(chan struct{})
+	t.mu.Lock()
+	t.canal, t.done = c, done
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		t.canal = nil
+		t.mu.Unlock()
+		close(done)
+	}()
+
+	// 定时刷新延迟
+	stopTick := make(chan struct{})
+	defer close(stopTick)
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopTick:
+				return
+			case <-ticker.C:
+				t.update(m, false, func(s *CDCStatus) { s.LagSeconds = c.GetDelay() })
+			}
+		}
+	}()
+
+	if st.GTID != "" {
+		set, err := gomysql.ParseGTIDSet(gomysql.MySQLFlavor, st.GTID)
+		if err != nil {
+			c.Close()
+			return fmt.Errorf("GTID 无效: %v", err)
+		}
+		err = c.StartFromGTID(set)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	}
+	err = c.RunFrom(gomysql.Position{Name: st.File, Pos: st.Pos})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// cdcTableRegex 生成 canal 的表过滤正则（库名.表名）
+func cdcTableRegex(database string, tables []string) string {
+	if len(tables) == 0 {
+		return "^" + regexp.QuoteMeta(database) + `\..+$`
+	}
+	quoted := make([]string, len(tables))
+	for i, t := range tables {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	return "^" + regexp.QuoteMeta(database) + `\.(` + strings.Join(quoted, "|") + ")$"
+}
+
+// cdcKeylessTables 返回同步范围内没有主键的表；tables 为空表示库中全部表
+func cdcKeylessTables(cfg DBConfig, database string, tables []string) ([]string, error) {
+	cfg.Database = ""
+	dsn, err := buildDSN(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.Query(`SELECT t.TABLE_NAME FROM information_schema.TABLES t
+		WHERE t.TABLE_SCHEMA = ? AND t.TABLE_TYPE = 'BASE TABLE'
+		AND NOT EXISTS (SELECT 1 FROM information_schema.STATISTICS s
+			WHERE s.TABLE_SCHEMA = t.TABLE_SCHEMA AND s.TABLE_NAME = t.TABLE_NAME AND s.INDEX_NAME = 'PRIMARY')
+		ORDER BY t.TABLE_NAME`, database)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	selected := map[string]bool{}
+	for _, t := range tables {
+		selected[t] = true
+	}
+	var keyless []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if len(tables) == 0 || selected[name] {
+			keyless = append(keyless, name)
+		}
+	}
+	return keyless, rows.Err()
+}
+
+// binlogPosition binlog 位点；开启 GTID 时 GTID 为已执行的 GTID 集合
+type binlogPosition struct {
+	File string
+	Pos  uint32
+	GTID string
+}
+
+// binlogQuerier *sql.DB 与 *sql.Conn 共有的查询方法
+type binlogQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// captureBinlogPosition 读取源库当前 binlog 位点；开启 GTID 时同时返回已执行的 GTID 集合
+func captureBinlogPosition(cfg DBConfig) (string, uint32, string, error) {
+	cfg.Database = ""
+	dsn, err := buildDSN(cfg)
+	if err != nil {
+		return "", 0, "", err
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return "", 0, "", err
+	}
+	defer db.Close()
+	pos, err := readBinlogPosition(context.Background(), db)
+	if err != nil {
+		return "", 0, "", err
+	}
+	return pos.File, pos.Pos, pos.GTID, nil
+}
+
+// readBinlogPosition 在指定连接上读取当前 binlog 位点，持有全局读锁的连接读到的即快照位点
+func readBinlogPosition(ctx context.Context, q binlogQuerier) (binlogPosition, error) {
+	var res binlogPosition
+	rows, err := q.QueryContext(ctx, "SHOW MASTER STATUS")
+	if err != nil {
+		// MySQL 8.4 起改为 SHOW BINARY LOG STATUS
+		rows, err = q.QueryContext(ctx, "SHOW BINARY LOG STATUS")
+		if err != nil {
+			return res, fmt.Errorf("读取 binlog 位点失败: %v", err)
+		}
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return res, err
+	}
+	if !rows.Next() {
+		return res, fmt.Errorf("源库未开启 binlog")
+	}
+	values := make([]sql.NullString, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return res, err
+	}
+	rows.Close()
+	for i, c := range cols {
+		switch strings.ToLower(c) {
+		case "file":
+			res.File = values[i].String
+		case "position":
+			fmt.Sscan(values[i].String, &res.Pos)
+		case "executed_gtid_set":
+			res.GTID = strings.ReplaceAll(values[i].String, "\n", "")
+		}
+	}
+	var mode sql.NullString
+	if err := q.QueryRowContext(ctx, "SELECT @@GLOBAL.gtid_mode").Scan(&mode); err != nil || !strings.EqualFold(mode.String, "ON") {
+		res.GTID = ""
+	}
+	return res, nil
+}
+
+// cdcHandler 把行变更应用到目标库：插入与更新按主键覆盖写入，删除按主键删除，
+// 重放已复制过的变更不会产生重复数据
+type cdcHandler struct {
+	canal.DummyEventHandler
+	m        *cdcManager
+	t        *cdcTask
+	db       *sql.DB
+	targetDB string
+	mapping  SyncMapping
+	source   DBConfig // 启用脱敏时用于匹配源连接的规则
+	masking  bool
+}
+
+func (h *cdcHandler) String() string { return "dms-cdc" }
+
+func (h *cdcHandler) OnRow(e *canal.RowsEvent) error {
+	tm := h.mapping.forTable(e.Table.Name)
+	if h.masking {
+		tm.masks = newMaskTableRules(h.source, e.Table.Schema, e.Table.Name)
+	}
+	ctx := context.Background()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	var inserted, updated, deleted int64
+	apply := func(q string, args []interface{}) error {
+		if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+			return fmt.Errorf("应用 %s.%s 的变更失败: %v", e.Table.Schema, e.Table.Name, err)
+		}
+		return nil
+	}
+	switch e.Action {
+	case canal.InsertAction:
+		for _, row := range e.Rows {
+			q, args := cdcUpsertSQL(h.targetDB, tm, e, row)
+			if err = apply(q, args); err != nil {
+				break
+			}
+			inserted++
+		}
+	case canal.UpdateAction:
+		for i := 0; i+1 < len(e.Rows); i += 2 {
+			before, after := e.Rows[i], e.Rows[i+1]
+			// 主键变化时先删除旧行
+			if len(e.Table.PKColumns) > 0 && !samePrimaryKey(e, before, after) {
+				q, args := cdcDeleteSQL(h.targetDB, tm, e, before)
+				if err = apply(q, args); err != nil {
+					break
+				}
+			}
+			if len(e.Table.PKColumns) == 0 {
+				q, args := cdcUpdateNoKeySQL(h.targetDB, tm, e, before, after)
+				err = apply(q, args)
+			} else {
+				q, args := cdcUpsertSQL(h.targetDB, tm, e, after)
+				err = apply(q, args)
+			}
+			if err != nil {
+				break
+			}
+			updated++
+		}
+	case canal.DeleteAction:
+		for _, row := range e.Rows {
+			q, args := cdcDeleteSQL(h.targetDB, tm, e, row)
+			if err = apply(q, args); err != nil {
+				break
+			}
+			deleted++
+		}
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	h.t.update(h.m, false, func(s *CDCStatus) {
+		s.Inserted += inserted
+		s.Updated += updated
+		s.Deleted += deleted
+		if e.Header != nil {
+			s.LastEventAt = time.Unix(int64(e.Header.Timestamp), 0).Format(time.RFC3339)
+		}
+	})
+	return nil
+}
+
+// OnDDL 结构变更不自动应用，只记录下来由用户处理
+func (h *cdcHandler) OnDDL(header *replication.EventHeader, nextPos gomysql.Position, e *replication.QueryEvent) error {
+	if string(e.Schema) != "" && string(e.Schema) != h.t.status().SourceDB {
+		return nil
+	}
+	h.t.update(h.m, true, func(s *CDCStatus) {
+		s.SkippedDDL++
+		s.LastDDL = string(e.Query)
+	})
+	return nil
+}
+
+// OnPosSynced 事务提交后记录已应用的位点
+func (h *cdcHandler) OnPosSynced(header *replication.EventHeader, pos gomysql.Position, set gomysql.GTIDSet, force bool) error {
+	h.t.update(h.m, force, func(s *CDCStatus) {
+		if pos.Name != "" {
+			s.File, s.Pos = pos.Name, pos.Pos
+		}
+		if set != nil && s.GTID != "" {
+			s.GTID = set.String()
+		}
+	})
+	return nil
+}
+
+func samePrimaryKey(e *canal.RowsEvent, before []interface{}, after []interface{}) bool {
+	for _, idx := range e.Table.PKColumns {
+		if idx >= len(before) || idx >= len(after) || fmt.Sprint(before[idx]) != fmt.Sprint(after[idx]) {
+			return false
+		}
+	}
+	return true
+}
+
+// cdcUpsertSQL 生成覆盖写入语句
+func cdcUpsertSQL(targetDB string, tm *tableMapper, e *canal.RowsEvent, row []interface{}) (string, []interface{}) {
+	var cols, ph, updates []string
+	var args []interface{}
+	for i, c := range e.Table.Columns {
+		dst, ok := tm.column(c.Name)
+		if !ok || i >= len(row) {
+			continue
+		}
+		cols = append(cols, fmt.Sprintf("`%s`", dst))
+		ph = append(ph, "?")
+		updates = append(updates, fmt.Sprintf("`%s` = VALUES(`%s`)", dst, dst))
+		args = append(args, tm.transform(c.Name, row[i]))
+	}
+	return fmt.Sprintf("INSERT INTO `%s`.`%s` (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s",
+		targetDB, tm.target, strings.Join(cols, ", "), strings.Join(ph, ", "), strings.Join(updates, ", ")), args
+}
+
+// cdcRowWhere 定位目标行：有主键时按主键，否则按全部已同步列（NULL 安全比较）
+func cdcRowWhere(tm *tableMapper, e *canal.RowsEvent, row []interface{}) (string, []interface{}, bool) {
+	idxs := e.Table.PKColumns
+	for _, idx := range idxs {
+		if _, ok := tm.column(e.Table.Columns[idx].Name); !ok {
+			idxs = nil
+			break
+		}
+	}
+	keyed := len(idxs) > 0
+	if !keyed {
+		for i := range e.Table.Columns {
+			idxs = append(idxs, i)
+		}
+	}
+	var conds []string
+	var args []interface{}
+	for _, idx := range idxs {
+		c := e.Table.Columns[idx]
+		dst, ok := tm.column(c.Name)
+		if !ok || idx >= len(row) {
+			continue
+		}
+		conds = append(conds, fmt.Sprintf("`%s` <=> ?", dst))
+		args = append(args, tm.transform(c.Name, row[idx]))
+	}
+	return strings.Join(conds, " AND "), args, keyed
+}
+
+func cdcDeleteSQL(targetDB string, tm *tableMapper, e *canal.RowsEvent, row []interface{}) (string, []interface{}) {
+	where, args, keyed := cdcRowWhere(tm, e, row)
+	q := fmt.Sprintf("DELETE FROM `%s`.`%s` WHERE %s", targetDB, tm.target, where)
+	if !keyed {
+		q += " LIMIT 1"
+	}
+	return q, args
+}
+
+// cdcUpdateNoKeySQL 无主键表按旧值定位并更新一行
+func cdcUpdateNoKeySQL(targetDB string, tm *tableMapper, e *canal.RowsEvent, before []interface{}, after []interface{}) (string, []interface{}) {
+	var sets []string
+	var args []interface{}
+	for i, c := range e.Table.Columns {
+		dst, ok := tm.column(c.Name)
+		if !ok || i >= len(after) {
+			continue
+		}
+		sets = append(sets, fmt.Sprintf("`%s` = ?", dst))
+		args = append(args, tm.transform(c.Name, after[i]))
+	}
+	where, whereArgs, _ := cdcRowWhere(tm, e, before)
+	return fmt.Sprintf("UPDATE `%s`.`%s` SET %s WHERE %s LIMIT 1", targetDB, tm.target, strings.Join(sets, ", "), where),
+		append(args, whereArgs...)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/binary"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// binlogTestEvent 拼接事件头（19 字节）与事件体
+func binlogTestEvent(typ replication.EventType, body []byte) []byte {
+	buf := make([]byte, replication.EventHeaderSize, replication.EventHeaderSize+len(body))
+	binary.LittleEndian.PutUint32(buf[0:], 1704067200)
+	buf[4] = byte(typ)
+	binary.LittleEndian.PutUint32(buf[5:], 1)
+	binary.LittleEndian.PutUint32(buf[9:], uint32(replication.EventHeaderSize+len(body)))
+	return append(buf, body...)
+}
+
+// timestampRowEvents 构造一张单列 TIMESTAMP 表的格式描述、表映射和插入事件
+func timestampRowEvents(unix uint32) [][]byte {
+	// 格式描述事件：版本 4，各事件类型的 post-header 长度，末尾为关闭的校验算法和校验值
+	fde := make([]byte, 2+50+4+1)
+	binary.LittleEndian.PutUint16(fde[0:], 4)
+	copy(fde[2:], "8.0.36")
+	fde[56] = byte(replication.EventHeaderSize)
+	lengths := make([]byte, 40)
+	lengths[replication.TABLE_MAP_EVENT-1] = 8
+	lengths[replication.WRITE_ROWS_EVENTv2-1] = 10
+	fde = append(fde, lengths...)
+	fde = append(fde, replication.BINLOG_CHECKSUM_ALG_OFF, 0, 0, 0, 0)
+
+	// 表映射事件：table id 1，库 d，表 t，一列 TIMESTAMP(0)
+	tm := []byte{1, 0, 0, 0, 0, 0, 0, 0, 1, 'd', 0, 1, 't', 0, 1, gomysql.MYSQL_TYPE_TIMESTAMP2, 1, 0, 0}
+
+	// 插入事件：table id 1，语句结束标志，无附加数据，一列且不为 NULL
+	rows := []byte{1, 0, 0, 0, 0, 0, 1, 0, 2, 0, 1, 0x01, 0x00}
+	rows = binary.BigEndian.AppendUint32(rows, unix)
+
+	return [][]byte{
+		binlogTestEvent(replication.FORMAT_DESCRIPTION_EVENT, fde),
+		binlogTestEvent(replication.TABLE_MAP_EVENT, tm),
+		binlogTestEvent(replication.WRITE_ROWS_EVENTv2, rows),
+	}
+}
+
+func TestCDCTimestampIndependentOfLocalZone(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC+8", 8*3600)
+	defer func() { time.Local = local }()
+
+	cfg := cdcCanalConfig(DBConfig{Host: "127.0.0.1", Port: 3306, User: "root"}, 1, `d\.t`)
+	if cfg.TimestampStringLocation != time.UTC {
+		t.Fatalf("TimestampStringLocation = %v, want UTC", cfg.TimestampStringLocation)
+	}
+
+	tests := []struct {
+		unix uint32
+		want string
+	}{
+		{1704067200, "2024-01-01 00:00:00"},
+		{1710054000, "2024-03-10 07:00:00"},
+		{2147483647, "2038-01-19 03:14:07"},
+	}
+	for _, tt := range tests {
+		p := replication.NewBinlogParser()
+		p.SetTimestampStringLocation(cfg.TimestampStringLocation)
+		var ev *replication.BinlogEvent
+		for _, data := range timestampRowEvents(tt.unix) {
+			var err error
+			if ev, err = p.Parse(data); err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+		}
+		re, ok := ev.Event.(*replication.RowsEvent)
+		if !ok || len(re.Rows) != 1 {
+			t.Fatalf("unexpected event %T", ev.Event)
+		}
+		if got := re.Rows[0][0]; got != tt.want {
+			t.Errorf("unix %d decoded as %v, want %q", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestSyncSessionsPinTimeZone(t *testing.T) {
+	dsn, err := syncTargetDSN(DBConfig{Host: "127.0.0.1", Port: 3306, User: "root", Database: "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := url.ParseQuery(dsn[strings.Index(dsn, "?")+1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := q.Get("time_zone"); got != syncSessionTimeZone {
+		t.Errorf("target time_zone = %q, want %q", got, syncSessionTimeZone)
+	}
+	if got := q.Get("foreign_key_checks"); got != "0" {
+		t.Errorf("target foreign_key_checks = %q, want 0", got)
+	}
+}
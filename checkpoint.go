@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syncCheckpoint 同步断点：记录任务参数与每张表、每个分片已提交的主键位置，
+// 保存在 dms-new/sync-checkpoints/<任务ID>.json
+type syncCheckpoint struct {
+	JobID     string                      `json:"jobId"`
+	Source    DBConfig                    `json:"source"`
+	SourceDB  string                      `json:"sourceDb"`
+	Target    DBConfig                    `json:"target"`
+	TargetDB  string                      `json:"targetDb"`
+	Mode      string                      `json:"mode"`
+	Tables    []string                    `json:"tables"`
+	Options   SyncOptions                 `json:"options"`
+	State     map[string]*tableCheckpoint `json:"state"`
+	UpdatedAt string                      `json:"updatedAt"`
+
+	mu       sync.Mutex
+	lastSave time.Time
+}
+
+// tableCheckpoint 单表断点；Status 为 pending / created / copying / done
+type tableCheckpoint struct {
+	Status      string       `json:"status"`
+	Chunks      []tableChunk `json:"chunks,omitempty"`
+	TablePolicy string       `json:"tablePolicy,omitempty"`
+	DataPolicy  string       `json:"dataPolicy,omitempty"`
+	Write       string       `json:"write,omitempty"`       // insert / ignore / upsert
+	Preexisting bool         `json:"preexisting,omitempty"` // 写入前目标表已有数据
+}
+
+// SyncCheckpointInfo 可续传的同步任务概要
+type SyncCheckpointInfo struct {
+	JobID      string `json:"jobId"`
+	TargetID   string `json:"targetId"` // 目标连接ID，续传前用于确认生产环境
+	SourceDB   string `json:"sourceDb"`
+	TargetDB   string `json:"targetDb"`
+	Mode       string `json:"mode"`
+	TablesDone int    `json:"tablesDone"`
+	TablesAll  int    `json:"tablesAll"`
+	UpdatedAt  string `json:"updatedAt"`
+}
+
+const checkpointSaveInterval = time.Second
+
+func syncCheckpointDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dms-new", "sync-checkpoints"), nil
+}
+
+func syncCheckpointPath(jobID string) (string, error) {
+	dir, err := syncCheckpointDir()
+	if err != nil {
+		return "", err
+	}
+	if jobID == "" || strings.ContainsAny(jobID, `/\.`) {
+		return "", fmt.Errorf("无效的任务ID: %s", jobID)
+	}
+	return filepath.Join(dir, jobID+".json"), nil
+}
+
+func newSyncCheckpoint(jobID string, source DBConfig, sourceDB string, target DBConfig, targetDB string, mode string, tables []string, opts SyncOptions) *syncCheckpoint {
+	return &syncCheckpoint{
+		JobID:    jobID,
+		Source:   source,
+		SourceDB: sourceDB,
+		Target:   target,
+		TargetDB: targetDB,
+		Mode:     mode,
+		Tables:   tables,
+		Options:  opts,
+		State:    map[string]*tableCheckpoint{},
+	}
+}
+
+func loadSyncCheckpoint(jobID string) (*syncCheckpoint, error) {
+	path, err := syncCheckpointPath(jobID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("未找到任务的断点记录: %s", jobID)
+		}
+		return nil, err
+	}
+	var cp syncCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("断点文件损坏: %v", err)
+	}
+	if cp.State == nil {
+		cp.State = map[string]*tableCheckpoint{}
+	}
+	return &cp, nil
+}
+
+// table 返回单表断点，不存在时创建
+func (cp *syncCheckpoint) table(name string) *tableCheckpoint {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.tableLocked(name)
+}
+
+func (cp *syncCheckpoint) tableLocked(name string) *tableCheckpoint {
+	st, ok := cp.State[name]
+	if !ok {
+		st = &tableCheckpoint{Status: "pending"}
+		cp.State[name] = st
+	}
+	return st
+}
+
+func (cp *syncCheckpoint) setStatus(name string, status string) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.tableLocked(name).Status = status
+}
+
+func (cp *syncCheckpoint) setChunks(name string, chunks []tableChunk) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.tableLocked(name).Chunks = chunks
+}
+
+func (cp *syncCheckpoint) chunk(name string, idx int) tableChunk {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.tableLocked(name).Chunks[idx]
+}
+
+// advance 记录分片已提交的最大主键值
+func (cp *syncCheckpoint) advance(name string, idx int, key int64) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	c := &cp.tableLocked(name).Chunks[idx]
+	if !c.Started || key > c.HighWater {
+		c.HighWater = key
+		c.Started = true
+	}
+}
+
+func (cp *syncCheckpoint) finishChunk(name string, idx int) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.tableLocked(name).Chunks[idx].Done = true
+}
+
+// save 写入断点文件；force 为 false 时按间隔节流
+func (cp *syncCheckpoint) save(force bool) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if !force && time.Since(cp.lastSave) < checkpointSaveInterval {
+		return nil
+	}
+	path, err := syncCheckpointPath(cp.JobID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	cp.UpdatedAt = time.Now().Format(time.RFC3339)
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再改名，避免中途退出留下不完整的断点
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	cp.lastSave = time.Now()
+	return nil
+}
+
+func (cp *syncCheckpoint) remove() error {
+	path, err := syncCheckpointPath(cp.JobID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (cp *syncCheckpoint) info() SyncCheckpointInfo {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	info := SyncCheckpointInfo{
+		JobID:     cp.JobID,
+		TargetID:  cp.Target.ID,
+		SourceDB:  cp.SourceDB,
+		TargetDB:  cp.TargetDB,
+		Mode:      cp.Mode,
+		TablesAll: len(cp.Tables),
+		UpdatedAt: cp.UpdatedAt,
+	}
+	for _, st := range cp.State {
+		if st.Status == "done" {
+			info.TablesDone++
+		}
+	}
+	return info
+}
+
+// ListSyncCheckpoints 列出可续传的同步任务（最近更新在前）
+func (a *App) ListSyncCheckpoints() ([]SyncCheckpointInfo, error) {
+	dir, err := syncCheckpointDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []SyncCheckpointInfo{}, nil
+		}
+		return nil, err
+	}
+	list := []SyncCheckpointInfo{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		cp, err := loadSyncCheckpoint(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		list = append(list, cp.info())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].UpdatedAt > list[j].UpdatedAt })
+	return list, nil
+}
+
+// DeleteSyncCheckpoint 删除同步断点（放弃续传）
+func (a *App) DeleteSyncCheckpoint(jobID string) error {
+	if j, ok := a.jobs.get(jobID); ok && (j.Status == "queued" || j.Status == "running") {
+		return fmt.Errorf("任务仍在执行中")
+	}
+	cp, err := loadSyncCheckpoint(jobID)
+	if err != nil {
+		return err
+	}
+	return cp.remove()
+}
+
+// ResumeSync 从断点继续未完成的同步任务，作为后台任务执行并等待其结束；
+// 返回结果中 Resumed / ResumedRows / Note 说明每张表的续传情况
+func (a *App) ResumeSync(jobID string, confirmToken string) ([]MigrationCheckRow, error) {
+	job, err := a.SubmitResumeSyncJob(jobID, confirmToken)
+	if err != nil {
+		return nil, err
+	}
+	res, err := a.jobs.wait(job.ID)
+	if err != nil {
+		return nil, err
+	}
+	results, _ := res.([]MigrationCheckRow)
+	return results, nil
+}
+
+// SubmitResumeSyncJob 提交续传任务，沿用原任务ID，立即返回任务信息；
+// 目标为生产环境且策略会删表或清空数据时须带确认令牌
+func (a *App) SubmitResumeSyncJob(jobID string, confirmToken string) (Job, error) {
+	cp, err := loadSyncCheckpoint(jobID)
+	if err != nil {
+		return Job{}, err
+	}
+	stmt := syncAuditStatement(cp.Source, cp.SourceDB, cp.TargetDB, cp.Mode, cp.Tables) + "（续传）"
+	if err := checkWritable(cp.Target); err != nil {
+		a.auditJob("sync", cp.Target, cp.TargetDB, stmt, time.Now(), &guardError{err.Error()})
+		return Job{}, err
+	}
+	if err := a.checkDestructiveTask(cp.Target, "sync", cp.TargetDB, cp.Options.destructiveReason(), confirmToken); err != nil {
+		a.auditJob("sync", cp.Target, cp.TargetDB, stmt, time.Now(), err)
+		return Job{}, err
+	}
+	return a.submitResumeSyncJob(cp)
+}
+
+// submitResumeSyncJob 提交已通过检查的续传任务
+func (a *App) submitResumeSyncJob(cp *syncCheckpoint) (Job, error) {
+	if j, ok := a.jobs.get(cp.JobID); ok && (j.Status == "queued" || j.Status == "running") {
+		return Job{}, fmt.Errorf("任务仍在执行中")
+	}
+	stmt := syncAuditStatement(cp.Source, cp.SourceDB, cp.TargetDB, cp.Mode, cp.Tables) + "（续传）"
+	title := fmt.Sprintf("同步 %s → %s（续传）", cp.SourceDB, cp.TargetDB)
+	job := a.jobs.submitWithID(cp.JobID, "sync", title, func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		start := time.Now()
+		res, err := runSyncDatabase(ctx, r, cp.Source, cp.SourceDB, cp.Target, cp.TargetDB, cp.Mode, cp.Tables, cp.Options, cp, nil)
+		a.auditJob("sync", cp.Target, cp.TargetDB, stmt, start, syncAuditError(res, err))
+		return res, err
+	})
+	return job, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyRangeWhere(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		lower    []interface{}
+		upper    []interface{}
+		want     string
+		wantArgs []interface{}
+	}{
+		{"unbounded", []string{"id"}, nil, nil, "", nil},
+		{"single key lower", []string{"id"}, []interface{}{10}, nil, " WHERE `id` > ?", []interface{}{10}},
+		{"single key range", []string{"id"}, []interface{}{10}, []interface{}{20}, " WHERE `id` > ? AND `id` <= ?", []interface{}{10, 20}},
+		{"composite lower", []string{"a", "b"}, []interface{}{1, 2}, nil,
+			" WHERE ((`a` > ?) OR (`a` = ? AND `b` > ?))", []interface{}{1, 1, 2}},
+		{"composite upper", []string{"a", "b", "c"}, nil, []interface{}{1, 2, 3},
+			" WHERE ((`a` < ?) OR (`a` = ? AND `b` < ?) OR (`a` = ? AND `b` = ? AND `c` <= ?))", []interface{}{1, 1, 2, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := keyRangeWhere(tt.keys, tt.lower, tt.upper)
+			if got != tt.want || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Fatalf("keyRangeWhere = %q %v, want %q %v", got, args, tt.want, tt.wantArgs)
+			}
+		})
+	}
+}
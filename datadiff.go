@@ -0,0 +1,495 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// DataDiffOptions 表数据比对选项
+type DataDiffOptions struct {
+	Output   string `json:"output"`   // grid：返回到结果表格；file：修复SQL写入文件
+	FilePath string `json:"filePath"` // Output 为 file 时的文件路径，为空则弹出保存对话框
+	MaxRows  int    `json:"maxRows"`  // 返回到结果表格的最大差异行数，默认 1000
+}
+
+// DataDiffRow 一条差异；Kind 为 missing（目标缺少）/ extra（目标多出）/ changed（内容不同）
+type DataDiffRow struct {
+	Kind    string   `json:"kind"`
+	Key     string   `json:"key"`
+	Columns []string `json:"columns"` // changed 时不同的列
+	SQL     string   `json:"sql"`     // 使目标与源一致的修复语句
+}
+
+// DataDiffResult 表数据比对结果
+type DataDiffResult struct {
+	Table      string        `json:"table"`
+	KeyColumns []string      `json:"keyColumns"`
+	SourceRows int64         `json:"sourceRows"`
+	TargetRows int64         `json:"targetRows"`
+	Missing    int64         `json:"missing"`
+	Extra      int64         `json:"extra"`
+	Changed    int64         `json:"changed"`
+	Rows       []DataDiffRow `json:"rows"`
+	Truncated  bool          `json:"truncated"`
+	Warnings   []string      `json:"warnings,omitempty"` // 比对键两侧类型或排序规则不同等，结果可能不准确的说明
+	FilePath   string        `json:"filePath,omitempty"`
+	ElapsedSec float64       `json:"elapsedSec"`
+}
+
+const defaultDataDiffMaxRows = 1000
+
+// CompareTableData 比对源表与目标表数据，生成使目标与源一致的 INSERT/UPDATE/DELETE 语句；
+// keyColumns 为空时使用源表主键。作为后台任务执行并等待其结束
+func (a *App) CompareTableData(source DBConfig, sourceDB string, target DBConfig, targetDB string, table string, keyColumns []string, opts DataDiffOptions) (DataDiffResult, error) {
+	job, err := a.SubmitDataDiffJob(source, sourceDB, target, targetDB, table, keyColumns, opts)
+	if err != nil {
+		return DataDiffResult{}, err
+	}
+	res, err := a.jobs.wait(job.ID)
+	result, _ := res.(DataDiffResult)
+	return result, err
+}
+
+// SubmitDataDiffJob 提交表数据比对任务，立即返回任务信息
+func (a *App) SubmitDataDiffJob(source DBConfig, sourceDB string, target DBConfig, targetDB string, table string, keyColumns []string, opts DataDiffOptions) (Job, error) {
+	if normalizeDBType(source.Type) != "mysql" || normalizeDBType(target.Type) != "mysql" {
+		return Job{}, fmt.Errorf("当前仅支持MySQL之间比对")
+	}
+	if sourceDB == "" || targetDB == "" || table == "" {
+		return Job{}, fmt.Errorf("源库、目标库和表名不能为空")
+	}
+	if opts.Output == "file" && opts.FilePath == "" {
+		if a.ctx == nil {
+			return Job{}, fmt.Errorf("应用未初始化")
+		}
+		path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "保存修复SQL",
+			DefaultFilename: fmt.Sprintf("%s_diff.sql", table),
+			Filters:         []runtime.FileFilter{{DisplayName: "SQL", Pattern: "*.sql"}},
+		})
+		if err != nil {
+			return Job{}, err
+		}
+		if path == "" {
+			return Job{}, fmt.Errorf("已取消")
+		}
+		opts.FilePath = path
+	}
+	title := fmt.Sprintf("比对 %s.%s → %s.%s", sourceDB, table, targetDB, table)
+	job := a.jobs.submit("diff", title, func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		return runCompareTableData(ctx, r, source, sourceDB, target, targetDB, table, keyColumns, opts)
+	})
+	return job, nil
+}
+
+// 比对键的排序与比较方式
+const (
+	diffKeyNumeric = iota // 数值：按列排序，按数值比较
+	diffKeyRaw            // 无排序规则的类型（二进制、日期等）：按列排序，按字节比较
+	diffKeyWeight         // 两侧排序规则相同的字符类型：按列排序，比较 WEIGHT_STRING 的字节
+	diffKeyCast           // 两侧排序规则不同或 ENUM/SET：按 CAST(... AS BINARY) 排序，无法利用索引
+)
+
+// keyColumnClass 比对键列的类别：numeric / enum（ENUM、SET）/ char（有排序规则）/ raw（二进制、日期等）
+func keyColumnClass(c targetColumn) string {
+	switch {
+	case isNumericType(c.DataType):
+		return "numeric"
+	case c.DataType == "enum" || c.DataType == "set":
+		return "enum"
+	case c.Collation != "":
+		return "char"
+	default:
+		return "raw"
+	}
+}
+
+// keyColumnKinds 按两侧列的类型确定每个比对键的排序与比较方式，两侧必须采用同一种方式才能归并；
+// 尽量直接按列排序以利用主键索引。数值与非数值无法按同一顺序排序，直接拒绝；
+// 其余两侧不一致的情况统一按二进制排序，并返回提示
+func keyColumnKinds(srcCols, tgtCols map[string]targetColumn, keys []string) ([]int, []string, error) {
+	kinds := make([]int, len(keys))
+	var warnings []string
+	for i, k := range keys {
+		sc, ok := srcCols[strings.ToLower(k)]
+		if !ok {
+			return nil, nil, fmt.Errorf("比对键 %s 不存在", k)
+		}
+		tc, ok := tgtCols[strings.ToLower(k)]
+		if !ok {
+			return nil, nil, fmt.Errorf("目标表缺少比对键 %s", k)
+		}
+		sClass, tClass := keyColumnClass(sc), keyColumnClass(tc)
+		switch {
+		case sClass != tClass && (sClass == "numeric" || tClass == "numeric"):
+			return nil, nil, fmt.Errorf("比对键 %s 两侧类型不兼容（源 %s，目标 %s），无法按同一顺序比对", k, sc.DataType, tc.DataType)
+		case sClass != tClass:
+			kinds[i] = diffKeyCast
+			warnings = append(warnings, fmt.Sprintf("比对键 %s 两侧类型不同（源 %s，目标 %s），按二进制排序比较，取值写法不同的键会被视为不同行", k, sc.DataType, tc.DataType))
+		case sClass == "numeric":
+			kinds[i] = diffKeyNumeric
+		case sClass == "enum":
+			kinds[i] = diffKeyCast
+		case sClass == "char" && strings.EqualFold(sc.Collation, tc.Collation):
+			kinds[i] = diffKeyWeight
+		case sClass == "char":
+			kinds[i] = diffKeyCast
+			warnings = append(warnings, fmt.Sprintf("比对键 %s 两侧排序规则不同（源 %s，目标 %s），按二进制比较，大小写或重音不同的键会被视为不同行", k, sc.Collation, tc.Collation))
+		default:
+			kinds[i] = diffKeyRaw
+			if !strings.EqualFold(sc.DataType, tc.DataType) {
+				warnings = append(warnings, fmt.Sprintf("比对键 %s 两侧类型不同（源 %s，目标 %s），取值格式不同的键会被视为不同行", k, sc.DataType, tc.DataType))
+			}
+		}
+	}
+	return kinds, warnings, nil
+}
+
+func isNumericType(dataType string) bool {
+	switch dataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "decimal", "numeric", "float", "double", "real":
+		return true
+	}
+	return false
+}
+
+// diffValueString 将扫描值规范为可比较的字符串，nil 单独标记
+func diffValueString(v interface{}) (string, bool) {
+	switch t := v.(type) {
+	case nil:
+		return "", false
+	case []byte:
+		return string(t), true
+	case time.Time:
+		return t.Format("2006-01-02 15:04:05.999999"), true
+	default:
+		return fmt.Sprint(t), true
+	}
+}
+
+// compareKeyValues 按与 ORDER BY 一致的规则比较两个键；字符键传入的是 WEIGHT_STRING 或二进制值
+func compareKeyValues(a, b []interface{}, kinds []int) int {
+	for i := range a {
+		as, aok := diffValueString(a[i])
+		bs, bok := diffValueString(b[i])
+		if !aok || !bok {
+			// NULL 排在最前
+			switch {
+			case !aok && bok:
+				return -1
+			case aok && !bok:
+				return 1
+			}
+			continue
+		}
+		var c int
+		if kinds[i] == diffKeyNumeric {
+			ar, ok1 := new(big.Rat).SetString(as)
+			br, ok2 := new(big.Rat).SetString(bs)
+			if ok1 && ok2 {
+				c = ar.Cmp(br)
+			} else {
+				c = strings.Compare(as, bs)
+			}
+		} else {
+			c = strings.Compare(as, bs)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// diffCursor 按键顺序流式读取一侧的数据；查询结果在表列之后附带 WEIGHT_STRING 比较列
+type diffCursor struct {
+	rows   *sql.Rows
+	n      int
+	cmpIdx []int // 比较键所在列，WEIGHT_STRING 列的下标从 n 开始
+	extra  int
+	cur    []interface{}
+	all    []interface{}
+	done   bool
+}
+
+func (c *diffCursor) next() error {
+	if !c.rows.Next() {
+		c.done = true
+		c.cur = nil
+		return c.rows.Err()
+	}
+	values, err := scanRowValues(c.rows, c.n+c.extra)
+	if err != nil {
+		return err
+	}
+	c.all = values
+	c.cur = values[:c.n]
+	return nil
+}
+
+func (c *diffCursor) key() []interface{} {
+	k := make([]interface{}, len(c.cmpIdx))
+	for i, idx := range c.cmpIdx {
+		k[i] = c.all[idx]
+	}
+	return k
+}
+
+// runCompareTableData 比对任务执行体：两侧按键排序流式归并
+func runCompareTableData(ctx context.Context, r *jobReporter, source DBConfig, sourceDB string, target DBConfig, targetDB string, table string, keyColumns []string, opts DataDiffOptions) (DataDiffResult, error) {
+	start := time.Now()
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = defaultDataDiffMaxRows
+	}
+	result := DataDiffResult{Table: table, Rows: []DataDiffRow{}}
+	source.Database = sourceDB
+	target.Database = targetDB
+	sourceDSN, err := buildDSN(source)
+	if err != nil {
+		return result, err
+	}
+	targetDSN, err := buildDSN(target)
+	if err != nil {
+		return result, err
+	}
+	srcDB, err := sql.Open("mysql", sourceDSN)
+	if err != nil {
+		return result, err
+	}
+	defer srcDB.Close()
+	tgtDB, err := sql.Open("mysql", targetDSN)
+	if err != nil {
+		return result, err
+	}
+	defer tgtDB.Close()
+
+	cols, err := tableColumnNames(srcDB, sourceDB, table)
+	if err != nil {
+		return result, fmt.Errorf("读取源表列失败: %v", err)
+	}
+	if len(cols) == 0 {
+		return result, fmt.Errorf("源表不存在: %s", table)
+	}
+	targetCols, err := fetchTargetColumns(tgtDB, targetDB, table)
+	if err != nil {
+		return result, fmt.Errorf("读取目标表列失败: %v", err)
+	}
+	if len(targetCols) == 0 {
+		return result, fmt.Errorf("目标表不存在: %s", table)
+	}
+	for _, c := range cols {
+		if _, ok := targetCols[strings.ToLower(c)]; !ok {
+			return result, fmt.Errorf("目标表缺少列: %s", c)
+		}
+	}
+	if len(keyColumns) == 0 {
+		keyColumns, err = primaryKeyColumns(srcDB, sourceDB, table)
+		if err != nil {
+			return result, fmt.Errorf("读取主键失败: %v", err)
+		}
+		if len(keyColumns) == 0 {
+			return result, fmt.Errorf("表 %s 没有主键，请指定比对键", table)
+		}
+	}
+	result.KeyColumns = keyColumns
+	sourceCols, err := fetchTargetColumns(srcDB, sourceDB, table)
+	if err != nil {
+		return result, fmt.Errorf("读取源表列失败: %v", err)
+	}
+	kinds, warnings, err := keyColumnKinds(sourceCols, targetCols, keyColumns)
+	if err != nil {
+		return result, err
+	}
+	result.Warnings = warnings
+	keyIdx := make([]int, len(keyColumns))
+	for i, k := range keyColumns {
+		keyIdx[i] = -1
+		for j, c := range cols {
+			if strings.EqualFold(c, k) {
+				keyIdx[i] = j
+			}
+		}
+		if keyIdx[i] < 0 {
+			return result, fmt.Errorf("比对键 %s 不存在", k)
+		}
+	}
+
+	// 按列排序以利用索引；字符键的排序规则在 Go 中无法复现，改为比较同一排序规则下的 WEIGHT_STRING，
+	// 其字节顺序与 ORDER BY 一致。两侧排序规则不同时才按二进制排序
+	orderBy := make([]string, len(keyColumns))
+	cmpIdx := make([]int, len(keyColumns))
+	var weights []string
+	for i, k := range keyColumns {
+		cmpIdx[i] = keyIdx[i]
+		switch kinds[i] {
+		case diffKeyCast:
+			orderBy[i] = fmt.Sprintf("CAST(`%s` AS BINARY)", k)
+		case diffKeyWeight:
+			cmpIdx[i] = len(cols) + len(weights)
+			weights = append(weights, fmt.Sprintf("WEIGHT_STRING(`%s`)", k))
+			orderBy[i] = fmt.Sprintf("`%s`", k)
+		default:
+			orderBy[i] = fmt.Sprintf("`%s`", k)
+		}
+	}
+	selectList := quoteColumns(cols)
+	if len(weights) > 0 {
+		selectList += ", " + strings.Join(weights, ", ")
+	}
+	selectSQL := func(database string) string {
+		return fmt.Sprintf("SELECT %s FROM `%s`.`%s` ORDER BY %s", selectList, database, table, strings.Join(orderBy, ", "))
+	}
+	srcRows, err := srcDB.QueryContext(ctx, selectSQL(sourceDB))
+	if err != nil {
+		return result, fmt.Errorf("读取源表失败: %v", err)
+	}
+	defer srcRows.Close()
+	tgtRows, err := tgtDB.QueryContext(ctx, selectSQL(targetDB))
+	if err != nil {
+		return result, fmt.Errorf("读取目标表失败: %v", err)
+	}
+	defer tgtRows.Close()
+
+	var out *bufio.Writer
+	if opts.Output == "file" {
+		f, err := os.Create(opts.FilePath)
+		if err != nil {
+			return result, fmt.Errorf("无法创建文件: %v", err)
+		}
+		defer f.Close()
+		out = bufio.NewWriter(f)
+		result.FilePath = opts.FilePath
+		fmt.Fprintf(out, "-- 数据比对 %s.%s → %s.%s，生成于 %s\n", sourceDB, table, targetDB, table, time.Now().Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(out, "-- 执行以下语句使目标表与源表一致\n\n")
+	}
+
+	keyWhere := func(values []interface{}) string {
+		conds := make([]string, len(keyIdx))
+		for i, idx := range keyIdx {
+			if values[idx] == nil {
+				conds[i] = fmt.Sprintf("`%s` IS NULL", cols[idx])
+			} else {
+				conds[i] = fmt.Sprintf("`%s` = %s", cols[idx], valueToSQL(values[idx]))
+			}
+		}
+		return strings.Join(conds, " AND ")
+	}
+	emit := func(kind string, values []interface{}, changed []string, stmt string) error {
+		switch kind {
+		case "missing":
+			result.Missing++
+		case "extra":
+			result.Extra++
+		case "changed":
+			result.Changed++
+		}
+		if out != nil {
+			if _, err := out.WriteString(stmt + ";\n"); err != nil {
+				return err
+			}
+		}
+		if len(result.Rows) >= opts.MaxRows {
+			result.Truncated = true
+			return nil
+		}
+		key := make([]interface{}, len(keyIdx))
+		for i, idx := range keyIdx {
+			key[i] = values[idx]
+		}
+		result.Rows = append(result.Rows, DataDiffRow{Kind: kind, Key: formatKey(keyColumns, key), Columns: changed, SQL: stmt})
+		return nil
+	}
+	insertSQL := func(values []interface{}) string {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = valueToSQL(v)
+		}
+		return fmt.Sprintf("INSERT INTO `%s`.`%s` (%s) VALUES (%s)", targetDB, table, quoteColumns(cols), strings.Join(parts, ", "))
+	}
+
+	src := &diffCursor{rows: srcRows, n: len(cols), cmpIdx: cmpIdx, extra: len(weights)}
+	tgt := &diffCursor{rows: tgtRows, n: len(cols), cmpIdx: cmpIdx, extra: len(weights)}
+	if err := src.next(); err != nil {
+		return result, err
+	}
+	if err := tgt.next(); err != nil {
+		return result, err
+	}
+	var processed int64
+	for !src.done || !tgt.done {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		processed++
+		if processed%5000 == 0 {
+			r.Progress(-1, fmt.Sprintf("已比对 %d 行，差异 %d", processed, result.Missing+result.Extra+result.Changed))
+		}
+		var c int
+		switch {
+		case src.done:
+			c = 1
+		case tgt.done:
+			c = -1
+		default:
+			c = compareKeyValues(src.key(), tgt.key(), kinds)
+		}
+		switch {
+		case c < 0:
+			result.SourceRows++
+			if err := emit("missing", src.cur, nil, insertSQL(src.cur)); err != nil {
+				return result, err
+			}
+			if err := src.next(); err != nil {
+				return result, err
+			}
+		case c > 0:
+			result.TargetRows++
+			stmt := fmt.Sprintf("DELETE FROM `%s`.`%s` WHERE %s", targetDB, table, keyWhere(tgt.cur))
+			if err := emit("extra", tgt.cur, nil, stmt); err != nil {
+				return result, err
+			}
+			if err := tgt.next(); err != nil {
+				return result, err
+			}
+		default:
+			result.SourceRows++
+			result.TargetRows++
+			var changed, sets []string
+			for i := range cols {
+				sv, sok := diffValueString(src.cur[i])
+				tv, tok := diffValueString(tgt.cur[i])
+				if sok != tok || sv != tv {
+					changed = append(changed, cols[i])
+					sets = append(sets, fmt.Sprintf("`%s` = %s", cols[i], valueToSQL(src.cur[i])))
+				}
+			}
+			if len(changed) > 0 {
+				stmt := fmt.Sprintf("UPDATE `%s`.`%s` SET %s WHERE %s", targetDB, table, strings.Join(sets, ", "), keyWhere(tgt.cur))
+				if err := emit("changed", src.cur, changed, stmt); err != nil {
+					return result, err
+				}
+			}
+			if err := src.next(); err != nil {
+				return result, err
+			}
+			if err := tgt.next(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if out != nil {
+		if err := out.Flush(); err != nil {
+			return result, fmt.Errorf("写入文件失败: %v", err)
+		}
+	}
+	result.ElapsedSec = time.Since(start).Seconds()
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyColumnKinds(t *testing.T) {
+	col := func(dataType, collation string) map[string]targetColumn {
+		return map[string]targetColumn{"k": {Name: "k", DataType: dataType, Collation: collation}}
+	}
+	tests := []struct {
+		name     string
+		src, tgt map[string]targetColumn
+		want     int
+		warn     bool
+		wantErr  bool
+	}{
+		{"int / bigint", col("int", ""), col("bigint", ""), diffKeyNumeric, false, false},
+		{"same collation", col("varchar", "utf8mb4_general_ci"), col("char", "utf8mb4_general_ci"), diffKeyWeight, false, false},
+		{"different collation", col("varchar", "utf8mb4_general_ci"), col("varchar", "utf8mb4_bin"), diffKeyCast, true, false},
+		{"varchar / int", col("varchar", "utf8mb4_general_ci"), col("int", ""), 0, false, true},
+		{"int / varchar", col("int", ""), col("varchar", "utf8mb4_general_ci"), 0, false, true},
+		{"varchar / date", col("varchar", "utf8mb4_general_ci"), col("date", ""), diffKeyCast, true, false},
+		{"enum / varchar", col("enum", "utf8mb4_general_ci"), col("varchar", "utf8mb4_general_ci"), diffKeyCast, true, false},
+		{"enum / enum", col("enum", "utf8mb4_general_ci"), col("enum", "utf8mb4_general_ci"), diffKeyCast, false, false},
+		{"date / date", col("date", ""), col("date", ""), diffKeyRaw, false, false},
+		{"date / datetime", col("date", ""), col("datetime", ""), diffKeyRaw, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kinds, warnings, err := keyColumnKinds(tt.src, tt.tgt, []string{"k"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(kinds, []int{tt.want}) || (len(warnings) > 0) != tt.warn {
+				t.Fatalf("kinds = %v warnings = %v, want %d warn %v", kinds, warnings, tt.want, tt.warn)
+			}
+		})
+	}
+}
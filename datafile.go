@@ -0,0 +1,751 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/xuri/excelize/v2"
+)
+
+// DataFileOptions CSV/TSV/XLSX 文件读取选项
+type DataFileOptions struct {
+	Format      string `json:"format"` // csv / tsv / xlsx，留空按文件后缀判断
+	Delimiter   string `json:"delimiter"`
+	HasHeader   bool   `json:"hasHeader"`
+	Sheet       string `json:"sheet"`
+	PreviewRows int    `json:"previewRows"`
+}
+
+// DataColumn 文件中的一列及推断出的类型
+type DataColumn struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Nullable  bool   `json:"nullable"`
+	MaxLength int    `json:"maxLength"`
+}
+
+// DataFilePreview 文件预览结果
+type DataFilePreview struct {
+	Path    string       `json:"path"`
+	Format  string       `json:"format"`
+	Sheets  []string     `json:"sheets"`
+	Columns []DataColumn `json:"columns"`
+	Rows    [][]string   `json:"rows"`
+}
+
+// ColumnMapping 源列到目标表列的映射，Source 为源列名
+type ColumnMapping struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// DataLoadRequest 数据文件导入请求
+type DataLoadRequest struct {
+	DataFileOptions
+	Path        string          `json:"path"`
+	Table       string          `json:"table"`
+	CreateTable bool            `json:"createTable"`
+	Columns     []DataColumn    `json:"columns"`    // 新建表时使用的列定义（可在预览结果上修改）
+	PrimaryKey  []string        `json:"primaryKey"` // 新建表时的主键列
+	Mappings    []ColumnMapping `json:"mappings"`
+	Mode        string          `json:"mode"` // insert / upsert / replace
+	BatchSize   int             `json:"batchSize"`
+	MaxRejected int             `json:"maxRejected"`
+
+	// 目标为生产环境且以 replace 方式导入时须带 ConfirmTaskTarget 签发的令牌
+	ConfirmToken string `json:"confirmToken"`
+}
+
+// RejectedRow 被拒绝的行
+type RejectedRow struct {
+	Line   int      `json:"line"`
+	Reason string   `json:"reason"`
+	Values []string `json:"values"`
+}
+
+// DataLoadResult 数据文件导入结果
+type DataLoadResult struct {
+	Table        string        `json:"table"`
+	Created      bool          `json:"created"`
+	Loaded       int64         `json:"loaded"`
+	Rejected     int64         `json:"rejected"`
+	RejectedRows []RejectedRow `json:"rejectedRows"`
+	ElapsedSec   float64       `json:"elapsedSec"`
+}
+
+// SelectDataFile 弹出打开文件对话框选择 CSV/TSV/XLSX 文件
+func (a *App) SelectDataFile() (string, error) {
+	if a.ctx == nil {
+		return "", fmt.Errorf("应用未初始化")
+	}
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "选择数据文件",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "数据文件", Pattern: "*.csv;*.tsv;*.txt;*.xlsx"},
+			{DisplayName: "All Files", Pattern: "*"},
+		},
+	})
+}
+
+func normalizeDataFileOptions(path string, opts DataFileOptions) DataFileOptions {
+	format := strings.ToLower(strings.TrimSpace(opts.Format))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".xlsx", ".xlsm":
+			format = "xlsx"
+		case ".tsv":
+			format = "tsv"
+		default:
+			format = "csv"
+		}
+	}
+	opts.Format = format
+	if opts.Delimiter == "" {
+		if format == "tsv" {
+			opts.Delimiter = "\t"
+		} else {
+			opts.Delimiter = ","
+		}
+	}
+	if opts.PreviewRows <= 0 {
+		opts.PreviewRows = 100
+	}
+	return opts
+}
+
+// dataRowReader 逐行读取数据文件
+type dataRowReader interface {
+	Next() ([]string, error)
+	Line() int
+	Close() error
+}
+
+type csvRowReader struct {
+	f    *os.File
+	r    *csv.Reader
+	line int
+}
+
+func (c *csvRowReader) Next() ([]string, error) {
+	rec, err := c.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	c.line, _ = c.r.FieldPos(0)
+	return rec, nil
+}
+
+func (c *csvRowReader) Line() int    { return c.line }
+func (c *csvRowReader) Close() error { return c.f.Close() }
+
+type xlsxRowReader struct {
+	f    *excelize.File
+	rows *excelize.Rows
+	line int
+}
+
+func (x *xlsxRowReader) Next() ([]string, error) {
+	if !x.rows.Next() {
+		if err := x.rows.Error(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	x.line++
+	return x.rows.Columns()
+}
+
+func (x *xlsxRowReader) Line() int { return x.line }
+func (x *xlsxRowReader) Close() error {
+	_ = x.rows.Close()
+	return x.f.Close()
+}
+
+// openDataRowReader 打开数据文件，返回行读取器与工作表列表（仅 xlsx）
+func openDataRowReader(path string, opts DataFileOptions) (dataRowReader, []string, error) {
+	if opts.Format == "xlsx" {
+		f, err := excelize.OpenFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("打开Excel失败: %v", err)
+		}
+		sheets := f.GetSheetList()
+		sheet := opts.Sheet
+		if sheet == "" && len(sheets) > 0 {
+			sheet = sheets[0]
+		}
+		rows, err := f.Rows(sheet)
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("读取工作表失败: %v", err)
+		}
+		return &xlsxRowReader{f: f, rows: rows}, sheets, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	br := bufio.NewReader(f)
+	// 跳过 UTF-8 BOM
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		_, _ = br.Discard(3)
+	}
+	r := csv.NewReader(br)
+	r.Comma = []rune(opts.Delimiter)[0]
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.ReuseRecord = false
+	return &csvRowReader{f: f, r: r}, nil, nil
+}
+
+var (
+	dateLayouts     = []string{"2006-01-02", "2006/01/02", "2006.01.02"}
+	datetimeLayouts = []string{"2006-01-02 15:04:05", "2006/01/02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006/01/02 15:04", time.RFC3339}
+)
+
+func parseDateValue(v string, layouts []string) (time.Time, bool) {
+	for _, l := range layouts {
+		if t, err := time.Parse(l, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// columnTypeInferrer 根据样本值逐步收窄列类型
+type columnTypeInferrer struct {
+	isInt, isDecimal, isDate, isDatetime bool
+	seen                                 bool
+	nullable                             bool
+	maxLen, maxInt, maxScale             int
+	big                                  bool
+}
+
+func newColumnTypeInferrer() *columnTypeInferrer {
+	return &columnTypeInferrer{isInt: true, isDecimal: true, isDate: true, isDatetime: true}
+}
+
+// hasLeadingZero 判断整数部分是否以 0 开头且后面还有数字，如 007、-01.5
+func hasLeadingZero(v string) bool {
+	digits := strings.TrimLeft(v, "+-")
+	return len(digits) > 1 && digits[0] == '0' && digits[1] >= '0' && digits[1] <= '9'
+}
+
+func (c *columnTypeInferrer) add(v string) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		c.nullable = true
+		return
+	}
+	c.seen = true
+	if n := len([]rune(v)); n > c.maxLen {
+		c.maxLen = n
+	}
+	if hasLeadingZero(v) {
+		// 带前导零的数字（编号、邮编等）按数值导入会丢失前导零，按字符串处理
+		c.isInt, c.isDecimal = false, false
+	}
+	if c.isInt {
+		if n, err := strconv.ParseInt(v, 10, 64); err != nil {
+			c.isInt = false
+		} else if n > 2147483647 || n < -2147483648 {
+			c.big = true
+		}
+	}
+	if c.isDecimal {
+		if _, err := strconv.ParseFloat(v, 64); err != nil || strings.ContainsAny(v, "eEnN") {
+			c.isDecimal = false
+		} else {
+			digits := strings.TrimLeft(v, "+-")
+			intPart, frac, _ := strings.Cut(digits, ".")
+			if len(intPart) > c.maxInt {
+				c.maxInt = len(intPart)
+			}
+			if len(frac) > c.maxScale {
+				c.maxScale = len(frac)
+			}
+		}
+	}
+	if c.isDate {
+		if _, ok := parseDateValue(v, dateLayouts); !ok {
+			c.isDate = false
+		}
+	}
+	if c.isDatetime {
+		if _, ok := parseDateValue(v, datetimeLayouts); !ok {
+			if _, ok := parseDateValue(v, dateLayouts); !ok {
+				c.isDatetime = false
+			}
+		}
+	}
+}
+
+func (c *columnTypeInferrer) mysqlType() string {
+	switch {
+	case !c.seen:
+		return "VARCHAR(255)"
+	case c.isInt && c.big:
+		return "BIGINT"
+	case c.isInt:
+		return "INT"
+	case c.isDecimal && c.maxInt+c.maxScale <= 65 && c.maxScale <= 30:
+		precision := c.maxInt + c.maxScale
+		if precision < 10 {
+			precision = 10
+		}
+		if precision > 65 {
+			precision = 65
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", precision, c.maxScale)
+	case c.isDecimal:
+		return "DOUBLE"
+	case c.isDate:
+		return "DATE"
+	case c.isDatetime:
+		return "DATETIME"
+	case c.maxLen > 4000:
+		return "TEXT"
+	default:
+		size := 32
+		for size < c.maxLen*2 && size < 4000 {
+			size *= 2
+		}
+		if size > 4000 {
+			size = 4000
+		}
+		return fmt.Sprintf("VARCHAR(%d)", size)
+	}
+}
+
+// PreviewDataFile 预览数据文件的前 N 行并推断每列的类型
+func (a *App) PreviewDataFile(path string, opts DataFileOptions) (DataFilePreview, error) {
+	opts = normalizeDataFileOptions(path, opts)
+	preview := DataFilePreview{Path: path, Format: opts.Format, Columns: []DataColumn{}, Rows: [][]string{}}
+	reader, sheets, err := openDataRowReader(path, opts)
+	if err != nil {
+		return preview, err
+	}
+	defer reader.Close()
+	preview.Sheets = sheets
+
+	var header []string
+	if opts.HasHeader {
+		header, err = reader.Next()
+		if err != nil && err != io.EOF {
+			return preview, err
+		}
+	}
+	var inferrers []*columnTypeInferrer
+	for len(preview.Rows) < opts.PreviewRows {
+		rec, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return preview, fmt.Errorf("第 %d 行读取失败: %v", reader.Line(), err)
+		}
+		for len(inferrers) < len(rec) {
+			inferrers = append(inferrers, newColumnTypeInferrer())
+		}
+		for i, v := range rec {
+			inferrers[i].add(v)
+		}
+		preview.Rows = append(preview.Rows, rec)
+	}
+	for len(inferrers) < len(header) {
+		inferrers = append(inferrers, newColumnTypeInferrer())
+	}
+	for i, inf := range inferrers {
+		name := fmt.Sprintf("column_%d", i+1)
+		if i < len(header) && strings.TrimSpace(header[i]) != "" {
+			name = strings.TrimSpace(header[i])
+		}
+		preview.Columns = append(preview.Columns, DataColumn{
+			Name:      name,
+			Type:      inf.mysqlType(),
+			Nullable:  inf.nullable || !inf.seen,
+			MaxLength: inf.maxLen,
+		})
+	}
+	return preview, nil
+}
+
+// LoadDataFile 按列映射将数据文件导入目标表，作为后台任务执行并等待其结束
+func (a *App) LoadDataFile(cfg DBConfig, db string, req DataLoadRequest) (DataLoadResult, error) {
+	job, err := a.SubmitDataLoadJob(cfg, db, req)
+	if err != nil {
+		return DataLoadResult{}, err
+	}
+	res, err := a.jobs.wait(job.ID)
+	result, _ := res.(DataLoadResult)
+	return result, err
+}
+
+// SubmitDataLoadJob 提交数据文件导入任务，立即返回任务信息
+func (a *App) SubmitDataLoadJob(cfg DBConfig, db string, req DataLoadRequest) (Job, error) {
+	if normalizeDBType(cfg.Type) != "mysql" {
+		return Job{}, fmt.Errorf("当前连接类型暂不支持数据导入")
+	}
+	if db == "" || req.Table == "" {
+		return Job{}, fmt.Errorf("目标库和目标表不能为空")
+	}
+	if err := checkWritable(cfg); err != nil {
+		return Job{}, err
+	}
+	if req.Path == "" {
+		return Job{}, fmt.Errorf("请选择数据文件")
+	}
+	switch req.Mode {
+	case "", "insert":
+		req.Mode = "insert"
+	case "upsert", "replace":
+	default:
+		return Job{}, fmt.Errorf("不支持的导入方式: %s", req.Mode)
+	}
+	if req.Mode == "replace" {
+		if err := a.checkDestructiveTask(cfg, "load", db, "REPLACE 将删除主键或唯一键冲突的已有行", req.ConfirmToken); err != nil {
+			return Job{}, err
+		}
+	}
+	cfg.Database = db
+	title := fmt.Sprintf("导入 %s → %s.%s", filepath.Base(req.Path), db, req.Table)
+	job := a.jobs.submit("import", title, func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		return runDataLoad(ctx, r, cfg, req)
+	})
+	return job, nil
+}
+
+// targetColumn 目标表列信息
+type targetColumn struct {
+	Name      string
+	DataType  string
+	Nullable  bool
+	Collation string // 非字符类型为空
+}
+
+func fetchTargetColumns(db *sql.DB, database string, table string) (map[string]targetColumn, error) {
+	rows, err := db.Query(
+		`SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE, COLLATION_NAME
+		 FROM information_schema.columns
+		 WHERE table_schema = ? AND table_name = ?`,
+		database, table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols := map[string]targetColumn{}
+	for rows.Next() {
+		var c targetColumn
+		var nullable string
+		var collation sql.NullString
+		if err := rows.Scan(&c.Name, &c.DataType, &nullable, &collation); err != nil {
+			return nil, err
+		}
+		c.Collation = collation.String
+		c.DataType = strings.ToLower(c.DataType)
+		c.Nullable = nullable == "YES"
+		cols[strings.ToLower(c.Name)] = c
+	}
+	return cols, rows.Err()
+}
+
+// convertDataValue 按目标列类型转换单元格值，返回 nil 表示 NULL
+func convertDataValue(v string, col targetColumn) (interface{}, error) {
+	trimmed := strings.TrimSpace(v)
+	switch col.DataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "decimal", "numeric", "float", "double", "real", "bit":
+		if trimmed == "" {
+			if !col.Nullable {
+				return nil, fmt.Errorf("列 %s 不能为空", col.Name)
+			}
+			return nil, nil
+		}
+		if _, err := strconv.ParseFloat(strings.ReplaceAll(trimmed, ",", ""), 64); err != nil {
+			return nil, fmt.Errorf("列 %s 不是有效数字: %s", col.Name, trimmed)
+		}
+		return strings.ReplaceAll(trimmed, ",", ""), nil
+	case "date":
+		if trimmed == "" {
+			if !col.Nullable {
+				return nil, fmt.Errorf("列 %s 不能为空", col.Name)
+			}
+			return nil, nil
+		}
+		t, ok := parseDateValue(trimmed, dateLayouts)
+		if !ok {
+			if t, ok = parseDateValue(trimmed, datetimeLayouts); !ok {
+				return nil, fmt.Errorf("列 %s 不是有效日期: %s", col.Name, trimmed)
+			}
+		}
+		return t.Format("2006-01-02"), nil
+	case "datetime", "timestamp":
+		if trimmed == "" {
+			if !col.Nullable {
+				return nil, fmt.Errorf("列 %s 不能为空", col.Name)
+			}
+			return nil, nil
+		}
+		t, ok := parseDateValue(trimmed, datetimeLayouts)
+		if !ok {
+			if t, ok = parseDateValue(trimmed, dateLayouts); !ok {
+				return nil, fmt.Errorf("列 %s 不是有效时间: %s", col.Name, trimmed)
+			}
+		}
+		return t.Format("2006-01-02 15:04:05"), nil
+	default:
+		return v, nil
+	}
+}
+
+// runDataLoad 数据文件导入任务执行体
+func runDataLoad(ctx context.Context, r *jobReporter, cfg DBConfig, req DataLoadRequest) (DataLoadResult, error) {
+	start := time.Now()
+	opts := normalizeDataFileOptions(req.Path, req.DataFileOptions)
+	result := DataLoadResult{Table: req.Table, RejectedRows: []RejectedRow{}}
+	if req.BatchSize <= 0 {
+		req.BatchSize = 500
+	}
+	if req.MaxRejected <= 0 {
+		req.MaxRejected = 1000
+	}
+
+	dsn, err := buildDSN(cfg)
+	if err != nil {
+		return result, err
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return result, err
+	}
+	defer db.Close()
+
+	if req.CreateTable {
+		exists, err := tableExists(db, cfg.Database, req.Table)
+		if err != nil {
+			return result, err
+		}
+		if exists {
+			return result, fmt.Errorf("目标已存在同名表")
+		}
+		if len(req.Columns) == 0 {
+			return result, fmt.Errorf("新建表时列定义不能为空")
+		}
+		ddl := buildCreateTableFromColumns(req.Table, req.Columns, req.PrimaryKey)
+		if _, err := db.ExecContext(ctx, ddl); err != nil {
+			return result, fmt.Errorf("创建目标表失败: %v", err)
+		}
+		result.Created = true
+	}
+
+	targetCols, err := fetchTargetColumns(db, cfg.Database, req.Table)
+	if err != nil {
+		return result, err
+	}
+	if len(targetCols) == 0 {
+		return result, fmt.Errorf("目标表不存在")
+	}
+
+	reader, _, err := openDataRowReader(req.Path, opts)
+	if err != nil {
+		return result, err
+	}
+	defer reader.Close()
+
+	var header []string
+	if opts.HasHeader {
+		if header, err = reader.Next(); err != nil && err != io.EOF {
+			return result, err
+		}
+	}
+	sourceIndex := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i
+			}
+		}
+		if strings.HasPrefix(name, "column_") {
+			if n, err := strconv.Atoi(strings.TrimPrefix(name, "column_")); err == nil {
+				return n - 1
+			}
+		}
+		return -1
+	}
+
+	mappings := req.Mappings
+	if len(mappings) == 0 {
+		// 未指定映射时按列名一一对应；新建表时按列定义顺序对应
+		for i, c := range req.Columns {
+			src := c.Name
+			if i < len(header) && req.CreateTable {
+				src = header[i]
+			}
+			mappings = append(mappings, ColumnMapping{Source: src, Target: c.Name})
+		}
+		if len(mappings) == 0 {
+			for _, h := range header {
+				if _, ok := targetCols[strings.ToLower(strings.TrimSpace(h))]; ok {
+					mappings = append(mappings, ColumnMapping{Source: h, Target: strings.TrimSpace(h)})
+				}
+			}
+		}
+	}
+	if len(mappings) == 0 {
+		return result, fmt.Errorf("未找到可导入的列映射")
+	}
+
+	type mapped struct {
+		src int
+		col targetColumn
+	}
+	var cols []mapped
+	colNames := make([]string, 0, len(mappings))
+	for _, m := range mappings {
+		if m.Target == "" {
+			continue
+		}
+		col, ok := targetCols[strings.ToLower(m.Target)]
+		if !ok {
+			return result, fmt.Errorf("目标表不存在列: %s", m.Target)
+		}
+		idx := sourceIndex(m.Source)
+		if idx < 0 {
+			return result, fmt.Errorf("源文件不存在列: %s", m.Source)
+		}
+		cols = append(cols, mapped{src: idx, col: col})
+		colNames = append(colNames, fmt.Sprintf("`%s`", col.Name))
+	}
+
+	verb := "INSERT INTO"
+	if req.Mode == "replace" {
+		verb = "REPLACE INTO"
+	}
+	suffix := ""
+	if req.Mode == "upsert" {
+		updates := make([]string, len(colNames))
+		for i, c := range colNames {
+			updates[i] = fmt.Sprintf("%s = VALUES(%s)", c, c)
+		}
+		suffix = " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+	}
+	rowPH := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ") + ")"
+	prefix := fmt.Sprintf("%s `%s`.`%s` (%s) VALUES ", verb, cfg.Database, req.Table, strings.Join(colNames, ", "))
+
+	reject := func(line int, reason string, values []string) {
+		result.Rejected++
+		if len(result.RejectedRows) < req.MaxRejected {
+			result.RejectedRows = append(result.RejectedRows, RejectedRow{Line: line, Reason: reason, Values: values})
+		}
+	}
+
+	type pendingRow struct {
+		line   int
+		values []string
+		args   []interface{}
+	}
+	var batch []pendingRow
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		phs := make([]string, len(batch))
+		var args []interface{}
+		for i, p := range batch {
+			phs[i] = rowPH
+			args = append(args, p.args...)
+		}
+		if _, err := db.ExecContext(ctx, prefix+strings.Join(phs, ",")+suffix, args...); err == nil {
+			result.Loaded += int64(len(batch))
+			batch = batch[:0]
+			return nil
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// 整批失败时逐行重试，找出具体被拒绝的行及原因
+		for _, p := range batch {
+			if _, err := db.ExecContext(ctx, prefix+rowPH+suffix, p.args...); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				reject(p.line, err.Error(), p.values)
+				continue
+			}
+			result.Loaded++
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	var processed int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		rec, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("第 %d 行读取失败: %v", reader.Line(), err)
+		}
+		processed++
+		line := reader.Line()
+		args := make([]interface{}, len(cols))
+		var convErr error
+		for i, c := range cols {
+			v := ""
+			if c.src < len(rec) {
+				v = rec[c.src]
+			}
+			if args[i], convErr = convertDataValue(v, c.col); convErr != nil {
+				break
+			}
+		}
+		if convErr != nil {
+			reject(line, convErr.Error(), rec)
+			continue
+		}
+		batch = append(batch, pendingRow{line: line, values: rec, args: args})
+		if len(batch) >= req.BatchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+			r.Progress(-1, fmt.Sprintf("已处理 %d 行，导入 %d 行，拒绝 %d 行", processed, result.Loaded, result.Rejected))
+		}
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+	result.ElapsedSec = time.Since(start).Seconds()
+	return result, nil
+}
+
+// buildCreateTableFromColumns 根据推断或用户修改后的列定义生成建表语句
+func buildCreateTableFromColumns(table string, columns []DataColumn, primaryKey []string) string {
+	defs := make([]string, 0, len(columns)+1)
+	for _, c := range columns {
+		null := "NULL"
+		if !c.Nullable {
+			null = "NOT NULL"
+		}
+		defs = append(defs, fmt.Sprintf("  `%s` %s %s", c.Name, c.Type, null))
+	}
+	if len(primaryKey) > 0 {
+		keys := make([]string, len(primaryKey))
+		for i, k := range primaryKey {
+			keys[i] = fmt.Sprintf("`%s`", k)
+		}
+		defs = append(defs, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(keys, ", ")))
+	}
+	return fmt.Sprintf("CREATE TABLE `%s` (\n%s\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4", table, strings.Join(defs, ",\n"))
+}
@@ -0,0 +1,559 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ExportOptions 导出选项：压缩、分卷与校验
+type ExportOptions struct {
+	Compression string `json:"compression"` // none / gzip / zstd
+	SplitMode   string `json:"splitMode"`   // none / size / table
+	SplitSizeMB int    `json:"splitSizeMB"`
+	Checksum    bool   `json:"checksum"`
+}
+
+// ExportFile 导出产生的单个文件
+type ExportFile struct {
+	File   string `json:"file"`
+	Table  string `json:"table,omitempty"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// ExportManifest 分卷导出的清单文件内容
+type ExportManifest struct {
+	Database    string       `json:"database"`
+	Mode        string       `json:"mode"`
+	Compression string       `json:"compression"`
+	SplitMode   string       `json:"splitMode"`
+	CreatedAt   string       `json:"createdAt"`
+	Tables      []string     `json:"tables"`
+	Files       []ExportFile `json:"files"`
+}
+
+func normalizeExportOptions(opts ExportOptions) ExportOptions {
+	switch strings.ToLower(strings.TrimSpace(opts.Compression)) {
+	case "gzip", "gz":
+		opts.Compression = "gzip"
+	case "zstd", "zst":
+		opts.Compression = "zstd"
+	default:
+		opts.Compression = "none"
+	}
+	switch strings.ToLower(strings.TrimSpace(opts.SplitMode)) {
+	case "size":
+		opts.SplitMode = "size"
+		if opts.SplitSizeMB <= 0 {
+			opts.SplitSizeMB = 1024
+		}
+	case "table":
+		opts.SplitMode = "table"
+	default:
+		opts.SplitMode = "none"
+	}
+	return opts
+}
+
+// compressionExt 返回压缩格式对应的文件后缀
+func compressionExt(compression string) string {
+	switch compression {
+	case "gzip":
+		return ".gz"
+	case "zstd":
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// exportBaseName 去掉用户选择路径上的 .sql/.gz/.zst 后缀，作为分卷文件名前缀
+func exportBaseName(path string) string {
+	base := path
+	for {
+		lower := strings.ToLower(base)
+		trimmed := false
+		for _, ext := range []string{".gz", ".zst", ".sql"} {
+			if strings.HasSuffix(lower, ext) {
+				base = base[:len(base)-len(ext)]
+				trimmed = true
+				break
+			}
+		}
+		if !trimmed {
+			return base
+		}
+	}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9_\-.]`)
+
+// exportPart 当前正在写入的一个输出文件
+type exportPart struct {
+	path  string
+	table string
+	file  *os.File
+	hash  hash.Hash
+	disk  *countingWriter
+	comp  io.WriteCloser
+}
+
+func (p *exportPart) Write(b []byte) (int, error) {
+	return p.comp.Write(b)
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// countingWriter 统计写入字节数
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// exportSink 负责把导出内容写到一个或多个（可压缩的）文件中
+type exportSink struct {
+	path   string
+	base   string
+	opts   ExportOptions
+	log    func(format string, args ...interface{})
+	cur    *exportPart
+	files  []ExportFile
+	paths  []string
+	partNo int
+	names  map[string]bool // 按表分卷时已使用的文件名（不区分大小写）
+	stmt   statementTracker
+}
+
+const (
+	statementHeadSize = 32 // 判断 DELIMITER 指令只需行首
+	statementTailSize = 16 // 判断语句结束只需行尾
+)
+
+// statementTracker 逐行跟踪导出内容，判断已写入的内容是否恰好结束于一条完整语句：
+// 行以 ; 结尾且不在 DELIMITER 块（触发器、存储过程等）内。只保留每行的首尾片段，长行不占内存
+type statementTracker struct {
+	head     []byte
+	tail     []byte
+	inBlock  bool
+	boundary bool
+}
+
+func (t *statementTracker) write(p []byte) {
+	for len(p) > 0 {
+		idx := bytes.IndexByte(p, '\n')
+		line := p
+		if idx >= 0 {
+			line = p[:idx]
+		}
+		if n := statementHeadSize - len(t.head); n > 0 {
+			t.head = append(t.head, line[:min(n, len(line))]...)
+		}
+		if len(line) >= statementTailSize {
+			t.tail = append(t.tail[:0], line[len(line)-statementTailSize:]...)
+		} else {
+			t.tail = append(t.tail, line...)
+			if extra := len(t.tail) - statementTailSize; extra > 0 {
+				t.tail = append(t.tail[:0], t.tail[extra:]...)
+			}
+		}
+		t.boundary = false
+		if idx < 0 {
+			return
+		}
+		t.endLine()
+		p = p[idx+1:]
+	}
+}
+
+// endLine 一行结束：处理 DELIMITER 指令，或判断该行是否结束了一条语句
+func (t *statementTracker) endLine() {
+	head := strings.TrimSpace(string(t.head))
+	if len(head) > len("DELIMITER ") && strings.EqualFold(head[:len("DELIMITER ")], "DELIMITER ") {
+		if fields := strings.Fields(head[len("DELIMITER "):]); len(fields) > 0 {
+			t.inBlock = fields[0] != ";"
+		}
+		t.boundary = !t.inBlock
+	} else {
+		t.boundary = !t.inBlock && strings.HasSuffix(strings.TrimRight(string(t.tail), " \t\r"), ";")
+	}
+	t.head, t.tail = t.head[:0], t.tail[:0]
+}
+
+func newExportSink(path string, opts ExportOptions, log func(format string, args ...interface{})) *exportSink {
+	return &exportSink{path: path, base: exportBaseName(path), opts: opts, log: log}
+}
+
+func (s *exportSink) nextPath(table string) string {
+	ext := ".sql" + compressionExt(s.opts.Compression)
+	switch s.opts.SplitMode {
+	case "size":
+		s.partNo++
+		return fmt.Sprintf("%s.part%03d%s", s.base, s.partNo, ext)
+	case "table":
+		// 替换特殊字符后不同表名可能重名（大小写不敏感的文件系统上也是），重名时追加序号
+		name := unsafeFileChars.ReplaceAllString(table, "_")
+		if s.names == nil {
+			s.names = map[string]bool{}
+		}
+		for i := 2; s.names[strings.ToLower(name)]; i++ {
+			name = fmt.Sprintf("%s_%d", unsafeFileChars.ReplaceAllString(table, "_"), i)
+		}
+		s.names[strings.ToLower(name)] = true
+		return fmt.Sprintf("%s.%s%s", s.base, name, ext)
+	default:
+		return s.path
+	}
+}
+
+func (s *exportSink) open(table string) error {
+	path := s.nextPath(table)
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	disk := &countingWriter{w: io.MultiWriter(f, h)}
+	part := &exportPart{path: path, table: table, file: f, hash: h, disk: disk}
+	switch s.opts.Compression {
+	case "gzip":
+		part.comp = gzip.NewWriter(disk)
+	case "zstd":
+		enc, err := zstd.NewWriter(disk)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		part.comp = enc
+	default:
+		part.comp = nopWriteCloser{disk}
+	}
+	s.cur = part
+	s.paths = append(s.paths, path)
+	s.log("写入文件：%s", filepath.Base(path))
+	return nil
+}
+
+// closePart 关闭当前文件并按需生成校验文件
+func (s *exportSink) closePart() error {
+	part := s.cur
+	if part == nil {
+		return nil
+	}
+	s.cur = nil
+	if err := part.comp.Close(); err != nil {
+		part.file.Close()
+		return err
+	}
+	if err := part.file.Close(); err != nil {
+		return err
+	}
+	info := ExportFile{File: filepath.Base(part.path), Table: part.table, Bytes: part.disk.n}
+	if s.opts.Checksum {
+		info.SHA256 = hex.EncodeToString(part.hash.Sum(nil))
+		sumPath := part.path + ".sha256"
+		line := fmt.Sprintf("%s  %s\n", info.SHA256, info.File)
+		if err := os.WriteFile(sumPath, []byte(line), 0o644); err != nil {
+			return err
+		}
+		s.paths = append(s.paths, sumPath)
+		s.log("校验文件：%s（sha256 %s）", filepath.Base(sumPath), info.SHA256)
+	}
+	s.files = append(s.files, info)
+	s.log("文件完成：%s，%d 字节", info.File, info.Bytes)
+	return nil
+}
+
+// BeginTable 按表分卷时为每张表切换到新文件
+func (s *exportSink) BeginTable(table string) error {
+	if s.opts.SplitMode != "table" {
+		return nil
+	}
+	if err := s.closePart(); err != nil {
+		return err
+	}
+	return s.open(table)
+}
+
+// Write 写入导出内容；按大小分卷时只在语句结束的行尾（DELIMITER 块之外）切换文件，
+// 保证单条语句不被拆开，每卷可单独恢复。
+// 分卷大小按已写入磁盘的（压缩后）字节数判断，压缩器内部缓冲的数据会在关闭时写出，单卷可能略超上限
+func (s *exportSink) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if s.cur == nil {
+			if err := s.open(""); err != nil {
+				return written, err
+			}
+		}
+		chunk := p
+		if s.opts.SplitMode == "size" {
+			if idx := bytes.IndexByte(p, '\n'); idx >= 0 {
+				chunk = p[:idx+1]
+			}
+		}
+		n, err := s.cur.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[len(chunk):]
+		if s.opts.SplitMode != "size" {
+			continue
+		}
+		s.stmt.write(chunk)
+		if s.stmt.boundary && s.cur.disk.n >= int64(s.opts.SplitSizeMB)*1024*1024 {
+			if err := s.closePart(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Finish 关闭最后一个文件，分卷时写出清单，返回给前端展示的主路径
+func (s *exportSink) Finish(database string, mode string, tables []string) (string, error) {
+	if s.cur == nil && len(s.files) == 0 && s.opts.SplitMode != "table" {
+		if err := s.open(""); err != nil {
+			return "", err
+		}
+	}
+	if err := s.closePart(); err != nil {
+		return "", err
+	}
+	if s.opts.SplitMode == "none" {
+		return s.path, nil
+	}
+	manifest := ExportManifest{
+		Database:    database,
+		Mode:        mode,
+		Compression: s.opts.Compression,
+		SplitMode:   s.opts.SplitMode,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+		Tables:      tables,
+		Files:       s.files,
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	manifestPath := s.base + ".manifest.json"
+	if err := os.WriteFile(manifestPath, data, 0o644); err != nil {
+		return "", err
+	}
+	s.paths = append(s.paths, manifestPath)
+	s.log("清单文件：%s（共 %d 个文件）", filepath.Base(manifestPath), len(s.files))
+	return manifestPath, nil
+}
+
+// Abort 出错时关闭已打开的文件
+func (s *exportSink) Abort() {
+	if s.cur != nil {
+		_ = s.cur.comp.Close()
+		_ = s.cur.file.Close()
+		s.cur = nil
+	}
+}
+
+// Cleanup 删除本次导出已生成的全部文件（取消导出时使用）
+func (s *exportSink) Cleanup() {
+	s.Abort()
+	for _, p := range s.paths {
+		_ = os.Remove(p)
+	}
+	s.paths = nil
+}
+
+// ExportProgress 导出进度事件（export-progress）
+type ExportProgress struct {
+	JobID         string  `json:"jobId"`
+	Status        string  `json:"status"` // running / success / failed / cancelled
+	Table         string  `json:"table"`
+	RowsWritten   int64   `json:"rowsWritten"`
+	RowsEstimated int64   `json:"rowsEstimated"`
+	BytesWritten  int64   `json:"bytesWritten"`
+	ElapsedSec    float64 `json:"elapsedSec"`
+	EtaSec        float64 `json:"etaSec"`
+	Message       string  `json:"message,omitempty"`
+}
+
+// newJobID 生成任务ID
+func newJobID() string {
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%s-%s", time.Now().Format("20060102150405"), hex.EncodeToString(buf))
+}
+
+// exportTracker 统计导出进度并节流推送到前端
+type exportTracker struct {
+	mu        sync.Mutex
+	jobID     string
+	emit      func(ExportProgress)
+	estimated int64
+	table     string
+	rows      int64
+	bytes     int64
+	start     time.Time
+	lastEmit  time.Time
+}
+
+func newExportTracker(jobID string, estimated int64, emit func(ExportProgress)) *exportTracker {
+	return &exportTracker{jobID: jobID, emit: emit, estimated: estimated, start: time.Now()}
+}
+
+func (t *exportTracker) snapshot(status string, msg string) ExportProgress {
+	elapsed := time.Since(t.start).Seconds()
+	p := ExportProgress{
+		JobID:         t.jobID,
+		Status:        status,
+		Table:         t.table,
+		RowsWritten:   t.rows,
+		RowsEstimated: t.estimated,
+		BytesWritten:  t.bytes,
+		ElapsedSec:    elapsed,
+		Message:       msg,
+	}
+	if t.rows > 0 && t.estimated > t.rows && elapsed > 0 {
+		rate := float64(t.rows) / elapsed
+		p.EtaSec = float64(t.estimated-t.rows) / rate
+	}
+	return p
+}
+
+func (t *exportTracker) update(table string, rows int64, bytes int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if table != "" {
+		t.table = table
+	}
+	t.rows += rows
+	t.bytes += bytes
+	if time.Since(t.lastEmit) < 500*time.Millisecond {
+		return
+	}
+	t.lastEmit = time.Now()
+	t.emit(t.snapshot("running", ""))
+}
+
+func (t *exportTracker) finish(status string, msg string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.emit(t.snapshot(status, msg))
+}
+
+// exportProgressWriter 位于导出内容与输出文件之间：
+// 取消后拒绝写入（mysqldump 进程由绑定的 ctx 结束），同时按行识别当前表并统计行数
+type exportProgressWriter struct {
+	ctx       context.Context
+	w         io.Writer
+	tracker   *exportTracker
+	lineStart bool
+	head      []byte
+	decided   bool
+	inInsert  bool
+	pending   int64
+	tail      []byte
+}
+
+func newExportProgressWriter(ctx context.Context, w io.Writer, tracker *exportTracker) *exportProgressWriter {
+	return &exportProgressWriter{ctx: ctx, w: w, tracker: tracker, lineStart: true}
+}
+
+var rowSeparator = []byte("),(")
+
+func (p *exportProgressWriter) Write(b []byte) (int, error) {
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.w.Write(b)
+	data := b[:n]
+
+	var table string
+	var rows int64
+	for len(data) > 0 {
+		if p.lineStart {
+			p.head = p.head[:0]
+			p.decided = false
+			p.inInsert = false
+			p.pending = 0
+			p.tail = p.tail[:0]
+			p.lineStart = false
+		}
+		line := data
+		idx := bytes.IndexByte(data, '\n')
+		if idx >= 0 {
+			line = data[:idx]
+		}
+		if !p.decided || p.inInsert {
+			// 扩展插入的每个 "),(" 代表多一行；行首未识别完之前先暂存计数
+			joined := append(p.tail, line...)
+			cnt := int64(bytes.Count(joined, rowSeparator))
+			if p.decided {
+				rows += cnt
+			} else {
+				p.pending += cnt
+			}
+			if len(joined) >= 2 {
+				p.tail = append(p.tail[:0], joined[len(joined)-2:]...)
+			} else {
+				p.tail = append(p.tail[:0], joined...)
+			}
+		}
+		if !p.decided {
+			need := 128 - len(p.head)
+			if need > len(line) {
+				need = len(line)
+			}
+			p.head = append(p.head, line[:need]...)
+			if len(p.head) >= 128 || idx >= 0 {
+				p.decided = true
+				if name := extractTableName(string(p.head)); name != "" {
+					table = name
+					if bytes.HasPrefix(bytes.ToUpper(p.head), []byte("INSERT")) {
+						p.inInsert = true
+						rows += p.pending + 1
+					}
+				}
+			}
+		}
+		if idx < 0 {
+			break
+		}
+		p.lineStart = true
+		data = data[idx+1:]
+	}
+	p.tracker.update(table, rows, int64(n))
+	return n, err
+}
+
+// CancelExport 取消正在运行的导出任务：结束 mysqldump 进程，待其退出后删除未完成的文件
+func (a *App) CancelExport(id string) error {
+	job, ok := a.jobs.get(id)
+	if !ok || job.Kind != "export" {
+		return fmt.Errorf("未找到导出任务: %s", id)
+	}
+	return a.jobs.cancel(id)
+}
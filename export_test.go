@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStatementTrackerBoundaries(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []bool // 每个分片写入后是否处于语句边界
+	}{
+		{"single line statement", []string{"INSERT INTO `t` VALUES (1);\n"}, []bool{true}},
+		{"trailing spaces", []string{"SET NAMES utf8mb4 ; \r\n"}, []bool{true}},
+		{"multi-line create table", []string{"CREATE TABLE `t` (\n", "  `id` int,\n", "  PRIMARY KEY (`id`)\n", ") ENGINE=InnoDB;\n"}, []bool{false, false, false, true}},
+		{"comment line", []string{"-- Dumping data for table `t`\n"}, []bool{false}},
+		{"line split across writes", []string{"INSERT INTO `t` VALUES ", "(1),(2)", ";\n"}, []bool{false, false, true}},
+		{"long line", []string{"INSERT INTO `t` VALUES (" + strings.Repeat("1,", 100) + "1);\n"}, []bool{true}},
+		{"delimiter block", []string{
+			"DELIMITER ;;\n",
+			"CREATE PROCEDURE `p`()\n",
+			"BEGIN\n",
+			"  SELECT 1;\n",
+			"END ;;\n",
+			"DELIMITER ;\n",
+			"SELECT 1;\n",
+		}, []bool{false, false, false, false, false, true, true}},
+		{"conditional trigger", []string{
+			"/*!50003 SET @saved_cs_client = @@character_set_client */ ;\n",
+			"DELIMITER ;;\n",
+			"/*!50003 CREATE*/ /*!50003 TRIGGER `tr` BEFORE INSERT ON `t` FOR EACH ROW SET NEW.a = 1; */;;\n",
+			"DELIMITER ;\n",
+		}, []bool{true, false, false, true}},
+		{"several lines in one write", []string{"SELECT 1;\nSELECT\n", "2;\n"}, []bool{false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tr statementTracker
+			for i, chunk := range tt.chunks {
+				tr.write([]byte(chunk))
+				if tr.boundary != tt.want[i] {
+					t.Fatalf("after chunk %d %q boundary = %v, want %v", i, chunk, tr.boundary, tt.want[i])
+				}
+			}
+		})
+	}
+}
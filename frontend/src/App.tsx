@@ -6,15 +6,16 @@ import './App.css';
 import {
   Layout, Menu, Button, Modal, Form, Input,
   Table, Card, Space, Typography, message,
-  Divider, Tooltip, Tabs, InputNumber, Select, Switch,
-  Radio
+  Divider, Tooltip, Tabs, InputNumber, Select, Switch, Checkbox,
+  Radio, Tag
 } from 'antd';
 import {
   PlusOutlined, DatabaseOutlined, ConsoleSqlOutlined,
   ReloadOutlined, DesktopOutlined,
   TableOutlined, EditOutlined, DeleteOutlined,
   ExclamationCircleOutlined, ThunderboltOutlined, CaretRightOutlined,
-  FileTextOutlined
+  FileTextOutlined, HistoryOutlined, AlignLeftOutlined, CompressOutlined, AuditOutlined,
+  EyeInvisibleOutlined, UserOutlined
 } from '@ant-design/icons';
 import mysqlLogo from './assets/images/mysql.svg';
 import { EventsOn } from '../wailsjs/runtime/runtime';
@@ -28,7 +29,13 @@ import {
   GetSavedConnections,
   SaveConnection,
   GetDatabases,
-  GetColumns,
+  LoadMetadata,
+  CheckStatement,
+  ConfirmTaskTarget,
+  QueryAuditLog,
+  VerifyAuditLog,
+  ExportAuditLog,
+  Suggest,
   GetTables,
   GetViews,
   UpdateConnection,
@@ -36,6 +43,22 @@ import {
   TestConnection,
   TestConnectionConfig,
   SaveExcelFromJSON,
+  SaveCSVFromJSON,
+  GetMaskingRules,
+  SaveMaskingRules,
+  PreviewMask,
+  GetEditableResult,
+  PreviewGridChanges,
+  ApplyGridChanges,
+  LoadTableModel,
+  PreviewTableDDL,
+  ExecuteTableDDL,
+  ListAccounts,
+  GetAccountGrants,
+  ListGrantablePrivileges,
+  PreviewAccountChange,
+  ApplyAccountChange,
+  GetPrivilegeMatrix,
   ExportSqlDump,
   GetProcessList,
   GetAppSettings,
@@ -43,7 +66,27 @@ import {
   GetDatabasesForConfig,
   GetTableStats,
   SaveAppSettings,
-  SyncDatabase
+  SyncDatabase,
+  ResumeSync,
+  VerifyChecksum,
+  CompareTableData,
+  CompareSchemas,
+  DryRunSync,
+  StartCDC,
+  PauseCDC,
+  ResumeCDC,
+  StopCDC,
+  ListCDC,
+  DeleteCDC,
+  ListBinlogFiles,
+  BrowseBinlog,
+  SelectBinlogFiles,
+  FormatSQL,
+  SaveTextFile,
+  ListSyncCheckpoints,
+  DeleteSyncCheckpoint,
+  GetOracleTypeMappings,
+  CancelExport
 } from '../wailsjs/go/main/App';
 const { Sider, Content, Header } = Layout;
 const { Text, Title } = Typography;
@@ -81,13 +124,15 @@ type QueryTab = {
   columns: any[];
   data: any[];
   loading: boolean;
-  kind?: 'query' | 'ddl' | 'session' | 'migration';
+  kind?: 'query' | 'ddl' | 'session' | 'migration' | 'binlog' | 'audit' | 'users';
   content?: string;
   durationMs?: number;
   connId?: string;
   dbName?: string;
   migration?: MigrationState;
-  results?: Array<{ key: string; title: string; columns: any[]; data: any[]; durationMs?: number }>;
+  results?: Array<{ key: string; title: string; columns: any[]; data: any[]; durationMs?: number; sql?: string; masked?: string[] }>;
+  resultSql?: string;
+  masked?: string[];
   activeResultKey?: string;
 };
 
@@ -112,6 +157,155 @@ type MigrationState = {
   check: Array<{ name: string; sourceRows: number; targetRows: number; status: string }>;
 };
 
+type EditableInfo = {
+  editable: boolean;
+  reason?: string;
+  schema: string;
+  table: string;
+  key: string[];
+  keyName: string;
+  columns: Array<{ name: string; column: string; type: string; nullable: boolean; editable: boolean; masked?: boolean }>;
+};
+
+type GridEditRow = {
+  key: string;
+  status: 'clean' | 'modified' | 'new' | 'deleted';
+  original: Record<string, any>;
+  values: Record<string, any>;
+};
+
+// 结果表格的编辑状态，同一时间只编辑一个结果
+type GridEditState = {
+  tabKey: string;
+  resultKey: string;
+  info: EditableInfo;
+  rows: GridEditRow[];
+};
+
+type MaskingRule = {
+  id: string;
+  name: string;
+  enabled: boolean;
+  connection: string;
+  schema: string;
+  table: string;
+  column: string;
+  strategy: 'partial' | 'hash' | 'null' | 'fixed';
+  keepPrefix: number;
+  keepSuffix: number;
+  maskChar: string;
+  fixedValue: string;
+};
+
+type DesignColumn = {
+  key: string;
+  name: string;
+  originalName: string;
+  type: string;
+  nullable: boolean;
+  default: string | null;
+  defaultIsExpr: boolean;
+  autoIncrement: boolean;
+  onUpdate: string;
+  charset: string;
+  collation: string;
+  generated: string;
+  stored: boolean;
+  comment: string;
+};
+
+// 索引列在界面上以文本编辑，如 name(10), user_id DESC
+type DesignIndex = {
+  key: string;
+  name: string;
+  originalName: string;
+  kind: 'primary' | 'unique' | 'index' | 'fulltext' | 'spatial';
+  columns: Array<{ name: string; length: number; desc: boolean }>;
+  columnsText: string;
+  comment: string;
+};
+
+type DesignForeignKey = {
+  key: string;
+  name: string;
+  columns: string[];
+  refSchema: string;
+  refTable: string;
+  refColumns: string[];
+  onDelete: string;
+  onUpdate: string;
+};
+
+type DesignCheck = {
+  key: string;
+  name: string;
+  expr: string;
+  notEnforced: boolean;
+};
+
+type TableDesignModel = {
+  schema: string;
+  name: string;
+  originalName: string;
+  engine: string;
+  charset: string;
+  collation: string;
+  autoIncrement: number;
+  originalAutoIncrement: number;
+  comment: string;
+  partition: string;
+  columns: DesignColumn[];
+  indexes: DesignIndex[];
+  foreignKeys: DesignForeignKey[];
+  checks: DesignCheck[];
+};
+
+type TableDesignerState = {
+  conn: DBConfig;
+  db: string;
+  model: TableDesignModel;
+  online: boolean;
+  ddl: { create: boolean; statements: string[]; script: string; warnings: string[] } | null;
+};
+
+type DBAccount = {
+  user: string;
+  host: string;
+  isRole: boolean;
+  locked: boolean;
+  passwordExpired: boolean;
+  plugin: string;
+  status: string;
+  defaultTablespace: string;
+  created: string;
+};
+
+// 账号与权限变更，action 见后端 AccountChange
+type AccountChange = {
+  action: string;
+  user?: string;
+  host?: string;
+  password?: string;
+  defaultTablespace?: string;
+  cascade?: boolean;
+  role?: string;
+  level?: string;
+  schema?: string;
+  table?: string;
+  columns?: string[];
+  privileges?: string[];
+  withGrantOption?: boolean;
+};
+
+type PrivilegeEntry = {
+  level: string;
+  schema: string;
+  table: string;
+  columns: string[] | null;
+  privilege: string;
+  grantable: boolean;
+};
+
 const App: React.FC = () => {
   // --- 状态管理 ---
   const [isModalOpen, setIsModalOpen] = useState(false);
@@ -130,11 +324,26 @@ const App: React.FC = () => {
   const [exportTables, setExportTables] = useState<string[]>([]);
   const [exportSearch, setExportSearch] = useState('');
   const [exportMode, setExportMode] = useState<'schema' | 'data' | 'both'>('schema');
+  const [exportOptions, setExportOptions] = useState<{ compression: 'none' | 'gzip' | 'zstd'; splitMode: 'none' | 'size' | 'table'; splitSizeMB: number; checksum: boolean }>({
+    compression: 'none',
+    splitMode: 'none',
+    splitSizeMB: 1024,
+    checksum: false
+  });
   const [exportLogs, setExportLogs] = useState<string[]>([]);
+  const [exportProgress, setExportProgress] = useState<{ jobId: string; status: string; table: string; rowsWritten: number; rowsEstimated: number; bytesWritten: number; etaSec: number } | null>(null);
   const exportLogRef = useRef<HTMLDivElement | null>(null);
-  const [appSettings, setAppSettings] = useState<{ mysqldumpPath: string }>({ mysqldumpPath: '' });
+  const [appSettings, setAppSettings] = useState<{ mysqldumpPath: string; [key: string]: any }>({ mysqldumpPath: '' });
   const [sessionRows, setSessionRows] = useState<any[]>([]);
   const [sessionLoading, setSessionLoading] = useState(false);
+  const [binlogFiles, setBinlogFiles] = useState<Array<{ name: string; size: number }>>([]);
+  const [binlogQuery, setBinlogQuery] = useState<{ source: 'server' | 'file'; files: string[]; startFile: string; startPos: number; endFile: string; startTime: string; endTime: string; databases: string; tables: string; eventTypes: string[]; maxEvents: number }>({ source: 'server', files: [], startFile: '', startPos: 0, endFile: '', startTime: '', endTime: '', databases: '', tables: '', eventTypes: [], maxEvents: 1000 });
+  const [binlogResult, setBinlogResult] = useState<any>(null);
+  const [binlogLoading, setBinlogLoading] = useState(false);
+  const [auditFilter, setAuditFilter] = useState<{ from: string; to: string; action: string; outcome: string; keyword: string; limit: number; offset: number }>({ from: '', to: '', action: '', outcome: '', keyword: '', limit: 200, offset: 0 });
+  const [auditResult, setAuditResult] = useState<{ entries: any[]; total: number }>({ entries: [], total: 0 });
+  const [auditVerify, setAuditVerify] = useState<any>(null);
+  const [auditLoading, setAuditLoading] = useState(false);
   const [sessionCommand, setSessionCommand] = useState<string | undefined>(undefined);
   const [sessionUser, setSessionUser] = useState<string | undefined>(undefined);
   const [sessionDb, setSessionDb] = useState<string | undefined>(undefined);
@@ -146,8 +355,39 @@ const App: React.FC = () => {
   const [migrationTargetConn, setMigrationTargetConn] = useState<string>('');
   const [migrationTargetDb, setMigrationTargetDb] = useState<string>('');
   const [migrationMode, setMigrationMode] = useState<'schema' | 'data' | 'both'>('both');
+  const [migrationOptions, setMigrationOptions] = useState<{ workers: number; batchSize: number; chunkRows: number; onTableExists: string; onDataExists: string; verify: boolean; applyMasking: boolean }>({ workers: 4, batchSize: 1000, chunkRows: 1000000, onTableExists: 'fail', onDataExists: 'fail', verify: false, applyMasking: false });
+  const [typeMappingText, setTypeMappingText] = useState('');
+  const [syncPlanScript, setSyncPlanScript] = useState('');
+  const [cdcTasks, setCdcTasks] = useState<any[]>([]);
+  const [cdcSkipCopy, setCdcSkipCopy] = useState(false);
+  const [isMaskingOpen, setIsMaskingOpen] = useState(false);
+  const [maskingRules, setMaskingRules] = useState<MaskingRule[]>([]);
+  const [maskSample, setMaskSample] = useState('13812345678');
+  const [maskPreview, setMaskPreview] = useState<Record<string, string>>({});
+  const [gridEdit, setGridEdit] = useState<GridEditState | null>(null);
+  const [gridSaving, setGridSaving] = useState(false);
+  const [tableDesigner, setTableDesigner] = useState<TableDesignerState | null>(null);
+  const [designerBusy, setDesignerBusy] = useState(false);
+  const [accounts, setAccounts] = useState<DBAccount[]>([]);
+  const [accountsLoading, setAccountsLoading] = useState(false);
+  const [accountFilter, setAccountFilter] = useState('');
+  const [selectedAccount, setSelectedAccount] = useState<DBAccount | null>(null);
+  const [accountGrants, setAccountGrants] = useState<{ grants: string[] | null; roles: string[] | null; privileges: PrivilegeEntry[] | null } | null>(null);
+  const [grantForm, setGrantForm] = useState<{ level: string; schema: string; table: string; columns: string; privileges: string[]; withGrantOption: boolean }>({ level: 'database', schema: '', table: '', columns: '', privileges: [], withGrantOption: false });
+  const [grantablePrivs, setGrantablePrivs] = useState<string[]>([]);
+  const [grantRoleName, setGrantRoleName] = useState<string | undefined>(undefined);
+  const [accountModal, setAccountModal] = useState<{ action: 'createUser' | 'createRole' | 'password'; user: string; host: string; password: string; defaultTablespace: string } | null>(null);
+  const [privMatrix, setPrivMatrix] = useState<{ schema: string; privileges: string[]; rows: Array<{ user: string; host: string; level: string; object: string; privileges: Record<string, boolean> }> | null } | null>(null);
+  const [matrixSchema, setMatrixSchema] = useState<string | undefined>(undefined);
+  const [matrixLoading, setMatrixLoading] = useState(false);
+  const [nameMapping, setNameMapping] = useState({ tableRules: '', tableCase: '', columnCase: '', tables: '' });
+  const [oracleTypeRules, setOracleTypeRules] = useState<Array<{ source: string; target: string; note: string }>>([]);
   const [migrationLoading, setMigrationLoading] = useState(false);
-  const [migrationCheck, setMigrationCheck] = useState<Array<{ name: string; sourceRows: number; targetRows: number; status: string }>>([]);
+  const [migrationCheck, setMigrationCheck] = useState<Array<{ name: string; sourceRows: number; targetRows: number; status: string; resumed?: boolean; resumedRows?: number; note?: string; tablePolicy?: string; dataPolicy?: string; inserted?: number; updated?: number; skipped?: number; checksum?: string; target?: string; actions?: string[]; ddl?: string[] }>>([]);
+  const [dataDiff, setDataDiff] = useState<{ table: string; missing: number; extra: number; changed: number; truncated: boolean; filePath?: string; rows: Array<{ kind: string; key: string; columns: string[]; sql: string }> } | null>(null);
+  const [schemaDiffOptions, setSchemaDiffOptions] = useState({ ignoreAutoIncrement: true, ignoreComments: false, ignoreCharset: false, ignoreCollation: false });
+  const [schemaDiff, setSchemaDiff] = useState<{ items: Array<{ objectType: string; table: string; name: string; action: string; source: string; target: string }>; script: string } | null>(null);
+  const [syncCheckpoints, setSyncCheckpoints] = useState<Array<{ jobId: string; targetId: string; sourceDb: string; targetDb: string; mode: string; tablesDone: number; tablesAll: number; updatedAt: string }>>([]);
   const [migrationSources, setMigrationSources] = useState<Record<string, string[]>>({});
   const [migrationTargets, setMigrationTargets] = useState<Record<string, string[]>>({});
   const [sessionAuto, setSessionAuto] = useState(false);
@@ -166,10 +406,8 @@ const App: React.FC = () => {
   const tabSeq = useRef(1);
   const editorRef = useRef<Monaco.editor.IStandaloneCodeEditor | null>(null);
   const monacoRef = useRef<typeof Monaco | null>(null);
-  const suggestionRef = useRef<{ tables: string[]; columns: Array<{ table: string; column: string }> }>({
-    tables: [],
-    columns: []
-  });
+  const formatSqlRef = useRef<(minify: boolean) => void>(() => {});
+  const suggestContextRef = useRef<{ conn?: DBConfig; db?: string }>({});
   const completionRegistered = useRef(false);
 
   const [form] = Form.useForm();
@@ -243,7 +481,7 @@ const App: React.FC = () => {
   const loadAppSettings = async () => {
     try {
       const res = await GetAppSettings();
-      setAppSettings({ mysqldumpPath: res?.mysqldumpPath || '' });
+      setAppSettings({ ...res, mysqldumpPath: res?.mysqldumpPath || '' });
     } catch (err) {
       message.error('获取设置失败');
     }
@@ -276,12 +514,7 @@ const App: React.FC = () => {
     try {
       await ConnectDBConfig(conn);
       await switchDatabase(conn, dbName);
-      const cols = await GetColumns(dbName);
-      const tables = await GetTables(dbName);
-      suggestionRef.current = {
-        tables: (tables || []).map(t => t.name),
-        columns: (cols || []).map(c => ({ table: c.table, column: c.column }))
-      };
+      await LoadMetadata(conn, dbName, false);
     } catch (err) {
       message.error('初始化 SQL 窗口失败: ' + err);
     }
@@ -366,22 +599,6 @@ const App: React.FC = () => {
 
 
 
-  const parseAliasMap = (sqlText: string) => {
-    const map: Record<string, string> = {};
-    const cleaned = sqlText.replace(/\s+/g, ' ');
-    const regex = /\b(from|join)\s+(?:"([^"]+)"|`([^`]+)`|([a-zA-Z0-9_$#]+))(?:\s+as)?\s+(?:"([^"]+)"|`([^`]+)`|([a-zA-Z0-9_$#]+))/gi;
-    let match: RegExpExecArray | null;
-    while ((match = regex.exec(cleaned)) !== null) {
-      const table = match[2] || match[3] || match[4];
-      const alias = match[5] || match[6] || match[7];
-      if (alias && alias !== table) {
-        map[alias] = table;
-      }
-    }
-    return map;
-  };
-
-
   // 1. 保存新连接 / 更新连接
   const handleSaveConnection = async (values: any) => {
     try {
@@ -409,7 +626,7 @@ const App: React.FC = () => {
   const openCreateModal = () => {
     setEditingConn(null);
     form.resetFields();
-    form.setFieldsValue({ type: 'mysql', port: 3306 });
+    form.setFieldsValue({ type: 'mysql', port: 3306, environment: 'dev', readOnly: false });
     setIsModalOpen(true);
   };
 
@@ -422,7 +639,9 @@ const App: React.FC = () => {
       port: conn.port,
       user: conn.user,
       password: conn.password,
-      database: conn.database
+      database: conn.database,
+      environment: conn.environment || 'dev',
+      readOnly: !!conn.readOnly
     });
     setIsManagerOpen(false);
     setIsModalOpen(true);
@@ -523,13 +742,9 @@ const App: React.FC = () => {
       await switchDatabase(conn, dbName);
       updateActiveTab({ connId: conn.id, dbName });
       message.info(`当前数据库: ${dbName}`);
-      const cols = await GetColumns(dbName);
       const tables = await GetTables(dbName);
       const views = await GetViews(dbName);
-      suggestionRef.current = {
-        tables: (tables || []).map(t => t.name),
-        columns: (cols || []).map(c => ({ table: c.table, column: c.column }))
-      };
+      LoadMetadata(conn, dbName, false).catch(() => {});
       setTableList(prev => ({
         ...prev,
         [conn.id]: {
@@ -555,6 +770,7 @@ const App: React.FC = () => {
       await switchDatabase(conn, dbName);
       const tables = await GetTables(dbName);
       const views = await GetViews(dbName);
+      LoadMetadata(conn, dbName, true).catch(() => {});
       setTableList(prev => ({
         ...prev,
         [conn.id]: {
@@ -651,7 +867,369 @@ const App: React.FC = () => {
     fetchSessions(next.connId);
   };
 
+  const openBinlogTab = () => {
+    const existing = queryTabs.find(tab => tab.kind === 'binlog');
+    if (existing) {
+      setActiveTabKey(existing.key);
+      return;
+    }
+    const key = `binlog-${Date.now()}`;
+    const next: QueryTab = {
+      key,
+      title: 'Binlog 闪回',
+      sql: '',
+      columns: [],
+      data: [],
+      loading: false,
+      kind: 'binlog',
+      content: '',
+      connId: activeConn?.id,
+      dbName: currentDb || undefined
+    };
+    setQueryTabs(prev => {
+      const filtered = prev.filter(tab => tab.kind !== 'query' || tab.sql !== '');
+      return [...filtered, next];
+    });
+    setActiveTabKey(key);
+    loadBinlogFiles(next.connId);
+  };
+
+  const openAuditTab = () => {
+    const existing = queryTabs.find(tab => tab.kind === 'audit');
+    if (existing) {
+      setActiveTabKey(existing.key);
+      loadAuditLog();
+      return;
+    }
+    const key = `audit-${Date.now()}`;
+    const next: QueryTab = {
+      key,
+      title: '审计日志',
+      sql: '',
+      columns: [],
+      data: [],
+      loading: false,
+      kind: 'audit',
+      content: ''
+    };
+    setQueryTabs(prev => {
+      const filtered = prev.filter(tab => tab.kind !== 'query' || tab.sql !== '');
+      return [...filtered, next];
+    });
+    setActiveTabKey(key);
+    loadAuditLog();
+  };
+
+  // --- 用户与权限 ---
+  const connectUsersTab = async (connId?: string) => {
+    const id = connId || queryTabs.find(tab => tab.kind === 'users')?.connId;
+    const conn = connections.find(c => c.id === id);
+    if (conn) {
+      await ConnectDBConfig(conn);
+    }
+    return conn;
+  };
+
+  const openUsersTab = (conn: DBConfig) => {
+    const existing = queryTabs.find(tab => tab.kind === 'users');
+    const title = `用户与权限 - ${conn.name}`;
+    let key = existing?.key || `users-${Date.now()}`;
+    if (existing) {
+      setQueryTabs(prev => prev.map(tab => (tab.key === key ? { ...tab, title, connId: conn.id } : tab)));
+    } else {
+      const next: QueryTab = {
+        key,
+        title,
+        sql: '',
+        columns: [],
+        data: [],
+        loading: false,
+        kind: 'users',
+        content: '',
+        connId: conn.id
+      };
+      setQueryTabs(prev => {
+        const filtered = prev.filter(tab => tab.kind !== 'query' || tab.sql !== '');
+        return [...filtered, next];
+      });
+    }
+    setActiveTabKey(key);
+    if (existing?.connId !== conn.id) {
+      setSelectedAccount(null);
+      setAccountGrants(null);
+      setPrivMatrix(null);
+      setMatrixSchema(undefined);
+    }
+    const level = normalizeConnType(conn.type) === 'oracle' && grantForm.level === 'database' ? 'table' : grantForm.level;
+    setGrantForm(prev => ({ ...prev, level, privileges: [] }));
+    loadAccounts(conn.id).then(() => loadGrantablePrivs(level, conn.id));
+  };
+
+  const loadAccounts = async (connId?: string) => {
+    setAccountsLoading(true);
+    try {
+      const conn = await connectUsersTab(connId);
+      const list = await ListAccounts();
+      setAccounts((list || []) as DBAccount[]);
+      if (conn && !dbList[conn.id]) {
+        const dbs = await GetDatabases();
+        setDbList(prev => ({ ...prev, [conn.id]: dbs || [] }));
+      }
+    } catch (err) {
+      message.error('读取账号失败: ' + err);
+    } finally {
+      setAccountsLoading(false);
+    }
+  };
+
+  const selectAccount = async (acc: DBAccount) => {
+    setSelectedAccount(acc);
+    setAccountGrants(null);
+    try {
+      await connectUsersTab();
+      const grants = await GetAccountGrants(acc.user, acc.host);
+      setAccountGrants(grants as any);
+    } catch (err) {
+      message.error('读取授权失败: ' + err);
+    }
+  };
+
+  const loadGrantablePrivs = async (level: string, connId?: string) => {
+    try {
+      await connectUsersTab(connId);
+      const list = await ListGrantablePrivileges(level);
+      setGrantablePrivs(list || []);
+    } catch (err) {
+      setGrantablePrivs([]);
+    }
+  };
+
+  // 预览语句并确认后执行；生产环境再经过危险语句确认
+  const runAccountChange = async (change: AccountChange, successText: string) => {
+    try {
+      await connectUsersTab();
+      const preview = await PreviewAccountChange(change as any);
+      const ok = await new Promise<boolean>(resolve => {
+        Modal.confirm({
+          title: '确认执行以下语句？',
+          width: 640,
+          okText: '执行',
+          cancelText: '取消',
+          content: <pre className="grid-preview-sql">{preview.script}</pre>,
+          onOk: () => resolve(true),
+          onCancel: () => resolve(false)
+        });
+      });
+      if (!ok) return false;
+      const token = await confirmGuard(preview.script);
+      if (token === null) return false;
+      await ApplyAccountChange(change as any, token);
+      message.success(successText);
+      await loadAccounts();
+      if (selectedAccount) {
+        const dropped = (change.action === 'dropUser' || change.action === 'dropRole')
+          && (change.user || change.role) === selectedAccount.user && (change.action === 'dropRole' || (change.host || '%') === (selectedAccount.host || '%'));
+        if (dropped) {
+          setSelectedAccount(null);
+          setAccountGrants(null);
+        } else {
+          selectAccount(selectedAccount);
+        }
+      }
+      return true;
+    } catch (err) {
+      message.error('执行失败: ' + err);
+      return false;
+    }
+  };
+
+  const dropAccount = (acc: DBAccount, isOracle: boolean) => {
+    if (acc.isRole) {
+      runAccountChange({ action: 'dropRole', role: isOracle ? acc.user : `${acc.user}@${acc.host}` }, `角色 ${acc.user} 已删除`);
+      return;
+    }
+    if (!isOracle) {
+      runAccountChange({ action: 'dropUser', user: acc.user, host: acc.host }, `用户 ${acc.user}@${acc.host} 已删除`);
+      return;
+    }
+    let cascade = false;
+    Modal.confirm({
+      title: `删除用户 ${acc.user}`,
+      okText: '下一步',
+      cancelText: '取消',
+      content: <Checkbox onChange={e => { cascade = e.target.checked; }}>同时删除该用户的所有对象 (CASCADE)</Checkbox>,
+      onOk: () => { runAccountChange({ action: 'dropUser', user: acc.user, cascade }, `用户 ${acc.user} 已删除`); }
+    });
+  };
+
+  const submitAccountModal = async () => {
+    if (!accountModal) return;
+    const m = accountModal;
+    const change: AccountChange = m.action === 'createRole'
+      ? { action: 'createRole', role: m.user }
+      : { action: m.action, user: m.user, host: m.host, password: m.password, defaultTablespace: m.defaultTablespace };
+    const text = m.action === 'createRole' ? `角色 ${m.user} 已创建` : m.action === 'createUser' ? `用户 ${m.user} 已创建` : '密码已修改';
+    if (await runAccountChange(change, text)) {
+      setAccountModal(null);
+    }
+  };
+
+  const submitGrant = (action: 'grant' | 'revoke') => {
+    if (!selectedAccount) return;
+    runAccountChange({
+      action,
+      user: selectedAccount.user,
+      host: selectedAccount.host,
+      level: grantForm.level,
+      schema: grantForm.schema,
+      table: grantForm.table,
+      columns: splitNames(grantForm.columns),
+      privileges: grantForm.privileges,
+      withGrantOption: grantForm.withGrantOption
+    }, action === 'grant' ? '已授权' : '已回收');
+  };
+
+  const revokeEntry = (p: PrivilegeEntry) => {
+    if (!selectedAccount) return;
+    runAccountChange({
+      action: 'revoke',
+      user: selectedAccount.user,
+      host: selectedAccount.host,
+      level: p.level,
+      schema: p.schema,
+      table: p.table,
+      columns: p.columns || [],
+      privileges: [p.privilege]
+    }, '已回收');
+  };
+
+  const loadPrivilegeMatrix = async (schema?: string) => {
+    if (!schema) return;
+    setMatrixLoading(true);
+    try {
+      await connectUsersTab();
+      const m = await GetPrivilegeMatrix(schema);
+      setPrivMatrix(m as any);
+    } catch (err) {
+      message.error('读取权限矩阵失败: ' + err);
+    } finally {
+      setMatrixLoading(false);
+    }
+  };
+
+  const loadAuditLog = async (filter = auditFilter) => {
+    setAuditLoading(true);
+    try {
+      const res = await QueryAuditLog(filter as any);
+      setAuditResult({ entries: res?.entries || [], total: res?.total || 0 });
+    } catch (err) {
+      message.error('读取审计日志失败: ' + err);
+    } finally {
+      setAuditLoading(false);
+    }
+  };
+
+  const runVerifyAuditLog = async () => {
+    try {
+      const res = await VerifyAuditLog();
+      setAuditVerify(res);
+      if (res.ok) {
+        message.success(res.message);
+      } else {
+        message.error(res.message);
+      }
+    } catch (err) {
+      message.error('校验审计日志失败: ' + err);
+    }
+  };
+
+  const exportAuditLog = async (format: 'csv' | 'jsonl') => {
+    try {
+      const path = await ExportAuditLog(auditFilter as any, format);
+      if (path) {
+        message.success(`已导出到 ${path}`);
+      }
+    } catch (err) {
+      message.error('导出审计日志失败: ' + err);
+    }
+  };
+
+  const saveAuditRetention = async (days: number) => {
+    const next = { ...appSettings, auditRetentionDays: days };
+    try {
+      await SaveAppSettings(next as any);
+      setAppSettings(next);
+      message.success(days > 0 ? `审计日志保留 ${days} 天` : '审计日志永久保留');
+    } catch (err) {
+      message.error('保存设置失败: ' + err);
+    }
+  };
+
+  const loadBinlogFiles = async (connId?: string) => {
+    const conn = connections.find(c => c.id === connId);
+    if (!conn || normalizeConnType(conn.type) !== 'mysql') return;
+    try {
+      const files = (await ListBinlogFiles(conn)) || [];
+      setBinlogFiles(files);
+      if (files.length > 0) {
+        setBinlogQuery(prev => ({ ...prev, startFile: prev.startFile || files[files.length - 1].name }));
+      }
+    } catch (err) {
+      message.error('读取 binlog 列表失败: ' + err);
+    }
+  };
+
+  const chooseBinlogFiles = async () => {
+    try {
+      const files = await SelectBinlogFiles();
+      if (files && files.length > 0) {
+        setBinlogQuery(prev => ({ ...prev, source: 'file', files }));
+      }
+    } catch (err) {
+      message.error('选择文件失败: ' + err);
+    }
+  };
+
+  // 按条件读取 binlog 行变更，生成正向与回滚 SQL
+  const runBrowseBinlog = async (connId?: string) => {
+    const conn = connections.find(c => c.id === connId);
+    if (binlogQuery.source === 'server' && !conn) {
+      message.warning('请先选择MySQL连接');
+      return;
+    }
+    const split = (v: string) => v.split(/[,\s]+/).map(x => x.trim()).filter(Boolean);
+    setBinlogLoading(true);
+    try {
+      const res: any = await BrowseBinlog((conn || {}) as any, {
+        ...binlogQuery,
+        databases: split(binlogQuery.databases),
+        tables: split(binlogQuery.tables)
+      } as any);
+      setBinlogResult(res);
+      (res.warnings || []).forEach((w: string) => message.warning(w));
+      message.success(`共 ${res.changes?.length || 0} 行变更` + (res.truncated ? '（已达上限，结果不完整）' : ''));
+    } catch (err) {
+      message.error('读取 binlog 失败: ' + err);
+    } finally {
+      setBinlogLoading(false);
+    }
+  };
+
+  const saveBinlogScript = async (kind: 'forward' | 'flashback') => {
+    if (!binlogResult) return;
+    try {
+      const content = kind === 'forward' ? binlogResult.forwardScript : binlogResult.flashbackScript;
+      const path = await SaveTextFile(`binlog_${kind}.sql`, content || '');
+      if (path) {
+        message.success('已保存到 ' + path);
+      }
+    } catch (err) {
+      message.error('保存失败: ' + err);
+    }
+  };
+
   const openMigrationTab = () => {
+    loadSyncCheckpoints();
     const existing = queryTabs.find(tab => tab.kind === 'migration');
     if (existing) {
       setActiveTabKey(existing.key);
@@ -756,10 +1334,12 @@ const App: React.FC = () => {
       const targetRows = target ? target.rows : 0;
       const reasons: string[] = [];
 
-      if ((migrationMode === 'schema' || migrationMode === 'both') && target) {
+      const tableConflict = (migrationMode === 'schema' || migrationMode === 'both') && target;
+      if (tableConflict && migrationOptions.onTableExists === 'fail') {
         reasons.push('目标已存在同名表');
       }
-      if ((migrationMode === 'data' || migrationMode === 'both') && target && targetRows > 0) {
+      const dataKept = !tableConflict || migrationOptions.onTableExists === 'keep';
+      if ((migrationMode === 'data' || migrationMode === 'both') && target && targetRows > 0 && dataKept && migrationOptions.onDataExists === 'fail') {
         reasons.push('目标表有数据');
       }
       if (!target) {
@@ -786,67 +1366,382 @@ const App: React.FC = () => {
     }
   };
 
-  const runMigration = async () => {
+  const runChecksumVerify = async () => {
     const sourceConn = connections.find(c => c.id === migrationSourceConn);
     const targetConn = connections.find(c => c.id === migrationTargetConn);
-    if (!sourceConn || !targetConn) {
-      message.warning('请选择源实例和目标实例');
-      return;
-    }
-    if (!migrationSourceDb || !migrationTargetDb) {
+    if (!sourceConn || !targetConn || !migrationSourceDb || !migrationTargetDb) {
       message.warning('未找到源或目标数据库');
       return;
     }
     setMigrationLoading(true);
     try {
-      const rows = await buildMigrationCheckRows();
-      const hasBlock = rows.some(r => String(r.status).startsWith('检测不通过'));
-      setMigrationCheck(rows);
-      if (hasBlock) {
-        message.error('检测不通过：目标库存在冲突表/数据');
-        return;
-      }
-      const resultRows = await SyncDatabase(sourceConn, migrationSourceDb, targetConn, migrationTargetDb, migrationMode, migrationSourceTables);
-      setMigrationCheck(resultRows as any);
-      const failed = (resultRows || []).filter((r: any) => String(r.status || '').startsWith('failed'));
+      const results = await VerifyChecksum(sourceConn, migrationSourceDb, targetConn, migrationTargetDb, migrationSourceTables, { chunkRows: 10000, algorithm: 'crc32', drillDown: true, maxDiffKeys: 100 } as any);
+      setMigrationCheck((results || []).map((r: any) => ({
+        name: r.name,
+        sourceRows: r.sourceRows,
+        targetRows: r.targetRows,
+        status: r.status,
+        checksum: ((r.mismatchChunks || []).length === 0 && r.status === 'success'
+          ? '一致'
+          : (r.diffKeys || []).map((k: any) => `${k.kind} ${k.key}`).join('; ') || `${(r.mismatchChunks || []).length} 个分片不一致`)
+          + (r.warning ? `（${r.warning}）` : '')
+      })));
+      const failed = (results || []).filter((r: any) => String(r.status || '').startsWith('failed'));
       if (failed.length > 0) {
-        message.warning(`同步完成（失败 ${failed.length} 张表）`);
+        message.warning(`校验完成（${failed.length} 张表不一致）`);
       } else {
-        message.success('同步完成');
+        message.success('校验完成，数据一致');
       }
     } catch (err) {
-      message.error('同步失败: ' + err);
+      message.error('校验失败: ' + err);
     } finally {
       setMigrationLoading(false);
     }
   };
 
-  const killSession = async (id: number) => {
+  const runDataDiff = async (output: 'grid' | 'file') => {
+    const sourceConn = connections.find(c => c.id === migrationSourceConn);
+    const targetConn = connections.find(c => c.id === migrationTargetConn);
+    if (!sourceConn || !targetConn || !migrationSourceDb || !migrationTargetDb) {
+      message.warning('未找到源或目标数据库');
+      return;
+    }
+    if (migrationSourceTables.length !== 1) {
+      message.warning('请选择一张要比对的表');
+      return;
+    }
+    setMigrationLoading(true);
     try {
-      await KillProcess(id);
-      message.success('会话已终止');
-      fetchSessions(activeTab?.connId);
+      const res = await CompareTableData(sourceConn, migrationSourceDb, targetConn, migrationTargetDb, migrationSourceTables[0], [], { output, filePath: '', maxRows: 1000 } as any);
+      setDataDiff(res as any);
+      (res.warnings || []).forEach((w: string) => message.warning(w, 8));
+      const total = res.missing + res.extra + res.changed;
+      if (total === 0) {
+        message.success('数据一致');
+      } else if (output === 'file') {
+        message.success(`发现 ${total} 处差异，修复SQL已保存到 ${res.filePath}`);
+      } else {
+        message.warning(`发现 ${total} 处差异`);
+      }
     } catch (err) {
-      message.error('终止会话失败: ' + err);
+      if (String(err) !== '已取消') {
+        message.error('比对失败: ' + err);
+      }
+    } finally {
+      setMigrationLoading(false);
     }
   };
 
-  const killSelectedSessions = () => {
-    if (selectedSessionIds.length === 0) {
-      message.info('请先选择会话');
+  const runSchemaDiff = async () => {
+    const sourceConn = connections.find(c => c.id === migrationSourceConn);
+    const targetConn = connections.find(c => c.id === migrationTargetConn);
+    if (!sourceConn || !targetConn || !migrationSourceDb || !migrationTargetDb) {
+      message.warning('未找到源或目标数据库');
       return;
     }
-    Modal.confirm({
-      title: `终止选中的 ${selectedSessionIds.length} 个会话？`,
-      okText: '终止',
-      okButtonProps: { danger: true },
-      cancelText: '取消',
-      async onOk() {
-        try {
-          for (const id of selectedSessionIds) {
-            await KillProcess(id);
-          }
-          message.success('已终止选中会话');
+    setMigrationLoading(true);
+    try {
+      const res = await CompareSchemas(sourceConn, migrationSourceDb, targetConn, migrationTargetDb, schemaDiffOptions as any);
+      setSchemaDiff(res as any);
+      if ((res.items || []).length === 0) {
+        message.success('结构一致');
+      } else {
+        message.warning(`发现 ${res.items.length} 处结构差异`);
+      }
+    } catch (err) {
+      message.error('结构比对失败: ' + err);
+    } finally {
+      setMigrationLoading(false);
+    }
+  };
+
+  const saveSchemaDiffScript = async () => {
+    if (!schemaDiff?.script) {
+      return;
+    }
+    try {
+      const path = await SaveTextFile(`${migrationTargetDb || 'schema'}_diff.sql`, schemaDiff.script);
+      if (path) {
+        message.success('已保存到 ' + path);
+      }
+    } catch (err) {
+      message.error('保存失败: ' + err);
+    }
+  };
+
+  const loadSyncCheckpoints = async () => {
+    try {
+      const list = await ListSyncCheckpoints();
+      setSyncCheckpoints((list || []) as any);
+    } catch (err) {
+      setSyncCheckpoints([]);
+    }
+  };
+
+  const resumeSync = async (jobId: string) => {
+    const cp = syncCheckpoints.find(c => c.jobId === jobId);
+    const targetConn = connections.find(c => c.id === cp?.targetId);
+    let token = '';
+    if (cp && targetConn) {
+      try {
+        const confirmed = await confirmTask(targetConn, 'sync', cp.targetDb, '将续传同步，按原策略处理已存在的目标表与数据');
+        if (confirmed === null) return;
+        token = confirmed;
+      } catch (err) {
+        message.error('续传失败: ' + err);
+        return;
+      }
+    }
+    setMigrationLoading(true);
+    try {
+      const resultRows = await ResumeSync(jobId, token);
+      setMigrationCheck(resultRows as any);
+      const resumed = (resultRows || []).filter((r: any) => r.resumed);
+      const failed = (resultRows || []).filter((r: any) => String(r.status || '').startsWith('failed'));
+      if (failed.length > 0) {
+        message.warning(`续传完成（续传 ${resumed.length} 张表，失败 ${failed.length} 张表）`);
+      } else {
+        message.success(`续传完成（续传 ${resumed.length} 张表）`);
+      }
+    } catch (err) {
+      message.error('续传失败: ' + err);
+    } finally {
+      setMigrationLoading(false);
+      loadSyncCheckpoints();
+    }
+  };
+
+  const deleteSyncCheckpoint = async (jobId: string) => {
+    try {
+      await DeleteSyncCheckpoint(jobId);
+      loadSyncCheckpoints();
+    } catch (err) {
+      message.error('删除断点失败: ' + err);
+    }
+  };
+
+  // 解析类型映射覆盖：每行一条“Oracle类型=MySQL类型”
+  const parseTypeMappings = (text: string): Record<string, string> => {
+    const mappings: Record<string, string> = {};
+    text.split('\n').forEach(line => {
+      const idx = line.indexOf('=');
+      if (idx <= 0) return;
+      const key = line.slice(0, idx).trim();
+      const value = line.slice(idx + 1).trim();
+      if (key && value) mappings[key] = value;
+    });
+    return mappings;
+  };
+
+  // 名称映射：表名规则每行一条“正则 => 替换”，单表映射为 JSON（源表名 → { target, include, exclude, columns, transforms }）
+  const buildNameMapping = () => {
+    const tableRules: any[] = [];
+    nameMapping.tableRules.split('\n').forEach(line => {
+      const idx = line.indexOf('=>');
+      if (idx <= 0) return;
+      tableRules.push({ match: line.slice(0, idx).trim(), replace: line.slice(idx + 2).trim(), regex: true, case: '' });
+    });
+    if (nameMapping.tableCase) tableRules.push({ match: '', replace: '', regex: false, case: nameMapping.tableCase });
+    const columnRules = nameMapping.columnCase ? [{ match: '', replace: '', regex: false, case: nameMapping.columnCase }] : [];
+    const tables = nameMapping.tables.trim() ? JSON.parse(nameMapping.tables) : {};
+    return { tableRules, columnRules, tables };
+  };
+
+  const migrationSourceIsOracle = normalizeConnType(connections.find(c => c.id === migrationSourceConn)?.type) === 'oracle';
+
+  useEffect(() => {
+    if (migrationSourceIsOracle && oracleTypeRules.length === 0) {
+      GetOracleTypeMappings().then((rules: any) => setOracleTypeRules(rules || [])).catch(() => {});
+    }
+  }, [migrationSourceIsOracle]);
+
+  const buildSyncOptions = () => {
+    let mapping;
+    try {
+      mapping = buildNameMapping();
+    } catch (e) {
+      message.error('单表映射不是有效的 JSON: ' + e);
+      return null;
+    }
+    return { ...migrationOptions, typeMappings: migrationSourceIsOracle ? parseTypeMappings(typeMappingText) : {}, mapping };
+  };
+
+  // 演练：执行全部检查并列出计划操作与语句，不写入目标库
+  const runDryRun = async () => {
+    const sourceConn = connections.find(c => c.id === migrationSourceConn);
+    const targetConn = connections.find(c => c.id === migrationTargetConn);
+    if (!sourceConn || !targetConn || !migrationSourceDb || !migrationTargetDb) {
+      message.warning('请选择源和目标实例及数据库');
+      return;
+    }
+    const opts = buildSyncOptions();
+    if (!opts) return;
+    setMigrationLoading(true);
+    try {
+      const plan: any = await DryRunSync(sourceConn, migrationSourceDb, targetConn, migrationTargetDb, migrationMode, migrationSourceTables, opts as any);
+      setMigrationCheck(plan.rows || []);
+      setSyncPlanScript(plan.script || '');
+      const failed = (plan.rows || []).filter((r: any) => String(r.status || '').startsWith('failed'));
+      if (failed.length > 0) {
+        message.warning(`演练完成：${failed.length} 张表检查不通过`);
+      } else {
+        message.success('演练完成' + (plan.createDatabase ? '（目标库将自动创建）' : ''));
+      }
+    } catch (err) {
+      message.error('演练失败: ' + err);
+    } finally {
+      setMigrationLoading(false);
+    }
+  };
+
+  const saveSyncPlanScript = async () => {
+    if (!syncPlanScript) {
+      return;
+    }
+    try {
+      const path = await SaveTextFile(`${migrationTargetDb || 'sync'}_plan.sql`, syncPlanScript);
+      if (path) {
+        message.success('已保存到 ' + path);
+      }
+    } catch (err) {
+      message.error('保存失败: ' + err);
+    }
+  };
+
+  const refreshCdcTasks = async () => {
+    try {
+      setCdcTasks((await ListCDC()) || []);
+    } catch (err) {
+      message.error('读取增量同步任务失败: ' + err);
+    }
+  };
+
+  // 增量同步：全量复制后从复制前记录的 binlog 位点持续应用变更
+  const startCdc = async () => {
+    const sourceConn = connections.find(c => c.id === migrationSourceConn);
+    const targetConn = connections.find(c => c.id === migrationTargetConn);
+    if (!sourceConn || !targetConn || !migrationSourceDb || !migrationTargetDb) {
+      message.warning('请选择源和目标实例及数据库');
+      return;
+    }
+    const opts: any = buildSyncOptions();
+    if (!opts) return;
+    try {
+      const risk = cdcSkipCopy ? '' : syncPolicyRisk(opts);
+      if (risk) {
+        const token = await confirmTask(targetConn, 'cdc', migrationTargetDb, risk);
+        if (token === null) return;
+        opts.confirmToken = token;
+      }
+      await StartCDC(sourceConn, migrationSourceDb, targetConn, migrationTargetDb, migrationSourceTables, { sync: opts, skipInitialCopy: cdcSkipCopy } as any);
+      message.success(cdcSkipCopy ? '增量同步已启动' : '增量同步已启动，正在全量复制');
+      refreshCdcTasks();
+    } catch (err) {
+      message.error('启动增量同步失败: ' + err);
+    }
+  };
+
+  const cdcAction = async (action: 'pause' | 'resume' | 'stop' | 'cutover' | 'delete', id: string) => {
+    try {
+      if (action === 'pause') await PauseCDC(id);
+      if (action === 'resume') {
+        const task = cdcTasks.find(t => t.id === id);
+        const targetConn = connections.find(c => c.id === task?.targetId);
+        let token = '';
+        if (task && !task.copyDone && targetConn) {
+          const confirmed = await confirmTask(targetConn, 'cdc', task.targetDb, '将续传全量复制，按原策略处理已存在的目标表与数据');
+          if (confirmed === null) return;
+          token = confirmed;
+        }
+        await ResumeCDC(id, token);
+      }
+      if (action === 'stop') await StopCDC(id, false);
+      if (action === 'cutover') await StopCDC(id, true);
+      if (action === 'delete') await DeleteCDC(id);
+      refreshCdcTasks();
+    } catch (err) {
+      message.error('操作失败: ' + err);
+    }
+  };
+
+  const runMigration = async () => {
+    const sourceConn = connections.find(c => c.id === migrationSourceConn);
+    const targetConn = connections.find(c => c.id === migrationTargetConn);
+    if (!sourceConn || !targetConn) {
+      message.warning('请选择源实例和目标实例');
+      return;
+    }
+    if (!migrationSourceDb || !migrationTargetDb) {
+      message.warning('未找到源或目标数据库');
+      return;
+    }
+    setMigrationLoading(true);
+    try {
+      const rows = await buildMigrationCheckRows();
+      const hasBlock = rows.some(r => String(r.status).startsWith('检测不通过'));
+      setMigrationCheck(rows);
+      if (hasBlock) {
+        message.error('检测不通过：目标库存在冲突表/数据');
+        return;
+      }
+      const opts: any = buildSyncOptions();
+      if (!opts) return;
+      const risk = syncPolicyRisk(opts);
+      if (risk) {
+        const token = await confirmTask(targetConn, 'sync', migrationTargetDb, risk);
+        if (token === null) return;
+        opts.confirmToken = token;
+      }
+      const resultRows = await SyncDatabase(sourceConn, migrationSourceDb, targetConn, migrationTargetDb, migrationMode, migrationSourceTables, opts as any);
+      setMigrationCheck(resultRows as any);
+      const failed = (resultRows || []).filter((r: any) => String(r.status || '').startsWith('failed'));
+      if (failed.length > 0) {
+        message.warning(`同步完成（失败 ${failed.length} 张表）`);
+      } else {
+        message.success('同步完成');
+      }
+    } catch (err) {
+      message.error('同步失败: ' + err);
+    } finally {
+      setMigrationLoading(false);
+      loadSyncCheckpoints();
+    }
+  };
+
+  // 生产环境终止会话需确认，返回 null 表示已取消
+  const killSessions = async (ids: number[]) => {
+    const token = await confirmGuard(ids.map(id => `KILL ${id}`).join(';\n'));
+    if (token === null) return false;
+    for (const id of ids) {
+      await KillProcess(id, token);
+    }
+    return true;
+  };
+
+  const killSession = async (id: number) => {
+    try {
+      if (!(await killSessions([id]))) return;
+      message.success('会话已终止');
+      fetchSessions(activeTab?.connId);
+    } catch (err) {
+      message.error('终止会话失败: ' + err);
+    }
+  };
+
+  const killSelectedSessions = () => {
+    if (selectedSessionIds.length === 0) {
+      message.info('请先选择会话');
+      return;
+    }
+    Modal.confirm({
+      title: `终止选中的 ${selectedSessionIds.length} 个会话？`,
+      okText: '终止',
+      okButtonProps: { danger: true },
+      cancelText: '取消',
+      async onOk() {
+        try {
+          if (!(await killSessions(selectedSessionIds))) return;
+          message.success('已终止选中会话');
           setSelectedSessionIds([]);
           fetchSessions(activeTab?.connId);
         } catch (err) {
@@ -873,9 +1768,7 @@ const App: React.FC = () => {
       cancelText: '取消',
       async onOk() {
         try {
-          for (const id of ids) {
-            await KillProcess(id);
-          }
+          if (!(await killSessions(ids))) return;
           message.success('已终止该用户会话');
           setSelectedSessionIds([]);
           fetchSessions(activeTab?.connId);
@@ -898,12 +1791,33 @@ const App: React.FC = () => {
   useEffect(() => {
     if (!isExportOpen) return;
     setExportLogs([]);
+    setExportProgress(null);
     const off = EventsOn('export-log', (msg: string) => {
       setExportLogs(prev => [...prev, msg]);
     });
-    return () => off();
+    const offProgress = EventsOn('export-progress', (p: any) => {
+      setExportProgress(p);
+    });
+    return () => {
+      off();
+      offProgress();
+    };
   }, [isExportOpen]);
 
+  useEffect(() => {
+    refreshCdcTasks();
+    const off = EventsOn('cdc-status', (st: any) => {
+      setCdcTasks(prev => {
+        const idx = prev.findIndex(t => t.id === st.id);
+        if (idx < 0) return [st, ...prev];
+        const next = [...prev];
+        next[idx] = st;
+        return next;
+      });
+    });
+    return () => off();
+  }, []);
+
   useEffect(() => {
     if (!exportLogRef.current) return;
     exportLogRef.current.scrollTop = exportLogRef.current.scrollHeight;
@@ -948,7 +1862,7 @@ const App: React.FC = () => {
     }
     try {
       await SaveAppSettings(appSettings);
-      const savedPath = await ExportSqlDump({ ...exportDb.conn, database: exportDb.db }, exportTables, exportMode);
+      const savedPath = await ExportSqlDump({ ...exportDb.conn, database: exportDb.db }, exportTables, exportMode, exportOptions);
       if (!savedPath) {
         message.info('已取消导出');
         return;
@@ -960,7 +1874,298 @@ const App: React.FC = () => {
     }
   };
 
-  const exportResultToExcel = async (resultKey: string) => {
+  const handleCancelExport = async () => {
+    if (!exportProgress || exportProgress.status !== 'running') return;
+    try {
+      await CancelExport(exportProgress.jobId);
+    } catch (err) {
+      message.error('取消导出失败: ' + err);
+    }
+  };
+
+  // 进入结果表格编辑：后端确认单表查询及主键后才允许编辑
+  const startGridEdit = async (resultKey: string) => {
+    const tab = activeTab;
+    const result = (tab?.results || []).find(r => r.key === resultKey);
+    if (!tab || !result) return;
+    if (!result.sql) {
+      message.warning('该结果没有对应的查询语句，无法编辑');
+      return;
+    }
+    const conn = connections.find(c => c.id === tab.connId);
+    if (!conn) {
+      message.warning('连接不存在，请重新选择');
+      return;
+    }
+    try {
+      await ConnectDBConfig(conn);
+      if (tab.dbName) {
+        await switchDatabase(conn, tab.dbName);
+      }
+      const info = (await GetEditableResult(result.sql)) as EditableInfo;
+      if (!info.editable) {
+        message.warning('当前结果不可编辑：' + (info.reason || '未知原因'));
+        return;
+      }
+      setGridEdit({
+        tabKey: tab.key,
+        resultKey,
+        info,
+        rows: (result.data || []).map((row, idx) => ({ key: `row-${idx}`, status: 'clean', original: row, values: {} }))
+      });
+    } catch (err) {
+      message.error('进入编辑失败: ' + err);
+    }
+  };
+
+  const updateGridRow = (key: string, update: (row: GridEditRow) => GridEditRow | null) => {
+    setGridEdit(prev => {
+      if (!prev) return prev;
+      const rows: GridEditRow[] = [];
+      prev.rows.forEach(row => {
+        if (row.key !== key) {
+          rows.push(row);
+          return;
+        }
+        const next = update(row);
+        if (next) rows.push(next);
+      });
+      return { ...prev, rows };
+    });
+  };
+
+  const setGridCell = (key: string, column: string, value: any) => {
+    updateGridRow(key, row => {
+      const values = { ...row.values };
+      if (row.status !== 'new' && row.original[column] === value) {
+        delete values[column];
+      } else {
+        values[column] = value;
+      }
+      const status = row.status === 'new' ? 'new' : Object.keys(values).length ? 'modified' : 'clean';
+      return { ...row, values, status };
+    });
+  };
+
+  const toggleGridDelete = (key: string) => {
+    updateGridRow(key, row => {
+      if (row.status === 'new') return null;
+      if (row.status === 'deleted') {
+        return { ...row, status: Object.keys(row.values).length ? 'modified' : 'clean' };
+      }
+      return { ...row, status: 'deleted' };
+    });
+  };
+
+  const addGridRow = () => {
+    setGridEdit(prev => prev && {
+      ...prev,
+      rows: [{ key: `new-${Date.now()}`, status: 'new', original: {}, values: {} }, ...prev.rows]
+    });
+  };
+
+  const buildGridChanges = (edit: GridEditState) => edit.rows.flatMap(row => {
+    switch (row.status) {
+      case 'modified':
+        return [{ action: 'update', original: row.original, values: row.values }];
+      case 'new':
+        return [{ action: 'insert', original: {}, values: row.values }];
+      case 'deleted':
+        return [{ action: 'delete', original: row.original, values: {} }];
+      default:
+        return [];
+    }
+  });
+
+  // 预览生成的语句，确认后在一个事务中提交
+  const saveGridEdit = async () => {
+    const edit = gridEdit;
+    const tab = queryTabs.find(t => t.key === edit?.tabKey);
+    const result = (tab?.results || []).find(r => r.key === edit?.resultKey);
+    if (!edit || !tab || !result?.sql) return;
+    const changes = buildGridChanges(edit);
+    if (!changes.length) {
+      message.info('没有需要保存的修改');
+      return;
+    }
+    setGridSaving(true);
+    try {
+      const statements = (await PreviewGridChanges(result.sql, changes as any)) || [];
+      if (!statements.length) {
+        message.info('没有需要保存的修改');
+        return;
+      }
+      const confirmed = await new Promise<boolean>(resolve => {
+        Modal.confirm({
+          title: `提交 ${statements.length} 条语句到 ${edit.info.table}`,
+          width: 720,
+          okText: '提交',
+          cancelText: '取消',
+          content: (
+            <div className="guard-confirm">
+              <Text type="secondary">在同一事务中执行，任一语句失败或影响行数不是 1 行时整体回滚</Text>
+              <pre className="guard-sql grid-preview-sql">{statements.map(st => st.sql + ';').join('\n')}</pre>
+            </div>
+          ),
+          onOk: () => resolve(true),
+          onCancel: () => resolve(false)
+        });
+      });
+      if (!confirmed) return;
+      const token = await confirmGuard(statements.map(st => st.sql).join(';\n'));
+      if (token === null) {
+        message.info('已取消提交');
+        return;
+      }
+      const res = await ApplyGridChanges(result.sql, changes as any, token);
+      const data = edit.rows
+        .filter(row => row.status !== 'deleted')
+        .map(row => ({ ...row.original, ...row.values }));
+      updateTab(tab.key, {
+        results: (tab.results || []).map(r => (r.key === result.key ? { ...r, data } : r))
+      });
+      setGridEdit(null);
+      message.success(`已提交 ${res.statements} 条语句，影响 ${res.affected} 行`);
+    } catch (err) {
+      message.error('提交失败: ' + err);
+    } finally {
+      setGridSaving(false);
+    }
+  };
+
+  const cancelGridEdit = () => {
+    if (gridEdit && buildGridChanges(gridEdit).length) {
+      Modal.confirm({
+        title: '放弃未提交的修改？',
+        okText: '放弃',
+        cancelText: '继续编辑',
+        onOk: () => setGridEdit(null)
+      });
+      return;
+    }
+    setGridEdit(null);
+  };
+
+  // 编辑模式下的列：可编辑列渲染为输入框，可空列可设为 NULL
+  const gridEditColumns = (edit: GridEditState, resultColumns: any[]) => {
+    const cols: any[] = resultColumns.map((rc: any) => {
+      const name = String(rc.dataIndex);
+      const col = edit.info.columns.find(c => c.name.toLowerCase() === name.toLowerCase())
+        || { name, column: '', type: '', nullable: true, editable: false };
+      return {
+        title: (
+          <span className={col.column && edit.info.key.some(k => k.toLowerCase() === col.column.toLowerCase()) ? 'grid-key-col' : undefined}>
+            {rc.title}
+          </span>
+        ),
+        dataIndex: name,
+        key: name,
+        width: 180,
+        render: (value: any, record: any) => {
+          const row: GridEditRow = record.__row;
+          if (!col.editable || row.status === 'deleted') {
+            return <span className="result-cell">{value === null || value === undefined ? <Text type="secondary">NULL</Text> : truncateCellText(value, 80)}</span>;
+          }
+          const changed = Object.prototype.hasOwnProperty.call(row.values, name);
+          return (
+            <Input
+              size="small"
+              className={changed ? 'grid-cell-changed' : undefined}
+              value={value === null || value === undefined ? '' : String(value)}
+              placeholder={value === null ? 'NULL' : ''}
+              onChange={e => setGridCell(row.key, name, e.target.value)}
+              suffix={col.nullable ? (
+                <Tooltip title="设为 NULL">
+                  <span className="grid-null-btn" onClick={() => setGridCell(row.key, name, null)}>∅</span>
+                </Tooltip>
+              ) : undefined}
+            />
+          );
+        }
+      };
+    });
+    cols.unshift({
+      title: '',
+      key: '__action',
+      width: 44,
+      fixed: 'left',
+      render: (_: any, record: any) => (
+        <Tooltip title={record.__row.status === 'deleted' ? '恢复' : '删除行'}>
+          <Button
+            size="small"
+            type="text"
+            danger={record.__row.status !== 'deleted'}
+            icon={record.__row.status === 'deleted' ? <ReloadOutlined /> : <DeleteOutlined />}
+            onClick={() => toggleGridDelete(record.__row.key)}
+          />
+        </Tooltip>
+      )
+    });
+    return cols;
+  };
+
+  // 结果列定义，被脱敏的列在表头标记
+  const resultColumn = (name: string, masked: string[]) => ({
+    title: masked.includes(name)
+      ? <Tooltip title="已按脱敏规则处理"><span>{name} <EyeInvisibleOutlined className="masked-col-icon" /></span></Tooltip>
+      : name,
+    dataIndex: name,
+    key: name,
+    ellipsis: true,
+    width: 150
+  });
+
+  const openMaskingRules = async () => {
+    try {
+      setMaskingRules(((await GetMaskingRules()) || []) as MaskingRule[]);
+      setMaskPreview({});
+      setIsMaskingOpen(true);
+    } catch (err) {
+      message.error('读取脱敏规则失败: ' + err);
+    }
+  };
+
+  const updateMaskingRule = (id: string, patch: Partial<MaskingRule>) => {
+    setMaskingRules(prev => prev.map(r => (r.id === id ? { ...r, ...patch } : r)));
+  };
+
+  const addMaskingRule = () => {
+    setMaskingRules(prev => [...prev, {
+      id: `mask-${Date.now()}`,
+      name: '',
+      enabled: true,
+      connection: '*',
+      schema: '',
+      table: '',
+      column: '',
+      strategy: 'partial',
+      keepPrefix: 3,
+      keepSuffix: 4,
+      maskChar: '*',
+      fixedValue: ''
+    }]);
+  };
+
+  const previewMaskingRules = async () => {
+    const next: Record<string, string> = {};
+    for (const rule of maskingRules) {
+      const v = await PreviewMask(rule as any, maskSample);
+      next[rule.id] = v === null || v === undefined ? 'NULL' : String(v);
+    }
+    setMaskPreview(next);
+  };
+
+  const saveMaskingRules = async () => {
+    try {
+      await SaveMaskingRules(maskingRules as any);
+      message.success('脱敏规则已保存');
+      setIsMaskingOpen(false);
+    } catch (err) {
+      message.error('保存失败: ' + err);
+    }
+  };
+
+  const exportResultToFile = async (resultKey: string, format: 'xlsx' | 'csv') => {
     const tab = activeTab;
     if (!tab) return;
     const result = (tab.results || []).find(r => r.key === resultKey);
@@ -989,8 +2194,11 @@ const App: React.FC = () => {
       });
       const name = (result.title || 'result').replace(/[\\/:*?"<>|]+/g, '_');
       const headers = (result.columns || []).map((c: any) => c.dataIndex || c.key || c.title).filter(Boolean);
-      const payload = JSON.stringify({ headers, rows: safeRows });
-      const savedPath = await SaveExcelFromJSON(`${name}.xlsx`, payload);
+      // 带上原始 SQL 与已脱敏列，后端据此补齐脱敏，避免导出文件泄露敏感数据
+      const payload = JSON.stringify({ headers, rows: safeRows, sql: result.sql || '', masked: result.masked || [] });
+      const savedPath = format === 'csv'
+        ? await SaveCSVFromJSON(`${name}.csv`, payload)
+        : await SaveExcelFromJSON(`${name}.xlsx`, payload);
       if (!savedPath) {
         message.info('已取消导出');
         return;
@@ -1001,6 +2209,213 @@ const App: React.FC = () => {
     }
   };
 
+  // --- 表设计器 ---
+  const designKey = () => `d-${Date.now()}-${Math.random().toString(36).slice(2, 8)}`;
+
+  const formatIndexColumns = (cols: Array<{ name: string; length: number; desc: boolean }>) =>
+    (cols || []).map(c => `${c.name}${c.length > 0 ? `(${c.length})` : ''}${c.desc ? ' DESC' : ''}`).join(', ');
+
+  const parseIndexColumns = (text: string) =>
+    String(text || '').split(',').map(part => part.trim()).filter(Boolean).map(part => {
+      const m = part.match(/^(.+?)(?:\((\d+)\))?(?:\s+(ASC|DESC))?$/i);
+      return {
+        name: (m ? m[1] : part).trim(),
+        length: m && m[2] ? Number(m[2]) : 0,
+        desc: !!(m && m[3] && m[3].toUpperCase() === 'DESC')
+      };
+    });
+
+  const splitNames = (text: string) => String(text || '').split(',').map(v => v.trim()).filter(Boolean);
+
+  const emptyDesignColumn = (): DesignColumn => ({
+    key: designKey(), name: '', originalName: '', type: '', nullable: true, default: null, defaultIsExpr: false,
+    autoIncrement: false, onUpdate: '', charset: '', collation: '', generated: '', stored: false, comment: ''
+  });
+
+  // 打开表设计器：table 为空表示新建表
+  const openTableDesigner = async (conn: DBConfig, db: string, table?: string) => {
+    try {
+      await ConnectDBConfig(conn);
+      await switchDatabase(conn, db);
+      const isOracle = normalizeConnType(conn.type) === 'oracle';
+      let model: TableDesignModel;
+      if (table) {
+        const raw: any = await LoadTableModel(db, table);
+        model = {
+          ...raw,
+          columns: (raw.columns || []).map((c: any) => ({ ...c, key: designKey() })),
+          indexes: (raw.indexes || []).map((i: any) => ({ ...i, key: designKey(), columnsText: formatIndexColumns(i.columns) })),
+          foreignKeys: (raw.foreignKeys || []).map((f: any) => ({ ...f, key: designKey(), columns: f.columns || [], refColumns: f.refColumns || [] })),
+          checks: (raw.checks || []).map((c: any) => ({ ...c, key: designKey() }))
+        };
+      } else {
+        model = {
+          schema: db, name: '', originalName: '', engine: isOracle ? '' : 'InnoDB', charset: isOracle ? '' : 'utf8mb4', collation: '',
+          autoIncrement: 0, originalAutoIncrement: 0, comment: '', partition: '',
+          columns: [{ ...emptyDesignColumn(), name: isOracle ? 'ID' : 'id', type: isOracle ? 'NUMBER(19)' : 'bigint', nullable: false, autoIncrement: true }],
+          indexes: [{ key: designKey(), name: '', originalName: '', kind: 'primary', columns: [], columnsText: isOracle ? 'ID' : 'id', comment: '' }],
+          foreignKeys: [],
+          checks: []
+        };
+      }
+      setTableDesigner({ conn, db, model, online: false, ddl: null });
+    } catch (err) {
+      message.error('读取表结构失败: ' + err);
+    }
+  };
+
+  const updateDesignModel = (patch: Partial<TableDesignModel>) => {
+    setTableDesigner(prev => (prev ? { ...prev, model: { ...prev.model, ...patch }, ddl: null } : prev));
+  };
+
+  const updateDesignItem = <K extends 'columns' | 'indexes' | 'foreignKeys' | 'checks'>(field: K, key: string, patch: Partial<TableDesignModel[K][number]>) => {
+    setTableDesigner(prev => {
+      if (!prev) return prev;
+      const list = (prev.model[field] as any[]).map(item => (item.key === key ? { ...item, ...patch } : item));
+      return { ...prev, model: { ...prev.model, [field]: list }, ddl: null };
+    });
+  };
+
+  const removeDesignItem = (field: 'columns' | 'indexes' | 'foreignKeys' | 'checks', key: string) => {
+    setTableDesigner(prev => {
+      if (!prev) return prev;
+      const list = (prev.model[field] as any[]).filter(item => item.key !== key);
+      return { ...prev, model: { ...prev.model, [field]: list }, ddl: null };
+    });
+  };
+
+  const moveDesignColumn = (key: string, delta: number) => {
+    setTableDesigner(prev => {
+      if (!prev) return prev;
+      const cols = [...prev.model.columns];
+      const idx = cols.findIndex(c => c.key === key);
+      const next = idx + delta;
+      if (idx < 0 || next < 0 || next >= cols.length) return prev;
+      [cols[idx], cols[next]] = [cols[next], cols[idx]];
+      return { ...prev, model: { ...prev.model, columns: cols }, ddl: null };
+    });
+  };
+
+  const addDesignItem = (field: 'columns' | 'indexes' | 'foreignKeys' | 'checks') => {
+    setTableDesigner(prev => {
+      if (!prev) return prev;
+      const item = field === 'columns'
+        ? emptyDesignColumn()
+        : field === 'indexes'
+          ? { key: designKey(), name: '', originalName: '', kind: 'index', columns: [], columnsText: '', comment: '' }
+          : field === 'checks'
+            ? { key: designKey(), name: '', expr: '', notEnforced: false }
+            : { key: designKey(), name: '', columns: [], refSchema: '', refTable: '', refColumns: [], onDelete: '', onUpdate: '' };
+      return { ...prev, model: { ...prev.model, [field]: [...(prev.model[field] as any[]), item] }, ddl: null };
+    });
+  };
+
+  const designPayload = (model: TableDesignModel) => ({
+    ...model,
+    autoIncrement: Number(model.autoIncrement) || 0,
+    indexes: model.indexes.map(i => ({ ...i, columns: parseIndexColumns(i.columnsText) }))
+  });
+
+  const previewTableDesign = async () => {
+    if (!tableDesigner) return;
+    setDesignerBusy(true);
+    try {
+      const ddl = await PreviewTableDDL(designPayload(tableDesigner.model) as any, { online: tableDesigner.online });
+      setTableDesigner(prev => (prev ? { ...prev, ddl: ddl as any } : prev));
+      if (!ddl.statements || ddl.statements.length === 0) {
+        message.info('表结构没有变化');
+      }
+    } catch (err) {
+      message.error('生成语句失败: ' + err);
+    } finally {
+      setDesignerBusy(false);
+    }
+  };
+
+  const executeTableDesign = async () => {
+    if (!tableDesigner || !tableDesigner.ddl || tableDesigner.ddl.statements.length === 0) return;
+    const { conn, db, model, online, ddl } = tableDesigner;
+    setDesignerBusy(true);
+    try {
+      const token = await confirmGuard(ddl.statements.join(';\n'));
+      if (token === null) return;
+      await ExecuteTableDDL(designPayload(model) as any, { online }, token);
+      message.success(ddl.create ? `表 ${model.name} 已创建` : `表 ${model.name} 已修改`);
+      setTableDesigner(null);
+      loadDbObjects(conn, db);
+    } catch (err) {
+      message.error('执行失败: ' + err);
+    } finally {
+      setDesignerBusy(false);
+    }
+  };
+
+  // 执行前检查：只读连接直接拦截；生产环境的危险语句弹窗确认后返回确认令牌，取消时返回 null
+  const confirmGuard = async (sqlText: string): Promise<string | null> => {
+    const check = await CheckStatement(sqlText);
+    if (check.blocked) {
+      throw check.blockReason || '语句被拦截';
+    }
+    if (!check.requiresConfirm) return '';
+    const risky = (check.statements || []).filter(s => s.dangerous);
+    return new Promise(resolve => {
+      Modal.confirm({
+        title: `生产环境 ${check.connName}：确认执行以下操作？`,
+        width: 640,
+        okText: '确认执行',
+        okButtonProps: { danger: true },
+        cancelText: '取消',
+        content: (
+          <div className="guard-confirm">
+            {risky.map((s, idx) => (
+              <div key={idx} className="guard-item">
+                <Text strong>{s.reason}</Text>
+                <pre className="guard-sql">{s.sql}</pre>
+                {s.estimatedRows >= 0 && <Text type="secondary">预计影响约 {s.estimatedRows} 行</Text>}
+                {s.detail && <Text type="secondary">{s.detail}</Text>}
+              </div>
+            ))}
+            {risky.length > 1 && check.estimatedRows >= 0 && <Text>合计预计影响约 {check.estimatedRows} 行</Text>}
+          </div>
+        ),
+        onOk: () => resolve(check.token || ''),
+        onCancel: () => resolve(null)
+      });
+    });
+  };
+
+  // 写入任务的生产环境确认：目标为生产环境时弹窗说明后返回确认令牌，取消时返回 null
+  const confirmTask = async (conn: any, kind: string, db: string, reason: string): Promise<string | null> => {
+    const check = await ConfirmTaskTarget(conn, kind, db);
+    if (check.blocked) {
+      throw check.blockReason || '任务被拦截';
+    }
+    if (!check.requiresConfirm) return '';
+    return new Promise(resolve => {
+      Modal.confirm({
+        title: `生产环境 ${check.connName}：确认执行该任务？`,
+        width: 520,
+        okText: '确认执行',
+        okButtonProps: { danger: true },
+        cancelText: '取消',
+        content: <Text strong>{reason}</Text>,
+        onOk: () => resolve(check.token || ''),
+        onCancel: () => resolve(null)
+      });
+    });
+  };
+
+  // 同步策略中会删表或清空数据的说明，与后端判断一致
+  const syncPolicyRisk = (opts: any): string => {
+    const policies = Object.values(opts.tablePolicies || {}) as any[];
+    const drop = opts.onTableExists === 'drop' || policies.some(p => p.onTableExists === 'drop');
+    const truncate = opts.onDataExists === 'truncate' || policies.some(p => p.onDataExists === 'truncate');
+    if (drop && truncate) return '将删除重建已存在的目标表并清空目标表数据';
+    if (drop) return '将删除重建已存在的目标表';
+    if (truncate) return '将清空目标表已有数据';
+    return '';
+  };
+
   // 4. 运行 SQL
   const runSqlText = async (tabKey: string, sqlText: string, tabOverride?: QueryTab) => {
     if (!sqlText.trim()) return;
@@ -1020,29 +2435,31 @@ const App: React.FC = () => {
       if (tab.dbName) {
         await switchDatabase(conn, tab.dbName);
       }
+      const token = await confirmGuard(sqlText);
+      if (token === null) {
+        message.info('已取消执行');
+        return;
+      }
       const start = performance.now();
-      const result = await ExecuteQueryWithColumns(sqlText);
+      const result = await ExecuteQueryWithColumns(sqlText, token);
       const durationMs = Math.round(performance.now() - start);
       const data = result?.rows || [];
       const orderedCols = result?.columns || [];
+      const masked = result?.masked || [];
       if (data && data.length > 0) {
-        const cols = orderedCols.map(k => ({
-          title: k,
-          dataIndex: k,
-          key: k,
-          ellipsis: true,
-          width: 150
-        }));
+        const cols = orderedCols.map(k => resultColumn(k, masked));
         const resultKey = `result-${Date.now()}`;
         const resultTitle = `结果 ${new Date().toLocaleTimeString()}`;
         updateTab(tabKey, {
           columns: cols,
           data,
           durationMs,
+          resultSql: sqlText,
+          masked,
           activeResultKey: resultKey,
           results: [
             ...(tab.results || []),
-            { key: resultKey, title: resultTitle, columns: cols, data, durationMs }
+            { key: resultKey, title: resultTitle, columns: cols, data, durationMs, sql: sqlText, masked }
           ]
         });
       } else {
@@ -1087,6 +2504,41 @@ const App: React.FC = () => {
     await runSqlText(tabKey, text);
   };
 
+  // 格式化或压缩选中的 SQL（未选中则处理全部），通过编辑操作写回以便撤销
+  const formatTabSql = async (tabKey: string, minify: boolean) => {
+    const tab = queryTabs.find(t => t.key === tabKey);
+    if (!tab) return;
+    const conn = connections.find(c => c.id === tab.connId);
+    const dialect = normalizeConnType(conn?.type);
+    const editor = editorRef.current;
+    const model = editor?.getModel();
+    const selection = editor?.getSelection();
+    const useSelection = !!(model && selection && !selection.isEmpty());
+    const text = useSelection ? model!.getValueInRange(selection!) : tab.sql;
+    if (!text.trim()) return;
+    try {
+      let formatted = await FormatSQL(text, dialect, { keywordCase: 'upper', indentSize: 2, useTabs: false, minify } as any);
+      if (useSelection) {
+        formatted = formatted.replace(/\n$/, '');
+      }
+      if (editor && model) {
+        editor.pushUndoStop();
+        editor.executeEdits('format', [{ range: useSelection ? selection! : model.getFullModelRange(), text: formatted }]);
+        editor.pushUndoStop();
+      } else {
+        updateTab(tabKey, { sql: formatted });
+      }
+    } catch (err) {
+      message.error('格式化失败: ' + err);
+    }
+  };
+
+  formatSqlRef.current = (minify: boolean) => formatTabSql(activeTabKey, minify);
+  suggestContextRef.current = {
+    conn: connections.find(c => c.id === activeTab?.connId),
+    db: activeTab?.dbName
+  };
+
   const resetSql = (tabKey: string) => {
     updateTab(tabKey, { sql: '' });
   };
@@ -1118,24 +2570,27 @@ const App: React.FC = () => {
     }
     updateTab(tabKey, { loading: true });
     try {
+      const token = await confirmGuard(statements.join(';\n'));
+      if (token === null) {
+        message.info('已取消执行');
+        return;
+      }
       const start = performance.now();
       let lastData: any[] = [];
       let lastColumns: string[] = [];
+      let lastMasked: string[] = [];
+      let lastSql = '';
       for (const stmt of statements) {
-        const result = await ExecuteQueryWithColumns(stmt);
+        const result = await ExecuteQueryWithColumns(stmt, token);
         lastData = result?.rows || [];
         lastColumns = result?.columns || [];
+        lastMasked = result?.masked || [];
+        lastSql = stmt;
       }
       const durationMs = Math.round(performance.now() - start);
       if (lastData && lastData.length > 0) {
-        const cols = lastColumns.map(k => ({
-          title: k,
-          dataIndex: k,
-          key: k,
-          ellipsis: true,
-          width: 150
-        }));
-        updateTab(tabKey, { columns: cols, data: lastData, durationMs });
+        const cols = lastColumns.map(k => resultColumn(k, lastMasked));
+        updateTab(tabKey, { columns: cols, data: lastData, durationMs, resultSql: lastSql, masked: lastMasked });
       } else {
         updateTab(tabKey, { data: [], columns: [], durationMs });
         message.warning('执行成功，但结果集为空');
@@ -1147,7 +2602,7 @@ const App: React.FC = () => {
     }
   };
 
-  const exportActiveToExcel = async () => {
+  const exportActiveToFile = async (format: 'xlsx' | 'csv') => {
     if (!activeTab || activeTab.kind === 'ddl') return;
     const rows = activeTab.data || [];
     if (!rows.length) {
@@ -1174,8 +2629,10 @@ const App: React.FC = () => {
     const name = (activeTab.title || 'results').replace(/[\\/:*?"<>|]+/g, '_');
     try {
       const headers = (activeTab.columns || []).map((c: any) => c.dataIndex || c.key || c.title).filter(Boolean);
-      const payload = JSON.stringify({ headers, rows: safeRows });
-      const savedPath = await SaveExcelFromJSON(`${name}.xlsx`, payload);
+      const payload = JSON.stringify({ headers, rows: safeRows, sql: activeTab.resultSql || '', masked: activeTab.masked || [] });
+      const savedPath = format === 'csv'
+        ? await SaveCSVFromJSON(`${name}.csv`, payload)
+        : await SaveExcelFromJSON(`${name}.xlsx`, payload);
       if (!savedPath) {
         message.info('已取消导出');
         return;
@@ -1312,6 +2769,10 @@ const App: React.FC = () => {
     >
       <span className={`status-dot status-${connStatus[conn.id] || 'disconnected'}`} />
       <span className="tree-label" title={conn.name}>{conn.name}</span>
+      {conn.environment && conn.environment !== 'dev' && (
+        <span className={`env-tag env-${conn.environment}`}>{conn.environment === 'prod' ? '生产' : '测试'}</span>
+      )}
+      {conn.readOnly && <span className="env-tag env-readonly">只读</span>}
     </div>
   );
 
@@ -1352,7 +2813,9 @@ const App: React.FC = () => {
       items.push({ key: 'connect', label: '连接', icon: <DatabaseOutlined />, onClick: () => handleConnect(menu.conn) });
       if (normalizeConnType(menu.conn.type) === 'mysql') {
         items.push({ key: 'sessions', label: '会话管理', icon: <DesktopOutlined />, onClick: () => openSessionTab() });
+        items.push({ key: 'binlog', label: 'Binlog 闪回', icon: <HistoryOutlined />, onClick: () => openBinlogTab() });
       }
+      items.push({ key: 'users', label: '用户与权限', icon: <UserOutlined />, onClick: () => openUsersTab(menu.conn) });
       items.push(
         { key: 'edit', label: '编辑', icon: <EditOutlined />, onClick: () => openEditModal(menu.conn) },
         { key: 'delete', label: '删除', icon: <DeleteOutlined />, onClick: () => handleDeleteConnection(menu.conn) }
@@ -1360,7 +2823,8 @@ const App: React.FC = () => {
     } else if (menu.type === 'db') {
       items.push(
         { key: 'create-sql', label: '创建SQL窗口', icon: <ConsoleSqlOutlined />, onClick: () => createSqlWindowForDb(menu.conn, menu.db) },
-        { key: 'refresh', label: '刷新对象', icon: <ReloadOutlined />, onClick: () => handleSelectDb(menu.conn, menu.db) }
+        { key: 'refresh', label: '刷新对象', icon: <ReloadOutlined />, onClick: () => handleSelectDb(menu.conn, menu.db) },
+        { key: 'create-table', label: '新建表', icon: <PlusOutlined />, onClick: () => openTableDesigner(menu.conn, menu.db) }
       );
       if (normalizeConnType(menu.conn.type) === 'mysql') {
         items.push({ key: 'export', label: '导出SQL', icon: <FileTextOutlined />, onClick: () => openExportModal(menu.conn, menu.db) });
@@ -1384,6 +2848,12 @@ const App: React.FC = () => {
             if (!activeConn) return;
             showTableDdl(menu.conn, menu.db, menu.table.name, false);
           }
+        },
+        {
+          key: 'design',
+          label: '设计表',
+          icon: <EditOutlined />,
+          onClick: () => openTableDesigner(menu.conn, menu.db, menu.table.name)
         }
       );
     } else if (menu.type === 'view') {
@@ -1571,6 +3041,14 @@ const App: React.FC = () => {
             <Button size="small" icon={<ReloadOutlined />} onClick={() => activeConn && handleConnect(activeConn)} />
             <Button size="small" icon={<DesktopOutlined />} onClick={openSessionTab} />
             <Button size="small" icon={<DatabaseOutlined />} onClick={openMigrationTab} />
+            <Button size="small" icon={<HistoryOutlined />} onClick={openBinlogTab} />
+            <Button size="small" icon={<AuditOutlined />} onClick={openAuditTab} />
+            <Tooltip title="用户与权限">
+              <Button size="small" icon={<UserOutlined />} onClick={() => activeConn && openUsersTab(activeConn)} />
+            </Tooltip>
+            <Tooltip title="脱敏规则">
+              <Button size="small" icon={<EyeInvisibleOutlined />} onClick={openMaskingRules} />
+            </Tooltip>
           </div>
         </div>
       </Header>
@@ -1643,7 +3121,7 @@ const App: React.FC = () => {
               <Card
                 className="sql-card"
                 size="small"
-                style={(activeTab?.kind === 'migration' || activeTab?.kind === 'ddl' || activeTab?.kind === 'session')
+                style={(activeTab?.kind === 'migration' || activeTab?.kind === 'ddl' || activeTab?.kind === 'session' || activeTab?.kind === 'audit' || activeTab?.kind === 'users')
                   ? { flex: 1, minHeight: 0 }
                   : { height: sqlPaneHeight, minHeight: 220 }}
                 title={
@@ -1655,7 +3133,13 @@ const App: React.FC = () => {
                           ? '会话管理'
                           : activeTab?.kind === 'migration'
                             ? '数据库迁移'
-                            : 'SQL 查询窗口'}
+                            : activeTab?.kind === 'binlog'
+                              ? 'Binlog 闪回'
+                              : activeTab?.kind === 'audit'
+                                ? '审计日志'
+                                : activeTab?.kind === 'users'
+                                  ? '用户与权限'
+                                  : 'SQL 查询窗口'}
                     </span>
                     <span className="sql-card-conn">
                       {activeTab?.connId
@@ -1857,10 +3341,527 @@ const App: React.FC = () => {
                                 { title: '会话数', dataIndex: 'count', key: 'count', width: 80 }
                               ]}
                             />
-                          </div>
+                          </div>
+                        </div>
+                      </div>
+                    ) : tab.kind === 'binlog' ? (
+                      <div className="migration-page">
+                        <div className="migration-result">
+                          <Space wrap>
+                            <Radio.Group
+                              value={binlogQuery.source}
+                              onChange={e => setBinlogQuery(prev => ({ ...prev, source: e.target.value }))}
+                              options={[{ label: '从实例读取', value: 'server' }, { label: '本地文件', value: 'file' }]}
+                              optionType="button"
+                              size="small"
+                            />
+                            {binlogQuery.source === 'server' ? (
+                              <>
+                                <Select
+                                  size="small"
+                                  style={{ width: 200 }}
+                                  placeholder="起始文件"
+                                  value={binlogQuery.startFile || undefined}
+                                  onChange={v => setBinlogQuery(prev => ({ ...prev, startFile: v }))}
+                                  options={binlogFiles.map(f => ({ label: f.name, value: f.name }))}
+                                />
+                                <InputNumber
+                                  size="small"
+                                  min={0}
+                                  placeholder="起始位置"
+                                  value={binlogQuery.startPos || undefined}
+                                  onChange={v => setBinlogQuery(prev => ({ ...prev, startPos: Number(v) || 0 }))}
+                                />
+                                <Select
+                                  size="small"
+                                  style={{ width: 200 }}
+                                  allowClear
+                                  placeholder="结束文件（默认当前位点）"
+                                  value={binlogQuery.endFile || undefined}
+                                  onChange={v => setBinlogQuery(prev => ({ ...prev, endFile: v || '' }))}
+                                  options={binlogFiles.map(f => ({ label: f.name, value: f.name }))}
+                                />
+                                <Button size="small" onClick={() => loadBinlogFiles(tab.connId)}>刷新</Button>
+                              </>
+                            ) : (
+                              <>
+                                <Button size="small" onClick={chooseBinlogFiles}>选择文件</Button>
+                                <span>{binlogQuery.files.length > 0 ? `${binlogQuery.files.length} 个文件` : '未选择'}</span>
+                              </>
+                            )}
+                          </Space>
+                          <Space wrap>
+                            <Input size="small" style={{ width: 170 }} placeholder="开始时间 2006-01-02 15:04:05" value={binlogQuery.startTime} onChange={e => setBinlogQuery(prev => ({ ...prev, startTime: e.target.value }))} />
+                            <Input size="small" style={{ width: 170 }} placeholder="结束时间" value={binlogQuery.endTime} onChange={e => setBinlogQuery(prev => ({ ...prev, endTime: e.target.value }))} />
+                            <Input size="small" style={{ width: 140 }} placeholder="库（逗号分隔）" value={binlogQuery.databases} onChange={e => setBinlogQuery(prev => ({ ...prev, databases: e.target.value }))} />
+                            <Input size="small" style={{ width: 160 }} placeholder="表或 库.表（逗号分隔）" value={binlogQuery.tables} onChange={e => setBinlogQuery(prev => ({ ...prev, tables: e.target.value }))} />
+                            <Checkbox.Group
+                              value={binlogQuery.eventTypes}
+                              onChange={v => setBinlogQuery(prev => ({ ...prev, eventTypes: v as string[] }))}
+                              options={[{ label: 'INSERT', value: 'insert' }, { label: 'UPDATE', value: 'update' }, { label: 'DELETE', value: 'delete' }]}
+                            />
+                            <InputNumber size="small" min={1} max={100000} addonBefore="上限" value={binlogQuery.maxEvents} onChange={v => setBinlogQuery(prev => ({ ...prev, maxEvents: Number(v) || 1000 }))} />
+                            <Button size="small" type="primary" loading={binlogLoading} onClick={() => runBrowseBinlog(tab.connId)}>查询</Button>
+                          </Space>
+                        </div>
+
+                        {binlogResult && (
+                          <div className="migration-result">
+                            <div className="migration-section-title">
+                              <Space>
+                                <span>行变更（{binlogResult.changes?.length || 0}{binlogResult.truncated ? '，已截断' : ''}）</span>
+                                <span>扫描事件 {binlogResult.scannedEvents}，读到 {binlogResult.endFile}:{binlogResult.endPos}，时区 {binlogResult.timeZone}</span>
+                                <Button size="small" onClick={() => saveBinlogScript('forward')}>导出正向SQL</Button>
+                                <Button size="small" danger onClick={() => saveBinlogScript('flashback')}>导出回滚SQL</Button>
+                              </Space>
+                            </div>
+                            <Table
+                              size="small"
+                              rowKey={(r: any, i?: number) => `${r.file}-${r.pos}-${i}`}
+                              dataSource={binlogResult.changes || []}
+                              pagination={{ pageSize: 20, showSizeChanger: false }}
+                              expandable={{
+                                expandedRowRender: (r: any) => (
+                                  <div>
+                                    <Table
+                                      size="small"
+                                      rowKey="column"
+                                      pagination={false}
+                                      dataSource={(r.columns || []).map((c: string, i: number) => ({
+                                        column: c,
+                                        before: r.before ? r.before[i] : undefined,
+                                        after: r.after ? r.after[i] : undefined,
+                                        changed: (r.changed || []).includes(c)
+                                      }))}
+                                      rowClassName={(row: any) => (row.changed ? 'session-row-lock' : '')}
+                                      columns={[
+                                        { title: '列', dataIndex: 'column', key: 'column', width: 160 },
+                                        { title: '变更前', dataIndex: 'before', key: 'before', render: (v: any) => (v === null ? 'NULL' : v === undefined ? '' : String(v)) },
+                                        { title: '变更后', dataIndex: 'after', key: 'after', render: (v: any) => (v === null ? 'NULL' : v === undefined ? '' : String(v)) }
+                                      ]}
+                                    />
+                                    <Input.TextArea value={`-- 正向\n${r.forwardSql};\n-- 回滚\n${r.flashbackSql};`} readOnly autoSize={{ minRows: 3, maxRows: 8 }} style={{ fontFamily: 'monospace', marginTop: 8 }} />
+                                  </div>
+                                )
+                              }}
+                              columns={[
+                                { title: '时间', dataIndex: 'time', key: 'time', width: 160 },
+                                { title: '位点', key: 'pos', width: 200, render: (_: any, r: any) => `${r.file}:${r.pos}` },
+                                { title: '库.表', key: 'table', width: 200, render: (_: any, r: any) => `${r.database}.${r.table}` },
+                                { title: '类型', dataIndex: 'type', key: 'type', width: 80, render: (v: string) => v.toUpperCase() },
+                                { title: '变更列', key: 'changed', ellipsis: true, render: (_: any, r: any) => (r.changed || []).join(', ') },
+                                { title: 'GTID', dataIndex: 'gtid', key: 'gtid', ellipsis: true }
+                              ]}
+                            />
+                          </div>
+                        )}
+                      </div>
+                    ) : tab.kind === 'audit' ? (
+                      <div className="migration-page">
+                        <div className="migration-result">
+                          <Space wrap>
+                            <Input
+                              size="small"
+                              style={{ width: 130 }}
+                              placeholder="开始日期 2024-01-01"
+                              value={auditFilter.from}
+                              onChange={e => setAuditFilter(prev => ({ ...prev, from: e.target.value.trim() }))}
+                            />
+                            <Input
+                              size="small"
+                              style={{ width: 130 }}
+                              placeholder="结束日期"
+                              value={auditFilter.to}
+                              onChange={e => setAuditFilter(prev => ({ ...prev, to: e.target.value.trim() }))}
+                            />
+                            <Select
+                              size="small"
+                              style={{ width: 120 }}
+                              value={auditFilter.action}
+                              onChange={v => setAuditFilter(prev => ({ ...prev, action: v }))}
+                              options={[
+                                { label: '全部动作', value: '' },
+                                { label: '执行语句', value: 'query' },
+                                { label: '终止会话', value: 'kill' },
+                                { label: '同步', value: 'sync' },
+                                { label: '导出', value: 'export' },
+                                { label: '连接变更', value: 'connection' },
+                                { label: '表结构变更', value: 'ddl' },
+                                { label: '账号权限', value: 'account' },
+                                { label: '日志清理', value: 'audit' }
+                              ]}
+                            />
+                            <Select
+                              size="small"
+                              style={{ width: 100 }}
+                              value={auditFilter.outcome}
+                              onChange={v => setAuditFilter(prev => ({ ...prev, outcome: v }))}
+                              options={[
+                                { label: '全部结果', value: '' },
+                                { label: '成功', value: 'success' },
+                                { label: '失败', value: 'error' },
+                                { label: '拦截', value: 'blocked' },
+                                { label: '取消', value: 'canceled' }
+                              ]}
+                            />
+                            <Input.Search
+                              size="small"
+                              style={{ width: 220 }}
+                              placeholder="语句 / 连接 / 库 / 用户"
+                              allowClear
+                              value={auditFilter.keyword}
+                              onChange={e => setAuditFilter(prev => ({ ...prev, keyword: e.target.value }))}
+                              onSearch={() => {
+                                const next = { ...auditFilter, offset: 0 };
+                                setAuditFilter(next);
+                                loadAuditLog(next);
+                              }}
+                            />
+                            <Button size="small" type="primary" loading={auditLoading} onClick={() => {
+                              const next = { ...auditFilter, offset: 0 };
+                              setAuditFilter(next);
+                              loadAuditLog(next);
+                            }}>查询</Button>
+                            <Button size="small" onClick={runVerifyAuditLog}>校验哈希链</Button>
+                            <Button size="small" onClick={() => exportAuditLog('csv')}>导出 CSV</Button>
+                            <Button size="small" onClick={() => exportAuditLog('jsonl')}>导出 JSONL</Button>
+                            <Text type="secondary">保留天数</Text>
+                            <InputNumber
+                              size="small"
+                              min={0}
+                              placeholder="0 为永久"
+                              value={appSettings.auditRetentionDays || 0}
+                              onChange={v => setAppSettings(prev => ({ ...prev, auditRetentionDays: Number(v) || 0 }))}
+                              onBlur={() => saveAuditRetention(appSettings.auditRetentionDays || 0)}
+                            />
+                          </Space>
+                          {auditVerify && (
+                            <div style={{ marginTop: 8 }}>
+                              <Text type={auditVerify.ok ? 'success' : 'danger'}>
+                                {auditVerify.message}
+                                {!auditVerify.ok && auditVerify.brokenFile ? `（${auditVerify.brokenFile} 第 ${auditVerify.brokenLine} 行）` : ''}
+                              </Text>
+                            </div>
+                          )}
+                        </div>
+                        <div className="migration-result">
+                          <Table
+                            size="small"
+                            rowKey="seq"
+                            loading={auditLoading}
+                            dataSource={auditResult.entries}
+                            pagination={{
+                              current: Math.floor(auditFilter.offset / auditFilter.limit) + 1,
+                              pageSize: auditFilter.limit,
+                              total: auditResult.total,
+                              showSizeChanger: false,
+                              onChange: page => {
+                                const next = { ...auditFilter, offset: (page - 1) * auditFilter.limit };
+                                setAuditFilter(next);
+                                loadAuditLog(next);
+                              }
+                            }}
+                            expandable={{
+                              expandedRowRender: (r: any) => (
+                                <div>
+                                  <pre className="guard-sql">{r.statement}</pre>
+                                  {r.error && <Text type="danger">{r.error}</Text>}
+                                  <div><Text type="secondary">{`${r.osUser}@${r.hostname}  hash ${r.hash}  prev ${r.prevHash || '-'}`}</Text></div>
+                                </div>
+                              )
+                            }}
+                            columns={[
+                              { title: '#', dataIndex: 'seq', key: 'seq', width: 70 },
+                              { title: '时间', dataIndex: 'time', key: 'time', width: 170, render: (v: string) => new Date(v).toLocaleString() },
+                              { title: '用户', dataIndex: 'osUser', key: 'osUser', width: 100 },
+                              { title: '动作', dataIndex: 'action', key: 'action', width: 140 },
+                              {
+                                title: '连接',
+                                key: 'conn',
+                                width: 160,
+                                render: (_: any, r: any) => (
+                                  <span>
+                                    {r.connName || '-'}
+                                    {r.environment && r.environment !== 'dev' && <span className={`env-tag env-${r.environment}`}>{r.environment === 'prod' ? '生产' : '测试'}</span>}
+                                  </span>
+                                )
+                              },
+                              { title: '库', dataIndex: 'database', key: 'database', width: 120 },
+                              { title: '语句', dataIndex: 'statement', key: 'statement', ellipsis: true },
+                              {
+                                title: '结果',
+                                dataIndex: 'outcome',
+                                key: 'outcome',
+                                width: 80,
+                                render: (v: string) => (
+                                  <Text type={v === 'success' ? 'success' : v === 'canceled' ? 'secondary' : 'danger'}>
+                                    {({ success: '成功', error: '失败', blocked: '拦截', canceled: '取消' } as Record<string, string>)[v] || v}
+                                  </Text>
+                                )
+                              },
+                              { title: '耗时(ms)', dataIndex: 'durationMs', key: 'durationMs', width: 90 }
+                            ]}
+                          />
                         </div>
                       </div>
-                    ) : tab.kind === 'migration' ? (
+                    ) : tab.kind === 'users' ? (() => {
+                      const usersConn = connections.find(c => c.id === tab.connId);
+                      const isOracle = normalizeConnType(usersConn?.type || '') === 'oracle';
+                      const schemas = (tab.connId && dbList[tab.connId]) || [];
+                      const roles = accounts.filter(acc => acc.isRole);
+                      const keyword = accountFilter.trim().toLowerCase();
+                      const shownAccounts = keyword
+                        ? accounts.filter(acc => `${acc.user}@${acc.host}`.toLowerCase().includes(keyword))
+                        : accounts;
+                      const levelOptions = isOracle
+                        ? [{ label: '系统权限', value: 'global' }, { label: '对象', value: 'table' }, { label: '列', value: 'column' }]
+                        : [{ label: '全局', value: 'global' }, { label: '库', value: 'database' }, { label: '表', value: 'table' }, { label: '列', value: 'column' }];
+                      const levelLabel = (level: string) => levelOptions.find(o => o.value === level)?.label || level;
+                      return (
+                        <div className="migration-page">
+                          <Tabs
+                            size="small"
+                            items={[
+                              {
+                                key: 'accounts',
+                                label: '账号',
+                                children: (
+                                  <div className="users-pane">
+                                    <div className="users-list">
+                                      <Space wrap style={{ marginBottom: 8 }}>
+                                        <Input.Search size="small" style={{ width: 180 }} allowClear placeholder="用户名 / 主机" value={accountFilter} onChange={e => setAccountFilter(e.target.value)} />
+                                        <Button size="small" icon={<ReloadOutlined />} loading={accountsLoading} onClick={() => loadAccounts()}>刷新</Button>
+                                        <Button size="small" icon={<PlusOutlined />} onClick={() => setAccountModal({ action: 'createUser', user: '', host: '%', password: '', defaultTablespace: '' })}>新建用户</Button>
+                                        <Button size="small" icon={<PlusOutlined />} onClick={() => setAccountModal({ action: 'createRole', user: '', host: '%', password: '', defaultTablespace: '' })}>新建角色</Button>
+                                      </Space>
+                                      <Table
+                                        size="small"
+                                        rowKey={(r: DBAccount) => `${r.isRole ? 'role' : 'user'}|${r.user}@${r.host}`}
+                                        loading={accountsLoading}
+                                        dataSource={shownAccounts}
+                                        pagination={{ pageSize: 50, showSizeChanger: false }}
+                                        rowClassName={(r: DBAccount) => (selectedAccount && selectedAccount.user === r.user && selectedAccount.host === r.host && selectedAccount.isRole === r.isRole ? 'users-row-selected' : '')}
+                                        onRow={(r: DBAccount) => ({ onClick: () => selectAccount(r) })}
+                                        columns={[
+                                          {
+                                            title: '用户', dataIndex: 'user', key: 'user', ellipsis: true,
+                                            render: (v: string, r: DBAccount) => (
+                                              <span>{v}{r.isRole && <Tag color="blue" style={{ marginLeft: 6 }}>角色</Tag>}</span>
+                                            )
+                                          },
+                                          ...(isOracle ? [] : [{ title: '主机', dataIndex: 'host', key: 'host', width: 120, ellipsis: true }]),
+                                          {
+                                            title: '状态', key: 'status', width: 120,
+                                            render: (_: any, r: DBAccount) => (isOracle && r.status
+                                              ? <Text type={r.locked || r.passwordExpired ? 'warning' : undefined}>{r.status}</Text>
+                                              : r.isRole ? '-' : r.locked ? <Text type="warning">已锁定</Text> : r.passwordExpired ? <Text type="warning">密码过期</Text> : '正常')
+                                          },
+                                          isOracle
+                                            ? { title: '默认表空间', dataIndex: 'defaultTablespace', key: 'defaultTablespace', width: 110, ellipsis: true }
+                                            : { title: '认证插件', dataIndex: 'plugin', key: 'plugin', width: 150, ellipsis: true },
+                                          {
+                                            title: '操作', key: 'actions', width: 150,
+                                            render: (_: any, r: DBAccount) => (
+                                              <Space size={0} onClick={e => e.stopPropagation()}>
+                                                {!r.isRole && (
+                                                  <Button size="small" type="link" onClick={() => runAccountChange({ action: r.locked ? 'unlock' : 'lock', user: r.user, host: r.host }, r.locked ? '账号已解锁' : '账号已锁定')}>
+                                                    {r.locked ? '解锁' : '锁定'}
+                                                  </Button>
+                                                )}
+                                                {!r.isRole && (
+                                                  <Button size="small" type="link" onClick={() => setAccountModal({ action: 'password', user: r.user, host: r.host, password: '', defaultTablespace: '' })}>改密</Button>
+                                                )}
+                                                <Button size="small" type="link" danger onClick={() => dropAccount(r, isOracle)}>删除</Button>
+                                              </Space>
+                                            )
+                                          }
+                                        ]}
+                                      />
+                                    </div>
+                                    <div className="users-detail">
+                                      {!selectedAccount ? (
+                                        <Text type="secondary">选择左侧账号查看与修改授权</Text>
+                                      ) : (
+                                        <>
+                                          <div className="migration-section-title">
+                                            {selectedAccount.isRole ? '角色' : '用户'} {isOracle ? selectedAccount.user : `'${selectedAccount.user}'@'${selectedAccount.host}'`}
+                                          </div>
+                                          <div className="users-section">
+                                            <Text strong>已授予角色</Text>
+                                            <div className="users-roles">
+                                              {(accountGrants?.roles || []).map(role => (
+                                                <Tag
+                                                  key={role}
+                                                  closable
+                                                  onClose={e => {
+                                                    e.preventDefault();
+                                                    runAccountChange({ action: 'revokeRole', user: selectedAccount.user, host: selectedAccount.host, role }, `已回收角色 ${role}`);
+                                                  }}
+                                                >
+                                                  {role}
+                                                </Tag>
+                                              ))}
+                                              {(accountGrants?.roles || []).length === 0 && <Text type="secondary">无</Text>}
+                                            </div>
+                                            <Space>
+                                              <Select
+                                                size="small"
+                                                style={{ width: 200 }}
+                                                showSearch
+                                                placeholder="选择角色"
+                                                value={grantRoleName}
+                                                onChange={setGrantRoleName}
+                                                options={roles.map(r => {
+                                                  const name = isOracle ? r.user : `${r.user}@${r.host}`;
+                                                  return { label: name, value: name };
+                                                })}
+                                              />
+                                              <Button
+                                                size="small"
+                                                disabled={!grantRoleName}
+                                                onClick={() => grantRoleName && runAccountChange({ action: 'grantRole', user: selectedAccount.user, host: selectedAccount.host, role: grantRoleName }, `已授予角色 ${grantRoleName}`)}
+                                              >
+                                                授予角色
+                                              </Button>
+                                            </Space>
+                                          </div>
+                                          <div className="users-section">
+                                            <Text strong>授权 / 回收</Text>
+                                            <Space wrap style={{ marginTop: 6 }}>
+                                              <Select
+                                                size="small"
+                                                style={{ width: 110 }}
+                                                value={grantForm.level}
+                                                onChange={v => {
+                                                  setGrantForm(prev => ({ ...prev, level: v, privileges: [] }));
+                                                  loadGrantablePrivs(v);
+                                                }}
+                                                options={levelOptions}
+                                              />
+                                              {grantForm.level !== 'global' && (
+                                                <Select
+                                                  size="small"
+                                                  style={{ width: 150 }}
+                                                  showSearch
+                                                  placeholder={isOracle ? 'Schema' : '数据库'}
+                                                  value={grantForm.schema || undefined}
+                                                  onChange={v => setGrantForm(prev => ({ ...prev, schema: v }))}
+                                                  options={schemas.map(d => ({ label: d, value: d }))}
+                                                />
+                                              )}
+                                              {(grantForm.level === 'table' || grantForm.level === 'column') && (
+                                                <Input size="small" style={{ width: 150 }} placeholder={isOracle ? '对象名' : '表名'} value={grantForm.table} onChange={e => setGrantForm(prev => ({ ...prev, table: e.target.value }))} />
+                                              )}
+                                              {grantForm.level === 'column' && (
+                                                <Input size="small" style={{ width: 180 }} placeholder="列，逗号分隔" value={grantForm.columns} onChange={e => setGrantForm(prev => ({ ...prev, columns: e.target.value }))} />
+                                              )}
+                                            </Space>
+                                            <div style={{ marginTop: 6 }}>
+                                              <Select
+                                                size="small"
+                                                mode="multiple"
+                                                style={{ width: '100%' }}
+                                                placeholder="权限"
+                                                value={grantForm.privileges}
+                                                onChange={v => setGrantForm(prev => ({ ...prev, privileges: v }))}
+                                                options={[{ label: 'ALL PRIVILEGES', value: 'ALL PRIVILEGES' }, ...grantablePrivs.map(p => ({ label: p, value: p }))]}
+                                              />
+                                            </div>
+                                            <Space style={{ marginTop: 6 }}>
+                                              <Checkbox checked={grantForm.withGrantOption} onChange={e => setGrantForm(prev => ({ ...prev, withGrantOption: e.target.checked }))}>
+                                                {isOracle && grantForm.level === 'global' ? 'WITH ADMIN OPTION' : 'WITH GRANT OPTION'}
+                                              </Checkbox>
+                                              <Button size="small" type="primary" onClick={() => submitGrant('grant')}>授予</Button>
+                                              <Button size="small" danger onClick={() => submitGrant('revoke')}>回收</Button>
+                                            </Space>
+                                          </div>
+                                          <div className="users-section">
+                                            <Text strong>权限明细</Text>
+                                            <Table
+                                              size="small"
+                                              rowKey={(r: PrivilegeEntry) => `${r.level}|${r.schema}|${r.table}|${(r.columns || []).join(',')}|${r.privilege}`}
+                                              loading={!accountGrants}
+                                              dataSource={accountGrants?.privileges || []}
+                                              pagination={false}
+                                              scroll={{ y: 260 }}
+                                              columns={[
+                                                { title: '级别', dataIndex: 'level', key: 'level', width: 80, render: (v: string) => levelLabel(v) },
+                                                {
+                                                  title: '对象', key: 'object', ellipsis: true,
+                                                  render: (_: any, r: PrivilegeEntry) => (r.level === 'global'
+                                                    ? '*'
+                                                    : [r.schema, r.table].filter(Boolean).join('.') + (r.columns && r.columns.length ? ` (${r.columns.join(', ')})` : ''))
+                                                },
+                                                { title: '权限', dataIndex: 'privilege', key: 'privilege', width: 160 },
+                                                { title: '可转授', dataIndex: 'grantable', key: 'grantable', width: 70, render: (v: boolean) => (v ? '是' : '') },
+                                                {
+                                                  title: '', key: 'action', width: 60,
+                                                  render: (_: any, r: PrivilegeEntry) => <Button size="small" type="link" danger onClick={() => revokeEntry(r)}>回收</Button>
+                                                }
+                                              ]}
+                                            />
+                                          </div>
+                                          {(accountGrants?.grants || []).length > 0 && (
+                                            <div className="users-section">
+                                              <Text strong>GRANT 语句</Text>
+                                              <pre className="guard-sql">{(accountGrants?.grants || []).join(';\n') + ';'}</pre>
+                                            </div>
+                                          )}
+                                        </>
+                                      )}
+                                    </div>
+                                  </div>
+                                )
+                              },
+                              {
+                                key: 'matrix',
+                                label: '权限矩阵',
+                                children: (
+                                  <div>
+                                    <Space style={{ marginBottom: 8 }}>
+                                      <Select
+                                        size="small"
+                                        style={{ width: 200 }}
+                                        showSearch
+                                        placeholder={isOracle ? '选择 Schema' : '选择数据库'}
+                                        value={matrixSchema}
+                                        onChange={v => {
+                                          setMatrixSchema(v);
+                                          loadPrivilegeMatrix(v);
+                                        }}
+                                        options={schemas.map(d => ({ label: d, value: d }))}
+                                      />
+                                      <Button size="small" icon={<ReloadOutlined />} loading={matrixLoading} disabled={!matrixSchema} onClick={() => loadPrivilegeMatrix(matrixSchema)}>刷新</Button>
+                                      <Text type="secondary">✓ 已授予，✓G 可转授</Text>
+                                    </Space>
+                                    <Table
+                                      size="small"
+                                      loading={matrixLoading}
+                                      rowKey={(r: any) => `${r.user}@${r.host}|${r.level}|${r.object}`}
+                                      dataSource={privMatrix?.rows || []}
+                                      pagination={{ pageSize: 100, showSizeChanger: false }}
+                                      scroll={{ x: 'max-content', y: 480 }}
+                                      columns={[
+                                        { title: '账号', key: 'account', width: 180, fixed: 'left' as const, render: (_: any, r: any) => (isOracle ? r.user : `${r.user}@${r.host}`) },
+                                        { title: '级别', dataIndex: 'level', key: 'level', width: 70, fixed: 'left' as const, render: (v: string) => levelLabel(v) },
+                                        { title: '对象', dataIndex: 'object', key: 'object', width: 160, fixed: 'left' as const, ellipsis: true },
+                                        ...(privMatrix?.privileges || []).map(p => ({
+                                          title: p,
+                                          key: p,
+                                          width: Math.max(70, p.length * 8 + 16),
+                                          align: 'center' as const,
+                                          render: (_: any, r: any) => (p in (r.privileges || {}) ? (r.privileges[p] ? '✓G' : '✓') : '')
+                                        }))
+                                      ]}
+                                    />
+                                  </div>
+                                )
+                              }
+                            ]}
+                          />
+                        </div>
+                      );
+                    })() : tab.kind === 'migration' ? (
                       <div className="migration-page">
                         <div className="migration-mode">
                           <div className="migration-section-title">迁移模式</div>
@@ -1878,6 +3879,130 @@ const App: React.FC = () => {
                             optionType="button"
                             buttonStyle="solid"
                           />
+                          <Space style={{ marginLeft: 16 }}>
+                            <span>并发</span>
+                            <InputNumber
+                              min={1}
+                              max={32}
+                              value={migrationOptions.workers}
+                              onChange={(v) => setMigrationOptions(o => ({ ...o, workers: Number(v) || 4 }))}
+                            />
+                            <span>每批行数</span>
+                            <InputNumber
+                              min={1}
+                              max={100000}
+                              value={migrationOptions.batchSize}
+                              onChange={(v) => setMigrationOptions(o => ({ ...o, batchSize: Number(v) || 1000 }))}
+                            />
+                            <Tooltip title="行数超过该值且为单列整型主键的表按主键范围分片并行复制">
+                              <span>分片行数</span>
+                            </Tooltip>
+                            <InputNumber
+                              min={10000}
+                              step={100000}
+                              value={migrationOptions.chunkRows}
+                              onChange={(v) => setMigrationOptions(o => ({ ...o, chunkRows: Number(v) || 1000000 }))}
+                            />
+                            <span>目标表已存在</span>
+                            <Select
+                              style={{ width: 120 }}
+                              value={migrationOptions.onTableExists}
+                              onChange={(v) => {
+                                setMigrationOptions(o => ({ ...o, onTableExists: v }));
+                                setMigrationCheck([]);
+                              }}
+                              options={[
+                                { label: '报错', value: 'fail' },
+                                { label: '保留', value: 'keep' },
+                                { label: '删除重建', value: 'drop' }
+                              ]}
+                            />
+                            <span>目标表已有数据</span>
+                            <Select
+                              style={{ width: 150 }}
+                              value={migrationOptions.onDataExists}
+                              onChange={(v) => {
+                                setMigrationOptions(o => ({ ...o, onDataExists: v }));
+                                setMigrationCheck([]);
+                              }}
+                              options={[
+                                { label: '报错', value: 'fail' },
+                                { label: '先清空', value: 'truncate' },
+                                { label: '追加', value: 'append' },
+                                { label: '覆盖（upsert）', value: 'upsert' },
+                                { label: '跳过重复', value: 'ignore' }
+                              ]}
+                            />
+                            <Checkbox
+                              checked={migrationOptions.verify}
+                              onChange={(e) => setMigrationOptions(o => ({ ...o, verify: e.target.checked }))}
+                            >
+                              复制后校验和比对
+                            </Checkbox>
+                            <Tooltip title="按源连接的脱敏规则处理写入目标的数据，用于生成脱敏的测试副本（增量同步同样生效）">
+                              <Checkbox
+                                checked={migrationOptions.applyMasking}
+                                onChange={(e) => setMigrationOptions(o => ({ ...o, applyMasking: e.target.checked }))}
+                              >
+                                应用脱敏规则
+                              </Checkbox>
+                            </Tooltip>
+                          </Space>
+                          {migrationSourceIsOracle && (
+                            <div style={{ marginTop: 8 }}>
+                              <Tooltip
+                                overlayStyle={{ maxWidth: 520, whiteSpace: 'pre-line' }}
+                                title={oracleTypeRules.map(r => `${r.source} → ${r.target}${r.note ? `（${r.note}）` : ''}`).join('\n')}
+                              >
+                                <Text type="secondary">Oracle → MySQL 类型映射覆盖（悬停查看默认规则）：</Text>
+                              </Tooltip>
+                              <Input.TextArea
+                                rows={3}
+                                value={typeMappingText}
+                                onChange={(e) => setTypeMappingText(e.target.value)}
+                                placeholder={'每行一条，如：\nNUMBER(1,0)=TINYINT(1)\nVARCHAR2=VARCHAR({len})\nDATE=DATE'}
+                              />
+                            </div>
+                          )}
+                          <div style={{ marginTop: 8 }}>
+                            <Space wrap>
+                              <Text type="secondary">名称映射：</Text>
+                              <Select
+                                style={{ width: 140 }}
+                                value={nameMapping.tableCase}
+                                onChange={(v) => setNameMapping(m => ({ ...m, tableCase: v }))}
+                                options={[
+                                  { label: '表名不变', value: '' },
+                                  { label: '表名转小写', value: 'lower' },
+                                  { label: '表名转大写', value: 'upper' }
+                                ]}
+                              />
+                              <Select
+                                style={{ width: 140 }}
+                                value={nameMapping.columnCase}
+                                onChange={(v) => setNameMapping(m => ({ ...m, columnCase: v }))}
+                                options={[
+                                  { label: '列名不变', value: '' },
+                                  { label: '列名转小写', value: 'lower' },
+                                  { label: '列名转大写', value: 'upper' }
+                                ]}
+                              />
+                            </Space>
+                            <Input.TextArea
+                              style={{ marginTop: 8 }}
+                              rows={2}
+                              value={nameMapping.tableRules}
+                              onChange={(e) => setNameMapping(m => ({ ...m, tableRules: e.target.value }))}
+                              placeholder={'表名改名规则，每行一条“正则 => 替换”，如：\n^(.*)$ => ods_$1'}
+                            />
+                            <Input.TextArea
+                              style={{ marginTop: 8 }}
+                              rows={2}
+                              value={nameMapping.tables}
+                              onChange={(e) => setNameMapping(m => ({ ...m, tables: e.target.value }))}
+                              placeholder={'单表映射（JSON，可选），如：{"user": {"target": "t_user", "exclude": ["password"], "columns": {"name": "user_name"}, "transforms": {"email": "lower"}}}'}
+                            />
+                          </div>
                         </div>
                         <div className="migration-grid">
                           <div className="migration-card">
@@ -1993,9 +4118,52 @@ const App: React.FC = () => {
                             <Button onClick={runMigrationCheck} loading={migrationLoading}>
                               预检查
                             </Button>
+                            <Tooltip title="执行全部检查并列出将执行的操作与语句，不写入目标库">
+                              <Button onClick={runDryRun} loading={migrationLoading}>
+                                演练
+                              </Button>
+                            </Tooltip>
                             <Button type="primary" onClick={runMigration} loading={migrationLoading}>
                               开始同步
                             </Button>
+                            <Button onClick={runChecksumVerify} loading={migrationLoading}>
+                              行级校验
+                            </Button>
+                            <Tooltip title="选择一张表，按主键比对两侧数据并生成修复SQL">
+                              <Button onClick={() => runDataDiff('grid')} loading={migrationLoading}>
+                                数据比对
+                              </Button>
+                            </Tooltip>
+                            <Button onClick={() => runDataDiff('file')} loading={migrationLoading}>
+                              导出修复SQL
+                            </Button>
+                            <Button onClick={runSchemaDiff} loading={migrationLoading}>
+                              结构比对
+                            </Button>
+                            <Checkbox
+                              checked={schemaDiffOptions.ignoreAutoIncrement}
+                              onChange={(e) => setSchemaDiffOptions(o => ({ ...o, ignoreAutoIncrement: e.target.checked }))}
+                            >
+                              忽略自增值
+                            </Checkbox>
+                            <Checkbox
+                              checked={schemaDiffOptions.ignoreComments}
+                              onChange={(e) => setSchemaDiffOptions(o => ({ ...o, ignoreComments: e.target.checked }))}
+                            >
+                              忽略注释
+                            </Checkbox>
+                            <Checkbox
+                              checked={schemaDiffOptions.ignoreCharset}
+                              onChange={(e) => setSchemaDiffOptions(o => ({ ...o, ignoreCharset: e.target.checked }))}
+                            >
+                              忽略字符集
+                            </Checkbox>
+                            <Checkbox
+                              checked={schemaDiffOptions.ignoreCollation}
+                              onChange={(e) => setSchemaDiffOptions(o => ({ ...o, ignoreCollation: e.target.checked }))}
+                            >
+                              忽略排序规则
+                            </Checkbox>
                           </Space>
                         </div>
 
@@ -2009,12 +4177,153 @@ const App: React.FC = () => {
                             pagination={{ pageSize: 10, showSizeChanger: false }}
                             columns={[
                               { title: '表名', dataIndex: 'name', key: 'name' },
+                              { title: '目标表', dataIndex: 'target', key: 'target', render: (v: string, r: any) => v || r.name },
                               { title: '源行数', dataIndex: 'sourceRows', key: 'sourceRows', width: 100 },
                               { title: '目标行数', dataIndex: 'targetRows', key: 'targetRows', width: 100 },
-                              { title: '状态', dataIndex: 'status', key: 'status', width: 200 }
+                              { title: '状态', dataIndex: 'status', key: 'status', width: 200 },
+                              { title: '策略', key: 'policy', width: 140, render: (_: any, r: any) => [r.tablePolicy, r.dataPolicy].filter(Boolean).join(' / ') },
+                              { title: '插入', dataIndex: 'inserted', key: 'inserted', width: 80 },
+                              { title: '更新', dataIndex: 'updated', key: 'updated', width: 80 },
+                              { title: '跳过', dataIndex: 'skipped', key: 'skipped', width: 80 },
+                              { title: '操作', dataIndex: 'actions', key: 'actions', width: 220, render: (v: string[]) => (v || []).join('；') },
+                              { title: '续传', dataIndex: 'note', key: 'note', width: 260 },
+                              { title: '校验和', dataIndex: 'checksum', key: 'checksum', width: 260 }
+                            ]}
+                          />
+                        </div>
+
+                        <div className="migration-result">
+                          <div className="migration-section-title">
+                            <Space>
+                              <span>增量同步（binlog）</span>
+                              <Checkbox checked={cdcSkipCopy} onChange={e => setCdcSkipCopy(e.target.checked)}>跳过全量复制</Checkbox>
+                              <Tooltip title="先全量复制所选表，再从复制开始前的 binlog 位点持续应用行变更；结构变更不会自动应用">
+                                <Button size="small" type="primary" onClick={startCdc}>启动增量同步</Button>
+                              </Tooltip>
+                              <Button size="small" onClick={refreshCdcTasks}>刷新</Button>
+                            </Space>
+                          </div>
+                          <Table
+                            size="small"
+                            rowKey="id"
+                            dataSource={cdcTasks}
+                            pagination={{ pageSize: 5, showSizeChanger: false }}
+                            columns={[
+                              { title: '源库 → 目标库', key: 'db', width: 200, render: (_: any, r: any) => `${r.sourceDb} → ${r.targetDb}` },
+                              { title: '状态', dataIndex: 'state', key: 'state', width: 90, render: (v: string, r: any) => {
+                                const label = ({ copying: '全量复制', running: '同步中', paused: '已暂停', stopping: '追平中', stopped: '已停止', failed: '失败' } as Record<string, string>)[v] || v;
+                                return r.error ? <Tooltip title={r.error}><span style={{ color: '#ff4d4f' }}>{label}</span></Tooltip> : label;
+                              } },
+                              { title: '延迟(秒)', dataIndex: 'lagSeconds', key: 'lagSeconds', width: 80 },
+                              { title: '位点', key: 'pos', ellipsis: true, render: (_: any, r: any) => r.gtid || (r.file ? `${r.file}:${r.pos}` : '') },
+                              { title: '插入/更新/删除', key: 'counts', width: 130, render: (_: any, r: any) => `${r.inserted}/${r.updated}/${r.deleted}` },
+                              { title: '跳过DDL', dataIndex: 'skippedDdl', key: 'skippedDdl', width: 80, render: (v: number, r: any) => v ? <Tooltip title={r.lastDdl}>{v}</Tooltip> : 0 },
+                              { title: '操作', key: 'actions', width: 220, render: (_: any, r: any) => (
+                                <Space size="small">
+                                  {r.state === 'running' && <Button size="small" onClick={() => cdcAction('pause', r.id)}>暂停</Button>}
+                                  {(r.state === 'paused' || r.state === 'failed') && <Button size="small" onClick={() => cdcAction('resume', r.id)}>继续</Button>}
+                                  {r.state === 'running' && (
+                                    <Tooltip title="等待追平源库当前位点后停止，用于切换">
+                                      <Button size="small" onClick={() => cdcAction('cutover', r.id)}>切换停止</Button>
+                                    </Tooltip>
+                                  )}
+                                  {r.state !== 'stopped' && <Button size="small" danger onClick={() => cdcAction('stop', r.id)}>停止</Button>}
+                                  {(r.state === 'stopped' || r.state === 'paused' || r.state === 'failed') && <Button size="small" onClick={() => cdcAction('delete', r.id)}>删除</Button>}
+                                </Space>
+                              ) }
                             ]}
                           />
                         </div>
+
+                        {syncPlanScript && (
+                          <div className="migration-result">
+                            <div className="migration-section-title">
+                              <Space>
+                                <span>演练脚本</span>
+                                <Button size="small" onClick={saveSyncPlanScript}>导出SQL脚本</Button>
+                                <Button size="small" onClick={() => setSyncPlanScript('')}>关闭</Button>
+                              </Space>
+                            </div>
+                            <Input.TextArea value={syncPlanScript} rows={12} readOnly style={{ fontFamily: 'monospace' }} />
+                          </div>
+                        )}
+
+                        {schemaDiff && (
+                          <div className="migration-result">
+                            <div className="migration-section-title">
+                              <Space>
+                                <span>结构差异（{schemaDiff.items.length}）</span>
+                                <Button size="small" onClick={saveSchemaDiffScript} disabled={!schemaDiff.script}>保存同步脚本</Button>
+                              </Space>
+                            </div>
+                            <Table
+                              size="small"
+                              rowKey={(r: any) => `${r.objectType}-${r.table}-${r.name}`}
+                              dataSource={schemaDiff.items}
+                              pagination={{ pageSize: 10, showSizeChanger: false }}
+                              columns={[
+                                { title: '对象', dataIndex: 'objectType', key: 'objectType', width: 110 },
+                                { title: '表', dataIndex: 'table', key: 'table', width: 160 },
+                                { title: '名称', dataIndex: 'name', key: 'name', width: 160 },
+                                { title: '操作', dataIndex: 'action', key: 'action', width: 80, render: (v: string) => ({ create: '新增', drop: '删除', alter: '修改' } as Record<string, string>)[v] || v },
+                                { title: '源定义', dataIndex: 'source', key: 'source', ellipsis: true },
+                                { title: '目标定义', dataIndex: 'target', key: 'target', ellipsis: true }
+                              ]}
+                            />
+                            {schemaDiff.script && (
+                              <Input.TextArea value={schemaDiff.script} readOnly autoSize={{ minRows: 6, maxRows: 20 }} style={{ fontFamily: 'monospace', marginTop: 8 }} />
+                            )}
+                          </div>
+                        )}
+
+                        {dataDiff && (
+                          <div className="migration-result">
+                            <div className="migration-section-title">
+                              数据比对：{dataDiff.table}（目标缺少 {dataDiff.missing}，目标多出 {dataDiff.extra}，内容不同 {dataDiff.changed}{dataDiff.truncated ? '，仅显示前 1000 条' : ''}）
+                            </div>
+                            <Table
+                              size="small"
+                              rowKey={(r: any) => `${r.kind}-${r.key}`}
+                              dataSource={dataDiff.rows}
+                              pagination={{ pageSize: 10, showSizeChanger: false }}
+                              columns={[
+                                { title: '类型', dataIndex: 'kind', key: 'kind', width: 90, render: (v: string) => ({ missing: '目标缺少', extra: '目标多出', changed: '内容不同' } as Record<string, string>)[v] || v },
+                                { title: '键', dataIndex: 'key', key: 'key', width: 200 },
+                                { title: '不同的列', dataIndex: 'columns', key: 'columns', width: 200, render: (v: string[]) => (v || []).join(', ') },
+                                { title: '修复SQL', dataIndex: 'sql', key: 'sql', ellipsis: true }
+                              ]}
+                            />
+                          </div>
+                        )}
+
+                        {syncCheckpoints.length > 0 && (
+                          <div className="migration-result">
+                            <div className="migration-section-title">未完成的同步（可续传）</div>
+                            <Table
+                              size="small"
+                              rowKey="jobId"
+                              dataSource={syncCheckpoints}
+                              pagination={false}
+                              columns={[
+                                { title: '源库', dataIndex: 'sourceDb', key: 'sourceDb' },
+                                { title: '目标库', dataIndex: 'targetDb', key: 'targetDb' },
+                                { title: '已完成表', key: 'tables', width: 100, render: (_: any, r: any) => `${r.tablesDone} / ${r.tablesAll}` },
+                                { title: '更新时间', dataIndex: 'updatedAt', key: 'updatedAt', width: 200 },
+                                {
+                                  title: '操作',
+                                  key: 'action',
+                                  width: 160,
+                                  render: (_: any, r: any) => (
+                                    <Space>
+                                      <Button size="small" type="link" onClick={() => resumeSync(r.jobId)} disabled={migrationLoading}>续传</Button>
+                                      <Button size="small" type="link" danger onClick={() => deleteSyncCheckpoint(r.jobId)} disabled={migrationLoading}>放弃</Button>
+                                    </Space>
+                                  )
+                                }
+                              ]}
+                            />
+                          </div>
+                        )}
                       </div>
                     ) : (
                       <div>
@@ -2037,6 +4346,12 @@ const App: React.FC = () => {
                                 loading={tab.loading}
                               />
                             </Tooltip>
+                            <Tooltip title="格式化 SQL（Shift+Alt+F）">
+                              <Button shape="circle" icon={<AlignLeftOutlined />} onClick={() => formatTabSql(tab.key, false)} />
+                            </Tooltip>
+                            <Tooltip title="压缩为一行">
+                              <Button shape="circle" icon={<CompressOutlined />} onClick={() => formatTabSql(tab.key, true)} />
+                            </Tooltip>
                           </div>
                           <div className="sql-editor-wrapper">
                             <Editor
@@ -2047,56 +4362,43 @@ const App: React.FC = () => {
                             onMount={(editor, monaco) => {
                               editorRef.current = editor;
                               monacoRef.current = monaco;
+                              editor.addCommand(monaco.KeyMod.Shift | monaco.KeyMod.Alt | monaco.KeyCode.KeyF, () => formatSqlRef.current(false));
                               if (!completionRegistered.current) {
                                 completionRegistered.current = true;
                                 monaco.languages.registerCompletionItemProvider('sql', {
                                   triggerCharacters: ['.', ' '],
-                                  provideCompletionItems: (model, position) => {
-                                    const line = model.getLineContent(position.lineNumber);
-                                    const prefix = line.slice(0, position.column - 1);
-                                    const tableMatch = prefix.match(/(?:"([^"]+)"|`([^`]+)`|([a-zA-Z0-9_$#]+))\.$/);
-                                    if (tableMatch) {
-                                      const upToCursor = model.getValue().slice(0, model.getOffsetAt(position));
-                                      const aliasMap = parseAliasMap(upToCursor);
-                                      const inputTableName = tableMatch[1] || tableMatch[2] || tableMatch[3];
-                                      const tableName = aliasMap[inputTableName] || inputTableName;
-                                      const range = new monaco.Range(
-                                        position.lineNumber,
-                                        position.column,
-                                        position.lineNumber,
-                                        position.column
-                                      );
-                                      const cols = suggestionRef.current.columns
-                                        .filter(col => col.table === tableName)
-                                        .map(col => ({
-                                          label: col.column,
-                                          kind: monaco.languages.CompletionItemKind.Field,
-                                          insertText: col.column,
-                                          range,
-                                          detail: tableName
-                                        }));
-                                      return { suggestions: cols };
+                                  provideCompletionItems: async (model, position) => {
+                                    const { conn, db } = suggestContextRef.current;
+                                    if (!conn || !db) return { suggestions: [] };
+                                    let result;
+                                    try {
+                                      result = await Suggest(conn, db, model.getValue(), model.getOffsetAt(position));
+                                    } catch {
+                                      return { suggestions: [] };
                                     }
                                     const range = new monaco.Range(
                                       position.lineNumber,
-                                      position.column - 1,
+                                      Math.max(1, position.column - (result.prefix || '').length),
                                       position.lineNumber,
                                       position.column
                                     );
-                                    const tables = suggestionRef.current.tables.map(name => ({
-                                      label: name,
-                                      kind: monaco.languages.CompletionItemKind.Class,
-                                      insertText: name,
+                                    const kinds: Record<string, Monaco.languages.CompletionItemKind> = {
+                                      table: monaco.languages.CompletionItemKind.Class,
+                                      view: monaco.languages.CompletionItemKind.Interface,
+                                      column: monaco.languages.CompletionItemKind.Field,
+                                      routine: monaco.languages.CompletionItemKind.Function,
+                                      keyword: monaco.languages.CompletionItemKind.Keyword,
+                                      schema: monaco.languages.CompletionItemKind.Module
+                                    };
+                                    const suggestions = (result.items || []).map((item, idx) => ({
+                                      label: item.label,
+                                      kind: kinds[item.kind] ?? monaco.languages.CompletionItemKind.Text,
+                                      insertText: item.label,
+                                      detail: item.detail,
+                                      sortText: String(idx).padStart(4, '0'),
                                       range
                                     }));
-                                    const columns = suggestionRef.current.columns.map(col => ({
-                                      label: `${col.table}.${col.column}`,
-                                      kind: monaco.languages.CompletionItemKind.Field,
-                                      insertText: col.column,
-                                      range,
-                                      detail: col.table
-                                    }));
-                                    return { suggestions: [...tables, ...columns] };
+                                    return { suggestions };
                                   }
                                 });
                               }
@@ -2148,6 +4450,7 @@ const App: React.FC = () => {
                       if (!activeTab) return;
                       const results = activeTab.results || [];
                       const nextResults = results.filter(r => r.key !== targetKey);
+                      if (gridEdit?.resultKey === targetKey) setGridEdit(null);
                       let nextActive = activeTab.activeResultKey;
                       if (nextActive === targetKey) {
                         nextActive = nextResults.length ? nextResults[nextResults.length - 1].key : undefined;
@@ -2164,9 +4467,33 @@ const App: React.FC = () => {
                                 耗时 {(activeTab?.results || []).find(r => r.key === activeTab?.activeResultKey)?.durationMs} ms
                               </Text>
                             )}
-                            <Button size="small" onClick={() => exportResultToExcel(activeTab?.activeResultKey || '')}>
+                            {gridEdit && gridEdit.tabKey === activeTab?.key && gridEdit.resultKey === activeTab?.activeResultKey ? (
+                              <>
+                                <Text type="secondary">
+                                  {gridEdit.info.table}（按 {gridEdit.info.key.join(', ')} 定位）
+                                </Text>
+                                <Button size="small" icon={<PlusOutlined />} onClick={addGridRow}>新增行</Button>
+                                <Button size="small" type="primary" loading={gridSaving} onClick={saveGridEdit}>
+                                  预览并提交
+                                </Button>
+                                <Button size="small" onClick={cancelGridEdit}>退出编辑</Button>
+                              </>
+                            ) : (
+                              <Button
+                                size="small"
+                                icon={<EditOutlined />}
+                                disabled={!!gridEdit}
+                                onClick={() => startGridEdit(activeTab?.activeResultKey || '')}
+                              >
+                                编辑
+                              </Button>
+                            )}
+                            <Button size="small" onClick={() => exportResultToFile(activeTab?.activeResultKey || '', 'xlsx')}>
                               导出 Excel
                             </Button>
+                            <Button size="small" onClick={() => exportResultToFile(activeTab?.activeResultKey || '', 'csv')}>
+                              导出 CSV
+                            </Button>
                           </Space>
                         </div>
                       </div>
@@ -2176,6 +4503,20 @@ const App: React.FC = () => {
                       label: result.title,
                       children: (
                         <div className="result-table-wrap">
+                          {gridEdit && gridEdit.tabKey === activeTab?.key && gridEdit.resultKey === result.key ? (
+                            <Table
+                              dataSource={gridEdit.rows.map(row => ({ ...row.original, ...row.values, __row: row }))}
+                              columns={gridEditColumns(gridEdit, result.columns || [])}
+                              size="small"
+                              bordered
+                              sticky
+                              rowKey={(record: any) => record.__row.key}
+                              rowClassName={(record: any) => `grid-row-${record.__row.status}`}
+                              pagination={{ defaultPageSize: 10, showSizeChanger: true }}
+                              scroll={{ x: 'max-content', y: 420 }}
+                              tableLayout="fixed"
+                            />
+                          ) : (
                           <Table
                             dataSource={result.data || []}
                             columns={(result.columns || []).map((col: any) => {
@@ -2209,6 +4550,7 @@ const App: React.FC = () => {
                             scroll={{ x: 'max-content', y: 420 }}
                             tableLayout="fixed"
                           />
+                          )}
                         </div>
                       )
                     }))}
@@ -2245,6 +4587,457 @@ const App: React.FC = () => {
         />
       </Modal>
 
+      {/* 脱敏规则弹窗 */}
+      <Modal
+        title="脱敏规则"
+        open={isMaskingOpen}
+        onCancel={() => setIsMaskingOpen(false)}
+        onOk={saveMaskingRules}
+        okText="保存"
+        cancelText="取消"
+        width={1100}
+      >
+        <div className="masking-toolbar">
+          <Space>
+            <Button size="small" icon={<PlusOutlined />} onClick={addMaskingRule}>新增规则</Button>
+            <Text type="secondary">示例值</Text>
+            <Input size="small" style={{ width: 200 }} value={maskSample} onChange={e => setMaskSample(e.target.value)} />
+            <Button size="small" onClick={previewMaskingRules}>预览</Button>
+          </Space>
+          <Text type="secondary">连接、库、表、列支持 * ? 通配，逗号分隔多个；连接可填名称或ID，库、表留空表示全部</Text>
+        </div>
+        <Table
+          rowKey="id"
+          size="small"
+          pagination={false}
+          dataSource={maskingRules}
+          scroll={{ y: 420 }}
+          columns={[
+            {
+              title: '启用', dataIndex: 'enabled', width: 60,
+              render: (_: any, r: MaskingRule) => <Switch size="small" checked={r.enabled} onChange={v => updateMaskingRule(r.id, { enabled: v })} />
+            },
+            {
+              title: '名称', dataIndex: 'name', width: 120,
+              render: (_: any, r: MaskingRule) => <Input size="small" value={r.name} onChange={e => updateMaskingRule(r.id, { name: e.target.value })} />
+            },
+            {
+              title: '连接', dataIndex: 'connection', width: 110,
+              render: (_: any, r: MaskingRule) => <Input size="small" value={r.connection} onChange={e => updateMaskingRule(r.id, { connection: e.target.value })} />
+            },
+            {
+              title: '库', dataIndex: 'schema', width: 100,
+              render: (_: any, r: MaskingRule) => <Input size="small" value={r.schema} onChange={e => updateMaskingRule(r.id, { schema: e.target.value })} />
+            },
+            {
+              title: '表', dataIndex: 'table', width: 100,
+              render: (_: any, r: MaskingRule) => <Input size="small" value={r.table} onChange={e => updateMaskingRule(r.id, { table: e.target.value })} />
+            },
+            {
+              title: '列', dataIndex: 'column', width: 120,
+              render: (_: any, r: MaskingRule) => <Input size="small" value={r.column} placeholder="phone,*mobile*" onChange={e => updateMaskingRule(r.id, { column: e.target.value })} />
+            },
+            {
+              title: '策略', dataIndex: 'strategy', width: 110,
+              render: (_: any, r: MaskingRule) => (
+                <Select
+                  size="small"
+                  style={{ width: '100%' }}
+                  value={r.strategy}
+                  onChange={v => updateMaskingRule(r.id, { strategy: v })}
+                  options={[
+                    { label: '部分遮盖', value: 'partial' },
+                    { label: '哈希', value: 'hash' },
+                    { label: '置空', value: 'null' },
+                    { label: '固定值', value: 'fixed' }
+                  ]}
+                />
+              )
+            },
+            {
+              title: '参数', key: 'params', width: 190,
+              render: (_: any, r: MaskingRule) => {
+                if (r.strategy === 'partial') {
+                  return (
+                    <Space size={4}>
+                      <Tooltip title="保留前几位"><InputNumber size="small" min={0} style={{ width: 56 }} value={r.keepPrefix} onChange={v => updateMaskingRule(r.id, { keepPrefix: Number(v) || 0 })} /></Tooltip>
+                      <Tooltip title="保留后几位"><InputNumber size="small" min={0} style={{ width: 56 }} value={r.keepSuffix} onChange={v => updateMaskingRule(r.id, { keepSuffix: Number(v) || 0 })} /></Tooltip>
+                      <Tooltip title="替换字符"><Input size="small" maxLength={1} style={{ width: 36 }} value={r.maskChar} onChange={e => updateMaskingRule(r.id, { maskChar: e.target.value })} /></Tooltip>
+                    </Space>
+                  );
+                }
+                if (r.strategy === 'fixed') {
+                  return <Input size="small" value={r.fixedValue} placeholder="替换值" onChange={e => updateMaskingRule(r.id, { fixedValue: e.target.value })} />;
+                }
+                return <Text type="secondary">-</Text>;
+              }
+            },
+            {
+              title: '预览', key: 'preview', width: 130, ellipsis: true,
+              render: (_: any, r: MaskingRule) => <Text code>{maskPreview[r.id] ?? ''}</Text>
+            },
+            {
+              title: '', key: 'action', width: 44,
+              render: (_: any, r: MaskingRule) => (
+                <Button size="small" type="text" danger icon={<DeleteOutlined />} onClick={() => setMaskingRules(prev => prev.filter(x => x.id !== r.id))} />
+              )
+            }
+          ]}
+        />
+      </Modal>
+
+      {/* 新建用户、角色与修改密码 */}
+      <Modal
+        title={accountModal ? ({ createUser: '新建用户', createRole: '新建角色', password: `修改密码 - ${accountModal.user}` } as Record<string, string>)[accountModal.action] : ''}
+        open={!!accountModal}
+        onCancel={() => setAccountModal(null)}
+        onOk={submitAccountModal}
+        okText="预览并执行"
+        cancelText="取消"
+        width={460}
+      >
+        {accountModal && (() => {
+          const usersConn = connections.find(c => c.id === queryTabs.find(tab => tab.kind === 'users')?.connId);
+          const isOracle = normalizeConnType(usersConn?.type || '') === 'oracle';
+          return (
+            <Form layout="vertical" size="small">
+              <Form.Item label={accountModal.action === 'createRole' ? '角色名' : '用户名'}>
+                <Input value={accountModal.user} disabled={accountModal.action === 'password'} onChange={e => setAccountModal(prev => (prev ? { ...prev, user: e.target.value } : prev))} />
+              </Form.Item>
+              {!isOracle && accountModal.action !== 'createRole' && (
+                <Form.Item label="主机" extra="% 表示任意主机">
+                  <Input value={accountModal.host} disabled={accountModal.action === 'password'} onChange={e => setAccountModal(prev => (prev ? { ...prev, host: e.target.value } : prev))} />
+                </Form.Item>
+              )}
+              {accountModal.action !== 'createRole' && (
+                <Form.Item label={accountModal.action === 'password' ? '新密码' : '密码'}>
+                  <Input.Password value={accountModal.password} onChange={e => setAccountModal(prev => (prev ? { ...prev, password: e.target.value } : prev))} />
+                </Form.Item>
+              )}
+              {isOracle && accountModal.action === 'createUser' && (
+                <Form.Item label="默认表空间" extra="留空使用数据库默认表空间；指定时同时授予该表空间的无限配额">
+                  <Input value={accountModal.defaultTablespace} onChange={e => setAccountModal(prev => (prev ? { ...prev, defaultTablespace: e.target.value } : prev))} />
+                </Form.Item>
+              )}
+            </Form>
+          );
+        })()}
+      </Modal>
+
+      {/* 表设计器 */}
+      <Modal
+        title={tableDesigner ? (tableDesigner.model.originalName ? `设计表 - ${tableDesigner.db}.${tableDesigner.model.originalName}` : `新建表 - ${tableDesigner.db}`) : ''}
+        open={!!tableDesigner}
+        onCancel={() => setTableDesigner(null)}
+        width={1200}
+        footer={tableDesigner && (
+          <Space>
+            <Checkbox checked={tableDesigner.online} onChange={e => setTableDesigner(prev => (prev ? { ...prev, online: e.target.checked, ddl: null } : prev))}>
+              {normalizeConnType(tableDesigner.conn.type) === 'oracle' ? '在线创建索引 (ONLINE)' : '在线变更 (ALGORITHM=INPLACE, LOCK=NONE)'}
+            </Checkbox>
+            <Button onClick={() => setTableDesigner(null)}>取消</Button>
+            <Button onClick={previewTableDesign} loading={designerBusy}>预览SQL</Button>
+            <Button
+              type="primary"
+              danger={!!tableDesigner.ddl && !tableDesigner.ddl.create}
+              disabled={!tableDesigner.ddl || tableDesigner.ddl.statements.length === 0}
+              loading={designerBusy}
+              onClick={executeTableDesign}
+            >
+              执行
+            </Button>
+          </Space>
+        )}
+      >
+        {tableDesigner && (() => {
+          const { model } = tableDesigner;
+          const isOracle = normalizeConnType(tableDesigner.conn.type) === 'oracle';
+          return (
+            <div className="table-designer">
+              <Space wrap className="table-designer-head">
+                <Input addonBefore="表名" style={{ width: 260 }} value={model.name} onChange={e => updateDesignModel({ name: e.target.value })} />
+                <Input addonBefore="注释" style={{ width: 320 }} value={model.comment} onChange={e => updateDesignModel({ comment: e.target.value })} />
+              </Space>
+              <Tabs
+                size="small"
+                items={[
+                  {
+                    key: 'columns',
+                    label: `列 (${model.columns.length})`,
+                    children: (
+                      <>
+                        <Button size="small" icon={<PlusOutlined />} onClick={() => addDesignItem('columns')} style={{ marginBottom: 8 }}>新增列</Button>
+                        <Table
+                          rowKey="key"
+                          size="small"
+                          pagination={false}
+                          dataSource={model.columns}
+                          scroll={{ y: 360 }}
+                          rowClassName={(r: DesignColumn) => (r.originalName ? '' : 'grid-row-new')}
+                          columns={[
+                            {
+                              title: '列名', dataIndex: 'name', width: 150,
+                              render: (_: any, r: DesignColumn) => <Input size="small" value={r.name} onChange={e => updateDesignItem('columns', r.key, { name: e.target.value })} />
+                            },
+                            {
+                              title: '类型', dataIndex: 'type', width: 150,
+                              render: (_: any, r: DesignColumn) => <Input size="small" value={r.type} placeholder={isOracle ? 'VARCHAR2(64)' : 'varchar(64)'} onChange={e => updateDesignItem('columns', r.key, { type: e.target.value })} />
+                            },
+                            {
+                              title: '可空', dataIndex: 'nullable', width: 56,
+                              render: (_: any, r: DesignColumn) => <Checkbox checked={r.nullable} onChange={e => updateDesignItem('columns', r.key, { nullable: e.target.checked })} />
+                            },
+                            {
+                              title: '默认值', dataIndex: 'default', width: 230,
+                              render: (_: any, r: DesignColumn) => (
+                                <Space size={4}>
+                                  <Tooltip title="设置默认值">
+                                    <Checkbox checked={r.default !== null} onChange={e => updateDesignItem('columns', r.key, { default: e.target.checked ? '' : null })} />
+                                  </Tooltip>
+                                  <Input size="small" style={{ width: 130 }} disabled={r.default === null} value={r.default ?? ''} onChange={e => updateDesignItem('columns', r.key, { default: e.target.value })} />
+                                  <Tooltip title="表达式，如 CURRENT_TIMESTAMP，原样输出不加引号">
+                                    <Checkbox checked={r.defaultIsExpr} disabled={r.default === null} onChange={e => updateDesignItem('columns', r.key, { defaultIsExpr: e.target.checked })}>表达式</Checkbox>
+                                  </Tooltip>
+                                </Space>
+                              )
+                            },
+                            {
+                              title: '自增', dataIndex: 'autoIncrement', width: 56,
+                              render: (_: any, r: DesignColumn) => <Checkbox checked={r.autoIncrement} onChange={e => updateDesignItem('columns', r.key, { autoIncrement: e.target.checked })} />
+                            },
+                            ...(isOracle ? [] : [
+                              {
+                                title: 'ON UPDATE', dataIndex: 'onUpdate', width: 150,
+                                render: (_: any, r: DesignColumn) => <Input size="small" value={r.onUpdate} placeholder="CURRENT_TIMESTAMP" onChange={e => updateDesignItem('columns', r.key, { onUpdate: e.target.value })} />
+                              },
+                              {
+                                title: '排序规则', dataIndex: 'collation', width: 140,
+                                render: (_: any, r: DesignColumn) => <Input size="small" value={r.collation} onChange={e => updateDesignItem('columns', r.key, { collation: e.target.value, charset: e.target.value ? e.target.value.split('_')[0] : '' })} />
+                              }
+                            ]),
+                            {
+                              title: '生成列表达式', dataIndex: 'generated', width: 160,
+                              render: (_: any, r: DesignColumn) => <Input size="small" value={r.generated} onChange={e => updateDesignItem('columns', r.key, { generated: e.target.value })} />
+                            },
+                            {
+                              title: '注释', dataIndex: 'comment',
+                              render: (_: any, r: DesignColumn) => <Input size="small" value={r.comment} onChange={e => updateDesignItem('columns', r.key, { comment: e.target.value })} />
+                            },
+                            {
+                              title: '', key: 'action', width: 96,
+                              render: (_: any, r: DesignColumn) => (
+                                <Space size={0}>
+                                  <Button size="small" type="text" onClick={() => moveDesignColumn(r.key, -1)}>↑</Button>
+                                  <Button size="small" type="text" onClick={() => moveDesignColumn(r.key, 1)}>↓</Button>
+                                  <Button size="small" type="text" danger icon={<DeleteOutlined />} onClick={() => removeDesignItem('columns', r.key)} />
+                                </Space>
+                              )
+                            }
+                          ]}
+                        />
+                      </>
+                    )
+                  },
+                  {
+                    key: 'indexes',
+                    label: `索引 (${model.indexes.length})`,
+                    children: (
+                      <>
+                        <Button size="small" icon={<PlusOutlined />} onClick={() => addDesignItem('indexes')} style={{ marginBottom: 8 }}>新增索引</Button>
+                        <Table
+                          rowKey="key"
+                          size="small"
+                          pagination={false}
+                          dataSource={model.indexes}
+                          columns={[
+                            {
+                              title: '名称', dataIndex: 'name', width: 180,
+                              render: (_: any, r: DesignIndex) => <Input size="small" value={r.name} disabled={r.kind === 'primary' && !isOracle} placeholder={r.kind === 'primary' ? 'PRIMARY' : ''} onChange={e => updateDesignItem('indexes', r.key, { name: e.target.value })} />
+                            },
+                            {
+                              title: '类型', dataIndex: 'kind', width: 120,
+                              render: (_: any, r: DesignIndex) => (
+                                <Select
+                                  size="small"
+                                  style={{ width: '100%' }}
+                                  value={r.kind}
+                                  onChange={v => updateDesignItem('indexes', r.key, { kind: v })}
+                                  options={[
+                                    { label: '主键', value: 'primary' },
+                                    { label: '唯一', value: 'unique' },
+                                    { label: '普通', value: 'index' },
+                                    ...(isOracle ? [] : [{ label: '全文', value: 'fulltext' }, { label: '空间', value: 'spatial' }])
+                                  ]}
+                                />
+                              )
+                            },
+                            {
+                              title: '列', dataIndex: 'columnsText',
+                              render: (_: any, r: DesignIndex) => <Input size="small" value={r.columnsText} placeholder={isOracle ? 'COL1, COL2 DESC' : 'col1, col2(10), col3 DESC'} onChange={e => updateDesignItem('indexes', r.key, { columnsText: e.target.value })} />
+                            },
+                            ...(isOracle ? [] : [{
+                              title: '注释', dataIndex: 'comment', width: 200,
+                              render: (_: any, r: DesignIndex) => <Input size="small" value={r.comment} onChange={e => updateDesignItem('indexes', r.key, { comment: e.target.value })} />
+                            }]),
+                            {
+                              title: '', key: 'action', width: 44,
+                              render: (_: any, r: DesignIndex) => <Button size="small" type="text" danger icon={<DeleteOutlined />} onClick={() => removeDesignItem('indexes', r.key)} />
+                            }
+                          ]}
+                        />
+                      </>
+                    )
+                  },
+                  {
+                    key: 'foreignKeys',
+                    label: `外键 (${model.foreignKeys.length})`,
+                    children: (
+                      <>
+                        <Button size="small" icon={<PlusOutlined />} onClick={() => addDesignItem('foreignKeys')} style={{ marginBottom: 8 }}>新增外键</Button>
+                        <Table
+                          rowKey="key"
+                          size="small"
+                          pagination={false}
+                          dataSource={model.foreignKeys}
+                          columns={[
+                            {
+                              title: '名称', dataIndex: 'name', width: 160,
+                              render: (_: any, r: DesignForeignKey) => <Input size="small" value={r.name} onChange={e => updateDesignItem('foreignKeys', r.key, { name: e.target.value })} />
+                            },
+                            {
+                              title: '列', dataIndex: 'columns', width: 160,
+                              render: (_: any, r: DesignForeignKey) => <Input size="small" value={r.columns.join(', ')} onChange={e => updateDesignItem('foreignKeys', r.key, { columns: splitNames(e.target.value) })} />
+                            },
+                            {
+                              title: '引用库', dataIndex: 'refSchema', width: 120,
+                              render: (_: any, r: DesignForeignKey) => <Input size="small" value={r.refSchema} placeholder="当前库" onChange={e => updateDesignItem('foreignKeys', r.key, { refSchema: e.target.value })} />
+                            },
+                            {
+                              title: '引用表', dataIndex: 'refTable', width: 140,
+                              render: (_: any, r: DesignForeignKey) => <Input size="small" value={r.refTable} onChange={e => updateDesignItem('foreignKeys', r.key, { refTable: e.target.value })} />
+                            },
+                            {
+                              title: '引用列', dataIndex: 'refColumns', width: 160,
+                              render: (_: any, r: DesignForeignKey) => <Input size="small" value={r.refColumns.join(', ')} onChange={e => updateDesignItem('foreignKeys', r.key, { refColumns: splitNames(e.target.value) })} />
+                            },
+                            {
+                              title: 'ON DELETE', dataIndex: 'onDelete', width: 130,
+                              render: (_: any, r: DesignForeignKey) => (
+                                <Select
+                                  size="small"
+                                  style={{ width: '100%' }}
+                                  value={r.onDelete || ''}
+                                  onChange={v => updateDesignItem('foreignKeys', r.key, { onDelete: v })}
+                                  options={[{ label: '无', value: '' }, { label: 'CASCADE', value: 'CASCADE' }, { label: 'SET NULL', value: 'SET NULL' }, ...(isOracle ? [] : [{ label: 'RESTRICT', value: 'RESTRICT' }])]}
+                                />
+                              )
+                            },
+                            ...(isOracle ? [] : [{
+                              title: 'ON UPDATE', dataIndex: 'onUpdate', width: 130,
+                              render: (_: any, r: DesignForeignKey) => (
+                                <Select
+                                  size="small"
+                                  style={{ width: '100%' }}
+                                  value={r.onUpdate || ''}
+                                  onChange={v => updateDesignItem('foreignKeys', r.key, { onUpdate: v })}
+                                  options={[{ label: '无', value: '' }, { label: 'CASCADE', value: 'CASCADE' }, { label: 'SET NULL', value: 'SET NULL' }, { label: 'RESTRICT', value: 'RESTRICT' }]}
+                                />
+                              )
+                            }]),
+                            {
+                              title: '', key: 'action', width: 44,
+                              render: (_: any, r: DesignForeignKey) => <Button size="small" type="text" danger icon={<DeleteOutlined />} onClick={() => removeDesignItem('foreignKeys', r.key)} />
+                            }
+                          ]}
+                        />
+                      </>
+                    )
+                  },
+                  {
+                    key: 'checks',
+                    label: `CHECK 约束 (${model.checks.length})`,
+                    children: (
+                      <>
+                        <Button size="small" icon={<PlusOutlined />} onClick={() => addDesignItem('checks')} style={{ marginBottom: 8 }}>新增 CHECK 约束</Button>
+                        <Table
+                          rowKey="key"
+                          size="small"
+                          pagination={false}
+                          dataSource={model.checks}
+                          columns={[
+                            {
+                              title: '名称', dataIndex: 'name', width: 180,
+                              render: (_: any, r: DesignCheck) => <Input size="small" value={r.name} placeholder="自动命名" onChange={e => updateDesignItem('checks', r.key, { name: e.target.value })} />
+                            },
+                            {
+                              title: '表达式', dataIndex: 'expr',
+                              render: (_: any, r: DesignCheck) => <Input size="small" value={r.expr} placeholder={isOracle ? 'AMOUNT >= 0' : '`amount` >= 0'} onChange={e => updateDesignItem('checks', r.key, { expr: e.target.value })} />
+                            },
+                            {
+                              title: '不生效', dataIndex: 'notEnforced', width: 72,
+                              render: (_: any, r: DesignCheck) => <Checkbox checked={r.notEnforced} onChange={e => updateDesignItem('checks', r.key, { notEnforced: e.target.checked })} />
+                            },
+                            {
+                              title: '', key: 'action', width: 44,
+                              render: (_: any, r: DesignCheck) => <Button size="small" type="text" danger icon={<DeleteOutlined />} onClick={() => removeDesignItem('checks', r.key)} />
+                            }
+                          ]}
+                        />
+                      </>
+                    )
+                  },
+                  {
+                    key: 'options',
+                    label: '选项',
+                    children: (
+                      <Form layout="vertical" size="small" className="table-designer-options">
+                        {!isOracle && (
+                          <Space wrap>
+                            <Form.Item label="存储引擎">
+                              <Select
+                                style={{ width: 160 }}
+                                value={model.engine}
+                                onChange={v => updateDesignModel({ engine: v })}
+                                options={['InnoDB', 'MyISAM', 'MEMORY', 'ARCHIVE'].map(v => ({ label: v, value: v }))}
+                              />
+                            </Form.Item>
+                            <Form.Item label="字符集">
+                              <Input style={{ width: 160 }} value={model.charset} onChange={e => updateDesignModel({ charset: e.target.value })} />
+                            </Form.Item>
+                            <Form.Item label="排序规则">
+                              <Input style={{ width: 200 }} value={model.collation} onChange={e => updateDesignModel({ collation: e.target.value })} />
+                            </Form.Item>
+                            <Form.Item label="自增起始值">
+                              <InputNumber style={{ width: 160 }} min={0} value={model.autoIncrement} onChange={v => updateDesignModel({ autoIncrement: Number(v) || 0 })} />
+                            </Form.Item>
+                          </Space>
+                        )}
+                        <Form.Item label="分区">
+                          <Input.TextArea
+                            rows={4}
+                            value={model.partition}
+                            placeholder={isOracle ? 'PARTITION BY RANGE (CREATED) INTERVAL (NUMTOYMINTERVAL(1, \'MONTH\')) (PARTITION P0 VALUES LESS THAN (DATE \'2024-01-01\'))' : 'PARTITION BY HASH (`id`) PARTITIONS 4'}
+                            onChange={e => updateDesignModel({ partition: e.target.value })}
+                          />
+                        </Form.Item>
+                      </Form>
+                    )
+                  }
+                ]}
+              />
+              {tableDesigner.ddl && (
+                <div className="table-designer-preview">
+                  {(tableDesigner.ddl.warnings || []).map((w, idx) => (
+                    <div key={idx}><Text type="warning"><ExclamationCircleOutlined /> {w}</Text></div>
+                  ))}
+                  <pre className="grid-preview-sql">{tableDesigner.ddl.script || '-- 表结构没有变化'}</pre>
+                </div>
+              )}
+            </div>
+          );
+        })()}
+      </Modal>
+
       {/* 导出SQL弹窗 */}
       <Modal
         title={`导出SQL${exportDb ? ` - ${exportDb.db}` : ''}`}
@@ -2272,6 +5065,53 @@ const App: React.FC = () => {
             </Button>
           </Space>
         </div>
+        <div className="export-mode">
+          <Text type="secondary">压缩格式：</Text>
+          <Space>
+            <Button size="small" type={exportOptions.compression === 'none' ? 'primary' : 'default'} onClick={() => setExportOptions(prev => ({ ...prev, compression: 'none' }))}>
+              不压缩
+            </Button>
+            <Button size="small" type={exportOptions.compression === 'gzip' ? 'primary' : 'default'} onClick={() => setExportOptions(prev => ({ ...prev, compression: 'gzip' }))}>
+              gzip
+            </Button>
+            <Button size="small" type={exportOptions.compression === 'zstd' ? 'primary' : 'default'} onClick={() => setExportOptions(prev => ({ ...prev, compression: 'zstd' }))}>
+              zstd
+            </Button>
+          </Space>
+        </div>
+        <div className="export-mode">
+          <Text type="secondary">输出方式：</Text>
+          <Space>
+            <Button size="small" type={exportOptions.splitMode === 'none' ? 'primary' : 'default'} onClick={() => setExportOptions(prev => ({ ...prev, splitMode: 'none' }))}>
+              单文件
+            </Button>
+            <Button size="small" type={exportOptions.splitMode === 'size' ? 'primary' : 'default'} onClick={() => setExportOptions(prev => ({ ...prev, splitMode: 'size' }))}>
+              按大小分卷
+            </Button>
+            <Button size="small" type={exportOptions.splitMode === 'table' ? 'primary' : 'default'} onClick={() => setExportOptions(prev => ({ ...prev, splitMode: 'table' }))}>
+              按表分文件
+            </Button>
+          </Space>
+        </div>
+        <div className="export-mode">
+          {exportOptions.splitMode === 'size' && (
+            <>
+              <Text type="secondary">每卷大小(MB)：</Text>
+              <InputNumber
+                size="small"
+                min={1}
+                value={exportOptions.splitSizeMB}
+                onChange={(v) => setExportOptions(prev => ({ ...prev, splitSizeMB: Number(v) || 1024 }))}
+              />
+            </>
+          )}
+          <Text type="secondary">生成校验文件：</Text>
+          <Switch
+            size="small"
+            checked={exportOptions.checksum}
+            onChange={(checked) => setExportOptions(prev => ({ ...prev, checksum: checked }))}
+          />
+        </div>
         <div className="export-mysqldump">
           <Text type="secondary">mysqldump 路径（可选）：</Text>
           <Input
@@ -2315,6 +5155,22 @@ const App: React.FC = () => {
             </label>
           ))}
         </div>
+        {exportProgress && (
+          <div className="export-mode">
+            <Text type="secondary">
+              {exportProgress.table ? `当前表：${exportProgress.table}，` : ''}
+              已写入 {exportProgress.rowsWritten}
+              {exportProgress.rowsEstimated > 0 ? ` / 约 ${exportProgress.rowsEstimated}` : ''} 行，
+              {(exportProgress.bytesWritten / 1024 / 1024).toFixed(1)} MB
+              {exportProgress.status === 'running' && exportProgress.etaSec > 0 ? `，预计剩余 ${Math.ceil(exportProgress.etaSec)} 秒` : ''}
+            </Text>
+            {exportProgress.status === 'running' && (
+              <Button size="small" danger onClick={handleCancelExport}>
+                停止导出
+              </Button>
+            )}
+          </div>
+        )}
         <div className="export-log-title">导出日志</div>
         <div className="export-log" ref={exportLogRef}>
           {exportLogs.length === 0 ? (
@@ -2381,6 +5237,19 @@ const App: React.FC = () => {
           <Form.Item name="password" label="Password">
             <Input.Password placeholder="密码(可选)" />
           </Form.Item>
+          <Form.Item name="environment" label="环境" initialValue="dev">
+            <Radio.Group
+              optionType="button"
+              options={[
+                { label: '开发', value: 'dev' },
+                { label: '测试', value: 'test' },
+                { label: '生产', value: 'prod' }
+              ]}
+            />
+          </Form.Item>
+          <Form.Item name="readOnly" label="只读连接" valuePropName="checked" initialValue={false}>
+            <Switch />
+          </Form.Item>
           <Form.Item shouldUpdate noStyle>
             {() => {
               const currentType = normalizeConnType(form.getFieldValue('type'));
@@ -2392,7 +5261,7 @@ const App: React.FC = () => {
             }}
           </Form.Item>
           <Text type="secondary" style={{ fontSize: '12px' }}>
-            连接信息将保存在本地，Oracle 默认端口 1521，MySQL 默认端口 3306。
+            连接信息将保存在本地，Oracle 默认端口 1521，MySQL 默认端口 3306。生产环境执行 DDL、无条件的 UPDATE/DELETE 或终止会话前需确认，只读连接禁止写入。
           </Text>
         </Form>
       </Modal>
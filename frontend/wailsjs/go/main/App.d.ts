@@ -2,17 +2,51 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function ApplyAccountChange(arg1:main.AccountChange,arg2:string):Promise<main.AccountChangeResult>;
+
+export function ApplyGridChanges(arg1:string,arg2:Array<main.GridChange>,arg3:string):Promise<main.GridApplyResult>;
+
+export function BrowseBinlog(arg1:main.DBConfig,arg2:main.BinlogQuery):Promise<main.BinlogBrowseResult>;
+
+export function CancelExport(arg1:string):Promise<void>;
+
+export function CancelJob(arg1:string):Promise<void>;
+
+export function CheckStatement(arg1:string):Promise<main.GuardCheck>;
+
+export function ClearJobHistory():Promise<void>;
+
+export function CompareSchemas(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:main.SchemaDiffOptions):Promise<main.SchemaDiffResult>;
+
+export function CompareTableData(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:string,arg6:Array<string>,arg7:main.DataDiffOptions):Promise<main.DataDiffResult>;
+
+export function ConfirmTaskTarget(arg1:main.DBConfig,arg2:string,arg3:string):Promise<main.GuardCheck>;
+
 export function ConnectDB(arg1:string):Promise<void>;
 
 export function ConnectDBConfig(arg1:main.DBConfig):Promise<void>;
 
+export function DeleteCDC(arg1:string):Promise<void>;
+
 export function DeleteConnection(arg1:string):Promise<void>;
 
+export function DeleteSyncCheckpoint(arg1:string):Promise<void>;
+
+export function DryRunSync(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:string,arg6:Array<string>,arg7:main.SyncOptions):Promise<main.SyncPlan>;
+
 export function ExecuteQuery(arg1:string):Promise<Array<Record<string, any>>>;
 
-export function ExecuteQueryWithColumns(arg1:string):Promise<main.QueryResult>;
+export function ExecuteQueryWithColumns(arg1:string,arg2:string):Promise<main.QueryResult>;
+
+export function ExecuteTableDDL(arg1:main.TableModel,arg2:main.TableDDLOptions,arg3:string):Promise<main.TableDDL>;
+
+export function ExportAuditLog(arg1:main.AuditFilter,arg2:string):Promise<string>;
+
+export function ExportSqlDump(arg1:main.DBConfig,arg2:Array<string>,arg3:string,arg4:main.ExportOptions):Promise<string>;
+
+export function FormatSQL(arg1:string,arg2:string,arg3:main.FormatOptions):Promise<string>;
 
-export function ExportSqlDump(arg1:main.DBConfig,arg2:Array<string>,arg3:string):Promise<string>;
+export function GetAccountGrants(arg1:string,arg2:string):Promise<main.AccountGrants>;
 
 export function GetAppSettings():Promise<main.AppSettings>;
 
@@ -22,6 +56,16 @@ export function GetDatabases():Promise<Array<string>>;
 
 export function GetDatabasesForConfig(arg1:main.DBConfig):Promise<Array<string>>;
 
+export function GetEditableResult(arg1:string):Promise<main.EditableResult>;
+
+export function GetJob(arg1:string):Promise<main.Job>;
+
+export function GetMaskingRules():Promise<Array<main.MaskingRule>>;
+
+export function GetOracleTypeMappings():Promise<Array<main.TypeMappingRule>>;
+
+export function GetPrivilegeMatrix(arg1:string):Promise<main.PrivilegeMatrix>;
+
 export function GetProcessList():Promise<Array<Record<string, any>>>;
 
 export function GetSavedConnections():Promise<Array<main.DBConfig>>;
@@ -32,22 +76,108 @@ export function GetTables(arg1:string):Promise<Array<main.TableMeta>>;
 
 export function GetViews(arg1:string):Promise<Array<main.ViewMeta>>;
 
-export function KillProcess(arg1:number):Promise<void>;
+export function ImportSqlFile(arg1:main.DBConfig,arg2:string,arg3:string,arg4:main.ImportOptions):Promise<main.ImportResult>;
+
+export function InvalidateMetadata(arg1:main.DBConfig,arg2:string):Promise<void>;
+
+export function KillProcess(arg1:number,arg2:string):Promise<void>;
+
+export function ListAccounts():Promise<Array<main.DBAccount>>;
+
+export function ListBinlogFiles(arg1:main.DBConfig):Promise<Array<main.BinlogFile>>;
+
+export function ListCDC():Promise<Array<main.CDCStatus>>;
+
+export function ListGrantablePrivileges(arg1:string):Promise<Array<string>>;
+
+export function ListJobs():Promise<Array<main.Job>>;
+
+export function ListSyncCheckpoints():Promise<Array<main.SyncCheckpointInfo>>;
+
+export function LoadDataFile(arg1:main.DBConfig,arg2:string,arg3:main.DataLoadRequest):Promise<main.DataLoadResult>;
+
+export function LoadMetadata(arg1:main.DBConfig,arg2:string,arg3:boolean):Promise<main.SchemaMetadata>;
+
+export function LoadTableModel(arg1:string,arg2:string):Promise<main.TableModel>;
+
+export function PauseCDC(arg1:string):Promise<void>;
+
+export function PreviewAccountChange(arg1:main.AccountChange):Promise<main.AccountChangeResult>;
+
+export function PreviewDataFile(arg1:string,arg2:main.DataFileOptions):Promise<main.DataFilePreview>;
+
+export function PreviewGridChanges(arg1:string,arg2:Array<main.GridChange>):Promise<Array<main.GridStatement>>;
+
+export function PreviewMask(arg1:main.MaskingRule,arg2:string):Promise<any>;
+
+export function PreviewTableDDL(arg1:main.TableModel,arg2:main.TableDDLOptions):Promise<main.TableDDL>;
+
+export function QueryAuditLog(arg1:main.AuditFilter):Promise<main.AuditQueryResult>;
+
+export function RestoreDumpFile(arg1:main.DBConfig,arg2:string,arg3:string,arg4:main.RestoreOptions):Promise<main.ImportResult>;
+
+export function ResumeCDC(arg1:string,arg2:string):Promise<main.CDCStatus>;
+
+export function ResumeSync(arg1:string,arg2:string):Promise<Array<main.MigrationCheckRow>>;
 
 export function SaveAppSettings(arg1:main.AppSettings):Promise<void>;
 
+export function SaveCSVFromJSON(arg1:string,arg2:string):Promise<string>;
+
 export function SaveConnection(arg1:main.DBConfig):Promise<void>;
 
 export function SaveExcelFile(arg1:string,arg2:string):Promise<string>;
 
 export function SaveExcelFromJSON(arg1:string,arg2:string):Promise<string>;
 
+export function SaveMaskingRules(arg1:Array<main.MaskingRule>):Promise<void>;
+
 export function SaveTextFile(arg1:string,arg2:string):Promise<string>;
 
-export function SyncDatabase(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:string,arg6:Array<string>):Promise<Array<main.MigrationCheckRow>>;
+export function ScanDumpFile(arg1:string):Promise<main.DumpScanResult>;
+
+export function SelectBinlogFiles():Promise<Array<string>>;
+
+export function SelectDataFile():Promise<string>;
+
+export function SelectSqlFile():Promise<string>;
+
+export function StartCDC(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:Array<string>,arg6:main.CDCOptions):Promise<main.CDCStatus>;
+
+export function StopCDC(arg1:string,arg2:boolean):Promise<void>;
+
+export function SubmitBinlogJob(arg1:main.DBConfig,arg2:main.BinlogQuery):Promise<main.Job>;
+
+export function SubmitCheckJob(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:Array<string>):Promise<main.Job>;
+
+export function SubmitChecksumJob(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:Array<string>,arg6:main.ChecksumOptions):Promise<main.Job>;
+
+export function SubmitDataDiffJob(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:string,arg6:Array<string>,arg7:main.DataDiffOptions):Promise<main.Job>;
+
+export function SubmitDataLoadJob(arg1:main.DBConfig,arg2:string,arg3:main.DataLoadRequest):Promise<main.Job>;
+
+export function SubmitDryRunSyncJob(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:string,arg6:Array<string>,arg7:main.SyncOptions):Promise<main.Job>;
+
+export function SubmitExportJob(arg1:main.DBConfig,arg2:Array<string>,arg3:string,arg4:main.ExportOptions):Promise<main.Job>;
+
+export function SubmitImportJob(arg1:main.DBConfig,arg2:string,arg3:string,arg4:main.ImportOptions):Promise<main.Job>;
+
+export function SubmitRestoreJob(arg1:main.DBConfig,arg2:string,arg3:string,arg4:main.RestoreOptions):Promise<main.Job>;
+
+export function SubmitResumeSyncJob(arg1:string,arg2:string):Promise<main.Job>;
+
+export function SubmitSyncJob(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:string,arg6:Array<string>,arg7:main.SyncOptions):Promise<main.Job>;
+
+export function Suggest(arg1:main.DBConfig,arg2:string,arg3:string,arg4:number):Promise<main.SuggestResult>;
+
+export function SyncDatabase(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:string,arg6:Array<string>,arg7:main.SyncOptions):Promise<Array<main.MigrationCheckRow>>;
 
 export function TestConnection(arg1:string):Promise<void>;
 
 export function TestConnectionConfig(arg1:main.DBConfig):Promise<void>;
 
 export function UpdateConnection(arg1:main.DBConfig):Promise<void>;
+
+export function VerifyAuditLog():Promise<main.AuditVerifyResult>;
+
+export function VerifyChecksum(arg1:main.DBConfig,arg2:string,arg3:main.DBConfig,arg4:string,arg5:Array<string>,arg6:main.ChecksumOptions):Promise<Array<main.ChecksumTableResult>>;
@@ -2,6 +2,46 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ApplyAccountChange(arg1, arg2) {
+  return window['go']['main']['App']['ApplyAccountChange'](arg1, arg2);
+}
+
+export function ApplyGridChanges(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ApplyGridChanges'](arg1, arg2, arg3);
+}
+
+export function BrowseBinlog(arg1, arg2) {
+  return window['go']['main']['App']['BrowseBinlog'](arg1, arg2);
+}
+
+export function CancelExport(arg1) {
+  return window['go']['main']['App']['CancelExport'](arg1);
+}
+
+export function CancelJob(arg1) {
+  return window['go']['main']['App']['CancelJob'](arg1);
+}
+
+export function CheckStatement(arg1) {
+  return window['go']['main']['App']['CheckStatement'](arg1);
+}
+
+export function ClearJobHistory() {
+  return window['go']['main']['App']['ClearJobHistory']();
+}
+
+export function CompareSchemas(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['CompareSchemas'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function CompareTableData(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['CompareTableData'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
+export function ConfirmTaskTarget(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ConfirmTaskTarget'](arg1, arg2, arg3);
+}
+
 export function ConnectDB(arg1) {
   return window['go']['main']['App']['ConnectDB'](arg1);
 }
@@ -10,20 +50,48 @@ export function ConnectDBConfig(arg1) {
   return window['go']['main']['App']['ConnectDBConfig'](arg1);
 }
 
+export function DeleteCDC(arg1) {
+  return window['go']['main']['App']['DeleteCDC'](arg1);
+}
+
 export function DeleteConnection(arg1) {
   return window['go']['main']['App']['DeleteConnection'](arg1);
 }
 
+export function DeleteSyncCheckpoint(arg1) {
+  return window['go']['main']['App']['DeleteSyncCheckpoint'](arg1);
+}
+
+export function DryRunSync(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['DryRunSync'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
 export function ExecuteQuery(arg1) {
   return window['go']['main']['App']['ExecuteQuery'](arg1);
 }
 
-export function ExecuteQueryWithColumns(arg1) {
-  return window['go']['main']['App']['ExecuteQueryWithColumns'](arg1);
+export function ExecuteQueryWithColumns(arg1, arg2) {
+  return window['go']['main']['App']['ExecuteQueryWithColumns'](arg1, arg2);
+}
+
+export function ExecuteTableDDL(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExecuteTableDDL'](arg1, arg2, arg3);
+}
+
+export function ExportAuditLog(arg1, arg2) {
+  return window['go']['main']['App']['ExportAuditLog'](arg1, arg2);
+}
+
+export function ExportSqlDump(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ExportSqlDump'](arg1, arg2, arg3, arg4);
 }
 
-export function ExportSqlDump(arg1, arg2, arg3) {
-  return window['go']['main']['App']['ExportSqlDump'](arg1, arg2, arg3);
+export function FormatSQL(arg1, arg2, arg3) {
+  return window['go']['main']['App']['FormatSQL'](arg1, arg2, arg3);
+}
+
+export function GetAccountGrants(arg1, arg2) {
+  return window['go']['main']['App']['GetAccountGrants'](arg1, arg2);
 }
 
 export function GetAppSettings() {
@@ -42,6 +110,26 @@ export function GetDatabasesForConfig(arg1) {
   return window['go']['main']['App']['GetDatabasesForConfig'](arg1);
 }
 
+export function GetEditableResult(arg1) {
+  return window['go']['main']['App']['GetEditableResult'](arg1);
+}
+
+export function GetJob(arg1) {
+  return window['go']['main']['App']['GetJob'](arg1);
+}
+
+export function GetMaskingRules() {
+  return window['go']['main']['App']['GetMaskingRules']();
+}
+
+export function GetOracleTypeMappings() {
+  return window['go']['main']['App']['GetOracleTypeMappings']();
+}
+
+export function GetPrivilegeMatrix(arg1) {
+  return window['go']['main']['App']['GetPrivilegeMatrix'](arg1);
+}
+
 export function GetProcessList() {
   return window['go']['main']['App']['GetProcessList']();
 }
@@ -62,14 +150,102 @@ export function GetViews(arg1) {
   return window['go']['main']['App']['GetViews'](arg1);
 }
 
-export function KillProcess(arg1) {
-  return window['go']['main']['App']['KillProcess'](arg1);
+export function ImportSqlFile(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ImportSqlFile'](arg1, arg2, arg3, arg4);
+}
+
+export function InvalidateMetadata(arg1, arg2) {
+  return window['go']['main']['App']['InvalidateMetadata'](arg1, arg2);
+}
+
+export function KillProcess(arg1, arg2) {
+  return window['go']['main']['App']['KillProcess'](arg1, arg2);
+}
+
+export function ListAccounts() {
+  return window['go']['main']['App']['ListAccounts']();
+}
+
+export function ListBinlogFiles(arg1) {
+  return window['go']['main']['App']['ListBinlogFiles'](arg1);
+}
+
+export function ListCDC() {
+  return window['go']['main']['App']['ListCDC']();
+}
+
+export function ListGrantablePrivileges(arg1) {
+  return window['go']['main']['App']['ListGrantablePrivileges'](arg1);
+}
+
+export function ListJobs() {
+  return window['go']['main']['App']['ListJobs']();
+}
+
+export function ListSyncCheckpoints() {
+  return window['go']['main']['App']['ListSyncCheckpoints']();
+}
+
+export function LoadDataFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LoadDataFile'](arg1, arg2, arg3);
+}
+
+export function LoadMetadata(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LoadMetadata'](arg1, arg2, arg3);
+}
+
+export function LoadTableModel(arg1, arg2) {
+  return window['go']['main']['App']['LoadTableModel'](arg1, arg2);
+}
+
+export function PauseCDC(arg1) {
+  return window['go']['main']['App']['PauseCDC'](arg1);
+}
+
+export function PreviewAccountChange(arg1) {
+  return window['go']['main']['App']['PreviewAccountChange'](arg1);
+}
+
+export function PreviewDataFile(arg1, arg2) {
+  return window['go']['main']['App']['PreviewDataFile'](arg1, arg2);
+}
+
+export function PreviewGridChanges(arg1, arg2) {
+  return window['go']['main']['App']['PreviewGridChanges'](arg1, arg2);
+}
+
+export function PreviewMask(arg1, arg2) {
+  return window['go']['main']['App']['PreviewMask'](arg1, arg2);
+}
+
+export function PreviewTableDDL(arg1, arg2) {
+  return window['go']['main']['App']['PreviewTableDDL'](arg1, arg2);
+}
+
+export function QueryAuditLog(arg1) {
+  return window['go']['main']['App']['QueryAuditLog'](arg1);
+}
+
+export function RestoreDumpFile(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['RestoreDumpFile'](arg1, arg2, arg3, arg4);
+}
+
+export function ResumeCDC(arg1, arg2) {
+  return window['go']['main']['App']['ResumeCDC'](arg1, arg2);
+}
+
+export function ResumeSync(arg1, arg2) {
+  return window['go']['main']['App']['ResumeSync'](arg1, arg2);
 }
 
 export function SaveAppSettings(arg1) {
   return window['go']['main']['App']['SaveAppSettings'](arg1);
 }
 
+export function SaveCSVFromJSON(arg1, arg2) {
+  return window['go']['main']['App']['SaveCSVFromJSON'](arg1, arg2);
+}
+
 export function SaveConnection(arg1) {
   return window['go']['main']['App']['SaveConnection'](arg1);
 }
@@ -82,12 +258,88 @@ export function SaveExcelFromJSON(arg1, arg2) {
   return window['go']['main']['App']['SaveExcelFromJSON'](arg1, arg2);
 }
 
+export function SaveMaskingRules(arg1) {
+  return window['go']['main']['App']['SaveMaskingRules'](arg1);
+}
+
 export function SaveTextFile(arg1, arg2) {
   return window['go']['main']['App']['SaveTextFile'](arg1, arg2);
 }
 
-export function SyncDatabase(arg1, arg2, arg3, arg4, arg5, arg6) {
-  return window['go']['main']['App']['SyncDatabase'](arg1, arg2, arg3, arg4, arg5, arg6);
+export function ScanDumpFile(arg1) {
+  return window['go']['main']['App']['ScanDumpFile'](arg1);
+}
+
+export function SelectBinlogFiles() {
+  return window['go']['main']['App']['SelectBinlogFiles']();
+}
+
+export function SelectDataFile() {
+  return window['go']['main']['App']['SelectDataFile']();
+}
+
+export function SelectSqlFile() {
+  return window['go']['main']['App']['SelectSqlFile']();
+}
+
+export function StartCDC(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['StartCDC'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function StopCDC(arg1, arg2) {
+  return window['go']['main']['App']['StopCDC'](arg1, arg2);
+}
+
+export function SubmitBinlogJob(arg1, arg2) {
+  return window['go']['main']['App']['SubmitBinlogJob'](arg1, arg2);
+}
+
+export function SubmitCheckJob(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['SubmitCheckJob'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function SubmitChecksumJob(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['SubmitChecksumJob'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function SubmitDataDiffJob(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['SubmitDataDiffJob'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
+export function SubmitDataLoadJob(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SubmitDataLoadJob'](arg1, arg2, arg3);
+}
+
+export function SubmitDryRunSyncJob(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['SubmitDryRunSyncJob'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
+export function SubmitExportJob(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SubmitExportJob'](arg1, arg2, arg3, arg4);
+}
+
+export function SubmitImportJob(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SubmitImportJob'](arg1, arg2, arg3, arg4);
+}
+
+export function SubmitRestoreJob(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SubmitRestoreJob'](arg1, arg2, arg3, arg4);
+}
+
+export function SubmitResumeSyncJob(arg1, arg2) {
+  return window['go']['main']['App']['SubmitResumeSyncJob'](arg1, arg2);
+}
+
+export function SubmitSyncJob(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['SubmitSyncJob'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
+export function Suggest(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['Suggest'](arg1, arg2, arg3, arg4);
+}
+
+export function SyncDatabase(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['SyncDatabase'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
 }
 
 export function TestConnection(arg1) {
@@ -101,3 +353,11 @@ export function TestConnectionConfig(arg1) {
 export function UpdateConnection(arg1) {
   return window['go']['main']['App']['UpdateConnection'](arg1);
 }
+
+export function VerifyAuditLog() {
+  return window['go']['main']['App']['VerifyAuditLog']();
+}
+
+export function VerifyChecksum(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['VerifyChecksum'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
@@ -1,78 +1,1836 @@
 export namespace main {
 	
+	export class AccountChange {
+	    action: string;
+	    user: string;
+	    host: string;
+	    password: string;
+	    defaultTablespace: string;
+	    cascade: boolean;
+	    role: string;
+	    level: string;
+	    schema: string;
+	    table: string;
+	    columns: string[];
+	    privileges: string[];
+	    withGrantOption: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new AccountChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.user = source["user"];
+	        this.host = source["host"];
+	        this.password = source["password"];
+	        this.defaultTablespace = source["defaultTablespace"];
+	        this.cascade = source["cascade"];
+	        this.role = source["role"];
+	        this.level = source["level"];
+	        this.schema = source["schema"];
+	        this.table = source["table"];
+	        this.columns = source["columns"];
+	        this.privileges = source["privileges"];
+	        this.withGrantOption = source["withGrantOption"];
+	    }
+	}
+	export class AccountChangeResult {
+	    statements: string[];
+	    script: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AccountChangeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statements = source["statements"];
+	        this.script = source["script"];
+	    }
+	}
+	export class PrivilegeEntry {
+	    level: string;
+	    schema: string;
+	    table: string;
+	    columns: string[];
+	    privilege: string;
+	    grantable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PrivilegeEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.level = source["level"];
+	        this.schema = source["schema"];
+	        this.table = source["table"];
+	        this.columns = source["columns"];
+	        this.privilege = source["privilege"];
+	        this.grantable = source["grantable"];
+	    }
+	}
+	export class AccountGrants {
+	    user: string;
+	    host: string;
+	    grants: string[];
+	    roles: string[];
+	    privileges: PrivilegeEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AccountGrants(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.user = source["user"];
+	        this.host = source["host"];
+	        this.grants = source["grants"];
+	        this.roles = source["roles"];
+	        this.privileges = this.convertValues(source["privileges"], PrivilegeEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class AppSettings {
 	    mysqldumpPath: string;
+	    maxConcurrentJobs: number;
+	    auditRetentionDays: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AppSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mysqldumpPath = source["mysqldumpPath"];
+	        this.maxConcurrentJobs = source["maxConcurrentJobs"];
+	        this.auditRetentionDays = source["auditRetentionDays"];
+	    }
+	}
+	export class AuditEntry {
+	    seq: number;
+	    time: string;
+	    osUser: string;
+	    hostname: string;
+	    action: string;
+	    connId?: string;
+	    connName?: string;
+	    environment?: string;
+	    database?: string;
+	    statement: string;
+	    outcome: string;
+	    error?: string;
+	    rows?: number;
+	    durationMs: number;
+	    prevHash: string;
+	    hash: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.seq = source["seq"];
+	        this.time = source["time"];
+	        this.osUser = source["osUser"];
+	        this.hostname = source["hostname"];
+	        this.action = source["action"];
+	        this.connId = source["connId"];
+	        this.connName = source["connName"];
+	        this.environment = source["environment"];
+	        this.database = source["database"];
+	        this.statement = source["statement"];
+	        this.outcome = source["outcome"];
+	        this.error = source["error"];
+	        this.rows = source["rows"];
+	        this.durationMs = source["durationMs"];
+	        this.prevHash = source["prevHash"];
+	        this.hash = source["hash"];
+	    }
+	}
+	export class AuditFilter {
+	    from: string;
+	    to: string;
+	    action: string;
+	    outcome: string;
+	    keyword: string;
+	    limit: number;
+	    offset: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.action = source["action"];
+	        this.outcome = source["outcome"];
+	        this.keyword = source["keyword"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	}
+	export class AuditQueryResult {
+	    entries: AuditEntry[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = this.convertValues(source["entries"], AuditEntry);
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AuditVerifyResult {
+	    ok: boolean;
+	    files: number;
+	    checked: number;
+	    firstSeq: number;
+	    lastSeq: number;
+	    brokenFile?: string;
+	    brokenLine?: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditVerifyResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ok = source["ok"];
+	        this.files = source["files"];
+	        this.checked = source["checked"];
+	        this.firstSeq = source["firstSeq"];
+	        this.lastSeq = source["lastSeq"];
+	        this.brokenFile = source["brokenFile"];
+	        this.brokenLine = source["brokenLine"];
+	        this.message = source["message"];
+	    }
+	}
+	export class BinlogRowChange {
+	    file: string;
+	    pos: number;
+	    time: string;
+	    gtid?: string;
+	    database: string;
+	    table: string;
+	    type: string;
+	    columns: string[];
+	    before?: any[];
+	    after?: any[];
+	    changed?: string[];
+	    forwardSql: string;
+	    flashbackSql: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BinlogRowChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.pos = source["pos"];
+	        this.time = source["time"];
+	        this.gtid = source["gtid"];
+	        this.database = source["database"];
+	        this.table = source["table"];
+	        this.type = source["type"];
+	        this.columns = source["columns"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	        this.changed = source["changed"];
+	        this.forwardSql = source["forwardSql"];
+	        this.flashbackSql = source["flashbackSql"];
+	    }
+	}
+	export class BinlogBrowseResult {
+	    changes: BinlogRowChange[];
+	    truncated: boolean;
+	    scannedEvents: number;
+	    endFile: string;
+	    endPos: number;
+	    forwardScript: string;
+	    flashbackScript: string;
+	    warnings?: string[];
+	    timeZone: string;
+	    elapsedSec: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BinlogBrowseResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.changes = this.convertValues(source["changes"], BinlogRowChange);
+	        this.truncated = source["truncated"];
+	        this.scannedEvents = source["scannedEvents"];
+	        this.endFile = source["endFile"];
+	        this.endPos = source["endPos"];
+	        this.forwardScript = source["forwardScript"];
+	        this.flashbackScript = source["flashbackScript"];
+	        this.warnings = source["warnings"];
+	        this.timeZone = source["timeZone"];
+	        this.elapsedSec = source["elapsedSec"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BinlogFile {
+	    name: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BinlogFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.size = source["size"];
+	    }
+	}
+	export class BinlogQuery {
+	    source: string;
+	    files: string[];
+	    startFile: string;
+	    startPos: number;
+	    endFile: string;
+	    startTime: string;
+	    endTime: string;
+	    databases: string[];
+	    tables: string[];
+	    eventTypes: string[];
+	    maxEvents: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BinlogQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.files = source["files"];
+	        this.startFile = source["startFile"];
+	        this.startPos = source["startPos"];
+	        this.endFile = source["endFile"];
+	        this.startTime = source["startTime"];
+	        this.endTime = source["endTime"];
+	        this.databases = source["databases"];
+	        this.tables = source["tables"];
+	        this.eventTypes = source["eventTypes"];
+	        this.maxEvents = source["maxEvents"];
+	    }
+	}
+	
+	export class TableMapping {
+	    target: string;
+	    include: string[];
+	    exclude: string[];
+	    columns: Record<string, string>;
+	    transforms: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new TableMapping(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = source["target"];
+	        this.include = source["include"];
+	        this.exclude = source["exclude"];
+	        this.columns = source["columns"];
+	        this.transforms = source["transforms"];
+	    }
+	}
+	export class NameRule {
+	    match: string;
+	    replace: string;
+	    regex: boolean;
+	    case: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NameRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.match = source["match"];
+	        this.replace = source["replace"];
+	        this.regex = source["regex"];
+	        this.case = source["case"];
+	    }
+	}
+	export class SyncMapping {
+	    tableRules: NameRule[];
+	    columnRules: NameRule[];
+	    tables: Record<string, TableMapping>;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncMapping(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tableRules = this.convertValues(source["tableRules"], NameRule);
+	        this.columnRules = this.convertValues(source["columnRules"], NameRule);
+	        this.tables = this.convertValues(source["tables"], TableMapping, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SyncTablePolicy {
+	    onTableExists: string;
+	    onDataExists: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncTablePolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.onTableExists = source["onTableExists"];
+	        this.onDataExists = source["onDataExists"];
+	    }
+	}
+	export class SyncOptions {
+	    workers: number;
+	    batchSize: number;
+	    chunkRows: number;
+	    verify: boolean;
+	    onTableExists: string;
+	    onDataExists: string;
+	    tablePolicies: Record<string, SyncTablePolicy>;
+	    typeMappings: Record<string, string>;
+	    mapping: SyncMapping;
+	    applyMasking: boolean;
+	    confirmToken: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.workers = source["workers"];
+	        this.batchSize = source["batchSize"];
+	        this.chunkRows = source["chunkRows"];
+	        this.verify = source["verify"];
+	        this.onTableExists = source["onTableExists"];
+	        this.onDataExists = source["onDataExists"];
+	        this.tablePolicies = this.convertValues(source["tablePolicies"], SyncTablePolicy, true);
+	        this.typeMappings = source["typeMappings"];
+	        this.mapping = this.convertValues(source["mapping"], SyncMapping);
+	        this.applyMasking = source["applyMasking"];
+	        this.confirmToken = source["confirmToken"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CDCOptions {
+	    sync: SyncOptions;
+	    skipInitialCopy: boolean;
+	    startFile: string;
+	    startPos: number;
+	    startGtid: string;
+	    serverId: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CDCOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sync = this.convertValues(source["sync"], SyncOptions);
+	        this.skipInitialCopy = source["skipInitialCopy"];
+	        this.startFile = source["startFile"];
+	        this.startPos = source["startPos"];
+	        this.startGtid = source["startGtid"];
+	        this.serverId = source["serverId"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MigrationCheckRow {
+	    name: string;
+	    sourceRows: number;
+	    targetRows: number;
+	    status: string;
+	    resumed?: boolean;
+	    resumedRows?: number;
+	    note?: string;
+	    tablePolicy?: string;
+	    dataPolicy?: string;
+	    inserted: number;
+	    updated: number;
+	    skipped: number;
+	    checksum?: string;
+	    target?: string;
+	    actions?: string[];
+	    ddl?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MigrationCheckRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.sourceRows = source["sourceRows"];
+	        this.targetRows = source["targetRows"];
+	        this.status = source["status"];
+	        this.resumed = source["resumed"];
+	        this.resumedRows = source["resumedRows"];
+	        this.note = source["note"];
+	        this.tablePolicy = source["tablePolicy"];
+	        this.dataPolicy = source["dataPolicy"];
+	        this.inserted = source["inserted"];
+	        this.updated = source["updated"];
+	        this.skipped = source["skipped"];
+	        this.checksum = source["checksum"];
+	        this.target = source["target"];
+	        this.actions = source["actions"];
+	        this.ddl = source["ddl"];
+	    }
+	}
+	export class CDCStatus {
+	    id: string;
+	    targetId: string;
+	    sourceDb: string;
+	    targetDb: string;
+	    state: string;
+	    copyJobId?: string;
+	    copyDone: boolean;
+	    initialCopy?: MigrationCheckRow[];
+	    file: string;
+	    pos: number;
+	    gtid?: string;
+	    lagSeconds: number;
+	    inserted: number;
+	    updated: number;
+	    deleted: number;
+	    skippedDdl: number;
+	    lastDdl?: string;
+	    lastEventAt?: string;
+	    error?: string;
+	    startedAt: string;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CDCStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.targetId = source["targetId"];
+	        this.sourceDb = source["sourceDb"];
+	        this.targetDb = source["targetDb"];
+	        this.state = source["state"];
+	        this.copyJobId = source["copyJobId"];
+	        this.copyDone = source["copyDone"];
+	        this.initialCopy = this.convertValues(source["initialCopy"], MigrationCheckRow);
+	        this.file = source["file"];
+	        this.pos = source["pos"];
+	        this.gtid = source["gtid"];
+	        this.lagSeconds = source["lagSeconds"];
+	        this.inserted = source["inserted"];
+	        this.updated = source["updated"];
+	        this.deleted = source["deleted"];
+	        this.skippedDdl = source["skippedDdl"];
+	        this.lastDdl = source["lastDdl"];
+	        this.lastEventAt = source["lastEventAt"];
+	        this.error = source["error"];
+	        this.startedAt = source["startedAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChecksumChunk {
+	    index: number;
+	    lower: string;
+	    upper: string;
+	    sourceRows: number;
+	    targetRows: number;
+	    sourceChecksum: string;
+	    targetChecksum: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChecksumChunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.lower = source["lower"];
+	        this.upper = source["upper"];
+	        this.sourceRows = source["sourceRows"];
+	        this.targetRows = source["targetRows"];
+	        this.sourceChecksum = source["sourceChecksum"];
+	        this.targetChecksum = source["targetChecksum"];
+	    }
+	}
+	export class ChecksumDiffKey {
+	    chunk: number;
+	    key: string;
+	    kind: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChecksumDiffKey(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.chunk = source["chunk"];
+	        this.key = source["key"];
+	        this.kind = source["kind"];
+	    }
+	}
+	export class ChecksumOptions {
+	    chunkRows: number;
+	    algorithm: string;
+	    drillDown: boolean;
+	    maxDiffKeys: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChecksumOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.chunkRows = source["chunkRows"];
+	        this.algorithm = source["algorithm"];
+	        this.drillDown = source["drillDown"];
+	        this.maxDiffKeys = source["maxDiffKeys"];
+	    }
+	}
+	export class ChecksumTableResult {
+	    name: string;
+	    keyColumns: string[];
+	    chunks: number;
+	    sourceRows: number;
+	    targetRows: number;
+	    sourceChecksum: string;
+	    targetChecksum: string;
+	    mismatchChunks: ChecksumChunk[];
+	    diffKeys: ChecksumDiffKey[];
+	    truncated: boolean;
+	    warning?: string;
+	    status: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChecksumTableResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.keyColumns = source["keyColumns"];
+	        this.chunks = source["chunks"];
+	        this.sourceRows = source["sourceRows"];
+	        this.targetRows = source["targetRows"];
+	        this.sourceChecksum = source["sourceChecksum"];
+	        this.targetChecksum = source["targetChecksum"];
+	        this.mismatchChunks = this.convertValues(source["mismatchChunks"], ChecksumChunk);
+	        this.diffKeys = this.convertValues(source["diffKeys"], ChecksumDiffKey);
+	        this.truncated = source["truncated"];
+	        this.warning = source["warning"];
+	        this.status = source["status"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ColumnMapping {
+	    source: string;
+	    target: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ColumnMapping(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.target = source["target"];
+	    }
+	}
+	export class ColumnMeta {
+	    table: string;
+	    column: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ColumnMeta(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.table = source["table"];
+	        this.column = source["column"];
+	    }
+	}
+	export class DBAccount {
+	    user: string;
+	    host: string;
+	    isRole: boolean;
+	    locked: boolean;
+	    passwordExpired: boolean;
+	    plugin: string;
+	    status: string;
+	    defaultTablespace: string;
+	    created: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DBAccount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.user = source["user"];
+	        this.host = source["host"];
+	        this.isRole = source["isRole"];
+	        this.locked = source["locked"];
+	        this.passwordExpired = source["passwordExpired"];
+	        this.plugin = source["plugin"];
+	        this.status = source["status"];
+	        this.defaultTablespace = source["defaultTablespace"];
+	        this.created = source["created"];
+	    }
+	}
+	export class DBConfig {
+	    id: string;
+	    name: string;
+	    type: string;
+	    host: string;
+	    port: number;
+	    user: string;
+	    password: string;
+	    database: string;
+	    environment: string;
+	    readOnly: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DBConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.user = source["user"];
+	        this.password = source["password"];
+	        this.database = source["database"];
+	        this.environment = source["environment"];
+	        this.readOnly = source["readOnly"];
+	    }
+	}
+	export class DataColumn {
+	    name: string;
+	    type: string;
+	    nullable: boolean;
+	    maxLength: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataColumn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.nullable = source["nullable"];
+	        this.maxLength = source["maxLength"];
+	    }
+	}
+	export class DataDiffOptions {
+	    output: string;
+	    filePath: string;
+	    maxRows: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataDiffOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.output = source["output"];
+	        this.filePath = source["filePath"];
+	        this.maxRows = source["maxRows"];
+	    }
+	}
+	export class DataDiffRow {
+	    kind: string;
+	    key: string;
+	    columns: string[];
+	    sql: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataDiffRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.key = source["key"];
+	        this.columns = source["columns"];
+	        this.sql = source["sql"];
+	    }
+	}
+	export class DataDiffResult {
+	    table: string;
+	    keyColumns: string[];
+	    sourceRows: number;
+	    targetRows: number;
+	    missing: number;
+	    extra: number;
+	    changed: number;
+	    rows: DataDiffRow[];
+	    truncated: boolean;
+	    warnings?: string[];
+	    filePath?: string;
+	    elapsedSec: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataDiffResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.table = source["table"];
+	        this.keyColumns = source["keyColumns"];
+	        this.sourceRows = source["sourceRows"];
+	        this.targetRows = source["targetRows"];
+	        this.missing = source["missing"];
+	        this.extra = source["extra"];
+	        this.changed = source["changed"];
+	        this.rows = this.convertValues(source["rows"], DataDiffRow);
+	        this.truncated = source["truncated"];
+	        this.warnings = source["warnings"];
+	        this.filePath = source["filePath"];
+	        this.elapsedSec = source["elapsedSec"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class DataFileOptions {
+	    format: string;
+	    delimiter: string;
+	    hasHeader: boolean;
+	    sheet: string;
+	    previewRows: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataFileOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.delimiter = source["delimiter"];
+	        this.hasHeader = source["hasHeader"];
+	        this.sheet = source["sheet"];
+	        this.previewRows = source["previewRows"];
+	    }
+	}
+	export class DataFilePreview {
+	    path: string;
+	    format: string;
+	    sheets: string[];
+	    columns: DataColumn[];
+	    rows: string[][];
+	
+	    static createFrom(source: any = {}) {
+	        return new DataFilePreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.format = source["format"];
+	        this.sheets = source["sheets"];
+	        this.columns = this.convertValues(source["columns"], DataColumn);
+	        this.rows = source["rows"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DataLoadRequest {
+	    format: string;
+	    delimiter: string;
+	    hasHeader: boolean;
+	    sheet: string;
+	    previewRows: number;
+	    path: string;
+	    table: string;
+	    createTable: boolean;
+	    columns: DataColumn[];
+	    primaryKey: string[];
+	    mappings: ColumnMapping[];
+	    mode: string;
+	    batchSize: number;
+	    maxRejected: number;
+	    confirmToken: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataLoadRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.delimiter = source["delimiter"];
+	        this.hasHeader = source["hasHeader"];
+	        this.sheet = source["sheet"];
+	        this.previewRows = source["previewRows"];
+	        this.path = source["path"];
+	        this.table = source["table"];
+	        this.createTable = source["createTable"];
+	        this.columns = this.convertValues(source["columns"], DataColumn);
+	        this.primaryKey = source["primaryKey"];
+	        this.mappings = this.convertValues(source["mappings"], ColumnMapping);
+	        this.mode = source["mode"];
+	        this.batchSize = source["batchSize"];
+	        this.maxRejected = source["maxRejected"];
+	        this.confirmToken = source["confirmToken"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RejectedRow {
+	    line: number;
+	    reason: string;
+	    values: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RejectedRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.reason = source["reason"];
+	        this.values = source["values"];
+	    }
+	}
+	export class DataLoadResult {
+	    table: string;
+	    created: boolean;
+	    loaded: number;
+	    rejected: number;
+	    rejectedRows: RejectedRow[];
+	    elapsedSec: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataLoadResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.table = source["table"];
+	        this.created = source["created"];
+	        this.loaded = source["loaded"];
+	        this.rejected = source["rejected"];
+	        this.rejectedRows = this.convertValues(source["rejectedRows"], RejectedRow);
+	        this.elapsedSec = source["elapsedSec"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DesignCheck {
+	    name: string;
+	    expr: string;
+	    notEnforced: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DesignCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.expr = source["expr"];
+	        this.notEnforced = source["notEnforced"];
+	    }
+	}
+	export class DesignColumn {
+	    name: string;
+	    originalName: string;
+	    type: string;
+	    nullable: boolean;
+	    default?: string;
+	    defaultIsExpr: boolean;
+	    autoIncrement: boolean;
+	    onUpdate: string;
+	    charset: string;
+	    collation: string;
+	    generated: string;
+	    stored: boolean;
+	    comment: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DesignColumn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.originalName = source["originalName"];
+	        this.type = source["type"];
+	        this.nullable = source["nullable"];
+	        this.default = source["default"];
+	        this.defaultIsExpr = source["defaultIsExpr"];
+	        this.autoIncrement = source["autoIncrement"];
+	        this.onUpdate = source["onUpdate"];
+	        this.charset = source["charset"];
+	        this.collation = source["collation"];
+	        this.generated = source["generated"];
+	        this.stored = source["stored"];
+	        this.comment = source["comment"];
+	    }
+	}
+	export class DesignForeignKey {
+	    name: string;
+	    columns: string[];
+	    refSchema: string;
+	    refTable: string;
+	    refColumns: string[];
+	    onDelete: string;
+	    onUpdate: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DesignForeignKey(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.columns = source["columns"];
+	        this.refSchema = source["refSchema"];
+	        this.refTable = source["refTable"];
+	        this.refColumns = source["refColumns"];
+	        this.onDelete = source["onDelete"];
+	        this.onUpdate = source["onUpdate"];
+	    }
+	}
+	export class DesignIndexColumn {
+	    name: string;
+	    length: number;
+	    desc: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DesignIndexColumn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.length = source["length"];
+	        this.desc = source["desc"];
+	    }
+	}
+	export class DesignIndex {
+	    name: string;
+	    originalName: string;
+	    kind: string;
+	    columns: DesignIndexColumn[];
+	    comment: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DesignIndex(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.originalName = source["originalName"];
+	        this.kind = source["kind"];
+	        this.columns = this.convertValues(source["columns"], DesignIndexColumn);
+	        this.comment = source["comment"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class DumpTableInfo {
+	    database: string;
+	    name: string;
+	    schemaBytes: number;
+	    dataBytes: number;
+	    inserts: number;
+	    estimatedRows: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DumpTableInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.database = source["database"];
+	        this.name = source["name"];
+	        this.schemaBytes = source["schemaBytes"];
+	        this.dataBytes = source["dataBytes"];
+	        this.inserts = source["inserts"];
+	        this.estimatedRows = source["estimatedRows"];
+	    }
+	}
+	export class DumpScanResult {
+	    path: string;
+	    databases: string[];
+	    tables: DumpTableInfo[];
+	    otherBytes: number;
+	    statements: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DumpScanResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.databases = source["databases"];
+	        this.tables = this.convertValues(source["tables"], DumpTableInfo);
+	        this.otherBytes = source["otherBytes"];
+	        this.statements = source["statements"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class EditableColumn {
+	    name: string;
+	    column: string;
+	    type: string;
+	    nullable: boolean;
+	    editable: boolean;
+	    masked?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EditableColumn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.column = source["column"];
+	        this.type = source["type"];
+	        this.nullable = source["nullable"];
+	        this.editable = source["editable"];
+	        this.masked = source["masked"];
+	    }
+	}
+	export class EditableResult {
+	    editable: boolean;
+	    reason?: string;
+	    schema: string;
+	    table: string;
+	    key: string[];
+	    keyName: string;
+	    columns: EditableColumn[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EditableResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.editable = source["editable"];
+	        this.reason = source["reason"];
+	        this.schema = source["schema"];
+	        this.table = source["table"];
+	        this.key = source["key"];
+	        this.keyName = source["keyName"];
+	        this.columns = this.convertValues(source["columns"], EditableColumn);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExportOptions {
+	    compression: string;
+	    splitMode: string;
+	    splitSizeMB: number;
+	    checksum: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.compression = source["compression"];
+	        this.splitMode = source["splitMode"];
+	        this.splitSizeMB = source["splitSizeMB"];
+	        this.checksum = source["checksum"];
+	    }
+	}
+	export class FormatOptions {
+	    keywordCase: string;
+	    indentSize: number;
+	    useTabs: boolean;
+	    minify: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FormatOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.keywordCase = source["keywordCase"];
+	        this.indentSize = source["indentSize"];
+	        this.useTabs = source["useTabs"];
+	        this.minify = source["minify"];
+	    }
+	}
+	export class GridApplyResult {
+	    statements: number;
+	    affected: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GridApplyResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statements = source["statements"];
+	        this.affected = source["affected"];
+	    }
+	}
+	export class GridChange {
+	    action: string;
+	    original: Record<string, any>;
+	    values: Record<string, any>;
 	
 	    static createFrom(source: any = {}) {
-	        return new AppSettings(source);
+	        return new GridChange(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.mysqldumpPath = source["mysqldumpPath"];
+	        this.action = source["action"];
+	        this.original = source["original"];
+	        this.values = source["values"];
 	    }
 	}
-	export class ColumnMeta {
+	export class GridStatement {
+	    action: string;
+	    sql: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GridStatement(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.sql = source["sql"];
+	    }
+	}
+	export class StatementRisk {
+	    sql: string;
+	    kind: string;
+	    verb: string;
+	    table?: string;
+	    dangerous: boolean;
+	    reason?: string;
+	    estimatedRows: number;
+	    detail?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatementRisk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sql = source["sql"];
+	        this.kind = source["kind"];
+	        this.verb = source["verb"];
+	        this.table = source["table"];
+	        this.dangerous = source["dangerous"];
+	        this.reason = source["reason"];
+	        this.estimatedRows = source["estimatedRows"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class GuardCheck {
+	    connName: string;
+	    environment: string;
+	    readOnly: boolean;
+	    blocked: boolean;
+	    blockReason?: string;
+	    requiresConfirm: boolean;
+	    token?: string;
+	    tokenTtlSec?: number;
+	    estimatedRows: number;
+	    statements: StatementRisk[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GuardCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connName = source["connName"];
+	        this.environment = source["environment"];
+	        this.readOnly = source["readOnly"];
+	        this.blocked = source["blocked"];
+	        this.blockReason = source["blockReason"];
+	        this.requiresConfirm = source["requiresConfirm"];
+	        this.token = source["token"];
+	        this.tokenTtlSec = source["tokenTtlSec"];
+	        this.estimatedRows = source["estimatedRows"];
+	        this.statements = this.convertValues(source["statements"], StatementRisk);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportOptions {
+	    continueOnError: boolean;
+	    errorLogPath: string;
+	    disableForeignKeyChecks: boolean;
+	    disableUniqueChecks: boolean;
+	    confirmToken: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.continueOnError = source["continueOnError"];
+	        this.errorLogPath = source["errorLogPath"];
+	        this.disableForeignKeyChecks = source["disableForeignKeyChecks"];
+	        this.disableUniqueChecks = source["disableUniqueChecks"];
+	        this.confirmToken = source["confirmToken"];
+	    }
+	}
+	export class ImportTableStat {
+	    name: string;
+	    statements: number;
+	    errors: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportTableStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.statements = source["statements"];
+	        this.errors = source["errors"];
+	    }
+	}
+	export class ImportResult {
+	    path: string;
+	    statements: number;
+	    errors: number;
+	    bytesRead: number;
+	    elapsedSec: number;
+	    errorLogPath?: string;
+	    tables: ImportTableStat[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.statements = source["statements"];
+	        this.errors = source["errors"];
+	        this.bytesRead = source["bytesRead"];
+	        this.elapsedSec = source["elapsedSec"];
+	        this.errorLogPath = source["errorLogPath"];
+	        this.tables = this.convertValues(source["tables"], ImportTableStat);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Job {
+	    id: string;
+	    kind: string;
+	    title: string;
+	    status: string;
+	    progress: number;
+	    message: string;
+	    error?: string;
+	    result?: any;
+	    counts?: Record<string, number>;
+	    createdAt: string;
+	    startedAt?: string;
+	    finishedAt?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Job(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.title = source["title"];
+	        this.status = source["status"];
+	        this.progress = source["progress"];
+	        this.message = source["message"];
+	        this.error = source["error"];
+	        this.result = source["result"];
+	        this.counts = source["counts"];
+	        this.createdAt = source["createdAt"];
+	        this.startedAt = source["startedAt"];
+	        this.finishedAt = source["finishedAt"];
+	    }
+	}
+	export class MaskingRule {
+	    id: string;
+	    name: string;
+	    enabled: boolean;
+	    connection: string;
+	    schema: string;
 	    table: string;
 	    column: string;
+	    strategy: string;
+	    keepPrefix: number;
+	    keepSuffix: number;
+	    maskChar: string;
+	    fixedValue: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ColumnMeta(source);
+	        return new MaskingRule(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.enabled = source["enabled"];
+	        this.connection = source["connection"];
+	        this.schema = source["schema"];
 	        this.table = source["table"];
 	        this.column = source["column"];
+	        this.strategy = source["strategy"];
+	        this.keepPrefix = source["keepPrefix"];
+	        this.keepSuffix = source["keepSuffix"];
+	        this.maskChar = source["maskChar"];
+	        this.fixedValue = source["fixedValue"];
 	    }
 	}
-	export class DBConfig {
-	    id: string;
+	export class MetaColumn {
 	    name: string;
 	    type: string;
-	    host: string;
-	    port: number;
-	    user: string;
-	    password: string;
-	    database: string;
+	    nullable: boolean;
+	    key?: string;
+	    comment?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new DBConfig(source);
+	        return new MetaColumn(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
 	        this.name = source["name"];
 	        this.type = source["type"];
-	        this.host = source["host"];
-	        this.port = source["port"];
-	        this.user = source["user"];
-	        this.password = source["password"];
-	        this.database = source["database"];
+	        this.nullable = source["nullable"];
+	        this.key = source["key"];
+	        this.comment = source["comment"];
 	    }
 	}
-	export class MigrationCheckRow {
+	export class MetaRoutine {
 	    name: string;
-	    sourceRows: number;
-	    targetRows: number;
-	    status: string;
+	    kind: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new MigrationCheckRow(source);
+	        return new MetaRoutine(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
-	        this.sourceRows = source["sourceRows"];
-	        this.targetRows = source["targetRows"];
-	        this.status = source["status"];
+	        this.kind = source["kind"];
+	    }
+	}
+	export class MetaTable {
+	    name: string;
+	    kind: string;
+	    comment?: string;
+	    columns: MetaColumn[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MetaTable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.comment = source["comment"];
+	        this.columns = this.convertValues(source["columns"], MetaColumn);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class PrivilegeMatrixRow {
+	    user: string;
+	    host: string;
+	    level: string;
+	    object: string;
+	    privileges: Record<string, boolean>;
+	
+	    static createFrom(source: any = {}) {
+	        return new PrivilegeMatrixRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.user = source["user"];
+	        this.host = source["host"];
+	        this.level = source["level"];
+	        this.object = source["object"];
+	        this.privileges = source["privileges"];
+	    }
+	}
+	export class PrivilegeMatrix {
+	    schema: string;
+	    privileges: string[];
+	    rows: PrivilegeMatrixRow[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PrivilegeMatrix(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.schema = source["schema"];
+	        this.privileges = source["privileges"];
+	        this.rows = this.convertValues(source["rows"], PrivilegeMatrixRow);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
 	export class QueryResult {
 	    columns: string[];
 	    rows: any[];
+	    masked?: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new QueryResult(source);
@@ -82,8 +1840,310 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.columns = source["columns"];
 	        this.rows = source["rows"];
+	        this.masked = source["masked"];
+	    }
+	}
+	
+	export class RestoreOptions {
+	    database: string;
+	    tables: string[];
+	    keepSchema: boolean;
+	    keepData: boolean;
+	    continueOnError: boolean;
+	    errorLogPath: string;
+	    disableForeignKeyChecks: boolean;
+	    disableUniqueChecks: boolean;
+	    confirmToken: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RestoreOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.database = source["database"];
+	        this.tables = source["tables"];
+	        this.keepSchema = source["keepSchema"];
+	        this.keepData = source["keepData"];
+	        this.continueOnError = source["continueOnError"];
+	        this.errorLogPath = source["errorLogPath"];
+	        this.disableForeignKeyChecks = source["disableForeignKeyChecks"];
+	        this.disableUniqueChecks = source["disableUniqueChecks"];
+	        this.confirmToken = source["confirmToken"];
+	    }
+	}
+	export class SchemaDiffItem {
+	    objectType: string;
+	    table: string;
+	    name: string;
+	    action: string;
+	    source: string;
+	    target: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SchemaDiffItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.objectType = source["objectType"];
+	        this.table = source["table"];
+	        this.name = source["name"];
+	        this.action = source["action"];
+	        this.source = source["source"];
+	        this.target = source["target"];
+	    }
+	}
+	export class SchemaDiffOptions {
+	    ignoreAutoIncrement: boolean;
+	    ignoreComments: boolean;
+	    ignoreCharset: boolean;
+	    ignoreCollation: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SchemaDiffOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ignoreAutoIncrement = source["ignoreAutoIncrement"];
+	        this.ignoreComments = source["ignoreComments"];
+	        this.ignoreCharset = source["ignoreCharset"];
+	        this.ignoreCollation = source["ignoreCollation"];
+	    }
+	}
+	export class SchemaDiffResult {
+	    sourceDb: string;
+	    targetDb: string;
+	    items: SchemaDiffItem[];
+	    script: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SchemaDiffResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sourceDb = source["sourceDb"];
+	        this.targetDb = source["targetDb"];
+	        this.items = this.convertValues(source["items"], SchemaDiffItem);
+	        this.script = source["script"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SchemaMetadata {
+	    connId: string;
+	    schema: string;
+	    dialect: string;
+	    tables: MetaTable[];
+	    routines: MetaRoutine[];
+	    keywords: string[];
+	    loadedAt: string;
+	    changed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SchemaMetadata(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connId = source["connId"];
+	        this.schema = source["schema"];
+	        this.dialect = source["dialect"];
+	        this.tables = this.convertValues(source["tables"], MetaTable);
+	        this.routines = this.convertValues(source["routines"], MetaRoutine);
+	        this.keywords = source["keywords"];
+	        this.loadedAt = source["loadedAt"];
+	        this.changed = source["changed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Suggestion {
+	    label: string;
+	    kind: string;
+	    detail?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Suggestion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.kind = source["kind"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class SuggestResult {
+	    context: string;
+	    prefix: string;
+	    items: Suggestion[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SuggestResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.context = source["context"];
+	        this.prefix = source["prefix"];
+	        this.items = this.convertValues(source["items"], Suggestion);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SyncCheckpointInfo {
+	    jobId: string;
+	    targetId: string;
+	    sourceDb: string;
+	    targetDb: string;
+	    mode: string;
+	    tablesDone: number;
+	    tablesAll: number;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncCheckpointInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.targetId = source["targetId"];
+	        this.sourceDb = source["sourceDb"];
+	        this.targetDb = source["targetDb"];
+	        this.mode = source["mode"];
+	        this.tablesDone = source["tablesDone"];
+	        this.tablesAll = source["tablesAll"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	
+	
+	export class SyncPlan {
+	    sourceDb: string;
+	    targetDb: string;
+	    mode: string;
+	    createDatabase: boolean;
+	    rows: MigrationCheckRow[];
+	    script: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncPlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sourceDb = source["sourceDb"];
+	        this.targetDb = source["targetDb"];
+	        this.mode = source["mode"];
+	        this.createDatabase = source["createDatabase"];
+	        this.rows = this.convertValues(source["rows"], MigrationCheckRow);
+	        this.script = source["script"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TableDDL {
+	    create: boolean;
+	    statements: string[];
+	    script: string;
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TableDDL(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.create = source["create"];
+	        this.statements = source["statements"];
+	        this.script = source["script"];
+	        this.warnings = source["warnings"];
+	    }
+	}
+	export class TableDDLOptions {
+	    online: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TableDDLOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.online = source["online"];
 	    }
 	}
+	
 	export class TableMeta {
 	    name: string;
 	    rows: number;
@@ -100,6 +2160,62 @@ export namespace main {
 	        this.sizeBytes = source["sizeBytes"];
 	    }
 	}
+	export class TableModel {
+	    schema: string;
+	    name: string;
+	    originalName: string;
+	    engine: string;
+	    charset: string;
+	    collation: string;
+	    autoIncrement: number;
+	    originalAutoIncrement: number;
+	    comment: string;
+	    partition: string;
+	    columns: DesignColumn[];
+	    indexes: DesignIndex[];
+	    foreignKeys: DesignForeignKey[];
+	    checks: DesignCheck[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TableModel(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.schema = source["schema"];
+	        this.name = source["name"];
+	        this.originalName = source["originalName"];
+	        this.engine = source["engine"];
+	        this.charset = source["charset"];
+	        this.collation = source["collation"];
+	        this.autoIncrement = source["autoIncrement"];
+	        this.originalAutoIncrement = source["originalAutoIncrement"];
+	        this.comment = source["comment"];
+	        this.partition = source["partition"];
+	        this.columns = this.convertValues(source["columns"], DesignColumn);
+	        this.indexes = this.convertValues(source["indexes"], DesignIndex);
+	        this.foreignKeys = this.convertValues(source["foreignKeys"], DesignForeignKey);
+	        this.checks = this.convertValues(source["checks"], DesignCheck);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class TableStat {
 	    name: string;
 	    rows: number;
@@ -116,6 +2232,22 @@ export namespace main {
 	        this.sizeBytes = source["sizeBytes"];
 	    }
 	}
+	export class TypeMappingRule {
+	    source: string;
+	    target: string;
+	    note: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TypeMappingRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.target = source["target"];
+	        this.note = source["note"];
+	    }
+	}
 	export class ViewMeta {
 	    name: string;
 	
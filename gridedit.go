@@ -0,0 +1,623 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// EditableColumn 结果列与表列的对应关系
+type EditableColumn struct {
+	Name     string `json:"name"`   // 结果列名
+	Column   string `json:"column"` // 表列名，表达式列为空
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+	Editable bool   `json:"editable"`
+	Masked   bool   `json:"masked,omitempty"`
+}
+
+// EditableResult 结果集能否在表格中直接编辑；不可编辑时给出原因
+type EditableResult struct {
+	Editable bool             `json:"editable"`
+	Reason   string           `json:"reason,omitempty"`
+	Schema   string           `json:"schema"`
+	Table    string           `json:"table"`
+	Key      []string         `json:"key"`     // 定位行所用的主键或非空唯一键（表列名）
+	KeyName  string           `json:"keyName"` // 主键或唯一键名称
+	Columns  []EditableColumn `json:"columns"`
+}
+
+// GridChange 表格中的一处修改：Original 为查询出的原始行，Values 为新值，均按结果列名
+type GridChange struct {
+	Action   string                 `json:"action"` // update / insert / delete
+	Original map[string]interface{} `json:"original"`
+	Values   map[string]interface{} `json:"values"`
+}
+
+// GridStatement 生成的一条 DML；SQL 为代入参数后的展示文本，执行时使用参数绑定
+type GridStatement struct {
+	Action string `json:"action"`
+	SQL    string `json:"sql"`
+
+	query string
+	args  []interface{}
+	where string // 定位行的条件，参数为 args 末尾的键列值
+}
+
+// GridApplyResult 提交结果
+type GridApplyResult struct {
+	Statements int   `json:"statements"`
+	Affected   int64 `json:"affected"`
+}
+
+// gridEditor 单表结果集的编辑上下文
+type gridEditor struct {
+	dialect string
+	result  EditableResult
+	byName  map[string]*EditableColumn
+}
+
+// GetEditableResult 分析查询结果能否编辑：只支持单表 SELECT，列须为表列本身，且结果中包含主键或非空唯一键
+func (a *App) GetEditableResult(query string) (EditableResult, error) {
+	if a.db == nil {
+		return EditableResult{}, fmt.Errorf("数据库未连接")
+	}
+	e, err := a.gridEditor(query)
+	if err != nil {
+		return EditableResult{Reason: err.Error()}, nil
+	}
+	return e.result, nil
+}
+
+// PreviewGridChanges 生成修改对应的 DML 供预览
+func (a *App) PreviewGridChanges(query string, changes []GridChange) ([]GridStatement, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("数据库未连接")
+	}
+	e, err := a.gridEditor(query)
+	if err != nil {
+		return nil, err
+	}
+	return e.statements(changes)
+}
+
+// ApplyGridChanges 在一个事务中执行全部修改；任一语句出错或影响行数不是 1 时整体回滚
+func (a *App) ApplyGridChanges(query string, changes []GridChange, confirmToken string) (res GridApplyResult, err error) {
+	if a.db == nil {
+		return GridApplyResult{}, fmt.Errorf("数据库未连接")
+	}
+	start := time.Now()
+	script := ""
+	defer func() {
+		if script != "" {
+			a.auditStatement("grid.edit", script, start, int(res.Affected), err)
+		}
+	}()
+	e, err := a.gridEditor(query)
+	if err != nil {
+		return GridApplyResult{}, err
+	}
+	stmts, err := e.statements(changes)
+	if err != nil {
+		return GridApplyResult{}, err
+	}
+	if len(stmts) == 0 {
+		return GridApplyResult{}, nil
+	}
+	texts := make([]string, len(stmts))
+	for i, st := range stmts {
+		texts[i] = st.SQL
+	}
+	script = strings.Join(texts, ";\n")
+	if _, err := a.guardQuery(script, confirmToken); err != nil {
+		return GridApplyResult{}, err
+	}
+
+	ctx := context.Background()
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return GridApplyResult{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	for i, st := range stmts {
+		r, err := tx.ExecContext(ctx, st.query, st.args...)
+		if err != nil {
+			return GridApplyResult{}, fmt.Errorf("第 %d 条语句执行失败，已回滚: %v", i+1, err)
+		}
+		n, err := r.RowsAffected()
+		if err != nil {
+			return GridApplyResult{}, err
+		}
+		if n == 0 && st.Action == "update" && e.dialect == "mysql" {
+			// MySQL 默认返回实际改变的行数，值未变化时为 0，需确认行仍然存在
+			n, err = e.countRows(ctx, tx, st)
+			if err != nil {
+				return GridApplyResult{}, err
+			}
+		}
+		if n != 1 {
+			return GridApplyResult{}, fmt.Errorf("第 %d 条语句影响 %d 行（预期 1 行），数据可能已被修改，已回滚：%s", i+1, n, st.SQL)
+		}
+		res.Affected += n
+	}
+	if err = tx.Commit(); err != nil {
+		return GridApplyResult{}, err
+	}
+	res.Statements = len(stmts)
+	return res, nil
+}
+
+// gridEditor 解析查询，定位来源表与行键
+func (a *App) gridEditor(query string) (*gridEditor, error) {
+	dialect := a.currentDBType
+	tokens, err := tokenizeSQL(query, dialect)
+	if err != nil {
+		return nil, err
+	}
+	sig := significantTokens(tokens)
+	for len(sig) > 0 && sig[len(sig)-1].kind == tokSemi {
+		sig = sig[:len(sig)-1]
+	}
+	if len(sig) == 0 || !tokenIs(sig[0], "SELECT") {
+		return nil, fmt.Errorf("只有 SELECT 查询的结果可以编辑")
+	}
+	for _, t := range sig {
+		if t.kind == tokSemi {
+			return nil, fmt.Errorf("只能编辑单条查询的结果")
+		}
+	}
+	if topLevelWord(sig, 0, "JOIN", "UNION", "INTERSECT", "EXCEPT", "MINUS", "GROUP", "HAVING", "DISTINCT", "DISTINCTROW", "INTO", "CONNECT") >= 0 {
+		return nil, fmt.Errorf("多表、聚合或去重查询的结果不能编辑")
+	}
+	lists := selectLists(sig)
+	refs := parseTableRefs(sig)
+	if len(lists) != 1 || len(refs) != 1 {
+		return nil, fmt.Errorf("只能编辑单表查询（不含子查询）的结果")
+	}
+	ref := refs[0]
+
+	schema := ref.schema
+	if schema == "" {
+		schema = a.currentSchema
+	}
+	if schema == "" && dialect == "oracle" {
+		if err := a.db.QueryRow("SELECT SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') FROM DUAL").Scan(&schema); err != nil {
+			return nil, err
+		}
+	}
+	if schema == "" {
+		return nil, fmt.Errorf("请先选择数据库")
+	}
+	if dialect == "oracle" {
+		schema = strings.ToUpper(schema)
+	}
+	meta, err := a.meta.get(a.currentCfg, schema, false)
+	if err != nil {
+		return nil, err
+	}
+	var table *MetaTable
+	for i := range meta.Tables {
+		if identEqual(meta.Tables[i].Name, ref.table) {
+			table = &meta.Tables[i]
+			break
+		}
+	}
+	if table == nil {
+		return nil, fmt.Errorf("表 %s.%s 不存在", schema, ref.table)
+	}
+	if table.Kind != "table" {
+		return nil, fmt.Errorf("%s 是视图，不能编辑", table.Name)
+	}
+
+	e := &gridEditor{
+		dialect: dialect,
+		result:  EditableResult{Schema: schema, Table: table.Name},
+		byName:  map[string]*EditableColumn{},
+	}
+	columnOf := func(name string) *MetaColumn {
+		for i := range table.Columns {
+			if identEqual(table.Columns[i].Name, name) {
+				return &table.Columns[i]
+			}
+		}
+		return nil
+	}
+	resultName := func(t sqlToken) string {
+		if dialect == "oracle" && t.kind == tokWord {
+			return strings.ToUpper(t.text)
+		}
+		return unquoteIdent(t.text)
+	}
+	isName := func(t sqlToken) bool { return t.kind == tokWord || t.kind == tokQuoted }
+	isOwnQualifier := func(q string) bool {
+		return identEqual(q, ref.alias) || (ref.alias == "" && identEqual(q, ref.table))
+	}
+	for _, item := range lists[0].items {
+		n := len(item)
+		// * 或 t.*
+		if n > 0 && item[n-1].kind == tokOp && item[n-1].text == "*" {
+			if n == 3 && !isOwnQualifier(unquoteIdent(item[0].text)) {
+				return nil, fmt.Errorf("无法识别的列 %s.*", item[0].text)
+			}
+			for _, c := range table.Columns {
+				e.result.Columns = append(e.result.Columns, EditableColumn{Name: c.Name, Column: c.Name, Type: c.Type, Nullable: c.Nullable, Editable: true})
+			}
+			continue
+		}
+		// 去掉别名
+		expr, alias := item, ""
+		switch {
+		case n >= 3 && isName(item[n-1]) && tokenIs(item[n-2], "AS"):
+			expr, alias = item[:n-2], resultName(item[n-1])
+		case n >= 2 && isName(item[n-1]) && item[n-2].kind != tokDot && item[n-2].kind != tokOp:
+			expr, alias = item[:n-1], resultName(item[n-1])
+		}
+		col := EditableColumn{Name: alias}
+		var ident sqlToken
+		switch {
+		case len(expr) == 1 && isName(expr[0]):
+			ident = expr[0]
+		case len(expr) == 3 && isName(expr[0]) && expr[1].kind == tokDot && isName(expr[2]) && isOwnQualifier(unquoteIdent(expr[0].text)):
+			ident = expr[2]
+		}
+		if mc := columnOf(unquoteIdent(ident.text)); ident.text != "" && mc != nil {
+			col.Column, col.Type, col.Nullable, col.Editable = mc.Name, mc.Type, mc.Nullable, true
+			if col.Name == "" {
+				col.Name = resultName(ident)
+			}
+		}
+		if col.Name == "" {
+			// 表达式列的结果列名无法可靠推断，按原文展示
+			texts := make([]string, len(expr))
+			for i, t := range expr {
+				texts[i] = t.text
+			}
+			col.Name = strings.Join(texts, "")
+		}
+		e.result.Columns = append(e.result.Columns, col)
+	}
+
+	// 脱敏的列既不能作为条件也不能原样写回
+	names := make([]string, len(e.result.Columns))
+	for i, c := range e.result.Columns {
+		names[i] = c.Name
+	}
+	for i, r := range columnRules(a.currentCfg, schema, dialect, query, names) {
+		if r != nil {
+			e.result.Columns[i].Masked = true
+			e.result.Columns[i].Editable = false
+		}
+	}
+	for i := range e.result.Columns {
+		c := &e.result.Columns[i]
+		if c.Column == "" {
+			continue
+		}
+		if prev, ok := e.byName[strings.ToLower(c.Name)]; ok && prev.Column != c.Column {
+			return nil, fmt.Errorf("结果中存在重名列 %s", c.Name)
+		}
+		e.byName[strings.ToLower(c.Name)] = c
+	}
+
+	keys, err := a.uniqueKeys(schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	missing := ""
+	for _, k := range keys {
+		ok := true
+		for _, col := range k.columns {
+			mc := columnOf(col)
+			if mc == nil || mc.Nullable {
+				ok = false
+				break
+			}
+			if rc := e.resultColumn(col); rc == nil || rc.Masked {
+				if missing == "" {
+					missing = col
+				}
+				ok = false
+				break
+			}
+		}
+		if ok {
+			e.result.Key, e.result.KeyName = k.columns, k.name
+			break
+		}
+	}
+	if e.result.Key == nil {
+		if missing != "" {
+			return nil, fmt.Errorf("结果中缺少键列 %s（或该列已脱敏），无法定位行", missing)
+		}
+		return nil, fmt.Errorf("表 %s 没有主键或非空唯一键，无法安全定位行", table.Name)
+	}
+	e.result.Editable = true
+	return e, nil
+}
+
+type gridKey struct {
+	name    string
+	columns []string
+}
+
+// uniqueKeys 主键在前，其余唯一键按名称排序
+func (a *App) uniqueKeys(schema, table string) ([]gridKey, error) {
+	var rows *sql.Rows
+	var err error
+	if a.currentDBType == "oracle" {
+		rows, err = a.db.Query(`SELECT c.CONSTRAINT_NAME, cc.COLUMN_NAME
+			FROM ALL_CONSTRAINTS c
+			JOIN ALL_CONS_COLUMNS cc ON cc.OWNER = c.OWNER AND cc.CONSTRAINT_NAME = c.CONSTRAINT_NAME
+			WHERE c.OWNER = :1 AND c.TABLE_NAME = :2 AND c.CONSTRAINT_TYPE IN ('P', 'U') AND c.STATUS = 'ENABLED'
+			ORDER BY DECODE(c.CONSTRAINT_TYPE, 'P', 0, 1), c.CONSTRAINT_NAME, cc.POSITION`, schema, table)
+	} else {
+		rows, err = a.db.Query(`SELECT INDEX_NAME, COLUMN_NAME FROM information_schema.STATISTICS
+			WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND NON_UNIQUE = 0
+			ORDER BY INDEX_NAME = 'PRIMARY' DESC, INDEX_NAME, SEQ_IN_INDEX`, schema, table)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []gridKey
+	for rows.Next() {
+		var name string
+		var col sql.NullString
+		if err := rows.Scan(&name, &col); err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 || keys[len(keys)-1].name != name {
+			keys = append(keys, gridKey{name: name})
+		}
+		k := &keys[len(keys)-1]
+		if !col.Valid {
+			// 函数索引等无法按列定位
+			k.columns = append(k.columns, "")
+			continue
+		}
+		k.columns = append(k.columns, col.String)
+	}
+	return keys, rows.Err()
+}
+
+// resultColumn 按表列名查找结果列
+func (e *gridEditor) resultColumn(column string) *EditableColumn {
+	for i := range e.result.Columns {
+		if c := &e.result.Columns[i]; c.Column != "" && identEqual(c.Column, column) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (e *gridEditor) quote(name string) string {
+	if e.dialect == "oracle" {
+		return quoteOracleIdent(name)
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (e *gridEditor) placeholder(n int) string {
+	if e.dialect == "oracle" {
+		return fmt.Sprintf(":%d", n)
+	}
+	return "?"
+}
+
+func (e *gridEditor) tableName() string {
+	return e.quote(e.result.Schema) + "." + e.quote(e.result.Table)
+}
+
+// statements 把修改转换为参数化语句：UPDATE/DELETE 以键列的原始值定位行，UPDATE 只设置变化的列
+func (e *gridEditor) statements(changes []GridChange) ([]GridStatement, error) {
+	var out []GridStatement
+	for i, ch := range changes {
+		var st GridStatement
+		var err error
+		switch ch.Action {
+		case "update":
+			st, err = e.updateStatement(ch)
+		case "insert":
+			st, err = e.insertStatement(ch)
+		case "delete":
+			st, err = e.deleteStatement(ch)
+		default:
+			err = fmt.Errorf("不支持的操作 %s", ch.Action)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 处修改: %v", i+1, err)
+		}
+		if st.query != "" {
+			out = append(out, st)
+		}
+	}
+	return out, nil
+}
+
+// values 校验并整理要写入的列，按结果列顺序返回
+func (e *gridEditor) values(values map[string]interface{}, original map[string]interface{}) ([]*EditableColumn, []interface{}, error) {
+	for name := range values {
+		c, ok := e.byName[strings.ToLower(name)]
+		if !ok {
+			return nil, nil, fmt.Errorf("列 %s 不是表 %s 的列，不能修改", name, e.result.Table)
+		}
+		if !c.Editable {
+			return nil, nil, fmt.Errorf("列 %s 已脱敏，不能修改", name)
+		}
+	}
+	var cols []*EditableColumn
+	var args []interface{}
+	seen := map[string]bool{}
+	for i := range e.result.Columns {
+		c := &e.result.Columns[i]
+		v, ok := lookupValue(values, c.Name)
+		if !ok || c.Column == "" || seen[strings.ToLower(c.Column)] {
+			continue
+		}
+		if original != nil {
+			if old, ok := lookupValue(original, c.Name); ok && fmt.Sprint(old) == fmt.Sprint(v) && (old == nil) == (v == nil) {
+				continue
+			}
+		}
+		if v == nil && !c.Nullable {
+			return nil, nil, fmt.Errorf("列 %s 不允许为空", c.Name)
+		}
+		seen[strings.ToLower(c.Column)] = true
+		cols = append(cols, c)
+		args = append(args, e.bindValue(c, v))
+	}
+	return cols, args, nil
+}
+
+// where 以键列的原始值生成条件
+func (e *gridEditor) where(original map[string]interface{}, n int) (string, []interface{}, error) {
+	parts := make([]string, len(e.result.Key))
+	args := make([]interface{}, len(e.result.Key))
+	for i, k := range e.result.Key {
+		c := e.resultColumn(k)
+		v, ok := lookupValue(original, c.Name)
+		if !ok || v == nil {
+			return "", nil, fmt.Errorf("缺少键列 %s 的原始值", c.Name)
+		}
+		parts[i] = e.quote(c.Column) + " = " + e.placeholder(n+i+1)
+		args[i] = e.bindValue(c, v)
+	}
+	return strings.Join(parts, " AND "), args, nil
+}
+
+func (e *gridEditor) updateStatement(ch GridChange) (GridStatement, error) {
+	cols, args, err := e.values(ch.Values, ch.Original)
+	if err != nil || len(cols) == 0 {
+		return GridStatement{}, err
+	}
+	sets := make([]string, len(cols))
+	for i, c := range cols {
+		sets[i] = e.quote(c.Column) + " = " + e.placeholder(i+1)
+	}
+	where, wargs, err := e.where(ch.Original, len(cols))
+	if err != nil {
+		return GridStatement{}, err
+	}
+	q := fmt.Sprintf("UPDATE %s SET %s WHERE %s", e.tableName(), strings.Join(sets, ", "), where)
+	st := e.statement("update", q, append(args, wargs...))
+	st.where = where
+	return st, nil
+}
+
+func (e *gridEditor) insertStatement(ch GridChange) (GridStatement, error) {
+	cols, args, err := e.values(ch.Values, nil)
+	if err != nil {
+		return GridStatement{}, err
+	}
+	if len(cols) == 0 {
+		return GridStatement{}, fmt.Errorf("新增行没有填写任何列")
+	}
+	names := make([]string, len(cols))
+	marks := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = e.quote(c.Column)
+		marks[i] = e.placeholder(i + 1)
+	}
+	q := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", e.tableName(), strings.Join(names, ", "), strings.Join(marks, ", "))
+	return e.statement("insert", q, args), nil
+}
+
+func (e *gridEditor) deleteStatement(ch GridChange) (GridStatement, error) {
+	where, args, err := e.where(ch.Original, 0)
+	if err != nil {
+		return GridStatement{}, err
+	}
+	q := fmt.Sprintf("DELETE FROM %s WHERE %s", e.tableName(), where)
+	return e.statement("delete", q, args), nil
+}
+
+// statement 生成代入参数的展示文本
+func (e *gridEditor) statement(action, query string, args []interface{}) GridStatement {
+	var b strings.Builder
+	n := 0
+	tokens, _ := tokenizeSQL(query, e.dialect)
+	last := 0
+	for _, t := range tokens {
+		if t.kind != tokVar || n >= len(args) {
+			continue
+		}
+		b.WriteString(query[last:t.start])
+		b.WriteString(e.literal(args[n]))
+		last = t.end
+		n++
+	}
+	b.WriteString(query[last:])
+	return GridStatement{Action: action, SQL: b.String(), query: query, args: args}
+}
+
+// literal 展示用的字面量
+func (e *gridEditor) literal(v interface{}) string {
+	if e.dialect != "oracle" {
+		return valueToSQL(v)
+	}
+	switch t := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + escapeSQLLiteral(t) + "'"
+	case time.Time:
+		return "TIMESTAMP '" + t.Format("2006-01-02 15:04:05.999999999") + "'"
+	case bool:
+		if t {
+			return "1"
+		}
+		return "0"
+	case int64, float64:
+		return fmt.Sprint(t)
+	default:
+		return "'" + escapeSQLLiteral(fmt.Sprint(t)) + "'"
+	}
+}
+
+// bindValue 把前端传回的值转换为适合绑定的类型：整数形式的数字转为 int64，Oracle 日期列的文本解析为时间
+func (e *gridEditor) bindValue(c *EditableColumn, v interface{}) interface{} {
+	switch t := v.(type) {
+	case float64:
+		if t == math.Trunc(t) && math.Abs(t) < 1<<53 {
+			return int64(t)
+		}
+	case string:
+		typ := strings.ToUpper(c.Type)
+		if e.dialect == "oracle" && (strings.HasPrefix(typ, "DATE") || strings.HasPrefix(typ, "TIMESTAMP")) {
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+				if ts, err := time.ParseInLocation(layout, t, time.Local); err == nil {
+					return ts
+				}
+			}
+		}
+	}
+	return v
+}
+
+// countRows 用 UPDATE 的条件确认目标行的数量（仅 MySQL，占位符不带序号）
+func (e *gridEditor) countRows(ctx context.Context, tx *sql.Tx, st GridStatement) (int64, error) {
+	var n int64
+	args := st.args[len(st.args)-len(e.result.Key):]
+	err := tx.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", e.tableName(), st.where), args...).Scan(&n)
+	return n, err
+}
+
+// lookupValue 按结果列名取值，优先精确匹配，其次忽略大小写
+func lookupValue(m map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
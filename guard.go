@@ -0,0 +1,787 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 连接环境标签
+const (
+	envDev  = "dev"
+	envTest = "test"
+	envProd = "prod"
+)
+
+// 语句类别
+const (
+	stmtRead    = "read"    // 查询
+	stmtSession = "session" // 会话控制：USE / SET / 事务
+	stmtWrite   = "write"   // DML
+	stmtDDL     = "ddl"
+	stmtAdmin   = "admin" // 管理命令：OPTIMIZE / SET GLOBAL / FLUSH 等
+	stmtKill    = "kill"
+	stmtBlock   = "block" // 匿名块，无法静态分析
+	stmtOther   = "other" // 无法识别
+)
+
+const guardTokenTTL = 5 * time.Minute
+
+// guardError 语句被拦截或缺少确认，审计中记为 blocked
+type guardError struct{ msg string }
+
+func (e *guardError) Error() string { return e.msg }
+
+// StatementRisk 单条语句的检查结果
+type StatementRisk struct {
+	SQL           string `json:"sql"`
+	Kind          string `json:"kind"` // read / session / write / ddl / admin / kill / block / other
+	Verb          string `json:"verb"`
+	Table         string `json:"table,omitempty"`
+	Dangerous     bool   `json:"dangerous"`
+	Reason        string `json:"reason,omitempty"`
+	EstimatedRows int64  `json:"estimatedRows"` // -1 表示无法估算
+	Detail        string `json:"detail,omitempty"`
+
+	schema   string
+	target   string // KILL 的会话ID
+	hasWhere bool
+	digest   string
+}
+
+// GuardCheck 执行前检查结果；需确认时携带一次性确认令牌
+type GuardCheck struct {
+	ConnName        string          `json:"connName"`
+	Environment     string          `json:"environment"`
+	ReadOnly        bool            `json:"readOnly"`
+	Blocked         bool            `json:"blocked"`
+	BlockReason     string          `json:"blockReason,omitempty"`
+	RequiresConfirm bool            `json:"requiresConfirm"`
+	Token           string          `json:"token,omitempty"`
+	TokenTTLSec     int             `json:"tokenTtlSec,omitempty"`
+	EstimatedRows   int64           `json:"estimatedRows"` // 可估算语句的影响行数合计，-1 表示均无法估算
+	Statements      []StatementRisk `json:"statements"`
+}
+
+// guardToken 确认令牌，pending 记录尚未执行的危险语句摘要及次数
+type guardToken struct {
+	conn    string
+	pending map[string]int
+	expires time.Time
+}
+
+// statementGuard 管理生产环境危险语句的确认令牌
+type statementGuard struct {
+	mu     sync.Mutex
+	tokens map[string]*guardToken
+}
+
+func newStatementGuard() *statementGuard {
+	return &statementGuard{tokens: map[string]*guardToken{}}
+}
+
+func (g *statementGuard) issue(conn string, digests []string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	for k, t := range g.tokens {
+		if now.After(t.expires) {
+			delete(g.tokens, k)
+		}
+	}
+	t := &guardToken{conn: conn, pending: map[string]int{}, expires: now.Add(guardTokenTTL)}
+	for _, d := range digests {
+		t.pending[d]++
+	}
+	token := newJobID()
+	g.tokens[token] = t
+	return token
+}
+
+// consume 校验并消耗令牌中的一条语句；令牌用尽后即失效
+func (g *statementGuard) consume(token, conn, digest string) bool {
+	if token == "" {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	t, ok := g.tokens[token]
+	if !ok {
+		return false
+	}
+	if time.Now().After(t.expires) {
+		delete(g.tokens, token)
+		return false
+	}
+	if t.conn != conn || t.pending[digest] == 0 {
+		return false
+	}
+	t.pending[digest]--
+	if t.pending[digest] == 0 {
+		delete(t.pending, digest)
+	}
+	if len(t.pending) == 0 {
+		delete(g.tokens, token)
+	}
+	return true
+}
+
+// normalizeEnvironment 规范化环境标签，未设置时视为开发环境
+func normalizeEnvironment(env string) string {
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "prod", "production", "prd":
+		return envProd
+	case "test", "testing", "staging", "uat":
+		return envTest
+	default:
+		return envDev
+	}
+}
+
+// resolveConnection 以已保存连接中的环境标签与只读标记为准，避免前端传入的配置绕过限制
+func resolveConnection(cfg DBConfig) DBConfig {
+	if cfg.ID != "" {
+		for _, c := range savedConfigs {
+			if c.ID == cfg.ID {
+				cfg.Environment = c.Environment
+				cfg.ReadOnly = c.ReadOnly
+				break
+			}
+		}
+	}
+	cfg.Environment = normalizeEnvironment(cfg.Environment)
+	return cfg
+}
+
+// checkWritable 写入类任务的目标连接不能是只读连接
+func checkWritable(cfg DBConfig) error {
+	cfg = resolveConnection(cfg)
+	if cfg.ReadOnly {
+		return fmt.Errorf("连接 %s 为只读连接，禁止写入", connDisplayName(cfg))
+	}
+	return nil
+}
+
+// taskDigest 写入任务的确认摘要，令牌按任务类别与目标库签发
+func taskDigest(kind, db string) string {
+	sum := sha256.Sum256([]byte("task\x00" + kind + "\x00" + db))
+	return hex.EncodeToString(sum[:])
+}
+
+// ConfirmTaskTarget 同步、导入、恢复等任务写入生产环境前的确认：目标为生产环境时返回确认令牌，
+// 提交任务时在选项中带上该令牌；kind 为 sync / cdc / import / restore / load
+func (a *App) ConfirmTaskTarget(target DBConfig, kind string, db string) (GuardCheck, error) {
+	cfg := resolveConnection(target)
+	check := GuardCheck{
+		ConnName:      connDisplayName(cfg),
+		Environment:   cfg.Environment,
+		ReadOnly:      cfg.ReadOnly,
+		EstimatedRows: -1,
+	}
+	if cfg.ReadOnly {
+		check.Blocked = true
+		check.BlockReason = fmt.Sprintf("连接 %s 为只读连接，禁止写入", check.ConnName)
+		return check, nil
+	}
+	if cfg.Environment != envProd {
+		return check, nil
+	}
+	check.RequiresConfirm = true
+	check.Token = a.guard.issue(guardConnKey(cfg), []string{taskDigest(kind, db)})
+	check.TokenTTLSec = int(guardTokenTTL / time.Second)
+	return check, nil
+}
+
+// checkDestructiveTask 会删表、清空或覆盖数据的任务写入生产环境时须带有效确认令牌；reason 为空表示任务无破坏性操作
+func (a *App) checkDestructiveTask(target DBConfig, kind, db, reason, token string) error {
+	if reason == "" {
+		return nil
+	}
+	cfg := resolveConnection(target)
+	if cfg.Environment != envProd || a.guard.consume(token, guardConnKey(cfg), taskDigest(kind, db)) {
+		return nil
+	}
+	return &guardError{fmt.Sprintf("生产环境 %s 的任务需要确认后执行（%s）", connDisplayName(cfg), reason)}
+}
+
+func connDisplayName(cfg DBConfig) string {
+	if cfg.Name != "" {
+		return cfg.Name
+	}
+	return fmt.Sprintf("%s@%s:%d", cfg.User, cfg.Host, cfg.Port)
+}
+
+func guardConnKey(cfg DBConfig) string {
+	if cfg.ID != "" {
+		return cfg.ID
+	}
+	return fmt.Sprintf("%s@%s:%d/%s", cfg.User, cfg.Host, cfg.Port, cfg.Database)
+}
+
+// CheckStatement 执行前检查：只读连接拦截写操作；生产环境的 DDL、无 WHERE 的 UPDATE/DELETE、
+// KILL 等需要确认，此时返回影响行数估算与确认令牌，执行时需带上该令牌
+func (a *App) CheckStatement(query string) (GuardCheck, error) {
+	if a.db == nil {
+		return GuardCheck{}, fmt.Errorf("数据库未连接")
+	}
+	check := a.analyzeQuery(query)
+	if check.Blocked || !check.RequiresConfirm {
+		return check, nil
+	}
+	check.EstimatedRows = -1
+	var digests []string
+	for i := range check.Statements {
+		st := &check.Statements[i]
+		if !st.Dangerous {
+			continue
+		}
+		digests = append(digests, st.digest)
+		a.estimateImpact(st)
+		if st.EstimatedRows >= 0 {
+			if check.EstimatedRows < 0 {
+				check.EstimatedRows = 0
+			}
+			check.EstimatedRows += st.EstimatedRows
+		}
+	}
+	check.Token = a.guard.issue(guardConnKey(a.currentCfg), digests)
+	check.TokenTTLSec = int(guardTokenTTL / time.Second)
+	return check, nil
+}
+
+// guardQuery 执行前校验：被拦截或缺少有效确认令牌时返回错误；返回分类后的语句供执行后使用
+func (a *App) guardQuery(query string, confirmToken string) ([]StatementRisk, error) {
+	check := a.analyzeQuery(query)
+	if check.Blocked {
+		return nil, &guardError{check.BlockReason}
+	}
+	if !check.RequiresConfirm {
+		return check.Statements, nil
+	}
+	conn := guardConnKey(a.currentCfg)
+	for _, st := range check.Statements {
+		if st.Dangerous && !a.guard.consume(confirmToken, conn, st.digest) {
+			return nil, &guardError{fmt.Sprintf("生产环境的危险语句需要确认后执行（%s）", st.Reason)}
+		}
+	}
+	return check.Statements, nil
+}
+
+// invalidateDDLMetadata 执行过 DDL 后清除相关库的元数据缓存：语句带库名时只清除该库，
+// 识别出表名但未带库名时清除当前库；无法确定所在库（未识别对象、当前库未知、脚本中切换过库）时
+// 清除该连接下的全部库。匿名块可能包含动态 DDL，同样处理
+func (a *App) invalidateDDLMetadata(stmts []StatementRisk, schema string) {
+	schemas := map[string]bool{}
+	for _, st := range stmts {
+		switch {
+		case st.Kind == stmtSession && (st.Verb == "USE" || st.Verb == "ALTER"):
+			// USE / ALTER SESSION SET CURRENT_SCHEMA 之后的语句所在库无法确定
+			schema = ""
+		case st.Kind != stmtDDL && st.Kind != stmtBlock:
+		case st.schema != "":
+			schemas[st.schema] = true
+		case st.Table != "":
+			schemas[schema] = true
+		default:
+			schemas[""] = true
+		}
+	}
+	if schemas[""] {
+		a.meta.invalidate(a.currentCfg, "")
+		return
+	}
+	for s := range schemas {
+		a.meta.invalidate(a.currentCfg, s)
+	}
+}
+
+// analyzeQuery 按当前连接的环境与只读标记分析语句
+func (a *App) analyzeQuery(query string) GuardCheck {
+	cfg := a.currentCfg
+	check := GuardCheck{
+		ConnName:      connDisplayName(cfg),
+		Environment:   normalizeEnvironment(cfg.Environment),
+		ReadOnly:      cfg.ReadOnly,
+		EstimatedRows: -1,
+		Statements:    classifyStatements(query, a.currentDBType),
+	}
+	for i := range check.Statements {
+		st := &check.Statements[i]
+		if check.ReadOnly && st.Kind != stmtRead && st.Kind != stmtSession {
+			check.Blocked = true
+			if check.BlockReason == "" {
+				check.BlockReason = fmt.Sprintf("连接 %s 为只读连接，禁止执行 %s 语句", check.ConnName, st.Verb)
+			}
+		}
+		if check.Environment == envProd && st.Dangerous {
+			check.RequiresConfirm = true
+		}
+	}
+	return check
+}
+
+// classifyStatements 切分并识别语句类别；非生产环境同样标记危险语句，由调用方决定是否拦截
+func classifyStatements(query string, dialect string) []StatementRisk {
+	tokens, err := tokenizeSQL(query, dialect)
+	if err != nil {
+		st := StatementRisk{SQL: strings.TrimSpace(query), Kind: stmtOther, Verb: "?", Dangerous: true,
+			Reason: fmt.Sprintf("无法解析语句: %v", err), EstimatedRows: -1}
+		st.digest = statementDigest([]sqlToken{{kind: tokString, text: st.SQL}})
+		return []StatementRisk{st}
+	}
+	if dialect != "oracle" {
+		tokens = expandConditionalComments(tokens)
+	}
+	f := &sqlFormatter{tokens: tokens, dialect: dialect}
+	var out []StatementRisk
+	start := 0
+	for start < len(tokens) {
+		sig := significantTokens(tokens[start:])
+		if len(sig) == 0 {
+			break
+		}
+		end := -1
+		if isProceduralStart(sig, dialect) {
+			end = f.verbatimEnd(start)
+		} else {
+			for j := start; j < len(tokens); j++ {
+				if tokens[j].kind == tokSemi {
+					end = j + 1
+					break
+				}
+			}
+			if end < 0 {
+				end = len(tokens)
+			}
+		}
+		stmt := significantTokens(tokens[start:end])
+		if n := len(stmt); n > 0 && stmt[n-1].kind == tokSemi {
+			stmt = stmt[:n-1]
+		}
+		if len(stmt) > 0 {
+			st := classifyStatement(stmt, dialect)
+			st.SQL = strings.TrimSpace(query[stmt[0].start:stmt[len(stmt)-1].end])
+			st.digest = statementDigest(stmt)
+			out = append(out, st)
+		}
+		start = end
+	}
+	return out
+}
+
+// isProceduralStart 存储过程、触发器及 Oracle 匿名块内部含分号，需整体作为一条语句；
+// MySQL 顶层的 BEGIN 为开启事务
+func isProceduralStart(sig []sqlToken, dialect string) bool {
+	if dialect != "oracle" && sig[0].kind == tokWord && strings.EqualFold(sig[0].text, "BEGIN") {
+		return false
+	}
+	return isBlockStart(sig)
+}
+
+func significantTokens(tokens []sqlToken) []sqlToken {
+	out := make([]sqlToken, 0, len(tokens))
+	for _, t := range tokens {
+		if t.kind != tokLineComment && t.kind != tokBlockComment {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// expandConditionalComments MySQL 会执行 /*! ... */ 中的内容，展开后参与分析
+func expandConditionalComments(tokens []sqlToken) []sqlToken {
+	var out []sqlToken
+	for _, t := range tokens {
+		if t.kind != tokBlockComment || !strings.HasPrefix(t.text, "/*!") || !strings.HasSuffix(t.text, "*/") {
+			out = append(out, t)
+			continue
+		}
+		inner := strings.TrimLeft(t.text[3:len(t.text)-2], "0123456789")
+		sub, err := tokenizeSQL(inner, "mysql")
+		if err != nil {
+			out = append(out, t)
+			continue
+		}
+		offset := t.start + len(t.text) - 2 - len(inner)
+		for _, s := range sub {
+			s.start += offset
+			s.end += offset
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// statementDigest 语句摘要：忽略注释与空白差异
+func statementDigest(tokens []sqlToken) string {
+	h := sha256.New()
+	for _, t := range tokens {
+		h.Write([]byte(t.text))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func tokenIs(t sqlToken, words ...string) bool {
+	if t.kind != tokWord {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// topLevelWord 查找不在括号内的关键字，返回下标，未找到返回 -1
+func topLevelWord(tokens []sqlToken, from int, words ...string) int {
+	depth := 0
+	for i := from; i < len(tokens); i++ {
+		switch t := tokens[i]; {
+		case t.kind == tokOpen:
+			depth++
+		case t.kind == tokClose:
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && tokenIs(t, words...):
+			return i
+		}
+	}
+	return -1
+}
+
+// readObjectName 读取 [schema.]name，返回下一个位置
+func readObjectName(tokens []sqlToken, i int) (schema, name string, next int) {
+	if i >= len(tokens) || (tokens[i].kind != tokWord && tokens[i].kind != tokQuoted) {
+		return "", "", i
+	}
+	name = unquoteIdent(tokens[i].text)
+	i++
+	if i+1 < len(tokens) && tokens[i].kind == tokDot && (tokens[i+1].kind == tokWord || tokens[i+1].kind == tokQuoted) {
+		schema, name = name, unquoteIdent(tokens[i+1].text)
+		i += 2
+	}
+	return schema, name, i
+}
+
+// skipWords 跳过可选修饰词
+func skipWords(tokens []sqlToken, i int, words ...string) int {
+	for i < len(tokens) && tokenIs(tokens[i], words...) {
+		i++
+	}
+	return i
+}
+
+func classifyStatement(tokens []sqlToken, dialect string) StatementRisk {
+	st := StatementRisk{EstimatedRows: -1}
+	i := 0
+	for i < len(tokens) && tokens[i].kind == tokOpen {
+		i++
+	}
+	if i >= len(tokens) || tokens[i].kind != tokWord {
+		st.Kind, st.Verb = stmtOther, "?"
+		st.Dangerous, st.Reason = true, "无法识别的语句"
+		return st
+	}
+	verb := strings.ToUpper(tokens[i].text)
+	st.Verb = verb
+	next := func(k int) string {
+		if i+k < len(tokens) && tokens[i+k].kind == tokWord {
+			return strings.ToUpper(tokens[i+k].text)
+		}
+		return ""
+	}
+	switch verb {
+	case "SELECT":
+		st.Kind = stmtRead
+		if j := topLevelWord(tokens, i, "INTO"); j >= 0 && j+1 < len(tokens) && tokenIs(tokens[j+1], "OUTFILE", "DUMPFILE") {
+			st.Kind = stmtWrite
+		}
+	case "SHOW", "DESC", "DESCRIBE", "HELP", "TABLE", "VALUES", "CHECKSUM":
+		st.Kind = stmtRead
+	case "EXPLAIN":
+		st.Kind = stmtRead
+		// EXPLAIN ANALYZE 会真正执行语句
+		if next(1) == "ANALYZE" {
+			inner := classifyStatement(tokens[i+2:], dialect)
+			inner.Verb = "EXPLAIN ANALYZE " + inner.Verb
+			return inner
+		}
+	case "WITH":
+		if j := topLevelWord(tokens, i+1, "SELECT", "INSERT", "UPDATE", "DELETE", "MERGE"); j >= 0 && !tokenIs(tokens[j], "SELECT") {
+			inner := classifyStatement(tokens[j:], dialect)
+			return inner
+		}
+		st.Kind = stmtRead
+	case "INSERT", "REPLACE", "MERGE", "LOAD", "UPSERT", "CALL", "DO", "HANDLER", "EXEC", "EXECUTE", "PREPARE":
+		st.Kind = stmtWrite
+	case "UPDATE", "DELETE":
+		st.Kind = stmtWrite
+		j := i + 1
+		if verb == "UPDATE" {
+			j = skipWords(tokens, j, "LOW_PRIORITY", "IGNORE", "ONLY")
+		} else {
+			j = skipWords(tokens, j, "LOW_PRIORITY", "QUICK", "IGNORE")
+			if f := topLevelWord(tokens, j, "FROM"); f == j {
+				j = f + 1
+			}
+		}
+		st.schema, st.Table, _ = readObjectName(tokens, j)
+		st.hasWhere = topLevelWord(tokens, i+1, "WHERE") >= 0
+		if !st.hasWhere {
+			st.Dangerous, st.Reason = true, fmt.Sprintf("%s 没有 WHERE 条件，将影响全表", verb)
+		}
+	case "CREATE", "DROP", "TRUNCATE", "RENAME", "GRANT", "REVOKE", "COMMENT", "FLASHBACK", "AUDIT", "NOAUDIT", "IMPORT":
+		st.Kind = stmtDDL
+		st.Dangerous, st.Reason = true, verb+" 为结构变更语句"
+		switch {
+		case verb == "TRUNCATE":
+			st.schema, st.Table, _ = readObjectName(tokens, skipWords(tokens, i+1, "TABLE"))
+			st.Reason = "TRUNCATE 将清空表数据"
+		case verb == "DROP" && next(1) == "TABLE":
+			st.schema, st.Table, _ = readObjectName(tokens, skipWords(tokens, i+2, "TEMPORARY", "IF", "EXISTS"))
+			st.Reason = "DROP TABLE 将删除表及其数据"
+		case verb == "DROP" && (next(1) == "DATABASE" || next(1) == "SCHEMA"):
+			_, st.schema, _ = readObjectName(tokens, skipWords(tokens, i+2, "IF", "EXISTS"))
+			st.Reason = "DROP " + next(1) + " 将删除整个库"
+		}
+	case "ALTER":
+		switch {
+		case next(1) == "SESSION":
+			st.Kind = stmtSession
+		case next(1) == "SYSTEM" && next(2) == "KILL":
+			st.Kind = stmtKill
+			if j := i + 4; j < len(tokens) && tokens[j].kind == tokString {
+				st.target = strings.Trim(tokens[j].text, "'")
+			}
+			st.Dangerous, st.Reason = true, "将终止会话"
+		case next(1) == "SYSTEM":
+			st.Kind = stmtAdmin
+			st.Dangerous, st.Reason = true, "ALTER SYSTEM 为实例级管理命令"
+		default:
+			st.Kind = stmtDDL
+			st.Dangerous, st.Reason = true, "ALTER 为结构变更语句"
+			if next(1) == "TABLE" {
+				st.schema, st.Table, _ = readObjectName(tokens, skipWords(tokens, i+2, "ONLINE", "IGNORE"))
+				st.Reason = "ALTER TABLE 可能重建表或锁表"
+			}
+		}
+	case "KILL":
+		st.Kind = stmtKill
+		j := skipWords(tokens, i+1, "QUERY", "CONNECTION")
+		if j < len(tokens) && tokens[j].kind == tokNumber {
+			st.target = tokens[j].text
+		}
+		st.Dangerous, st.Reason = true, "将终止会话"
+	case "OPTIMIZE", "REPAIR", "ANALYZE", "FLUSH", "RESET", "PURGE", "CHANGE", "STOP", "SHUTDOWN", "RESTART",
+		"INSTALL", "UNINSTALL", "CLONE", "CACHE", "BINLOG":
+		st.Kind = stmtAdmin
+		st.Dangerous, st.Reason = true, verb+" 为管理命令"
+	case "SET":
+		st.Kind = stmtSession
+		if next(1) == "PASSWORD" || setsGlobal(tokens, i+1) {
+			st.Kind = stmtAdmin
+			st.Dangerous, st.Reason = true, "修改全局设置"
+		}
+	case "START":
+		st.Kind = stmtSession
+		if w := next(1); w != "TRANSACTION" {
+			st.Kind = stmtAdmin
+			st.Dangerous, st.Reason = true, "START "+w+" 为管理命令"
+		}
+	case "LOCK":
+		st.Kind = stmtAdmin
+		st.Dangerous, st.Reason = true, "LOCK 将锁表，阻塞其他会话"
+	case "USE", "COMMIT", "ROLLBACK", "SAVEPOINT", "RELEASE", "UNLOCK", "XA", "DEALLOCATE":
+		st.Kind = stmtSession
+	case "BEGIN", "DECLARE":
+		if dialect == "oracle" || verb == "DECLARE" {
+			st.Kind = stmtBlock
+			st.Dangerous, st.Reason = true, "匿名块无法静态分析"
+		} else {
+			st.Kind = stmtSession
+		}
+	default:
+		st.Kind = stmtOther
+		st.Dangerous, st.Reason = true, "无法识别的语句"
+	}
+	return st
+}
+
+// setsGlobal SET 的任一赋值（逗号分隔）为 GLOBAL / PERSIST / @@global. 等全局设置时返回 true
+func setsGlobal(tokens []sqlToken, i int) bool {
+	depth := 0
+	first := true
+	for ; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.kind == tokOpen:
+			depth++
+			continue
+		case t.kind == tokClose:
+			if depth > 0 {
+				depth--
+			}
+			continue
+		case depth > 0:
+			continue
+		case t.kind == tokComma:
+			first = true
+			continue
+		}
+		if first {
+			if tokenIs(t, "GLOBAL", "PERSIST", "PERSIST_ONLY") {
+				return true
+			}
+			if t.kind == tokVar {
+				v := strings.ToLower(t.text)
+				if strings.HasPrefix(v, "@@global.") || strings.HasPrefix(v, "@@persist.") || strings.HasPrefix(v, "@@persist_only.") {
+					return true
+				}
+			}
+		}
+		first = false
+	}
+	return false
+}
+
+// estimateImpact 估算危险语句的影响：UPDATE/DELETE 取执行计划行数，TRUNCATE/DROP/ALTER 取表行数，KILL 给出会话信息
+func (a *App) estimateImpact(st *StatementRisk) {
+	var err error
+	switch {
+	case st.Verb == "UPDATE" || st.Verb == "DELETE":
+		st.EstimatedRows, err = a.explainRows(st.SQL)
+	case st.Kind == stmtDDL && st.Table != "":
+		st.EstimatedRows, err = a.tableRowEstimate(st.schema, st.Table)
+	case st.Kind == stmtDDL && st.schema != "" && a.currentDBType != "oracle":
+		err = a.db.QueryRow("SELECT COALESCE(SUM(TABLE_ROWS), 0) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?", st.schema).Scan(&st.EstimatedRows)
+	case st.Kind == stmtKill && st.target != "":
+		st.Detail, err = a.describeSession(st.target)
+	}
+	if err != nil {
+		st.EstimatedRows = -1
+		st.Detail = fmt.Sprintf("估算失败: %v", err)
+	}
+}
+
+// explainRows 通过执行计划估算影响行数
+func (a *App) explainRows(query string) (int64, error) {
+	if a.currentDBType == "oracle" {
+		return a.oracleExplainRows(query)
+	}
+	rows, err := a.db.Query("EXPLAIN " + query)
+	if err != nil {
+		return -1, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return -1, err
+	}
+	var best int64 = -1
+	for rows.Next() {
+		values := make([]sql.NullString, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return -1, err
+		}
+		var n, filtered float64 = -1, 100
+		for i, c := range cols {
+			switch strings.ToLower(c) {
+			case "rows":
+				if v, err := strconv.ParseFloat(values[i].String, 64); err == nil {
+					n = v
+				}
+			case "filtered":
+				if v, err := strconv.ParseFloat(values[i].String, 64); err == nil {
+					filtered = v
+				}
+			}
+		}
+		if n >= 0 {
+			if est := int64(n * filtered / 100); est > best {
+				best = est
+			}
+		}
+	}
+	return best, rows.Err()
+}
+
+// oracleExplainRows PLAN_TABLE 为会话级临时表，需在同一连接上完成生成、读取与清理
+func (a *App) oracleExplainRows(query string) (int64, error) {
+	ctx := context.Background()
+	conn, err := a.db.Conn(ctx)
+	if err != nil {
+		return -1, err
+	}
+	defer conn.Close()
+	id := "DMS" + strings.ReplaceAll(newJobID(), "-", "")
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("EXPLAIN PLAN SET STATEMENT_ID = '%s' FOR %s", id, query)); err != nil {
+		return -1, err
+	}
+	defer conn.ExecContext(ctx, "DELETE FROM PLAN_TABLE WHERE STATEMENT_ID = :1", id)
+	var n sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT CARDINALITY FROM PLAN_TABLE WHERE STATEMENT_ID = :1 AND ID = 0", id).Scan(&n); err != nil {
+		return -1, err
+	}
+	if !n.Valid {
+		return -1, nil
+	}
+	return n.Int64, nil
+}
+
+// tableRowEstimate 读取统计信息中的表行数
+func (a *App) tableRowEstimate(schema, table string) (int64, error) {
+	var n sql.NullInt64
+	var err error
+	if a.currentDBType == "oracle" {
+		err = a.db.QueryRow("SELECT NUM_ROWS FROM ALL_TABLES WHERE OWNER = NVL(:1, SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')) AND TABLE_NAME = :2",
+			strings.ToUpper(schema), strings.ToUpper(table)).Scan(&n)
+	} else {
+		var s interface{}
+		if schema != "" {
+			s = schema
+		}
+		err = a.db.QueryRow("SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = COALESCE(?, DATABASE()) AND TABLE_NAME = ?", s, table).Scan(&n)
+	}
+	if err == sql.ErrNoRows {
+		return -1, fmt.Errorf("表 %s 不存在", table)
+	}
+	if err != nil || !n.Valid {
+		return -1, err
+	}
+	return n.Int64, nil
+}
+
+// describeSession 被终止会话的概要信息
+func (a *App) describeSession(id string) (string, error) {
+	if a.currentDBType == "oracle" {
+		return "", nil
+	}
+	var user, host, db, command, info sql.NullString
+	var secs sql.NullInt64
+	err := a.db.QueryRow("SELECT USER, HOST, DB, COMMAND, TIME, LEFT(INFO, 200) FROM information_schema.PROCESSLIST WHERE ID = ?", id).
+		Scan(&user, &host, &db, &command, &secs, &info)
+	if err == sql.ErrNoRows {
+		return fmt.Sprintf("会话 %s 不存在", id), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	detail := fmt.Sprintf("%s@%s db=%s %s %ds", user.String, host.String, db.String, command.String, secs.Int64)
+	if info.String != "" {
+		detail += ": " + info.String
+	}
+	return detail, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// riskSummary 测试比较用的分类结果
+type riskSummary struct {
+	SQL       string
+	Kind      string
+	Verb      string
+	Table     string
+	Dangerous bool
+}
+
+func TestClassifyStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		query   string
+		want    []riskSummary
+	}{
+		{"multi statement script", "mysql", "SELECT 1; UPDATE t SET a = 1;\nDELETE FROM db.t2 WHERE id = 1", []riskSummary{
+			{"SELECT 1", stmtRead, "SELECT", "", false},
+			{"UPDATE t SET a = 1", stmtWrite, "UPDATE", "t", true},
+			{"DELETE FROM db.t2 WHERE id = 1", stmtWrite, "DELETE", "t2", false},
+		}},
+		{"quoted delimiter", "mysql", "SELECT 'a; DROP TABLE t', `b;c` FROM t", []riskSummary{
+			{"SELECT 'a; DROP TABLE t', `b;c` FROM t", stmtRead, "SELECT", "", false},
+		}},
+		{"comments", "mysql", "-- DROP TABLE t;\nSELECT 1 /* ; DELETE FROM t */; # TRUNCATE t;\nSELECT 2", []riskSummary{
+			{"SELECT 1", stmtRead, "SELECT", "", false},
+			{"SELECT 2", stmtRead, "SELECT", "", false},
+		}},
+		{"conditional comment executes", "mysql", "/*!40000 DROP TABLE t */", []riskSummary{
+			{"DROP TABLE t", stmtDDL, "DROP", "t", true},
+		}},
+		{"optimizer hint", "mysql", "SELECT /*+ MAX_EXECUTION_TIME(1000) */ * FROM t; DELETE /*+ NO_INDEX(t) */ FROM t", []riskSummary{
+			{"SELECT /*+ MAX_EXECUTION_TIME(1000) */ * FROM t", stmtRead, "SELECT", "", false},
+			{"DELETE /*+ NO_INDEX(t) */ FROM t", stmtWrite, "DELETE", "t", true},
+		}},
+		{"select into outfile", "mysql", "SELECT * FROM t INTO OUTFILE '/tmp/t.csv'", []riskSummary{
+			{"SELECT * FROM t INTO OUTFILE '/tmp/t.csv'", stmtWrite, "SELECT", "", false},
+		}},
+		{"explain analyze runs the statement", "mysql", "EXPLAIN ANALYZE DELETE FROM t", []riskSummary{
+			{"EXPLAIN ANALYZE DELETE FROM t", stmtWrite, "EXPLAIN ANALYZE DELETE", "t", true},
+		}},
+		{"cte with dml", "mysql", "WITH x AS (SELECT id FROM s) UPDATE t SET a = 1", []riskSummary{
+			{"WITH x AS (SELECT id FROM s) UPDATE t SET a = 1", stmtWrite, "UPDATE", "t", true},
+		}},
+		{"session and global settings", "mysql", "SET NAMES utf8mb4; SET a = 1, GLOBAL max_connections = 10; SET @@global.read_only = 1; BEGIN; USE db", []riskSummary{
+			{"SET NAMES utf8mb4", stmtSession, "SET", "", false},
+			{"SET a = 1, GLOBAL max_connections = 10", stmtAdmin, "SET", "", true},
+			{"SET @@global.read_only = 1", stmtAdmin, "SET", "", true},
+			{"BEGIN", stmtSession, "BEGIN", "", false},
+			{"USE db", stmtSession, "USE", "", false},
+		}},
+		{"ddl", "mysql", "ALTER TABLE `d`.`t` ADD COLUMN c int; TRUNCATE TABLE t; CREATE TABLE x (id int)", []riskSummary{
+			{"ALTER TABLE `d`.`t` ADD COLUMN c int", stmtDDL, "ALTER", "t", true},
+			{"TRUNCATE TABLE t", stmtDDL, "TRUNCATE", "t", true},
+			{"CREATE TABLE x (id int)", stmtDDL, "CREATE", "", true},
+		}},
+		{"procedure body stays whole", "mysql", "CREATE PROCEDURE p() BEGIN DELETE FROM t; SELECT 1; END; SELECT 2", []riskSummary{
+			{"CREATE PROCEDURE p() BEGIN DELETE FROM t; SELECT 1; END", stmtDDL, "CREATE", "", true},
+			{"SELECT 2", stmtRead, "SELECT", "", false},
+		}},
+		{"kill", "mysql", "KILL QUERY 42", []riskSummary{
+			{"KILL QUERY 42", stmtKill, "KILL", "", true},
+		}},
+		{"unknown", "mysql", "FROBNICATE t", []riskSummary{
+			{"FROBNICATE t", stmtOther, "FROBNICATE", "", true},
+		}},
+		{"oracle q string", "oracle", "SELECT q'[it's; DROP TABLE t]' FROM dual; DELETE FROM t", []riskSummary{
+			{"SELECT q'[it's; DROP TABLE t]' FROM dual", stmtRead, "SELECT", "", false},
+			{"DELETE FROM t", stmtWrite, "DELETE", "t", true},
+		}},
+		{"oracle anonymous block", "oracle", "BEGIN\n  DELETE FROM t;\n  COMMIT;\nEND;\n/\nSELECT 1 FROM dual", []riskSummary{
+			{"BEGIN\n  DELETE FROM t;\n  COMMIT;\nEND;\n/", stmtBlock, "BEGIN", "", true},
+			{"SELECT 1 FROM dual", stmtRead, "SELECT", "", false},
+		}},
+		// 没有单独一行的 / 时无法确定块在哪里结束，其余内容都算作块的一部分
+		{"oracle block without slash", "oracle", "DECLARE n NUMBER; BEGIN n := 1; END; SELECT 1 FROM dual", []riskSummary{
+			{"DECLARE n NUMBER; BEGIN n := 1; END; SELECT 1 FROM dual", stmtBlock, "DECLARE", "", true},
+		}},
+		{"oracle session", "oracle", "ALTER SESSION SET CURRENT_SCHEMA = hr; ALTER SYSTEM KILL SESSION '12,34'", []riskSummary{
+			{"ALTER SESSION SET CURRENT_SCHEMA = hr", stmtSession, "ALTER", "", false},
+			{"ALTER SYSTEM KILL SESSION '12,34'", stmtKill, "ALTER", "", true},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []riskSummary
+			for _, st := range classifyStatements(tt.query, tt.dialect) {
+				got = append(got, riskSummary{st.SQL, st.Kind, st.Verb, st.Table, st.Dangerous})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyStatementTargets(t *testing.T) {
+	tests := []struct {
+		dialect, query string
+		schema, target string
+	}{
+		{"mysql", "DROP DATABASE IF EXISTS shop", "shop", ""},
+		{"mysql", "DELETE FROM `shop`.`orders` WHERE id = 1", "shop", ""},
+		{"mysql", "KILL CONNECTION 42", "", "42"},
+		{"oracle", "ALTER SYSTEM KILL SESSION '12,34'", "", "12,34"},
+	}
+	for _, tt := range tests {
+		st := classifyStatements(tt.query, tt.dialect)
+		if len(st) != 1 || st[0].schema != tt.schema || st[0].target != tt.target {
+			t.Errorf("%s: got %+v, want schema %q target %q", tt.query, st, tt.schema, tt.target)
+		}
+	}
+}
+
+// 注释与空白不同的同一语句摘要相同，确认令牌仍然有效；语句内容不同则摘要不同
+func TestStatementDigest(t *testing.T) {
+	digest := func(q string) string { return classifyStatements(q, "mysql")[0].digest }
+	base := digest("DELETE FROM t WHERE id = 1")
+	if d := digest("DELETE  FROM t /* x */\nWHERE id = 1 -- y"); d != base {
+		t.Error("whitespace and comments changed the digest")
+	}
+	if d := digest("DELETE FROM t WHERE id = 2"); d == base {
+		t.Error("different statements share a digest")
+	}
+}
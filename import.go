@@ -0,0 +1,367 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ImportOptions SQL文件导入选项
+type ImportOptions struct {
+	ContinueOnError         bool   `json:"continueOnError"`
+	ErrorLogPath            string `json:"errorLogPath"`
+	DisableForeignKeyChecks bool   `json:"disableForeignKeyChecks"`
+	DisableUniqueChecks     bool   `json:"disableUniqueChecks"`
+	ConfirmToken            string `json:"confirmToken"` // 目标为生产环境时须带 ConfirmTaskTarget 签发的令牌
+}
+
+// ImportTableStat 导入过程中按表统计的语句数
+type ImportTableStat struct {
+	Name       string `json:"name"`
+	Statements int64  `json:"statements"`
+	Errors     int64  `json:"errors"`
+}
+
+// ImportResult SQL文件导入结果
+type ImportResult struct {
+	Path         string            `json:"path"`
+	Statements   int64             `json:"statements"`
+	Errors       int64             `json:"errors"`
+	BytesRead    int64             `json:"bytesRead"`
+	ElapsedSec   float64           `json:"elapsedSec"`
+	ErrorLogPath string            `json:"errorLogPath,omitempty"`
+	Tables       []ImportTableStat `json:"tables"`
+}
+
+// ImportProgress 导入进度事件（import-progress）
+type ImportProgress struct {
+	JobID      string  `json:"jobId"`
+	Status     string  `json:"status"` // running / success / failed / cancelled
+	Table      string  `json:"table"`
+	Statements int64   `json:"statements"`
+	Errors     int64   `json:"errors"`
+	BytesRead  int64   `json:"bytesRead"`
+	TotalBytes int64   `json:"totalBytes"`
+	ElapsedSec float64 `json:"elapsedSec"`
+	EtaSec     float64 `json:"etaSec"`
+	Message    string  `json:"message,omitempty"`
+}
+
+// SelectSqlFile 弹出打开文件对话框选择SQL文件
+func (a *App) SelectSqlFile() (string, error) {
+	if a.ctx == nil {
+		return "", fmt.Errorf("应用未初始化")
+	}
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "选择SQL文件",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "SQL", Pattern: "*.sql;*.sql.gz;*.sql.zst"},
+			{DisplayName: "All Files", Pattern: "*"},
+		},
+	})
+}
+
+// openSQLSource 打开SQL文件，按后缀自动解压 .gz/.zst；
+// 返回的 countingReader 统计的是原始文件已读字节数，用于计算进度
+func openSQLSource(path string) (io.Reader, *countingReader, int64, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, 0, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, 0, nil, err
+	}
+	counter := &countingReader{r: f}
+	closers := []func(){func() { f.Close() }}
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+	lower := strings.ToLower(path)
+	var r io.Reader = counter
+	switch {
+	case strings.HasSuffix(lower, ".gz"):
+		gz, err := gzip.NewReader(counter)
+		if err != nil {
+			closeAll()
+			return nil, nil, 0, nil, fmt.Errorf("解压失败: %v", err)
+		}
+		closers = append(closers, func() { gz.Close() })
+		r = gz
+	case strings.HasSuffix(lower, ".zst"):
+		dec, err := zstd.NewReader(counter)
+		if err != nil {
+			closeAll()
+			return nil, nil, 0, nil, fmt.Errorf("解压失败: %v", err)
+		}
+		closers = append(closers, dec.Close)
+		r = dec
+	}
+	return r, counter, info.Size(), closeAll, nil
+}
+
+// countingReader 统计读取字节数
+type countingReader struct {
+	mu sync.Mutex
+	r  io.Reader
+	n  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.mu.Lock()
+	c.n += int64(n)
+	c.mu.Unlock()
+	return n, err
+}
+
+func (c *countingReader) Count() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+// ImportSqlFile 将SQL文件导入到指定库，作为后台任务执行并等待其结束；
+// 执行过程中通过 import-progress 事件推送进度
+func (a *App) ImportSqlFile(cfg DBConfig, db string, path string, opts ImportOptions) (ImportResult, error) {
+	job, err := a.SubmitImportJob(cfg, db, path, opts)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	res, err := a.jobs.wait(job.ID)
+	result, _ := res.(ImportResult)
+	return result, err
+}
+
+// SubmitImportJob 提交SQL文件导入任务，立即返回任务信息
+func (a *App) SubmitImportJob(cfg DBConfig, db string, path string, opts ImportOptions) (Job, error) {
+	if normalizeDBType(cfg.Type) != "mysql" {
+		return Job{}, fmt.Errorf("当前连接类型暂不支持SQL导入")
+	}
+	if db == "" {
+		return Job{}, fmt.Errorf("数据库名不能为空")
+	}
+	if err := checkWritable(cfg); err != nil {
+		return Job{}, err
+	}
+	if err := a.checkDestructiveTask(cfg, "import", db, "SQL 文件中的语句不经逐条检查直接执行", opts.ConfirmToken); err != nil {
+		return Job{}, err
+	}
+	if path == "" {
+		return Job{}, fmt.Errorf("请选择SQL文件")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return Job{}, fmt.Errorf("无法读取文件: %v", err)
+	}
+	cfg.Database = db
+	title := fmt.Sprintf("导入 %s → %s", filepath.Base(path), db)
+	job := a.jobs.submit("import", title, func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		return a.runImportSqlFile(ctx, r, cfg, path, opts, nil)
+	})
+	return job, nil
+}
+
+// runImportSqlFile 导入任务执行体：流式读取并逐条执行语句；
+// filter 不为空时可改写或跳过语句（返回 false 表示跳过）
+func (a *App) runImportSqlFile(ctx context.Context, r *jobReporter, cfg DBConfig, path string, opts ImportOptions, filter func(stmt string) (string, bool)) (ImportResult, error) {
+	result := ImportResult{Path: path, Tables: []ImportTableStat{}}
+	start := time.Now()
+	log := func(format string, args ...interface{}) {
+		if a.ctx == nil {
+			return
+		}
+		ts := time.Now().Format("15:04:05")
+		runtime.EventsEmit(a.ctx, "import-log", fmt.Sprintf("[%s] %s", ts, fmt.Sprintf(format, args...)))
+	}
+
+	src, counter, total, closeSrc, err := openSQLSource(path)
+	if err != nil {
+		return result, err
+	}
+	defer closeSrc()
+
+	dsn, err := buildDSN(cfg)
+	if err != nil {
+		return result, err
+	}
+	sqlDB, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return result, err
+	}
+	defer sqlDB.Close()
+	// 会话级变量只对同一连接生效，因此整个导入过程固定使用一个连接
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return result, fmt.Errorf("无法连接到数据库: %v", err)
+	}
+	defer conn.Close()
+	if opts.DisableForeignKeyChecks {
+		if _, err := conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 0"); err != nil {
+			return result, err
+		}
+		log("已关闭 FOREIGN_KEY_CHECKS")
+	}
+	if opts.DisableUniqueChecks {
+		if _, err := conn.ExecContext(ctx, "SET UNIQUE_CHECKS = 0"); err != nil {
+			return result, err
+		}
+		log("已关闭 UNIQUE_CHECKS")
+	}
+
+	var errLog *os.File
+	if opts.ErrorLogPath != "" {
+		errLog, err = os.OpenFile(opts.ErrorLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return result, fmt.Errorf("无法创建错误日志: %v", err)
+		}
+		defer errLog.Close()
+		result.ErrorLogPath = opts.ErrorLogPath
+		fmt.Fprintf(errLog, "==== %s 导入 %s 到 %s ====\n", time.Now().Format("2006-01-02 15:04:05"), path, cfg.Database)
+	}
+
+	tableIdx := map[string]int{}
+	currentTable := ""
+	var lastEmit time.Time
+	progress := func(status string, msg string) ImportProgress {
+		elapsed := time.Since(start).Seconds()
+		read := counter.Count()
+		p := ImportProgress{
+			JobID:      r.ID(),
+			Status:     status,
+			Table:      currentTable,
+			Statements: result.Statements,
+			Errors:     result.Errors,
+			BytesRead:  read,
+			TotalBytes: total,
+			ElapsedSec: elapsed,
+			Message:    msg,
+		}
+		if read > 0 && total > read && elapsed > 0 {
+			p.EtaSec = float64(total-read) / (float64(read) / elapsed)
+		}
+		return p
+	}
+	emit := func(status string, msg string, force bool) {
+		if !force && time.Since(lastEmit) < 500*time.Millisecond {
+			return
+		}
+		lastEmit = time.Now()
+		p := progress(status, msg)
+		if a.ctx != nil {
+			runtime.EventsEmit(a.ctx, "import-progress", p)
+		}
+		pct := float64(-1)
+		if total > 0 {
+			pct = float64(p.BytesRead) * 100 / float64(total)
+		}
+		jobMsg := ""
+		if currentTable != "" {
+			jobMsg = "当前表：" + currentTable
+		}
+		r.Progress(pct, jobMsg)
+	}
+	finish := func(err error) (ImportResult, error) {
+		result.BytesRead = counter.Count()
+		result.ElapsedSec = time.Since(start).Seconds()
+		switch {
+		case err == nil:
+			emit("success", "", true)
+			log("导入完成：%d 条语句，%d 个错误，用时 %s", result.Statements, result.Errors, time.Since(start).Truncate(time.Millisecond))
+		case ctx.Err() != nil:
+			emit("cancelled", "导入已取消", true)
+			log("导入已取消")
+		default:
+			emit("failed", err.Error(), true)
+			log("导入失败：%v", err)
+		}
+		return result, err
+	}
+
+	log("开始导入 %s 到 %s", filepath.Base(path), cfg.Database)
+	reader := newSQLStatementReader(src)
+	for {
+		if err := ctx.Err(); err != nil {
+			return finish(err)
+		}
+		stmt, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return finish(fmt.Errorf("读取文件失败: %v", err))
+		}
+
+		if filter != nil {
+			text, keep := filter(stmt.SQL)
+			if !keep {
+				emit("running", "", false)
+				continue
+			}
+			stmt.SQL = text
+		}
+
+		if name := extractTableName(stmt.SQL); name != "" {
+			if name != currentTable {
+				currentTable = name
+				log("导入表：%s", name)
+			}
+			if _, ok := tableIdx[name]; !ok {
+				tableIdx[name] = len(result.Tables)
+				result.Tables = append(result.Tables, ImportTableStat{Name: name})
+			}
+		}
+
+		result.Statements++
+		var stat *ImportTableStat
+		if idx, ok := tableIdx[currentTable]; ok && currentTable != "" {
+			stat = &result.Tables[idx]
+			stat.Statements++
+		}
+		if _, err := conn.ExecContext(ctx, stmt.SQL); err != nil {
+			if ctx.Err() != nil {
+				return finish(ctx.Err())
+			}
+			result.Errors++
+			if stat != nil {
+				stat.Errors++
+			}
+			if errLog != nil {
+				fmt.Fprintf(errLog, "-- 第 %d 行：%v\n%s;\n\n", stmt.Line, err, truncateForLog(stmt.SQL, 2000))
+			}
+			log("第 %d 行执行失败：%v", stmt.Line, err)
+			if !opts.ContinueOnError {
+				return finish(fmt.Errorf("第 %d 行执行失败: %v", stmt.Line, err))
+			}
+		}
+		emit("running", "", false)
+	}
+
+	if opts.DisableUniqueChecks {
+		_, _ = conn.ExecContext(ctx, "SET UNIQUE_CHECKS = 1")
+	}
+	if opts.DisableForeignKeyChecks {
+		_, _ = conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 1")
+	}
+	return finish(nil)
+}
+
+// truncateForLog 截断过长的语句，避免错误日志过大
+func truncateForLog(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + fmt.Sprintf("...（共 %d 字节）", len(s))
+}
@@ -0,0 +1,480 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Job 后台任务（导出、同步、导入、校验等）
+type Job struct {
+	ID         string             `json:"id"`
+	Kind       string             `json:"kind"`
+	Title      string             `json:"title"`
+	Status     string             `json:"status"` // queued / running / success / failed / cancelled / interrupted
+	Progress   float64            `json:"progress"`
+	Message    string             `json:"message"`
+	Error      string             `json:"error,omitempty"`
+	Result     interface{}        `json:"result,omitempty"` // 任务结果，不超过 maxPersistedResultBytes 时随历史持久化
+	Counts     map[string]float64 `json:"counts,omitempty"` // 结果的计数摘要，结果过大未持久化时仍可查看
+	CreatedAt  string             `json:"createdAt"`
+	StartedAt  string             `json:"startedAt,omitempty"`
+	FinishedAt string             `json:"finishedAt,omitempty"`
+}
+
+// jobRunFunc 任务执行体；ctx 取消即表示任务被取消
+type jobRunFunc func(ctx context.Context, r *jobReporter) (interface{}, error)
+
+type jobEntry struct {
+	job    Job
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+	stored json.RawMessage // 持久化用的结果序列化，任务结束时生成一次
+}
+
+// storedResult 序列化任务结果用于持久化，超过上限或无法序列化时返回 nil
+func storedResult(result interface{}) json.RawMessage {
+	if result == nil {
+		return nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil || len(data) > maxPersistedResultBytes {
+		return nil
+	}
+	return data
+}
+
+// jobManager 后台任务管理：有界并发执行、状态推送与历史持久化
+type jobManager struct {
+	mu      sync.Mutex
+	jobs    map[string]*jobEntry
+	order   []string
+	sem     chan struct{}
+	emit    func(Job)
+	history int
+}
+
+// jobReporter 供任务执行体上报进度
+type jobReporter struct {
+	m        *jobManager
+	id       string
+	mu       sync.Mutex
+	lastEmit time.Time
+}
+
+const (
+	defaultMaxConcurrentJobs = 2
+	maxJobHistory            = 200
+	maxPersistedResultBytes  = 256 * 1024 // 单个任务持久化结果的上限，避免历史文件过大
+)
+
+var errJobCancelled = errors.New("任务已取消")
+
+func newJobManager(concurrency int, emit func(Job)) *jobManager {
+	if concurrency <= 0 {
+		concurrency = defaultMaxConcurrentJobs
+	}
+	return &jobManager{
+		jobs:    map[string]*jobEntry{},
+		sem:     make(chan struct{}, concurrency),
+		emit:    emit,
+		history: maxJobHistory,
+	}
+}
+
+func jobsFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "dms-new", "jobs.json")
+	return path, nil
+}
+
+// load 读取历史任务；上次未结束的任务标记为 interrupted
+func (m *jobManager) load() error {
+	path, err := jobsFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var list []Job
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, j := range list {
+		if _, ok := m.jobs[j.ID]; ok {
+			continue
+		}
+		if j.Status == "queued" || j.Status == "running" {
+			j.Status = "interrupted"
+			j.Message = "应用退出，任务中断"
+		}
+		done := make(chan struct{})
+		close(done)
+		m.jobs[j.ID] = &jobEntry{job: j, done: done, stored: storedResult(j.Result)}
+		m.order = append(m.order, j.ID)
+	}
+	return nil
+}
+
+// persistLocked 保存任务历史；结果序列化后超过上限的只保留计数摘要，调用方需持有锁
+func (m *jobManager) persistLocked() error {
+	path, err := jobsFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	list := make([]Job, 0, len(m.order))
+	for _, id := range m.order {
+		e := m.jobs[id]
+		j := e.job
+		j.Result = nil
+		if e.stored != nil {
+			j.Result = e.stored
+		}
+		list = append(list, j)
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// trimLocked 超出历史上限时丢弃最早的已结束任务
+func (m *jobManager) trimLocked() {
+	for len(m.order) > m.history {
+		removed := false
+		for i, id := range m.order {
+			e := m.jobs[id]
+			if e.job.Status != "queued" && e.job.Status != "running" {
+				delete(m.jobs, id)
+				m.order = append(m.order[:i], m.order[i+1:]...)
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			return
+		}
+	}
+}
+
+// update 修改任务状态并推送 job-status 事件；persist 为 true 时同时写入历史文件
+func (m *jobManager) update(id string, persist bool, fn func(j *Job)) {
+	m.mu.Lock()
+	e, ok := m.jobs[id]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	fn(&e.job)
+	snapshot := e.job
+	if persist {
+		_ = m.persistLocked()
+	}
+	m.mu.Unlock()
+	if m.emit != nil {
+		m.emit(snapshot)
+	}
+}
+
+// submit 提交任务，立即返回任务信息，任务在后台 goroutine 中排队执行
+func (m *jobManager) submit(kind string, title string, run jobRunFunc) Job {
+	return m.submitWithID(newJobID(), kind, title, run)
+}
+
+func (m *jobManager) submitWithID(id string, kind string, title string, run jobRunFunc) Job {
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &jobEntry{
+		job: Job{
+			ID:        id,
+			Kind:      kind,
+			Title:     title,
+			Status:    "queued",
+			CreatedAt: time.Now().Format(time.RFC3339),
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	m.mu.Lock()
+	if old, ok := m.jobs[id]; ok {
+		// 同一ID重新提交（如断点续传）时替换旧记录
+		for i, oid := range m.order {
+			if oid == id {
+				m.order = append(m.order[:i], m.order[i+1:]...)
+				break
+			}
+		}
+		e.job.CreatedAt = old.job.CreatedAt
+	}
+	m.jobs[id] = e
+	m.order = append(m.order, id)
+	m.trimLocked()
+	_ = m.persistLocked()
+	snapshot := e.job
+	m.mu.Unlock()
+	if m.emit != nil {
+		m.emit(snapshot)
+	}
+
+	go m.execute(ctx, e, run)
+	return snapshot
+}
+
+// setConcurrency 调整最大并发数，只影响之后开始排队的任务
+func (m *jobManager) setConcurrency(n int) {
+	if n <= 0 {
+		n = defaultMaxConcurrentJobs
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cap(m.sem) != n {
+		m.sem = make(chan struct{}, n)
+	}
+}
+
+func (m *jobManager) execute(ctx context.Context, e *jobEntry, run jobRunFunc) {
+	defer close(e.done)
+	defer e.cancel()
+	id := e.job.ID
+	m.mu.Lock()
+	sem := m.sem
+	m.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		e.err = errJobCancelled
+		m.update(id, true, func(j *Job) {
+			j.Status = "cancelled"
+			j.Message = "任务已取消"
+			j.FinishedAt = time.Now().Format(time.RFC3339)
+		})
+		return
+	}
+	defer func() { <-sem }()
+
+	m.update(id, true, func(j *Job) {
+		j.Status = "running"
+		j.StartedAt = time.Now().Format(time.RFC3339)
+	})
+
+	result, err := func() (res interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("任务异常: %v", p)
+			}
+		}()
+		return run(ctx, &jobReporter{m: m, id: id})
+	}()
+
+	e.err = err
+	stored := storedResult(result)
+	m.update(id, true, func(j *Job) {
+		j.FinishedAt = time.Now().Format(time.RFC3339)
+		j.Result = result
+		e.stored = stored
+		j.Counts = resultCounts(result)
+		switch {
+		case err == nil:
+			j.Status = "success"
+			j.Progress = 100
+		case ctx.Err() != nil:
+			j.Status = "cancelled"
+			j.Message = "任务已取消"
+			e.err = errJobCancelled
+		default:
+			j.Status = "failed"
+			j.Error = err.Error()
+		}
+	})
+}
+
+// resultCounts 提取任务结果中的计数：列表取条目数，结构体取数值字段与列表长度，不含具体数据
+func resultCounts(result interface{}) map[string]float64 {
+	v := reflect.ValueOf(result)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	counts := map[string]float64{}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		counts["items"] = float64(v.Len())
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Anonymous {
+				continue
+			}
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			fv := v.Field(i)
+			switch fv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				counts[name] = float64(fv.Int())
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				counts[name] = float64(fv.Uint())
+			case reflect.Float32, reflect.Float64:
+				counts[name] = fv.Float()
+			case reflect.Slice, reflect.Array, reflect.Map:
+				counts[name] = float64(fv.Len())
+			}
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	return counts
+}
+
+// wait 等待任务结束并返回结果
+func (m *jobManager) wait(id string) (interface{}, error) {
+	m.mu.Lock()
+	e, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("未找到任务: %s", id)
+	}
+	<-e.done
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return e.job.Result, e.err
+}
+
+// cancel 取消排队中或运行中的任务；任务不存在或已结束时返回错误
+func (m *jobManager) cancel(id string) error {
+	m.mu.Lock()
+	e, ok := m.jobs[id]
+	active := ok && e.cancel != nil && (e.job.Status == "queued" || e.job.Status == "running")
+	m.mu.Unlock()
+	if !active {
+		return fmt.Errorf("任务不存在或已结束: %s", id)
+	}
+	e.cancel()
+	return nil
+}
+
+func (m *jobManager) get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return e.job, true
+}
+
+func (m *jobManager) list() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]Job, 0, len(m.order))
+	for i := len(m.order) - 1; i >= 0; i-- {
+		list = append(list, m.jobs[m.order[i]].job)
+	}
+	return list
+}
+
+// clearFinished 清除已结束的任务历史
+func (m *jobManager) clearFinished() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.order[:0]
+	for _, id := range m.order {
+		e := m.jobs[id]
+		if e.job.Status == "queued" || e.job.Status == "running" {
+			kept = append(kept, id)
+			continue
+		}
+		delete(m.jobs, id)
+	}
+	m.order = kept
+	return m.persistLocked()
+}
+
+// ID 当前任务ID
+func (r *jobReporter) ID() string {
+	return r.id
+}
+
+// Progress 上报进度（0-100）与说明，推送做节流处理
+func (r *jobReporter) Progress(pct float64, msg string) {
+	r.mu.Lock()
+	if time.Since(r.lastEmit) < 300*time.Millisecond {
+		r.mu.Unlock()
+		return
+	}
+	r.lastEmit = time.Now()
+	r.mu.Unlock()
+	if pct > 100 {
+		pct = 100
+	}
+	r.m.update(r.id, false, func(j *Job) {
+		if pct >= 0 {
+			j.Progress = pct
+		}
+		if msg != "" {
+			j.Message = msg
+		}
+	})
+}
+
+// ListJobs 获取任务列表（含历史，最新在前）
+func (a *App) ListJobs() []Job {
+	return a.jobs.list()
+}
+
+// GetJob 获取任务状态
+func (a *App) GetJob(id string) (Job, error) {
+	j, ok := a.jobs.get(id)
+	if !ok {
+		return Job{}, fmt.Errorf("未找到任务: %s", id)
+	}
+	return j, nil
+}
+
+// CancelJob 取消排队中或运行中的任务
+func (a *App) CancelJob(id string) error {
+	return a.jobs.cancel(id)
+}
+
+// ClearJobHistory 清除已结束的任务历史
+func (a *App) ClearJobHistory() error {
+	return a.jobs.clearFinished()
+}
+
+func (a *App) emitJobStatus(j Job) {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "job-status", j)
+	}
+}
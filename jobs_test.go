@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestJobCancel(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := newJobManager(1, nil)
+	started := make(chan struct{})
+	running := m.submit("test", "running", func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	finished := m.submit("test", "finished", func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		return "ok", nil
+	})
+	<-started
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{"unknown", "no-such-job", true},
+		{"running", running.ID, false},
+		{"finished", finished.ID, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.id == finished.ID {
+				m.wait(running.ID)
+				m.wait(finished.ID)
+			}
+			err := m.cancel(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cancel = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if j, _ := m.get(running.ID); j.Status != "cancelled" {
+		t.Fatalf("running job status = %s", j.Status)
+	}
+}
+
+func TestJobResultPersisted(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := newJobManager(1, nil)
+	small := m.submit("test", "small", func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		return []MigrationCheckRow{{Name: "t", Status: "success"}}, nil
+	})
+	large := m.submit("test", "large", func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		return []string{strings.Repeat("x", maxPersistedResultBytes)}, nil
+	})
+	m.wait(small.ID)
+	m.wait(large.ID)
+
+	reloaded := newJobManager(1, nil)
+	if err := reloaded.load(); err != nil {
+		t.Fatal(err)
+	}
+	j, ok := reloaded.get(small.ID)
+	if !ok {
+		t.Fatal("small job missing after reload")
+	}
+	rows, _ := j.Result.([]interface{})
+	if len(rows) != 1 || rows[0].(map[string]interface{})["name"] != "t" {
+		t.Fatalf("small job result = %#v", j.Result)
+	}
+	j, _ = reloaded.get(large.ID)
+	if j.Result != nil || j.Counts["items"] != 1 {
+		t.Fatalf("large job result = %v, counts = %v", j.Result != nil, j.Counts)
+	}
+}
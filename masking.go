@@ -0,0 +1,489 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// MaskingRule 脱敏规则：连接、库、表、列均为通配模式（* ?，逗号分隔多个，不区分大小写），
+// 连接可填名称或ID，库、表为空表示全部，列必填
+type MaskingRule struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Enabled    bool   `json:"enabled"`
+	Connection string `json:"connection"`
+	Schema     string `json:"schema"`
+	Table      string `json:"table"`
+	Column     string `json:"column"`
+	Strategy   string `json:"strategy"`   // partial / hash / null / fixed
+	KeepPrefix int    `json:"keepPrefix"` // partial：保留前几个字符
+	KeepSuffix int    `json:"keepSuffix"` // partial：保留后几个字符
+	MaskChar   string `json:"maskChar"`   // partial：替换字符，默认 *
+	FixedValue string `json:"fixedValue"` // fixed：替换后的值
+}
+
+var (
+	maskingMu    sync.RWMutex
+	maskingRules []MaskingRule
+)
+
+func maskingFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dms-new", "masking.json"), nil
+}
+
+func loadMaskingRules() error {
+	path, err := maskingFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var list []MaskingRule
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	maskingMu.Lock()
+	maskingRules = list
+	maskingMu.Unlock()
+	return nil
+}
+
+// GetMaskingRules 获取脱敏规则
+func (a *App) GetMaskingRules() []MaskingRule {
+	maskingMu.RLock()
+	defer maskingMu.RUnlock()
+	return append([]MaskingRule{}, maskingRules...)
+}
+
+// SaveMaskingRules 校验并保存全部脱敏规则
+func (a *App) SaveMaskingRules(rules []MaskingRule) error {
+	for i := range rules {
+		r := &rules[i]
+		if strings.TrimSpace(r.Column) == "" {
+			return fmt.Errorf("规则 %s 的列不能为空", r.Name)
+		}
+		switch r.Strategy {
+		case "partial", "hash", "null", "fixed":
+		case "":
+			r.Strategy = "partial"
+		default:
+			return fmt.Errorf("规则 %s 不支持的脱敏方式: %s", r.Name, r.Strategy)
+		}
+		if r.KeepPrefix < 0 || r.KeepSuffix < 0 {
+			return fmt.Errorf("规则 %s 的保留字符数不能为负数", r.Name)
+		}
+		for _, p := range []string{r.Connection, r.Schema, r.Table, r.Column} {
+			for _, part := range splitPatterns(p) {
+				if _, err := path.Match(part, ""); err != nil {
+					return fmt.Errorf("规则 %s 的匹配模式无效 %s: %v", r.Name, part, err)
+				}
+			}
+		}
+		if r.ID == "" {
+			r.ID = newJobID()
+		}
+	}
+	file, err := maskingFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return err
+	}
+	maskingMu.Lock()
+	maskingRules = rules
+	maskingMu.Unlock()
+	return nil
+}
+
+// PreviewMask 预览规则对样例值的脱敏效果
+func (a *App) PreviewMask(rule MaskingRule, sample string) interface{} {
+	return maskValue(&rule, sample)
+}
+
+func splitPatterns(p string) []string {
+	var out []string
+	for _, s := range strings.Split(p, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, strings.ToLower(s))
+		}
+	}
+	return out
+}
+
+// matchPattern 通配匹配；模式为空时 allowEmpty 决定是否视为全部匹配
+func matchPattern(pattern, name string, allowEmpty bool) bool {
+	parts := splitPatterns(pattern)
+	if len(parts) == 0 {
+		return allowEmpty
+	}
+	name = strings.ToLower(name)
+	for _, p := range parts {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesForConn 返回对连接生效的规则
+func rulesForConn(cfg DBConfig) []*MaskingRule {
+	maskingMu.RLock()
+	defer maskingMu.RUnlock()
+	var out []*MaskingRule
+	for i := range maskingRules {
+		r := maskingRules[i]
+		if !r.Enabled {
+			continue
+		}
+		if len(splitPatterns(r.Connection)) > 0 && !matchPattern(r.Connection, cfg.Name, false) && !matchPattern(r.Connection, cfg.ID, false) {
+			continue
+		}
+		out = append(out, &r)
+	}
+	return out
+}
+
+// maskRule 在规则中查找匹配库、表、列的第一条
+func maskRule(rules []*MaskingRule, schema, table, column string) *MaskingRule {
+	for _, r := range rules {
+		if matchPattern(r.Schema, schema, true) && matchPattern(r.Table, table, true) && matchPattern(r.Column, column, false) {
+			return r
+		}
+	}
+	return nil
+}
+
+// maskValue 按策略脱敏；NULL 保持不变
+func maskValue(r *MaskingRule, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	var s string
+	switch x := v.(type) {
+	case string:
+		s = x
+	case []byte:
+		s = string(x)
+	default:
+		s = fmt.Sprint(x)
+	}
+	switch r.Strategy {
+	case "null":
+		return nil
+	case "fixed":
+		return r.FixedValue
+	case "hash":
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:8])
+	}
+	maskChar := r.MaskChar
+	if maskChar == "" {
+		maskChar = "*"
+	}
+	n := utf8.RuneCountInString(s)
+	prefix, suffix := r.KeepPrefix, r.KeepSuffix
+	if prefix+suffix >= n {
+		// 值过短时至少遮住一半，避免原样露出
+		prefix, suffix = min(prefix, n/2), 0
+	}
+	runes := []rune(s)
+	return string(runes[:prefix]) + strings.Repeat(maskChar, n-prefix-suffix) + string(runes[n-suffix:])
+}
+
+// maskTableRules 同步时对某个源表生效的规则
+type maskTableRules struct {
+	rules         []*MaskingRule
+	schema, table string
+}
+
+func newMaskTableRules(cfg DBConfig, schema, table string) *maskTableRules {
+	rules := rulesForConn(cfg)
+	var own []*MaskingRule
+	for _, r := range rules {
+		if matchPattern(r.Schema, schema, true) && matchPattern(r.Table, table, true) {
+			own = append(own, r)
+		}
+	}
+	if len(own) == 0 {
+		return nil
+	}
+	return &maskTableRules{rules: own, schema: schema, table: table}
+}
+
+func (m *maskTableRules) apply(column string, v interface{}) interface{} {
+	if m == nil {
+		return v
+	}
+	if r := maskRule(m.rules, m.schema, m.table, column); r != nil {
+		return maskValue(r, v)
+	}
+	return v
+}
+
+// queryMasker 根据查询语句确定结果集各列适用的规则：
+// 解析语句中引用的表，逐个 SELECT 列表分析每一项引用的列（含子查询中的别名传递），
+// 外层无 * 时按位置对应结果列，否则按结果列名匹配
+type queryMasker struct {
+	rules   []*MaskingRule
+	schema  string
+	refs    []sqlTableRef
+	aliases map[string]*MaskingRule // 子查询等处被起了别名的脱敏列
+}
+
+// columnRules 返回每个结果列的规则，无规则的列为 nil；不需要脱敏时返回 nil
+func columnRules(cfg DBConfig, schema, dialect, query string, columns []string) []*MaskingRule {
+	rules := rulesForConn(cfg)
+	if len(rules) == 0 {
+		return nil
+	}
+	tokens, err := tokenizeSQL(query, dialect)
+	if err != nil {
+		return nil
+	}
+	sig := significantTokens(tokens)
+	m := &queryMasker{rules: rules, schema: schema, refs: parseTableRefs(sig), aliases: map[string]*MaskingRule{}}
+	lists := selectLists(sig)
+	// 由内到外传递别名，最多嵌套若干层
+	for pass := 0; pass < 4; pass++ {
+		changed := false
+		for _, l := range lists {
+			for _, item := range l.items {
+				name, r := m.itemRule(item)
+				if r != nil && name != "" && m.aliases[strings.ToLower(name)] == nil {
+					m.aliases[strings.ToLower(name)] = r
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	out := make([]*MaskingRule, len(columns))
+	found := false
+	// 最外层的第一个 SELECT（跳过 WITH 中的子查询）决定结果列
+	var top [][]sqlToken
+	minDepth := -1
+	for _, l := range lists {
+		if minDepth < 0 || l.depth < minDepth {
+			minDepth, top = l.depth, l.items
+		}
+	}
+	positional := len(top) == len(columns)
+	for _, item := range top {
+		if n := len(item); n > 0 && item[n-1].kind == tokOp && item[n-1].text == "*" {
+			positional = false
+		}
+	}
+	for i, col := range columns {
+		if positional {
+			_, out[i] = m.itemRule(top[i])
+		} else {
+			out[i] = m.columnRule("", col)
+		}
+		found = found || out[i] != nil
+	}
+	if !found {
+		return nil
+	}
+	return out
+}
+
+// columnRule 按列引用（可带表别名限定）查找规则；限定符指向实际表时不再按别名匹配
+func (m *queryMasker) columnRule(qualifier, column string) *MaskingRule {
+	for _, ref := range m.refs {
+		if qualifier != "" && !identEqual(qualifier, ref.alias) && !(ref.alias == "" && identEqual(qualifier, ref.table)) {
+			continue
+		}
+		schema := ref.schema
+		if schema == "" {
+			schema = m.schema
+		}
+		if r := maskRule(m.rules, schema, ref.table, column); r != nil {
+			return r
+		}
+		if qualifier != "" {
+			return nil
+		}
+	}
+	return m.aliases[strings.ToLower(column)]
+}
+
+// itemRule 返回 SELECT 列表项的输出名，以及其表达式中引用到的第一个脱敏列的规则
+func (m *queryMasker) itemRule(item []sqlToken) (string, *MaskingRule) {
+	n := len(item)
+	if n == 0 {
+		return "", nil
+	}
+	isName := func(t sqlToken) bool { return t.kind == tokWord || t.kind == tokQuoted }
+	name := ""
+	expr := item
+	switch {
+	case n >= 3 && isName(item[n-1]) && tokenIs(item[n-2], "AS"):
+		name, expr = unquoteIdent(item[n-1].text), item[:n-2]
+	case n >= 2 && isName(item[n-1]) && item[n-2].kind != tokDot && item[n-2].kind != tokOp && !tokenIs(item[n-1], "END"):
+		name, expr = unquoteIdent(item[n-1].text), item[:n-1]
+	case isName(item[n-1]):
+		name = unquoteIdent(item[n-1].text)
+	}
+	for i := 0; i < len(expr); i++ {
+		t := expr[i]
+		if !isName(t) {
+			continue
+		}
+		if i+1 < len(expr) && expr[i+1].kind == tokOpen {
+			continue // 函数名
+		}
+		if i+2 < len(expr) && expr[i+1].kind == tokDot {
+			continue // 限定符，由后面的列名处理
+		}
+		qualifier := ""
+		if i >= 2 && expr[i-1].kind == tokDot && isName(expr[i-2]) {
+			qualifier = unquoteIdent(expr[i-2].text)
+		}
+		if r := m.columnRule(qualifier, unquoteIdent(t.text)); r != nil {
+			return name, r
+		}
+	}
+	return name, nil
+}
+
+// selectList SELECT 的列表项及其所在的括号深度
+type selectList struct {
+	depth int
+	items [][]sqlToken
+}
+
+// selectLists 提取语句中每个 SELECT 的列表项
+func selectLists(tokens []sqlToken) []selectList {
+	var lists []selectList
+	depth := 0
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch t.kind {
+		case tokOpen:
+			depth++
+			continue
+		case tokClose:
+			depth--
+			continue
+		}
+		if !tokenIs(t, "SELECT") {
+			continue
+		}
+		var items [][]sqlToken
+		var cur []sqlToken
+		d := 0
+		j := i + 1
+		j = skipWords(tokens, j, "DISTINCT", "ALL", "DISTINCTROW", "SQL_NO_CACHE", "SQL_CALC_FOUND_ROWS", "STRAIGHT_JOIN", "HIGH_PRIORITY")
+	loop:
+		for ; j < len(tokens); j++ {
+			u := tokens[j]
+			switch {
+			case u.kind == tokOpen:
+				d++
+			case u.kind == tokClose:
+				if d == 0 {
+					break loop
+				}
+				d--
+			case d == 0 && (u.kind == tokSemi || tokenIs(u, "FROM", "INTO", "WHERE", "UNION", "GROUP", "ORDER", "LIMIT", "HAVING", "FOR", "INTERSECT", "EXCEPT", "MINUS")):
+				break loop
+			case d == 0 && u.kind == tokComma:
+				items = append(items, cur)
+				cur = nil
+				continue
+			}
+			cur = append(cur, u)
+		}
+		if len(cur) > 0 {
+			items = append(items, cur)
+		}
+		lists = append(lists, selectList{depth: depth, items: items})
+	}
+	return lists
+}
+
+// maskRows 按列规则原地脱敏
+func maskRows(columns []string, rules []*MaskingRule, rows []map[string]interface{}) []string {
+	var masked []string
+	for i, r := range rules {
+		if r == nil {
+			continue
+		}
+		masked = append(masked, columns[i])
+		for _, row := range rows {
+			if v, ok := row[columns[i]]; ok {
+				row[columns[i]] = maskValue(r, v)
+			}
+		}
+	}
+	return masked
+}
+
+// maskQueryResult 对当前连接上的查询结果脱敏，返回被脱敏的列
+func (a *App) maskQueryResult(query string, columns []string, rows []map[string]interface{}) []string {
+	rules := columnRules(a.currentCfg, a.currentSchema, a.currentDBType, query, columns)
+	if rules == nil {
+		return nil
+	}
+	return maskRows(columns, rules, rows)
+}
+
+// maskExportRows 导出前脱敏：有来源语句时按语句分析，否则按列名匹配当前连接的全部规则；
+// 已在查询时脱敏过的列（masked）跳过，避免重复处理
+func (a *App) maskExportRows(query string, headers []string, masked []string, rows []map[string]interface{}) {
+	done := map[string]bool{}
+	for _, c := range masked {
+		done[c] = true
+	}
+	var rules []*MaskingRule
+	if strings.TrimSpace(query) != "" {
+		rules = columnRules(a.currentCfg, a.currentSchema, a.currentDBType, query, headers)
+	} else if all := rulesForConn(a.currentCfg); len(all) > 0 {
+		rules = make([]*MaskingRule, len(headers))
+		for i, h := range headers {
+			for _, r := range all {
+				if matchPattern(r.Column, h, false) {
+					rules[i] = r
+					break
+				}
+			}
+		}
+	}
+	if rules == nil {
+		return
+	}
+	for i := range rules {
+		if done[headers[i]] {
+			rules[i] = nil
+		}
+	}
+	maskRows(headers, rules, rows)
+}
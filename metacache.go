@@ -0,0 +1,782 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetaColumn 列信息
+type MetaColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+	Key      string `json:"key,omitempty"` // MySQL COLUMN_KEY：PRI / UNI / MUL
+	Comment  string `json:"comment,omitempty"`
+}
+
+// MetaTable 表或视图
+type MetaTable struct {
+	Name    string       `json:"name"`
+	Kind    string       `json:"kind"` // table / view
+	Comment string       `json:"comment,omitempty"`
+	Columns []MetaColumn `json:"columns"`
+	version string       // 结构指纹，用于增量刷新
+}
+
+// MetaRoutine 存储过程、函数与包
+type MetaRoutine struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"` // procedure / function / package
+}
+
+// SchemaMetadata 一个连接下某个库（Oracle 为用户）的元数据
+type SchemaMetadata struct {
+	ConnID   string        `json:"connId"`
+	Schema   string        `json:"schema"`
+	Dialect  string        `json:"dialect"`
+	Tables   []MetaTable   `json:"tables"`
+	Routines []MetaRoutine `json:"routines"`
+	Keywords []string      `json:"keywords"`
+	LoadedAt string        `json:"loadedAt"`
+	Changed  int           `json:"changed"` // 本次刷新新增、变更或删除的表数量
+}
+
+// metadataTTL 缓存超过该时间后，下次读取时做一次增量刷新
+const metadataTTL = 5 * time.Minute
+
+// metadataCache 按连接与库缓存元数据
+type metadataCache struct {
+	mu      sync.Mutex
+	entries map[string]*metadataEntry
+}
+
+type metadataEntry struct {
+	mu       sync.Mutex // 同一个库只允许一个刷新
+	meta     *SchemaMetadata
+	loadedAt time.Time
+}
+
+func newMetadataCache() *metadataCache {
+	return &metadataCache{entries: map[string]*metadataEntry{}}
+}
+
+// metadataKey 连接标识：优先使用连接ID，未保存的连接按地址与用户区分
+func metadataKey(cfg DBConfig, schema string) string {
+	id := cfg.ID
+	if id == "" {
+		id = fmt.Sprintf("%s@%s:%d/%s", cfg.User, cfg.Host, cfg.Port, cfg.Database)
+	}
+	if normalizeDBType(cfg.Type) == "oracle" {
+		schema = strings.ToUpper(schema)
+	}
+	return id + "|" + schema
+}
+
+func (c *metadataCache) entry(key string) *metadataEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &metadataEntry{}
+		c.entries[key] = e
+	}
+	return e
+}
+
+// get 返回缓存；不存在、过期或 force 时先增量刷新
+func (c *metadataCache) get(cfg DBConfig, schema string, force bool) (*SchemaMetadata, error) {
+	if schema == "" {
+		return nil, fmt.Errorf("数据库名不能为空")
+	}
+	e := c.entry(metadataKey(cfg, schema))
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.meta != nil && !force && time.Since(e.loadedAt) < metadataTTL {
+		return e.meta, nil
+	}
+	meta, err := refreshSchemaMetadata(cfg, schema, e.meta)
+	if err != nil {
+		if e.meta != nil && !force {
+			// 刷新失败时继续使用旧缓存
+			return e.meta, nil
+		}
+		return nil, err
+	}
+	e.meta, e.loadedAt = meta, time.Now()
+	return meta, nil
+}
+
+func (c *metadataCache) invalidate(cfg DBConfig, schema string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if schema == "" {
+		prefix := metadataKey(cfg, "")
+		for k := range c.entries {
+			if strings.HasPrefix(k, prefix) {
+				delete(c.entries, k)
+			}
+		}
+		return
+	}
+	delete(c.entries, metadataKey(cfg, schema))
+}
+
+// LoadMetadata 读取库的元数据（表、视图、列及类型、存储过程、关键字），优先使用缓存；
+// force 为 true 时立即增量刷新：只重新读取结构有变化的表
+func (a *App) LoadMetadata(cfg DBConfig, schema string, force bool) (SchemaMetadata, error) {
+	meta, err := a.meta.get(cfg, schema, force)
+	if err != nil {
+		return SchemaMetadata{}, err
+	}
+	return *meta, nil
+}
+
+// InvalidateMetadata 清除缓存；schema 为空时清除该连接下的全部库
+func (a *App) InvalidateMetadata(cfg DBConfig, schema string) {
+	a.meta.invalidate(cfg, schema)
+}
+
+// refreshSchemaMetadata 以旧快照为基础增量刷新：比较每张表的结构指纹，
+// 只为新增或变化的表读取列
+func refreshSchemaMetadata(cfg DBConfig, schema string, old *SchemaMetadata) (*SchemaMetadata, error) {
+	dialect := normalizeDBType(cfg.Type)
+	if dialect != "oracle" {
+		cfg.Database = ""
+	}
+	driver, dsn, err := buildDriverAndDSN(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var tables []MetaTable
+	var routines []MetaRoutine
+	if dialect == "oracle" {
+		schema = strings.ToUpper(schema)
+		tables, routines, err = loadOracleObjects(db, schema)
+	} else {
+		tables, routines, err = loadMySQLObjects(db, schema)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取元数据失败: %v", err)
+	}
+
+	previous := map[string]MetaTable{}
+	if old != nil {
+		for _, t := range old.Tables {
+			previous[t.Name] = t
+		}
+	}
+	var stale []string
+	for i := range tables {
+		if p, ok := previous[tables[i].Name]; ok && p.version == tables[i].version {
+			tables[i].Columns = p.Columns
+			continue
+		}
+		stale = append(stale, tables[i].Name)
+	}
+	changed := len(stale)
+	for name := range previous {
+		found := false
+		for _, t := range tables {
+			if t.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			changed++
+		}
+	}
+	if len(stale) > 0 {
+		var cols map[string][]MetaColumn
+		// 首次加载或变化较多时整库读取，否则只读变化的表
+		only := stale
+		if old == nil || len(stale) > 200 {
+			only = nil
+		}
+		if dialect == "oracle" {
+			cols, err = loadOracleColumns(db, schema, only)
+		} else {
+			cols, err = loadMySQLColumns(db, schema, only)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取列信息失败: %v", err)
+		}
+		for i := range tables {
+			if c, ok := cols[tables[i].Name]; ok {
+				tables[i].Columns = c
+			} else if tables[i].Columns == nil {
+				tables[i].Columns = []MetaColumn{}
+			}
+		}
+	}
+	return &SchemaMetadata{
+		ConnID:   cfg.ID,
+		Schema:   schema,
+		Dialect:  dialect,
+		Tables:   tables,
+		Routines: routines,
+		Keywords: dialectKeywords(dialect),
+		LoadedAt: time.Now().Format(time.RFC3339),
+		Changed:  changed,
+	}, nil
+}
+
+// loadMySQLObjects 表、视图及其结构指纹（按列定义计算），以及存储过程与函数
+func loadMySQLObjects(db *sql.DB, schema string) ([]MetaTable, []MetaRoutine, error) {
+	rows, err := db.Query(`SELECT t.TABLE_NAME, t.TABLE_TYPE, IFNULL(t.TABLE_COMMENT, ''),
+		IFNULL(c.cnt, 0), IFNULL(c.crc, 0)
+		FROM information_schema.TABLES t
+		LEFT JOIN (
+			SELECT TABLE_NAME, COUNT(*) cnt,
+				SUM(CRC32(CONCAT_WS(' ', ORDINAL_POSITION, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY, COLUMN_COMMENT))) crc
+			FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? GROUP BY TABLE_NAME
+		) c ON c.TABLE_NAME = t.TABLE_NAME
+		WHERE t.TABLE_SCHEMA = ? ORDER BY t.TABLE_NAME`, schema, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	tables := []MetaTable{}
+	for rows.Next() {
+		var t MetaTable
+		var kind string
+		var cnt int64
+		var crc sql.NullString
+		if err := rows.Scan(&t.Name, &kind, &t.Comment, &cnt, &crc); err != nil {
+			return nil, nil, err
+		}
+		t.Kind = "table"
+		if strings.Contains(kind, "VIEW") {
+			t.Kind = "view"
+		}
+		t.version = fmt.Sprintf("%d:%s", cnt, crc.String)
+		tables = append(tables, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	rrows, err := db.Query("SELECT ROUTINE_NAME, ROUTINE_TYPE FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = ? ORDER BY ROUTINE_NAME", schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rrows.Close()
+	routines := []MetaRoutine{}
+	for rrows.Next() {
+		var r MetaRoutine
+		if err := rrows.Scan(&r.Name, &r.Kind); err != nil {
+			return nil, nil, err
+		}
+		r.Kind = strings.ToLower(r.Kind)
+		routines = append(routines, r)
+	}
+	return tables, routines, rrows.Err()
+}
+
+func loadMySQLColumns(db *sql.DB, schema string, only []string) (map[string][]MetaColumn, error) {
+	out := map[string][]MetaColumn{}
+	query := func(names []string) error {
+		q := `SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY, IFNULL(COLUMN_COMMENT, '')
+			FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ?`
+		args := []interface{}{schema}
+		if len(names) > 0 {
+			q += " AND TABLE_NAME IN (?" + strings.Repeat(", ?", len(names)-1) + ")"
+			for _, n := range names {
+				args = append(args, n)
+			}
+		}
+		rows, err := db.Query(q+" ORDER BY TABLE_NAME, ORDINAL_POSITION", args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var table, nullable string
+			var c MetaColumn
+			if err := rows.Scan(&table, &c.Name, &c.Type, &nullable, &c.Key, &c.Comment); err != nil {
+				return err
+			}
+			c.Nullable = nullable == "YES"
+			out[table] = append(out[table], c)
+		}
+		return rows.Err()
+	}
+	if len(only) == 0 {
+		return out, query(nil)
+	}
+	for start := 0; start < len(only); start += 100 {
+		end := start + 100
+		if end > len(only) {
+			end = len(only)
+		}
+		if err := query(only[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// loadOracleObjects 表、视图以 LAST_DDL_TIME 作为结构指纹
+func loadOracleObjects(db *sql.DB, owner string) ([]MetaTable, []MetaRoutine, error) {
+	rows, err := db.Query(fmt.Sprintf(`SELECT o.OBJECT_NAME, o.OBJECT_TYPE, TO_CHAR(o.LAST_DDL_TIME, 'YYYYMMDDHH24MISS'), c.COMMENTS
+		FROM ALL_OBJECTS o
+		LEFT JOIN ALL_TAB_COMMENTS c ON c.OWNER = o.OWNER AND c.TABLE_NAME = o.OBJECT_NAME
+		WHERE o.OWNER = '%s' AND o.OBJECT_TYPE IN ('TABLE', 'VIEW', 'PROCEDURE', 'FUNCTION', 'PACKAGE')
+		AND o.OBJECT_NAME NOT LIKE 'BIN$%%' AND o.SUBOBJECT_NAME IS NULL
+		ORDER BY o.OBJECT_NAME`, escapeSQLLiteral(owner)))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	tables := []MetaTable{}
+	routines := []MetaRoutine{}
+	for rows.Next() {
+		var name, kind string
+		var ddl, comment sql.NullString
+		if err := rows.Scan(&name, &kind, &ddl, &comment); err != nil {
+			return nil, nil, err
+		}
+		switch kind {
+		case "TABLE", "VIEW":
+			tables = append(tables, MetaTable{Name: name, Kind: strings.ToLower(kind), Comment: comment.String, version: ddl.String})
+		default:
+			routines = append(routines, MetaRoutine{Name: name, Kind: strings.ToLower(kind)})
+		}
+	}
+	return tables, routines, rows.Err()
+}
+
+func loadOracleColumns(db *sql.DB, owner string, only []string) (map[string][]MetaColumn, error) {
+	out := map[string][]MetaColumn{}
+	query := func(names []string) error {
+		q := fmt.Sprintf(`SELECT c.TABLE_NAME, c.COLUMN_NAME, c.DATA_TYPE, c.DATA_LENGTH, NVL(c.CHAR_LENGTH, 0),
+			c.DATA_PRECISION, c.DATA_SCALE, c.NULLABLE, cc.COMMENTS
+			FROM ALL_TAB_COLUMNS c
+			LEFT JOIN ALL_COL_COMMENTS cc ON cc.OWNER = c.OWNER AND cc.TABLE_NAME = c.TABLE_NAME AND cc.COLUMN_NAME = c.COLUMN_NAME
+			WHERE c.OWNER = '%s'`, escapeSQLLiteral(owner))
+		if len(names) > 0 {
+			quoted := make([]string, len(names))
+			for i, n := range names {
+				quoted[i] = "'" + escapeSQLLiteral(n) + "'"
+			}
+			q += " AND c.TABLE_NAME IN (" + strings.Join(quoted, ", ") + ")"
+		}
+		rows, err := db.Query(q + " ORDER BY c.TABLE_NAME, c.COLUMN_ID")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var table, nullable string
+			var comment sql.NullString
+			var oc oracleColumn
+			if err := rows.Scan(&table, &oc.Name, &oc.DataType, &oc.DataLength, &oc.CharLength, &oc.Precision, &oc.Scale, &nullable, &comment); err != nil {
+				return err
+			}
+			out[table] = append(out[table], MetaColumn{
+				Name:     oc.Name,
+				Type:     oracleFullType(oc),
+				Nullable: nullable == "Y",
+				Comment:  comment.String,
+			})
+		}
+		return rows.Err()
+	}
+	if len(only) == 0 {
+		return out, query(nil)
+	}
+	// IN 列表最多 1000 项
+	for start := 0; start < len(only); start += 500 {
+		end := start + 500
+		if end > len(only) {
+			end = len(only)
+		}
+		if err := query(only[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+var mysqlExtraKeywords = strings.Fields(`AUTO_INCREMENT CHARSET COLLATE DELIMITER DUPLICATE ENGINE EXPLAIN FORCE GROUP_CONCAT
+IFNULL IGNORE LIMIT LOCK OFFSET REGEXP REPLACE RLIKE SHOW STRAIGHT_JOIN UNSIGNED USE ZEROFILL NOW CONCAT CONCAT_WS
+COALESCE COUNT SUM AVG MIN MAX DATE_FORMAT DATE_ADD DATE_SUB STR_TO_DATE UNIX_TIMESTAMP FROM_UNIXTIME JSON_EXTRACT`)
+
+var oracleExtraKeywords = strings.Fields(`CONNECT DECODE DUAL MINUS NOCOPY NVL NVL2 PRIOR ROWID ROWNUM SYSDATE SYSTIMESTAMP
+TO_CHAR TO_DATE TO_NUMBER TRUNC VARCHAR2 NUMBER CLOB BLOB LISTAGG MERGE MATCHED START COALESCE COUNT SUM AVG MIN MAX
+SUBSTR INSTR LENGTH REGEXP_LIKE REGEXP_SUBSTR`)
+
+// dialectKeywords 方言的关键字与常用函数
+func dialectKeywords(dialect string) []string {
+	set := map[string]bool{}
+	for w := range sqlKeywords {
+		set[w] = true
+	}
+	extra := mysqlExtraKeywords
+	if dialect == "oracle" {
+		extra = oracleExtraKeywords
+		for _, w := range mysqlOnlyKeywords {
+			delete(set, w)
+		}
+	}
+	for _, w := range extra {
+		set[w] = true
+	}
+	out := make([]string, 0, len(set))
+	for w := range set {
+		out = append(out, w)
+	}
+	sort.Strings(out)
+	return out
+}
+
+var mysqlOnlyKeywords = strings.Fields(`ACCESSIBLE AUTO_INCREMENT CHARSET DIV ENGINE FULLTEXT LONGTEXT MEDIUMINT MEDIUMTEXT
+REGEXP RLIKE SEPARATOR SHOW STRAIGHT_JOIN TINYINT TINYTEXT UNSIGNED XOR ZEROFILL`)
+
+// Suggestion 补全项
+type Suggestion struct {
+	Label  string `json:"label"`
+	Kind   string `json:"kind"` // table / view / column / routine / keyword / schema
+	Detail string `json:"detail,omitempty"`
+}
+
+// SuggestResult 补全结果；Prefix 为光标前正在输入的部分，前端据此确定替换范围
+type SuggestResult struct {
+	Context string       `json:"context"` // table / column / member / none
+	Prefix  string       `json:"prefix"`
+	Items   []Suggestion `json:"items"`
+}
+
+const maxSuggestions = 300
+
+// sqlTableRef 语句中引用的表及别名
+type sqlTableRef struct {
+	schema, table, alias string
+}
+
+// Suggest 按光标所在语句给出补全：FROM/JOIN 等之后补全表名，"别名." 之后补全该表的列，
+// 其他位置补全语句中引用表的列与关键字。cursorPos 为前端编辑器中的字符偏移（UTF-16）
+func (a *App) Suggest(cfg DBConfig, schema string, sqlText string, cursorPos int) (SuggestResult, error) {
+	dialect := normalizeDBType(cfg.Type)
+	cursor := utf16OffsetToByte(sqlText, cursorPos)
+	prefixTokens, err := tokenizeSQL(sqlText[:cursor], dialect)
+	if err != nil {
+		// 光标在未闭合的字符串或注释中
+		return SuggestResult{Context: "none", Items: []Suggestion{}}, nil
+	}
+	if n := len(prefixTokens); n > 0 && prefixTokens[n-1].kind == tokLineComment {
+		return SuggestResult{Context: "none", Items: []Suggestion{}}, nil
+	}
+	suffixTokens, _ := tokenizeSQL(sqlText[cursor:], dialect)
+
+	// 截取光标所在语句
+	for i := len(prefixTokens) - 1; i >= 0; i-- {
+		if prefixTokens[i].kind == tokSemi {
+			prefixTokens = prefixTokens[i+1:]
+			break
+		}
+	}
+	for i, t := range suffixTokens {
+		if t.kind == tokSemi {
+			suffixTokens = suffixTokens[:i]
+			break
+		}
+	}
+
+	// 光标前正在输入的单词
+	partial := ""
+	head := prefixTokens
+	if n := len(head); n > 0 && cursor > 0 && head[n-1].end == cursor &&
+		(head[n-1].kind == tokWord || head[n-1].kind == tokQuoted) {
+		partial = head[n-1].text
+		head = head[:n-1]
+	}
+	// 光标恰在单词中间时，后半部分属于同一个单词
+	if len(suffixTokens) > 0 && suffixTokens[0].start == 0 && suffixTokens[0].kind == tokWord {
+		suffixTokens = suffixTokens[1:]
+	}
+	var significant []sqlToken
+	for _, t := range head {
+		if t.kind != tokLineComment && t.kind != tokBlockComment {
+			significant = append(significant, t)
+		}
+	}
+	statement := append(append(append([]sqlToken{}, significant...), sqlToken{kind: tokWord, text: partial}), suffixTokens...)
+	refs := parseTableRefs(statement)
+
+	result := SuggestResult{Prefix: strings.TrimLeft(partial, "`\""), Items: []Suggestion{}}
+	meta, err := a.meta.get(cfg, schema, false)
+	if err != nil {
+		return result, err
+	}
+	lookup := func(ref sqlTableRef) *MetaTable {
+		m := meta
+		if ref.schema != "" && !identEqual(ref.schema, schema) {
+			if other, err := a.meta.get(cfg, ref.schema, false); err == nil {
+				m = other
+			} else {
+				return nil
+			}
+		}
+		for i := range m.Tables {
+			if identEqual(m.Tables[i].Name, ref.table) {
+				return &m.Tables[i]
+			}
+		}
+		return nil
+	}
+	add := func(label, kind, detail string) {
+		if len(result.Items) >= maxSuggestions {
+			return
+		}
+		if result.Prefix != "" && !strings.HasPrefix(strings.ToLower(label), strings.ToLower(result.Prefix)) {
+			return
+		}
+		result.Items = append(result.Items, Suggestion{Label: label, Kind: kind, Detail: detail})
+	}
+	addTables := func(m *SchemaMetadata) {
+		for _, t := range m.Tables {
+			add(t.Name, t.Kind, t.Comment)
+		}
+	}
+
+	n := len(significant)
+	// 别名.列 或 库.表
+	if n > 0 && significant[n-1].kind == tokDot {
+		result.Context = "member"
+		if n < 2 {
+			return result, nil
+		}
+		qualifier := unquoteIdent(significant[n-2].text)
+		for _, ref := range refs {
+			if identEqual(ref.alias, qualifier) || (ref.alias == "" && identEqual(ref.table, qualifier)) {
+				if t := lookup(ref); t != nil {
+					for _, c := range t.Columns {
+						add(c.Name, "column", c.Type)
+					}
+				}
+				return result, nil
+			}
+		}
+		if t := lookup(sqlTableRef{table: qualifier}); t != nil {
+			for _, c := range t.Columns {
+				add(c.Name, "column", c.Type)
+			}
+			return result, nil
+		}
+		if other, err := a.meta.get(cfg, qualifier, false); err == nil {
+			addTables(other)
+			for _, r := range other.Routines {
+				add(r.Name, "routine", r.Kind)
+			}
+		}
+		return result, nil
+	}
+
+	if expectsTable(significant) {
+		result.Context = "table"
+		addTables(meta)
+		return result, nil
+	}
+	if n > 0 && strings.EqualFold(significant[n-1].text, "CALL") && significant[n-1].kind == tokWord {
+		result.Context = "routine"
+		for _, r := range meta.Routines {
+			add(r.Name, "routine", r.Kind)
+		}
+		return result, nil
+	}
+
+	result.Context = "column"
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		t := lookup(ref)
+		if t == nil {
+			continue
+		}
+		owner := ref.table
+		if ref.alias != "" {
+			owner = ref.alias
+		}
+		for _, c := range t.Columns {
+			if seen[strings.ToLower(c.Name)] {
+				continue
+			}
+			seen[strings.ToLower(c.Name)] = true
+			add(c.Name, "column", owner+" · "+c.Type)
+		}
+	}
+	if result.Prefix != "" {
+		for _, r := range meta.Routines {
+			if r.Kind == "function" {
+				add(r.Name, "routine", r.Kind)
+			}
+		}
+		for _, k := range meta.Keywords {
+			add(k, "keyword", "")
+		}
+		if len(refs) == 0 {
+			addTables(meta)
+		}
+	}
+	return result, nil
+}
+
+// expectsTable 光标前的单元是否期望表名
+func expectsTable(tokens []sqlToken) bool {
+	n := len(tokens)
+	if n == 0 {
+		return false
+	}
+	last := tokens[n-1]
+	if last.kind == tokWord {
+		switch strings.ToUpper(last.text) {
+		case "FROM", "JOIN", "UPDATE", "INTO", "TABLE", "DESC", "DESCRIBE", "STRAIGHT_JOIN", "TRUNCATE":
+			return true
+		}
+		return false
+	}
+	if last.kind != tokComma {
+		return false
+	}
+	// FROM a, | ：向前找到同层最近的子句关键字
+	depth := 0
+	for i := n - 2; i >= 0; i-- {
+		t := tokens[i]
+		switch t.kind {
+		case tokClose:
+			depth++
+		case tokOpen:
+			if depth == 0 {
+				return false
+			}
+			depth--
+		case tokWord:
+			if depth > 0 {
+				continue
+			}
+			switch strings.ToUpper(t.text) {
+			case "FROM":
+				return true
+			case "SELECT", "WHERE", "SET", "ON", "GROUP", "ORDER", "HAVING", "VALUES", "BY", "JOIN", "USING":
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// parseTableRefs 提取 FROM / JOIN / UPDATE / INTO 之后的表及别名
+func parseTableRefs(tokens []sqlToken) []sqlTableRef {
+	var refs []sqlTableRef
+	inFrom := false
+	depth, fromDepth := 0, 0
+	isName := func(t sqlToken) bool { return (t.kind == tokWord || t.kind == tokQuoted) && t.text != "" }
+	readRef := func(i int) (sqlTableRef, int) {
+		var ref sqlTableRef
+		if i >= len(tokens) || !isName(tokens[i]) {
+			return ref, i
+		}
+		ref.table = unquoteIdent(tokens[i].text)
+		i++
+		if i+1 < len(tokens) && tokens[i].kind == tokDot && isName(tokens[i+1]) {
+			ref.schema, ref.table = ref.table, unquoteIdent(tokens[i+1].text)
+			i += 2
+		}
+		if i < len(tokens) && tokens[i].kind == tokWord && strings.EqualFold(tokens[i].text, "AS") {
+			i++
+		}
+		if i < len(tokens) && isName(tokens[i]) && !sqlReservedAfterTable[strings.ToUpper(tokens[i].text)] {
+			ref.alias = unquoteIdent(tokens[i].text)
+			i++
+		}
+		return ref, i
+	}
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch t.kind {
+		case tokOpen:
+			depth++
+			continue
+		case tokClose:
+			if depth > 0 {
+				depth--
+			}
+			if inFrom && depth < fromDepth {
+				inFrom = false
+			}
+			continue
+		case tokComma:
+			if inFrom && depth == fromDepth {
+				if ref, next := readRef(i + 1); ref.table != "" {
+					refs = append(refs, ref)
+					i = next - 1
+				}
+			}
+			continue
+		case tokWord:
+		default:
+			continue
+		}
+		switch strings.ToUpper(t.text) {
+		case "FROM", "JOIN", "UPDATE", "INTO", "STRAIGHT_JOIN", "USING":
+			if strings.EqualFold(t.text, "USING") && (i+1 >= len(tokens) || tokens[i+1].kind == tokOpen) {
+				continue
+			}
+			inFrom = strings.EqualFold(t.text, "FROM")
+			fromDepth = depth
+			if ref, next := readRef(i + 1); ref.table != "" {
+				refs = append(refs, ref)
+				i = next - 1
+			}
+		case "WHERE", "GROUP", "ORDER", "HAVING", "LIMIT", "SET", "ON", "UNION", "SELECT":
+			if depth == fromDepth {
+				inFrom = false
+			}
+		}
+	}
+	return refs
+}
+
+// sqlReservedAfterTable 紧跟在表名之后、不是别名的关键字
+var sqlReservedAfterTable = toKeywordSet(`WHERE GROUP ORDER HAVING LIMIT JOIN LEFT RIGHT INNER OUTER FULL CROSS NATURAL STRAIGHT_JOIN
+ON USING SET VALUES SELECT UNION MINUS EXCEPT INTERSECT FOR LOCK WINDOW PARTITION FORCE IGNORE USE CONNECT START FETCH
+OFFSET RETURNING WITH`)
+
+func unquoteIdent(s string) string {
+	if len(s) >= 2 && (s[0] == '`' || s[0] == '"') && s[len(s)-1] == s[0] {
+		q := string(s[0])
+		return strings.ReplaceAll(s[1:len(s)-1], q+q, q)
+	}
+	return s
+}
+
+// identEqual 比较标识符（不区分大小写；Oracle 未加引号的名称按大写存储）
+func identEqual(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// utf16OffsetToByte 把前端（UTF-16）字符偏移换算为字节偏移
+func utf16OffsetToByte(s string, offset int) int {
+	units := 0
+	for i, r := range s {
+		if units >= offset {
+			return i
+		}
+		if r >= 0x10000 {
+			units += 2
+		} else {
+			units++
+		}
+	}
+	return len(s)
+}
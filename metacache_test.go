@@ -0,0 +1,44 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestInvalidateDDLMetadata(t *testing.T) {
+	cfg := DBConfig{ID: "c1", Type: "mysql"}
+	tests := []struct {
+		name    string
+		query   string
+		current string
+		want    []string // 执行后仍在缓存中的库
+	}{
+		{"select keeps cache", "SELECT * FROM t", "a", []string{"a", "b", "c"}},
+		{"dml keeps cache", "UPDATE t SET x = 1 WHERE id = 1", "a", []string{"a", "b", "c"}},
+		{"ddl current schema", "ALTER TABLE t ADD COLUMN x int", "a", []string{"b", "c"}},
+		{"ddl qualified schema", "ALTER TABLE b.t ADD COLUMN x int", "a", []string{"a", "c"}},
+		{"ddl object not identified", "CREATE TABLE b.t (id int)", "a", nil},
+		{"drop database", "DROP DATABASE c", "a", []string{"a", "b"}},
+		{"multi statement", "SELECT 1; TRUNCATE TABLE t; DROP TABLE b.t", "a", []string{"c"}},
+		{"use before ddl", "USE b; DROP TABLE t", "a", nil},
+		{"unknown current schema", "DROP TABLE t", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{currentDBType: "mysql", currentCfg: cfg, meta: newMetadataCache()}
+			for _, s := range []string{"a", "b", "c"} {
+				a.meta.entry(metadataKey(cfg, s))
+			}
+			a.invalidateDDLMetadata(classifyStatements(tt.query, "mysql"), tt.current)
+			var got []string
+			for k := range a.meta.entries {
+				got = append(got, k[strings.LastIndex(k, "|")+1:])
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("cached schemas = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,575 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TypeMappingRule 默认类型映射规则说明（供界面展示）
+type TypeMappingRule struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Note   string `json:"note"`
+}
+
+// GetOracleTypeMappings 返回 Oracle → MySQL 的默认类型映射规则；
+// SyncOptions.TypeMappings 可按完整类型（如 NUMBER(1,0)）或基础类型（如 VARCHAR2）覆盖，
+// 目标类型中可使用 {p} {s} {len} 占位符
+func (a *App) GetOracleTypeMappings() []TypeMappingRule {
+	return []TypeMappingRule{
+		{Source: "NUMBER(p,0)", Target: "TINYINT / SMALLINT / INT / BIGINT / DECIMAL(p,0)", Note: "按精度选择：p<3、p<5、p<10、p<19，其余 DECIMAL"},
+		{Source: "NUMBER(p,s)", Target: "DECIMAL(p,s)", Note: "精度超过 65、小数位超过 30 时截断"},
+		{Source: "NUMBER", Target: "DECIMAL(38,10)", Note: "未指定精度"},
+		{Source: "FLOAT / BINARY_DOUBLE", Target: "DOUBLE"},
+		{Source: "BINARY_FLOAT", Target: "FLOAT"},
+		{Source: "VARCHAR2(n) / NVARCHAR2(n)", Target: "VARCHAR(n)", Note: "单行长度超过 MySQL 限制时较长的列改为 TEXT"},
+		{Source: "CHAR(n) / NCHAR(n)", Target: "CHAR(n)", Note: "n>255 时为 VARCHAR(n)"},
+		{Source: "DATE", Target: "DATETIME"},
+		{Source: "TIMESTAMP(n)", Target: "DATETIME(n)", Note: "小数位最多 6 位"},
+		{Source: "TIMESTAMP(n) WITH [LOCAL] TIME ZONE", Target: "DATETIME(n)", Note: "带时区的值转换为 UTC"},
+		{Source: "CLOB / NCLOB / LONG / XMLTYPE", Target: "LONGTEXT"},
+		{Source: "BLOB / LONG RAW", Target: "LONGBLOB"},
+		{Source: "RAW(n)", Target: "VARBINARY(n)"},
+		{Source: "ROWID / UROWID / INTERVAL", Target: "VARCHAR(64)"},
+		{Source: "其他", Target: "LONGTEXT"},
+	}
+}
+
+// oracleColumn ALL_TAB_COLUMNS 中的列信息
+type oracleColumn struct {
+	Name       string
+	DataType   string
+	DataLength int64
+	CharLength int64
+	Precision  sql.NullInt64
+	Scale      sql.NullInt64
+	Nullable   bool
+	Comment    string
+}
+
+// oracleConstraint ALL_CONSTRAINTS 中的主键、唯一、外键约束
+type oracleConstraint struct {
+	Name       string
+	Type       string // P / U / R
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	DeleteRule string
+}
+
+// oracleTable 一张表的元数据
+type oracleTable struct {
+	Columns     []oracleColumn
+	Constraints []oracleConstraint
+	Comment     string
+}
+
+// oracleSyncSource Oracle 源：按 owner（即所选 schema）读取元数据，生成 MySQL 建表语句，
+// 查询时把数值、日期等转换为 MySQL 可直接接收的文本
+type oracleSyncSource struct {
+	db       *sql.DB
+	owner    string
+	mappings map[string]string
+
+	mu    sync.Mutex
+	cache map[string]*oracleTable
+}
+
+func openOracleSyncSource(cfg DBConfig, schema string, mappings map[string]string, poolSize int) (*oracleSyncSource, error) {
+	driver, dsn, err := buildDriverAndDSN(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(poolSize)
+	db.SetMaxIdleConns(poolSize)
+	normalized := map[string]string{}
+	for k, v := range mappings {
+		if strings.TrimSpace(v) != "" {
+			normalized[normalizeOracleTypeKey(k)] = strings.TrimSpace(v)
+		}
+	}
+	return &oracleSyncSource{
+		db:       db,
+		owner:    strings.ToUpper(schema),
+		mappings: normalized,
+		cache:    map[string]*oracleTable{},
+	}, nil
+}
+
+func (s *oracleSyncSource) close() error {
+	return s.db.Close()
+}
+
+func (s *oracleSyncSource) qualified(table string) string {
+	return quoteOracleIdent(s.owner) + "." + quoteOracleIdent(table)
+}
+
+func (s *oracleSyncSource) tables() ([]string, error) {
+	rows, err := s.db.Query(fmt.Sprintf(
+		"SELECT TABLE_NAME FROM ALL_TABLES WHERE OWNER = '%s' AND NESTED = 'NO' AND SECONDARY = 'N' AND TEMPORARY = 'N' AND DROPPED = 'NO' ORDER BY TABLE_NAME",
+		escapeSQLLiteral(s.owner),
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+func (s *oracleSyncSource) countRows(table string) (int64, error) {
+	var c int64
+	err := s.db.QueryRow("SELECT COUNT(1) FROM " + s.qualified(table)).Scan(&c)
+	return c, err
+}
+
+// table 读取并缓存表的列与约束
+func (s *oracleSyncSource) table(table string) (*oracleTable, error) {
+	s.mu.Lock()
+	t, ok := s.cache[table]
+	s.mu.Unlock()
+	if ok {
+		return t, nil
+	}
+	owner, name := escapeSQLLiteral(s.owner), escapeSQLLiteral(table)
+	t = &oracleTable{}
+
+	rows, err := s.db.Query(fmt.Sprintf(
+		`SELECT c.COLUMN_NAME, c.DATA_TYPE, c.DATA_LENGTH, c.CHAR_LENGTH, c.DATA_PRECISION, c.DATA_SCALE, c.NULLABLE, cc.COMMENTS
+		 FROM ALL_TAB_COLUMNS c
+		 LEFT JOIN ALL_COL_COMMENTS cc ON cc.OWNER = c.OWNER AND cc.TABLE_NAME = c.TABLE_NAME AND cc.COLUMN_NAME = c.COLUMN_NAME
+		 WHERE c.OWNER = '%s' AND c.TABLE_NAME = '%s'
+		 ORDER BY c.COLUMN_ID`, owner, name))
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var c oracleColumn
+		var dataLength, charLength sql.NullInt64
+		var nullable, comment sql.NullString
+		if err := rows.Scan(&c.Name, &c.DataType, &dataLength, &charLength, &c.Precision, &c.Scale, &nullable, &comment); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		c.DataLength, c.CharLength = dataLength.Int64, charLength.Int64
+		c.Nullable = nullable.String != "N"
+		c.Comment = comment.String
+		t.Columns = append(t.Columns, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("表 %s.%s 不存在或没有访问权限", s.owner, table)
+	}
+
+	// 只迁移已启用的主键、唯一、外键约束；外键仅保留同一 schema 内的引用
+	rows, err = s.db.Query(fmt.Sprintf(
+		`SELECT c.CONSTRAINT_NAME, c.CONSTRAINT_TYPE, cc.COLUMN_NAME, c.R_OWNER, c.R_CONSTRAINT_NAME, c.DELETE_RULE
+		 FROM ALL_CONSTRAINTS c
+		 JOIN ALL_CONS_COLUMNS cc ON cc.OWNER = c.OWNER AND cc.CONSTRAINT_NAME = c.CONSTRAINT_NAME AND cc.TABLE_NAME = c.TABLE_NAME
+		 WHERE c.OWNER = '%s' AND c.TABLE_NAME = '%s' AND c.CONSTRAINT_TYPE IN ('P', 'U', 'R') AND c.STATUS = 'ENABLED'
+		 ORDER BY c.CONSTRAINT_TYPE, c.CONSTRAINT_NAME, cc.POSITION`, owner, name))
+	if err != nil {
+		return nil, err
+	}
+	refs := map[int]string{}
+	for rows.Next() {
+		var cname, ctype, col string
+		var rOwner, rName, deleteRule sql.NullString
+		if err := rows.Scan(&cname, &ctype, &col, &rOwner, &rName, &deleteRule); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		n := len(t.Constraints)
+		if n == 0 || t.Constraints[n-1].Name != cname {
+			if ctype == "R" && rOwner.String != s.owner {
+				continue
+			}
+			t.Constraints = append(t.Constraints, oracleConstraint{Name: cname, Type: ctype, DeleteRule: deleteRule.String})
+			n++
+			if ctype == "R" {
+				refs[n-1] = rName.String
+			}
+		}
+		t.Constraints[n-1].Columns = append(t.Constraints[n-1].Columns, col)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for idx, refName := range refs {
+		refRows, err := s.db.Query(fmt.Sprintf(
+			"SELECT TABLE_NAME, COLUMN_NAME FROM ALL_CONS_COLUMNS WHERE OWNER = '%s' AND CONSTRAINT_NAME = '%s' ORDER BY POSITION",
+			owner, escapeSQLLiteral(refName)))
+		if err != nil {
+			return nil, err
+		}
+		for refRows.Next() {
+			var refTable, refCol string
+			if err := refRows.Scan(&refTable, &refCol); err != nil {
+				refRows.Close()
+				return nil, err
+			}
+			t.Constraints[idx].RefTable = refTable
+			t.Constraints[idx].RefColumns = append(t.Constraints[idx].RefColumns, refCol)
+		}
+		refRows.Close()
+	}
+
+	var comment sql.NullString
+	_ = s.db.QueryRow(fmt.Sprintf(
+		"SELECT COMMENTS FROM ALL_TAB_COMMENTS WHERE OWNER = '%s' AND TABLE_NAME = '%s'", owner, name,
+	)).Scan(&comment)
+	t.Comment = comment.String
+
+	s.mu.Lock()
+	s.cache[table] = t
+	s.mu.Unlock()
+	return t, nil
+}
+
+// createTableSQL 根据列与约束生成 MySQL 建表语句；Oracle 的默认值（LONG 类型表达式）不迁移
+func (s *oracleSyncSource) createTableSQL(table string) (string, error) {
+	t, err := s.table(table)
+	if err != nil {
+		return "", err
+	}
+	types := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		types[i] = mapOracleType(c, s.mappings)
+	}
+	fitRowSize(types)
+
+	typeOf := map[string]string{}
+	var lines []string
+	for i, c := range t.Columns {
+		typeOf[c.Name] = types[i]
+		line := fmt.Sprintf("  `%s` %s", c.Name, types[i])
+		if !c.Nullable {
+			line += " NOT NULL"
+		}
+		if c.Comment != "" {
+			line += fmt.Sprintf(" COMMENT '%s'", escapeSQLString(c.Comment))
+		}
+		lines = append(lines, line)
+	}
+	keyCols := func(cols []string) string {
+		parts := make([]string, len(cols))
+		for i, col := range cols {
+			parts[i] = fmt.Sprintf("`%s`%s", col, keyPrefix(typeOf[col]))
+		}
+		return strings.Join(parts, ", ")
+	}
+	for _, c := range t.Constraints {
+		switch c.Type {
+		case "P":
+			lines = append(lines, fmt.Sprintf("  PRIMARY KEY (%s)", keyCols(c.Columns)))
+		case "U":
+			lines = append(lines, fmt.Sprintf("  UNIQUE KEY `%s` (%s)", c.Name, keyCols(c.Columns)))
+		case "R":
+			if c.RefTable == "" || len(c.RefColumns) != len(c.Columns) {
+				continue
+			}
+			fk := fmt.Sprintf("  CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES `%s` (%s)",
+				c.Name, quoteColumns(c.Columns), c.RefTable, quoteColumns(c.RefColumns))
+			switch c.DeleteRule {
+			case "CASCADE":
+				fk += " ON DELETE CASCADE"
+			case "SET NULL":
+				fk += " ON DELETE SET NULL"
+			}
+			lines = append(lines, fk)
+		}
+	}
+	ddl := fmt.Sprintf("CREATE TABLE `%s` (\n%s\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4", table, strings.Join(lines, ",\n"))
+	if t.Comment != "" {
+		ddl += fmt.Sprintf(" COMMENT='%s'", escapeSQLString(t.Comment))
+	}
+	return ddl, nil
+}
+
+// planChunks 单列整数主键（NUMBER 且小数位为 0）的表按主键范围分片，其余整表复制
+func (s *oracleSyncSource) planChunks(table string, rows int64, chunkRows int64) ([]tableChunk, error) {
+	whole := []tableChunk{{}}
+	t, err := s.table(table)
+	if err != nil {
+		return nil, err
+	}
+	pk := ""
+	for _, c := range t.Constraints {
+		if c.Type == "P" && len(c.Columns) == 1 {
+			pk = c.Columns[0]
+		}
+	}
+	if pk == "" {
+		return whole, nil
+	}
+	for _, c := range t.Columns {
+		if c.Name == pk && !(c.DataType == "NUMBER" && c.Scale.Valid && c.Scale.Int64 == 0 && c.Precision.Valid && c.Precision.Int64 <= 18) {
+			return whole, nil
+		}
+	}
+	var lo, hi sql.NullInt64
+	q := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", quoteOracleIdent(pk), quoteOracleIdent(pk), s.qualified(table))
+	if err := s.db.QueryRow(q).Scan(&lo, &hi); err != nil {
+		return nil, err
+	}
+	if !lo.Valid || !hi.Valid {
+		return whole, nil
+	}
+	return splitChunks(pk, lo.Int64, hi.Int64, rows, chunkRows), nil
+}
+
+// queryChunk 按分片读取数据，列别名为原列名
+func (s *oracleSyncSource) queryChunk(ctx context.Context, table string, chunk tableChunk) (*sourceRows, error) {
+	t, err := s.table(table)
+	if err != nil {
+		return nil, err
+	}
+	exprs := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		exprs[i] = oracleSelectExpr(c) + " AS " + quoteOracleIdent(c.Name)
+	}
+	q := fmt.Sprintf("SELECT %s FROM %s%s", strings.Join(exprs, ", "), s.qualified(table), oracleChunkWhere(chunk))
+	rows, err := s.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return &sourceRows{Rows: rows}, nil
+}
+
+// oracleChunkWhere 与 tableChunk.where 含义相同的 Oracle 条件（整数直接写入语句）
+func oracleChunkWhere(c tableChunk) string {
+	if c.Column == "" {
+		return ""
+	}
+	col := quoteOracleIdent(c.Column)
+	lowerOp, lower := ">=", c.Lo
+	if c.Started {
+		lowerOp, lower = ">", c.HighWater
+	}
+	upperOp := "<"
+	if c.Last {
+		upperOp = "<="
+	}
+	return fmt.Sprintf(" WHERE %s %s %d AND %s %s %d ORDER BY %s", col, lowerOp, lower, col, upperOp, c.Hi, col)
+}
+
+func quoteOracleIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+var oracleTypeParenRe = regexp.MustCompile(`\(\d+\)`)
+
+// oracleBaseType 去掉类型中的长度，如 TIMESTAMP(6) WITH TIME ZONE → TIMESTAMP WITH TIME ZONE
+func oracleBaseType(dataType string) string {
+	return strings.TrimSpace(oracleTypeParenRe.ReplaceAllString(strings.ToUpper(dataType), ""))
+}
+
+func normalizeOracleTypeKey(key string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(strings.ReplaceAll(key, " (", "("))), " ")
+}
+
+// oracleFullType 带长度/精度的完整类型，用于匹配用户映射
+func oracleFullType(c oracleColumn) string {
+	base := oracleBaseType(c.DataType)
+	switch base {
+	case "NUMBER":
+		if !c.Precision.Valid {
+			return "NUMBER"
+		}
+		return fmt.Sprintf("NUMBER(%d,%d)", c.Precision.Int64, c.Scale.Int64)
+	case "FLOAT":
+		if c.Precision.Valid {
+			return fmt.Sprintf("FLOAT(%d)", c.Precision.Int64)
+		}
+	case "VARCHAR2", "NVARCHAR2", "CHAR", "NCHAR":
+		return fmt.Sprintf("%s(%d)", base, oracleCharLength(c))
+	case "RAW":
+		return fmt.Sprintf("RAW(%d)", c.DataLength)
+	}
+	return normalizeOracleTypeKey(c.DataType)
+}
+
+func oracleCharLength(c oracleColumn) int64 {
+	if c.CharLength > 0 {
+		return c.CharLength
+	}
+	return c.DataLength
+}
+
+// mapOracleType 返回列对应的 MySQL 类型：用户映射优先（完整类型、基础类型依次匹配），否则按默认规则
+func mapOracleType(c oracleColumn, overrides map[string]string) string {
+	length := oracleCharLength(c)
+	if oracleBaseType(c.DataType) == "RAW" {
+		length = c.DataLength
+	}
+	for _, key := range []string{oracleFullType(c), normalizeOracleTypeKey(c.DataType), oracleBaseType(c.DataType)} {
+		if target, ok := overrides[key]; ok {
+			return strings.NewReplacer(
+				"{p}", strconv.FormatInt(c.Precision.Int64, 10),
+				"{s}", strconv.FormatInt(c.Scale.Int64, 10),
+				"{len}", strconv.FormatInt(length, 10),
+			).Replace(target)
+		}
+	}
+
+	base := oracleBaseType(c.DataType)
+	switch {
+	case base == "NUMBER":
+		if !c.Precision.Valid {
+			if c.Scale.Valid && c.Scale.Int64 == 0 {
+				return "DECIMAL(38,0)"
+			}
+			return "DECIMAL(38,10)"
+		}
+		p, sc := c.Precision.Int64, c.Scale.Int64
+		if sc == 0 {
+			switch {
+			case p < 3:
+				return "TINYINT"
+			case p < 5:
+				return "SMALLINT"
+			case p < 10:
+				return "INT"
+			case p < 19:
+				return "BIGINT"
+			}
+		}
+		if sc < 0 {
+			sc = 0
+		}
+		if p > 65 {
+			p = 65
+		}
+		if sc > 30 {
+			sc = 30
+		}
+		if sc > p {
+			p = sc
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", p, sc)
+	case base == "FLOAT", base == "BINARY_DOUBLE":
+		return "DOUBLE"
+	case base == "BINARY_FLOAT":
+		return "FLOAT"
+	case base == "VARCHAR2", base == "NVARCHAR2":
+		return fmt.Sprintf("VARCHAR(%d)", length)
+	case base == "CHAR", base == "NCHAR":
+		if length > 255 {
+			return fmt.Sprintf("VARCHAR(%d)", length)
+		}
+		return fmt.Sprintf("CHAR(%d)", length)
+	case base == "DATE":
+		return "DATETIME"
+	case strings.HasPrefix(base, "TIMESTAMP"):
+		fsp := c.Scale.Int64
+		if !c.Scale.Valid {
+			fsp = 6
+		}
+		if fsp > 6 {
+			fsp = 6
+		}
+		if fsp <= 0 {
+			return "DATETIME"
+		}
+		return fmt.Sprintf("DATETIME(%d)", fsp)
+	case base == "CLOB", base == "NCLOB", base == "LONG", base == "XMLTYPE":
+		return "LONGTEXT"
+	case base == "BLOB", base == "LONG RAW":
+		return "LONGBLOB"
+	case base == "RAW":
+		return fmt.Sprintf("VARBINARY(%d)", length)
+	case base == "ROWID", base == "UROWID", strings.HasPrefix(base, "INTERVAL"):
+		return "VARCHAR(64)"
+	}
+	return "LONGTEXT"
+}
+
+// oracleSelectExpr 读取列时的转换表达式：数值用文本保留全部精度，日期时间转为 MySQL 格式，
+// 带时区的时间戳换算为 UTC，ROWID / INTERVAL / XMLTYPE 转为文本
+func oracleSelectExpr(c oracleColumn) string {
+	col := quoteOracleIdent(c.Name)
+	base := oracleBaseType(c.DataType)
+	switch {
+	case base == "NUMBER", base == "FLOAT":
+		return fmt.Sprintf("TO_CHAR(%s, 'TM9', 'NLS_NUMERIC_CHARACTERS=''.,''')", col)
+	case base == "DATE":
+		return fmt.Sprintf("TO_CHAR(%s, 'YYYY-MM-DD HH24:MI:SS')", col)
+	case base == "TIMESTAMP":
+		return fmt.Sprintf("TO_CHAR(%s, 'YYYY-MM-DD HH24:MI:SS.FF6')", col)
+	case base == "TIMESTAMP WITH TIME ZONE", base == "TIMESTAMP WITH LOCAL TIME ZONE":
+		return fmt.Sprintf("TO_CHAR(SYS_EXTRACT_UTC(%s), 'YYYY-MM-DD HH24:MI:SS.FF6')", col)
+	case base == "ROWID", base == "UROWID":
+		return fmt.Sprintf("ROWIDTOCHAR(%s)", col)
+	case strings.HasPrefix(base, "INTERVAL"):
+		return fmt.Sprintf("TO_CHAR(%s)", col)
+	case base == "XMLTYPE":
+		return fmt.Sprintf("XMLSERIALIZE(CONTENT %s AS CLOB)", col)
+	}
+	return col
+}
+
+var mysqlVarcharRe = regexp.MustCompile(`^(?i)(?:VARCHAR|CHAR)\((\d+)\)$`)
+
+// maxRowVarcharBytes 单行内联列的字节上限（InnoDB 65535，预留部分给其他列）
+const maxRowVarcharBytes = 60000
+
+// fitRowSize 按 utf8mb4 估算单行长度，超过 MySQL 行长限制时把最长的 VARCHAR 列改为 TEXT
+func fitRowSize(types []string) {
+	size := func(t string) int64 {
+		m := mysqlVarcharRe.FindStringSubmatch(strings.TrimSpace(t))
+		if m == nil {
+			return 0
+		}
+		n, _ := strconv.ParseInt(m[1], 10, 64)
+		return n*4 + 2
+	}
+	for {
+		var total int64
+		longest := -1
+		for i, t := range types {
+			n := size(t)
+			total += n
+			if n > 0 && (longest < 0 || n > size(types[longest])) {
+				longest = i
+			}
+		}
+		if total <= maxRowVarcharBytes || longest < 0 {
+			return
+		}
+		types[longest] = "TEXT"
+	}
+}
+
+// keyPrefix 索引列为 TEXT / BLOB 或过长的 VARCHAR 时需要指定前缀长度
+func keyPrefix(mysqlType string) string {
+	t := strings.ToUpper(strings.TrimSpace(mysqlType))
+	if strings.HasSuffix(t, "TEXT") || strings.HasSuffix(t, "BLOB") {
+		return "(255)"
+	}
+	if m := mysqlVarcharRe.FindStringSubmatch(t); m != nil {
+		if n, _ := strconv.Atoi(m[1]); n > 768 {
+			return "(768)"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,883 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DBAccount 数据库账号或角色；MySQL 以 user@host 区分，Oracle 的 Host 为空
+type DBAccount struct {
+	User              string `json:"user"`
+	Host              string `json:"host"`
+	IsRole            bool   `json:"isRole"`
+	Locked            bool   `json:"locked"`
+	PasswordExpired   bool   `json:"passwordExpired"`
+	Plugin            string `json:"plugin"` // MySQL 认证插件
+	Status            string `json:"status"` // Oracle ACCOUNT_STATUS
+	DefaultTablespace string `json:"defaultTablespace"`
+	Created           string `json:"created"`
+}
+
+// PrivilegeEntry 一条权限；Level 为 global（Oracle 为系统权限）/ database / table / column
+type PrivilegeEntry struct {
+	Level     string   `json:"level"`
+	Schema    string   `json:"schema"`
+	Table     string   `json:"table"`
+	Columns   []string `json:"columns"`
+	Privilege string   `json:"privilege"`
+	Grantable bool     `json:"grantable"`
+}
+
+// AccountGrants 账号已有的授权：Grants 为可直接执行的 GRANT 语句，Privileges 为拆分后的明细
+type AccountGrants struct {
+	User       string           `json:"user"`
+	Host       string           `json:"host"`
+	Grants     []string         `json:"grants"`
+	Roles      []string         `json:"roles"`
+	Privileges []PrivilegeEntry `json:"privileges"`
+}
+
+// AccountChange 账号与权限变更
+// Action：createUser / dropUser / password / lock / unlock / grant / revoke / createRole / dropRole / grantRole / revokeRole
+type AccountChange struct {
+	Action            string   `json:"action"`
+	User              string   `json:"user"`
+	Host              string   `json:"host"`
+	Password          string   `json:"password"`
+	DefaultTablespace string   `json:"defaultTablespace"` // Oracle 建用户时的默认表空间
+	Cascade           bool     `json:"cascade"`           // Oracle 删除用户时一并删除其对象
+	Role              string   `json:"role"`
+	Level             string   `json:"level"`
+	Schema            string   `json:"schema"`
+	Table             string   `json:"table"`
+	Columns           []string `json:"columns"`
+	Privileges        []string `json:"privileges"`
+	WithGrantOption   bool     `json:"withGrantOption"` // 授予角色或 Oracle 系统权限时为 WITH ADMIN OPTION
+}
+
+// AccountChangeResult 生成的语句；密码以 ****** 代替
+type AccountChangeResult struct {
+	Statements []string `json:"statements"`
+	Script     string   `json:"script"`
+}
+
+// PrivilegeMatrix 某个库的权限矩阵：每行为一个账号在某一级别（全局、库、表、列）上的权限
+type PrivilegeMatrix struct {
+	Schema     string               `json:"schema"`
+	Privileges []string             `json:"privileges"`
+	Rows       []PrivilegeMatrixRow `json:"rows"`
+}
+
+// PrivilegeMatrixRow 权限矩阵的一行；Privileges 为 权限 → 是否可转授
+type PrivilegeMatrixRow struct {
+	User       string          `json:"user"`
+	Host       string          `json:"host"`
+	Level      string          `json:"level"`
+	Object     string          `json:"object"`
+	Privileges map[string]bool `json:"privileges"`
+}
+
+// accountPrivilege 带授权对象的权限明细
+type accountPrivilege struct {
+	User string
+	Host string
+	PrivilegeEntry
+}
+
+const passwordMask = "******"
+
+var privilegeNameRe = regexp.MustCompile(`^[A-Za-z_]+( [A-Za-z_]+)*$`)
+
+var mysqlLevelPrivileges = map[string][]string{
+	"global": {"SELECT", "INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "RELOAD", "SHUTDOWN", "PROCESS", "FILE",
+		"REFERENCES", "INDEX", "ALTER", "SHOW DATABASES", "SUPER", "CREATE TEMPORARY TABLES", "LOCK TABLES", "EXECUTE",
+		"REPLICATION SLAVE", "REPLICATION CLIENT", "CREATE VIEW", "SHOW VIEW", "CREATE ROUTINE", "ALTER ROUTINE",
+		"CREATE USER", "EVENT", "TRIGGER", "CREATE TABLESPACE"},
+	"database": {"SELECT", "INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "ALTER", "INDEX", "REFERENCES",
+		"CREATE TEMPORARY TABLES", "LOCK TABLES", "CREATE VIEW", "SHOW VIEW", "CREATE ROUTINE", "ALTER ROUTINE",
+		"EXECUTE", "EVENT", "TRIGGER"},
+	"table":  {"SELECT", "INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "ALTER", "INDEX", "REFERENCES", "CREATE VIEW", "SHOW VIEW", "TRIGGER"},
+	"column": {"SELECT", "INSERT", "UPDATE", "REFERENCES"},
+}
+
+var oracleLevelPrivileges = map[string][]string{
+	"table":  {"SELECT", "INSERT", "UPDATE", "DELETE", "ALTER", "INDEX", "REFERENCES", "EXECUTE", "READ", "DEBUG", "FLASHBACK", "ON COMMIT REFRESH", "QUERY REWRITE"},
+	"column": {"INSERT", "UPDATE", "REFERENCES"},
+}
+
+// ListAccounts 列出账号与角色
+func (a *App) ListAccounts() ([]DBAccount, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("数据库未连接")
+	}
+	if a.currentDBType == "oracle" {
+		return listOracleAccounts(a.db)
+	}
+	return listMySQLAccounts(a.db)
+}
+
+// GetAccountGrants 读取账号（或角色）已有的授权
+func (a *App) GetAccountGrants(user string, host string) (AccountGrants, error) {
+	if a.db == nil {
+		return AccountGrants{}, fmt.Errorf("数据库未连接")
+	}
+	if a.currentDBType == "oracle" {
+		return oracleAccountGrants(a.db, oracleObjectName(user))
+	}
+	return mysqlAccountGrants(a.db, user, accountHost(host))
+}
+
+// ListGrantablePrivileges 某一级别可授予的权限；MySQL 全局级取自 SHOW PRIVILEGES，Oracle 全局级为系统权限
+func (a *App) ListGrantablePrivileges(level string) ([]string, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("数据库未连接")
+	}
+	if a.currentDBType == "oracle" {
+		if level == "global" {
+			return queryStrings(a.db, "SELECT NAME FROM SYSTEM_PRIVILEGE_MAP ORDER BY NAME")
+		}
+		if list, ok := oracleLevelPrivileges[level]; ok {
+			return list, nil
+		}
+		return nil, fmt.Errorf("Oracle 不支持按 %s 级别授权", level)
+	}
+	list, ok := mysqlLevelPrivileges[level]
+	if !ok {
+		return nil, fmt.Errorf("未知的权限级别: %s", level)
+	}
+	if level != "global" {
+		return list, nil
+	}
+	rows, err := a.db.Query("SHOW PRIVILEGES")
+	if err != nil {
+		return list, nil
+	}
+	defer rows.Close()
+	cols, _ := rows.Columns()
+	var out []string
+	for rows.Next() {
+		vals := make([]sql.NullString, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return list, nil
+		}
+		name := strings.ToUpper(vals[0].String)
+		if name != "" && name != "USAGE" && name != "PROXY" && name != "GRANT OPTION" {
+			out = append(out, name)
+		}
+	}
+	if len(out) == 0 {
+		return list, nil
+	}
+	return out, nil
+}
+
+// PreviewAccountChange 生成变更语句，密码不回显
+func (a *App) PreviewAccountChange(change AccountChange) (AccountChangeResult, error) {
+	if a.db == nil {
+		return AccountChangeResult{}, fmt.Errorf("数据库未连接")
+	}
+	stmts, err := accountStatements(a.currentDBType, change, true)
+	if err != nil {
+		return AccountChangeResult{}, err
+	}
+	return accountResult(stmts), nil
+}
+
+// ApplyAccountChange 执行账号与权限变更；生产环境需确认，审计中的密码以 ****** 代替
+func (a *App) ApplyAccountChange(change AccountChange, confirmToken string) (res AccountChangeResult, err error) {
+	if a.db == nil {
+		return AccountChangeResult{}, fmt.Errorf("数据库未连接")
+	}
+	masked, err := accountStatements(a.currentDBType, change, true)
+	if err != nil {
+		return AccountChangeResult{}, err
+	}
+	stmts, _ := accountStatements(a.currentDBType, change, false)
+	res = accountResult(masked)
+	start := time.Now()
+	defer func() {
+		a.auditStatement("account", res.Script, start, 0, err)
+	}()
+	// 校验使用与预览一致的脱敏语句，确认令牌才能匹配
+	if _, err := a.guardQuery(strings.Join(masked, ";\n"), confirmToken); err != nil {
+		return res, err
+	}
+	for i, stmt := range stmts {
+		if _, err := a.db.Exec(stmt); err != nil {
+			if i > 0 {
+				return res, fmt.Errorf("第 %d 条语句执行失败（之前的 %d 条已生效）: %v", i+1, i, err)
+			}
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// GetPrivilegeMatrix 库级权限矩阵：MySQL 含全局、库、表、列权限，Oracle 含该 schema 下对象的授权
+func (a *App) GetPrivilegeMatrix(schema string) (PrivilegeMatrix, error) {
+	if a.db == nil {
+		return PrivilegeMatrix{}, fmt.Errorf("数据库未连接")
+	}
+	if schema == "" {
+		schema = a.currentSchema
+	}
+	if schema == "" {
+		return PrivilegeMatrix{}, fmt.Errorf("请先选择数据库")
+	}
+	var privs []accountPrivilege
+	var base []string
+	var err error
+	if a.currentDBType == "oracle" {
+		schema = strings.ToUpper(schema)
+		privs, err = oraclePrivileges(a.db, "", schema)
+		base = oracleLevelPrivileges["table"]
+	} else {
+		privs, err = mysqlPrivileges(a.db, "", schema)
+		base = mysqlLevelPrivileges["database"]
+	}
+	if err != nil {
+		return PrivilegeMatrix{}, err
+	}
+	return buildPrivilegeMatrix(schema, base, privs), nil
+}
+
+func accountResult(stmts []string) AccountChangeResult {
+	res := AccountChangeResult{Statements: stmts}
+	if len(stmts) > 0 {
+		res.Script = strings.Join(stmts, ";\n") + ";\n"
+	}
+	return res
+}
+
+func accountHost(host string) string {
+	if strings.TrimSpace(host) == "" {
+		return "%"
+	}
+	return strings.TrimSpace(host)
+}
+
+// mysqlAccount 'user'@'host'
+func mysqlAccount(user, host string) string {
+	return "'" + escapeSQLString(user) + "'@'" + escapeSQLString(accountHost(host)) + "'"
+}
+
+// mysqlRole 角色名可写作 name 或 name@host，省略 host 时为 %
+func mysqlRole(role string) string {
+	if i := strings.LastIndex(role, "@"); i > 0 {
+		return mysqlAccount(role[:i], role[i+1:])
+	}
+	return mysqlAccount(role, "%")
+}
+
+// normalizePrivileges 校验权限名，只允许字母、下划线与单个空格，统一为大写
+func normalizePrivileges(privs []string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, p := range privs {
+		p = strings.ToUpper(strings.Join(strings.Fields(p), " "))
+		if p == "" || seen[p] {
+			continue
+		}
+		if !privilegeNameRe.MatchString(p) {
+			return nil, fmt.Errorf("无效的权限名: %s", p)
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("请选择要授予或回收的权限")
+	}
+	return out, nil
+}
+
+// accountStatements 按方言生成变更语句；masked 为 true 时密码以 ****** 代替
+func accountStatements(dialect string, c AccountChange, masked bool) ([]string, error) {
+	c.User = strings.TrimSpace(c.User)
+	c.Role = strings.TrimSpace(c.Role)
+	needUser := c.Action != "createRole" && c.Action != "dropRole"
+	if needUser && c.User == "" {
+		return nil, fmt.Errorf("用户名不能为空")
+	}
+	if (c.Action == "createUser" || c.Action == "password") && c.Password == "" {
+		return nil, fmt.Errorf("密码不能为空")
+	}
+	switch c.Action {
+	case "createRole", "dropRole", "grantRole", "revokeRole":
+		if c.Role == "" {
+			return nil, fmt.Errorf("角色名不能为空")
+		}
+	}
+	if dialect == "oracle" {
+		return oracleAccountStatements(c, masked)
+	}
+	return mysqlAccountStatements(c, masked)
+}
+
+func mysqlAccountStatements(c AccountChange, masked bool) ([]string, error) {
+	account := mysqlAccount(c.User, c.Host)
+	password := "'" + escapeSQLString(c.Password) + "'"
+	if masked {
+		password = "'" + passwordMask + "'"
+	}
+	switch c.Action {
+	case "createUser":
+		return []string{fmt.Sprintf("CREATE USER %s IDENTIFIED BY %s", account, password)}, nil
+	case "dropUser":
+		return []string{"DROP USER " + account}, nil
+	case "password":
+		return []string{fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", account, password)}, nil
+	case "lock":
+		return []string{fmt.Sprintf("ALTER USER %s ACCOUNT LOCK", account)}, nil
+	case "unlock":
+		return []string{fmt.Sprintf("ALTER USER %s ACCOUNT UNLOCK", account)}, nil
+	case "createRole":
+		return []string{"CREATE ROLE " + mysqlRole(c.Role)}, nil
+	case "dropRole":
+		return []string{"DROP ROLE " + mysqlRole(c.Role)}, nil
+	case "grantRole":
+		stmt := fmt.Sprintf("GRANT %s TO %s", mysqlRole(c.Role), account)
+		if c.WithGrantOption {
+			stmt += " WITH ADMIN OPTION"
+		}
+		return []string{stmt}, nil
+	case "revokeRole":
+		return []string{fmt.Sprintf("REVOKE %s FROM %s", mysqlRole(c.Role), account)}, nil
+	case "grant", "revoke":
+		privs, err := normalizePrivileges(c.Privileges)
+		if err != nil {
+			return nil, err
+		}
+		quote := func(name string) string { return "`" + strings.ReplaceAll(name, "`", "``") + "`" }
+		var target string
+		switch c.Level {
+		case "global":
+			target = "*.*"
+		case "database":
+			if c.Schema == "" {
+				return nil, fmt.Errorf("请选择数据库")
+			}
+			target = quote(c.Schema) + ".*"
+		case "table", "column":
+			if c.Schema == "" || c.Table == "" {
+				return nil, fmt.Errorf("请选择数据库和表")
+			}
+			target = quote(c.Schema) + "." + quote(c.Table)
+		default:
+			return nil, fmt.Errorf("未知的权限级别: %s", c.Level)
+		}
+		if c.Level == "column" {
+			if len(c.Columns) == 0 {
+				return nil, fmt.Errorf("请选择列")
+			}
+			cols := make([]string, len(c.Columns))
+			for i, col := range c.Columns {
+				cols[i] = quote(col)
+			}
+			for i, p := range privs {
+				privs[i] = p + " (" + strings.Join(cols, ", ") + ")"
+			}
+		}
+		if c.Action == "revoke" {
+			if c.WithGrantOption {
+				privs = append(privs, "GRANT OPTION")
+			}
+			return []string{fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(privs, ", "), target, account)}, nil
+		}
+		stmt := fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(privs, ", "), target, account)
+		if c.WithGrantOption {
+			stmt += " WITH GRANT OPTION"
+		}
+		return []string{stmt}, nil
+	}
+	return nil, fmt.Errorf("未知的操作: %s", c.Action)
+}
+
+func oracleAccountStatements(c AccountChange, masked bool) ([]string, error) {
+	user := quoteOracleIdent(oracleObjectName(c.User))
+	role := quoteOracleIdent(oracleObjectName(c.Role))
+	if strings.Contains(c.Password, `"`) {
+		return nil, fmt.Errorf("Oracle 密码不能包含双引号")
+	}
+	password := `"` + c.Password + `"`
+	if masked {
+		password = `"` + passwordMask + `"`
+	}
+	switch c.Action {
+	case "createUser":
+		stmt := fmt.Sprintf("CREATE USER %s IDENTIFIED BY %s", user, password)
+		if ts := strings.TrimSpace(c.DefaultTablespace); ts != "" {
+			ts = quoteOracleIdent(oracleObjectName(ts))
+			stmt += fmt.Sprintf(" DEFAULT TABLESPACE %s QUOTA UNLIMITED ON %s", ts, ts)
+		}
+		return []string{stmt}, nil
+	case "dropUser":
+		stmt := "DROP USER " + user
+		if c.Cascade {
+			stmt += " CASCADE"
+		}
+		return []string{stmt}, nil
+	case "password":
+		return []string{fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", user, password)}, nil
+	case "lock":
+		return []string{fmt.Sprintf("ALTER USER %s ACCOUNT LOCK", user)}, nil
+	case "unlock":
+		return []string{fmt.Sprintf("ALTER USER %s ACCOUNT UNLOCK", user)}, nil
+	case "createRole":
+		return []string{"CREATE ROLE " + role}, nil
+	case "dropRole":
+		return []string{"DROP ROLE " + role}, nil
+	case "grantRole":
+		stmt := fmt.Sprintf("GRANT %s TO %s", role, user)
+		if c.WithGrantOption {
+			stmt += " WITH ADMIN OPTION"
+		}
+		return []string{stmt}, nil
+	case "revokeRole":
+		return []string{fmt.Sprintf("REVOKE %s FROM %s", role, user)}, nil
+	case "grant", "revoke":
+		privs, err := normalizePrivileges(c.Privileges)
+		if err != nil {
+			return nil, err
+		}
+		var target string
+		switch c.Level {
+		case "global":
+			// 系统权限
+		case "table", "column":
+			if c.Schema == "" || c.Table == "" {
+				return nil, fmt.Errorf("请选择 schema 和对象")
+			}
+			target = " ON " + quoteOracleIdent(oracleObjectName(c.Schema)) + "." + quoteOracleIdent(oracleObjectName(c.Table))
+		default:
+			return nil, fmt.Errorf("Oracle 不支持按 %s 级别授权，请按对象授权", c.Level)
+		}
+		if c.Level == "column" {
+			if c.Action == "revoke" {
+				return nil, fmt.Errorf("Oracle 不支持按列回收权限，请回收整个对象上的该权限")
+			}
+			if len(c.Columns) == 0 {
+				return nil, fmt.Errorf("请选择列")
+			}
+			cols := make([]string, len(c.Columns))
+			for i, col := range c.Columns {
+				cols[i] = quoteOracleIdent(oracleObjectName(col))
+			}
+			for i, p := range privs {
+				privs[i] = p + " (" + strings.Join(cols, ", ") + ")"
+			}
+		}
+		if c.Action == "revoke" {
+			return []string{fmt.Sprintf("REVOKE %s%s FROM %s", strings.Join(privs, ", "), target, user)}, nil
+		}
+		stmt := fmt.Sprintf("GRANT %s%s TO %s", strings.Join(privs, ", "), target, user)
+		if c.WithGrantOption {
+			if c.Level == "global" {
+				stmt += " WITH ADMIN OPTION"
+			} else {
+				stmt += " WITH GRANT OPTION"
+			}
+		}
+		return []string{stmt}, nil
+	}
+	return nil, fmt.Errorf("未知的操作: %s", c.Action)
+}
+
+func queryStrings(db *sql.DB, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var s sql.NullString
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		out = append(out, s.String)
+	}
+	return out, rows.Err()
+}
+
+// listMySQLAccounts MySQL 8 的角色是无密码且锁定、密码过期的账号，或出现在 role_edges 中的授予方
+func listMySQLAccounts(db *sql.DB) ([]DBAccount, error) {
+	rows, err := db.Query("SELECT User, Host, account_locked, password_expired, plugin, authentication_string FROM mysql.user ORDER BY User, Host")
+	if err != nil {
+		// 5.6 没有 account_locked
+		rows, err = db.Query("SELECT User, Host, 'N', password_expired, plugin, '' FROM mysql.user ORDER BY User, Host")
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer rows.Close()
+	roles := map[string]bool{}
+	if edges, err := db.Query("SELECT DISTINCT FROM_USER, FROM_HOST FROM mysql.role_edges"); err == nil {
+		for edges.Next() {
+			var u, h string
+			if edges.Scan(&u, &h) == nil {
+				roles[u+"@"+h] = true
+			}
+		}
+		edges.Close()
+	}
+	var out []DBAccount
+	for rows.Next() {
+		var user, host, locked, expired, plugin, auth sql.NullString
+		if err := rows.Scan(&user, &host, &locked, &expired, &plugin, &auth); err != nil {
+			return nil, err
+		}
+		acc := DBAccount{
+			User:            user.String,
+			Host:            host.String,
+			Locked:          locked.String == "Y",
+			PasswordExpired: expired.String == "Y",
+			Plugin:          plugin.String,
+		}
+		acc.IsRole = roles[acc.User+"@"+acc.Host] || (acc.Locked && acc.PasswordExpired && auth.String == "")
+		out = append(out, acc)
+	}
+	return out, rows.Err()
+}
+
+func listOracleAccounts(db *sql.DB) ([]DBAccount, error) {
+	var out []DBAccount
+	rows, err := db.Query(`SELECT USERNAME, ACCOUNT_STATUS, DEFAULT_TABLESPACE, TO_CHAR(CREATED, 'YYYY-MM-DD HH24:MI:SS')
+		FROM DBA_USERS ORDER BY USERNAME`)
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var user, status, ts, created sql.NullString
+			if err := rows.Scan(&user, &status, &ts, &created); err != nil {
+				return nil, err
+			}
+			out = append(out, DBAccount{
+				User:              user.String,
+				Status:            status.String,
+				Locked:            strings.Contains(status.String, "LOCKED"),
+				PasswordExpired:   strings.Contains(status.String, "EXPIRED"),
+				DefaultTablespace: ts.String,
+				Created:           created.String,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		// 没有 DBA 视图权限时只能看到用户名
+		rows, err := db.Query("SELECT USERNAME, TO_CHAR(CREATED, 'YYYY-MM-DD HH24:MI:SS') FROM ALL_USERS ORDER BY USERNAME")
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var user, created sql.NullString
+			if err := rows.Scan(&user, &created); err != nil {
+				return nil, err
+			}
+			out = append(out, DBAccount{User: user.String, Created: created.String})
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if roles, err := queryStrings(db, "SELECT ROLE FROM DBA_ROLES ORDER BY ROLE"); err == nil {
+		for _, r := range roles {
+			out = append(out, DBAccount{User: r, IsRole: true})
+		}
+	}
+	return out, nil
+}
+
+func mysqlAccountGrants(db *sql.DB, user, host string) (AccountGrants, error) {
+	g := AccountGrants{User: user, Host: host}
+	grants, err := queryStrings(db, "SHOW GRANTS FOR "+mysqlAccount(user, host))
+	if err != nil {
+		return g, err
+	}
+	g.Grants = grants
+	if roles, err := db.Query("SELECT FROM_USER, FROM_HOST FROM mysql.role_edges WHERE TO_USER = ? AND TO_HOST = ? ORDER BY FROM_USER", user, host); err == nil {
+		for roles.Next() {
+			var u, h string
+			if roles.Scan(&u, &h) == nil {
+				g.Roles = append(g.Roles, u+"@"+h)
+			}
+		}
+		roles.Close()
+	}
+	privs, err := mysqlPrivileges(db, mysqlAccount(user, host), "")
+	if err != nil {
+		return g, err
+	}
+	for _, p := range privs {
+		g.Privileges = append(g.Privileges, p.PrivilegeEntry)
+	}
+	return g, nil
+}
+
+// mysqlPrivileges 从 information_schema 读取权限明细；grantee 形如 'user'@'host'，schema 为空表示不限
+func mysqlPrivileges(db *sql.DB, grantee, schema string) ([]accountPrivilege, error) {
+	type source struct {
+		level, table, cols string
+	}
+	sources := []source{
+		{"global", "USER_PRIVILEGES", "''"},
+		{"database", "SCHEMA_PRIVILEGES", "TABLE_SCHEMA"},
+		{"table", "TABLE_PRIVILEGES", "TABLE_SCHEMA, TABLE_NAME"},
+		{"column", "COLUMN_PRIVILEGES", "TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME"},
+	}
+	var out []accountPrivilege
+	columnIndex := map[string]int{}
+	for _, src := range sources {
+		var conds []string
+		var args []interface{}
+		if grantee != "" {
+			conds = append(conds, "GRANTEE = ?")
+			args = append(args, grantee)
+		}
+		if schema != "" && src.level != "global" {
+			conds = append(conds, "TABLE_SCHEMA = ?")
+			args = append(args, schema)
+		}
+		query := fmt.Sprintf("SELECT GRANTEE, PRIVILEGE_TYPE, IS_GRANTABLE, %s FROM information_schema.%s", src.cols, src.table)
+		if len(conds) > 0 {
+			query += " WHERE " + strings.Join(conds, " AND ")
+		}
+		rows, err := db.Query(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var grantee, priv, grantable string
+			var s, t, c sql.NullString
+			dest := []interface{}{&grantee, &priv, &grantable}
+			switch src.level {
+			case "global", "database":
+				dest = append(dest, &s)
+			case "table":
+				dest = append(dest, &s, &t)
+			default:
+				dest = append(dest, &s, &t, &c)
+			}
+			if err := rows.Scan(dest...); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if priv == "USAGE" {
+				continue
+			}
+			user, host := splitGrantee(grantee)
+			if src.level == "global" {
+				s.String = ""
+			}
+			if src.level == "column" {
+				// 同一表上同一权限的多列合并为一条
+				key := strings.Join([]string{grantee, s.String, t.String, priv}, "\x00")
+				if i, ok := columnIndex[key]; ok {
+					out[i].Columns = append(out[i].Columns, c.String)
+					continue
+				}
+				columnIndex[key] = len(out)
+			}
+			p := accountPrivilege{User: user, Host: host, PrivilegeEntry: PrivilegeEntry{
+				Level: src.level, Schema: s.String, Table: t.String, Privilege: priv, Grantable: grantable == "YES",
+			}}
+			if c.Valid && c.String != "" {
+				p.Columns = []string{c.String}
+			}
+			out = append(out, p)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// splitGrantee 拆分 information_schema 中的 'user'@'host'
+func splitGrantee(grantee string) (string, string) {
+	if i := strings.LastIndex(grantee, "@"); i > 0 {
+		return strings.Trim(grantee[:i], "'"), strings.Trim(grantee[i+1:], "'")
+	}
+	return strings.Trim(grantee, "'"), ""
+}
+
+func oracleAccountGrants(db *sql.DB, user string) (AccountGrants, error) {
+	g := AccountGrants{User: user}
+	rows, err := db.Query("SELECT GRANTED_ROLE, ADMIN_OPTION FROM DBA_ROLE_PRIVS WHERE GRANTEE = :1 ORDER BY GRANTED_ROLE", user)
+	if err != nil {
+		return g, fmt.Errorf("读取授权需要 DBA 视图的访问权限: %v", err)
+	}
+	for rows.Next() {
+		var role, admin string
+		if err := rows.Scan(&role, &admin); err != nil {
+			rows.Close()
+			return g, err
+		}
+		g.Roles = append(g.Roles, role)
+		stmt := fmt.Sprintf("GRANT %s TO %s", quoteOracleIdent(role), quoteOracleIdent(user))
+		if admin == "YES" {
+			stmt += " WITH ADMIN OPTION"
+		}
+		g.Grants = append(g.Grants, stmt)
+	}
+	rows.Close()
+	privs, err := oraclePrivileges(db, user, "")
+	if err != nil {
+		return g, err
+	}
+	for _, p := range privs {
+		g.Privileges = append(g.Privileges, p.PrivilegeEntry)
+		stmt := "GRANT " + p.Privilege
+		if len(p.Columns) > 0 {
+			cols := make([]string, len(p.Columns))
+			for i, c := range p.Columns {
+				cols[i] = quoteOracleIdent(c)
+			}
+			stmt += " (" + strings.Join(cols, ", ") + ")"
+		}
+		if p.Level != "global" {
+			stmt += " ON " + quoteOracleIdent(p.Schema) + "." + quoteOracleIdent(p.Table)
+		}
+		stmt += " TO " + quoteOracleIdent(user)
+		if p.Grantable && p.Level == "global" {
+			stmt += " WITH ADMIN OPTION"
+		} else if p.Grantable {
+			stmt += " WITH GRANT OPTION"
+		}
+		g.Grants = append(g.Grants, stmt)
+	}
+	return g, nil
+}
+
+// oraclePrivileges 读取系统权限（仅指定用户时）、对象权限与列权限；user 与 owner 为空表示不限
+func oraclePrivileges(db *sql.DB, user, owner string) ([]accountPrivilege, error) {
+	var out []accountPrivilege
+	if user != "" {
+		rows, err := db.Query("SELECT PRIVILEGE, ADMIN_OPTION FROM DBA_SYS_PRIVS WHERE GRANTEE = :1 ORDER BY PRIVILEGE", user)
+		if err != nil {
+			return nil, fmt.Errorf("读取授权需要 DBA 视图的访问权限: %v", err)
+		}
+		for rows.Next() {
+			var priv, admin string
+			if err := rows.Scan(&priv, &admin); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			out = append(out, accountPrivilege{User: user, PrivilegeEntry: PrivilegeEntry{Level: "global", Privilege: priv, Grantable: admin == "YES"}})
+		}
+		rows.Close()
+	}
+	filter := func(base string) (string, []interface{}) {
+		var conds []string
+		var args []interface{}
+		if user != "" {
+			args = append(args, user)
+			conds = append(conds, fmt.Sprintf("GRANTEE = :%d", len(args)))
+		}
+		if owner != "" {
+			args = append(args, owner)
+			conds = append(conds, fmt.Sprintf("OWNER = :%d", len(args)))
+		}
+		if len(conds) > 0 {
+			base += " WHERE " + strings.Join(conds, " AND ")
+		}
+		return base, args
+	}
+	query, args := filter("SELECT GRANTEE, OWNER, TABLE_NAME, PRIVILEGE, GRANTABLE FROM DBA_TAB_PRIVS")
+	rows, err := db.Query(query+" ORDER BY GRANTEE, OWNER, TABLE_NAME, PRIVILEGE", args...)
+	if err != nil {
+		return nil, fmt.Errorf("读取授权需要 DBA 视图的访问权限: %v", err)
+	}
+	for rows.Next() {
+		var grantee, o, table, priv, grantable string
+		if err := rows.Scan(&grantee, &o, &table, &priv, &grantable); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		out = append(out, accountPrivilege{User: grantee, PrivilegeEntry: PrivilegeEntry{
+			Level: "table", Schema: o, Table: table, Privilege: priv, Grantable: grantable == "YES",
+		}})
+	}
+	rows.Close()
+	query, args = filter("SELECT GRANTEE, OWNER, TABLE_NAME, COLUMN_NAME, PRIVILEGE, GRANTABLE FROM DBA_COL_PRIVS")
+	rows, err = db.Query(query+" ORDER BY GRANTEE, OWNER, TABLE_NAME, PRIVILEGE, COLUMN_NAME", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columnIndex := map[string]int{}
+	for rows.Next() {
+		var grantee, o, table, col, priv, grantable string
+		if err := rows.Scan(&grantee, &o, &table, &col, &priv, &grantable); err != nil {
+			return nil, err
+		}
+		key := strings.Join([]string{grantee, o, table, priv}, "\x00")
+		if i, ok := columnIndex[key]; ok {
+			out[i].Columns = append(out[i].Columns, col)
+			continue
+		}
+		columnIndex[key] = len(out)
+		out = append(out, accountPrivilege{User: grantee, PrivilegeEntry: PrivilegeEntry{
+			Level: "column", Schema: o, Table: table, Columns: []string{col}, Privilege: priv, Grantable: grantable == "YES",
+		}})
+	}
+	return out, rows.Err()
+}
+
+// buildPrivilegeMatrix 按 账号 + 级别 + 对象 汇总；列为 base 中的常用权限，再追加实际出现的其余权限
+func buildPrivilegeMatrix(schema string, base []string, privs []accountPrivilege) PrivilegeMatrix {
+	m := PrivilegeMatrix{Schema: schema, Privileges: append([]string{}, base...)}
+	known := map[string]bool{}
+	for _, p := range base {
+		known[p] = true
+	}
+	levelOrder := map[string]int{"global": 0, "database": 1, "table": 2, "column": 3}
+	index := map[string]int{}
+	for _, p := range privs {
+		if !known[p.Privilege] {
+			known[p.Privilege] = true
+			m.Privileges = append(m.Privileges, p.Privilege)
+		}
+		object := "*"
+		switch p.Level {
+		case "database":
+			object = p.Schema
+		case "table":
+			object = p.Table
+		case "column":
+			object = p.Table + "." + strings.Join(p.Columns, ",")
+		}
+		key := strings.Join([]string{p.User, p.Host, p.Level, object}, "\x00")
+		i, ok := index[key]
+		if !ok {
+			i = len(m.Rows)
+			index[key] = i
+			m.Rows = append(m.Rows, PrivilegeMatrixRow{User: p.User, Host: p.Host, Level: p.Level, Object: object, Privileges: map[string]bool{}})
+		}
+		m.Rows[i].Privileges[p.Privilege] = m.Rows[i].Privileges[p.Privilege] || p.Grantable
+	}
+	sort.SliceStable(m.Rows, func(i, j int) bool {
+		a, b := m.Rows[i], m.Rows[j]
+		if a.User != b.User {
+			return a.User < b.User
+		}
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if levelOrder[a.Level] != levelOrder[b.Level] {
+			return levelOrder[a.Level] < levelOrder[b.Level]
+		}
+		return a.Object < b.Object
+	})
+	return m
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DumpTableInfo 备份文件中单张表的统计
+type DumpTableInfo struct {
+	Database      string `json:"database"` // 所属库（备份中的 USE 语句），单库备份可能为空
+	Name          string `json:"name"`
+	SchemaBytes   int64  `json:"schemaBytes"`
+	DataBytes     int64  `json:"dataBytes"`
+	Inserts       int64  `json:"inserts"`
+	EstimatedRows int64  `json:"estimatedRows"`
+}
+
+// DumpScanResult 备份文件扫描结果
+type DumpScanResult struct {
+	Path       string          `json:"path"`
+	Databases  []string        `json:"databases"`
+	Tables     []DumpTableInfo `json:"tables"`
+	OtherBytes int64           `json:"otherBytes"`
+	Statements int64           `json:"statements"`
+}
+
+// RestoreOptions 选择性恢复选项；Tables 为空表示恢复全部表
+type RestoreOptions struct {
+	Database   string   `json:"database"` // 要恢复的源库，备份包含多个库时必填，其余库的语句全部跳过
+	Tables     []string `json:"tables"`   // Database 中的表名
+	KeepSchema bool     `json:"keepSchema"`
+	KeepData   bool     `json:"keepData"`
+	ImportOptions
+}
+
+var (
+	useDatabaseRe    = regexp.MustCompile("(?i)^USE\\s+`?([^`;\\s]+)`?")
+	createDatabaseRe = regexp.MustCompile("(?i)^CREATE\\s+(?:DATABASE|SCHEMA)(?:\\s+IF\\s+NOT\\s+EXISTS)?\\s+`?([^`;\\s]+)`?")
+	conditionalRe    = regexp.MustCompile(`^/\*!\d*\s*`)
+	otherDDLRe       = regexp.MustCompile(`(?i)^(CREATE|DROP|ALTER)\b`)
+)
+
+// unwrapConditional 去掉 mysqldump 的版本条件注释外壳，返回其中的语句
+func unwrapConditional(stmt string) string {
+	text := stmt
+	if m := conditionalRe.FindString(text); m != "" {
+		text = strings.TrimSuffix(strings.TrimSpace(text[len(m):]), "*/")
+		text = strings.TrimSpace(text)
+	}
+	return text
+}
+
+// dumpDatabaseName 返回建库或切库语句中的库名，use 表示是否为切库语句
+func dumpDatabaseName(stmt string) (name string, use bool) {
+	text := unwrapConditional(stmt)
+	if m := useDatabaseRe.FindStringSubmatch(text); len(m) > 1 {
+		return m[1], true
+	}
+	if m := createDatabaseRe.FindStringSubmatch(text); len(m) > 1 {
+		return m[1], false
+	}
+	return "", false
+}
+
+// classifyDumpStatement 判断备份中一条语句的类型与所属表：
+// schema（建表/删表/改表）、data（插入、锁表、禁用索引）、database（建库/切库）、ddl（视图、存储过程等）、session（其余 SET 等会话语句）
+func classifyDumpStatement(stmt string) (kind string, table string) {
+	text := unwrapConditional(stmt)
+	if useDatabaseRe.MatchString(text) || createDatabaseRe.MatchString(text) {
+		return "database", ""
+	}
+	upper := strings.ToUpper(text)
+	if strings.HasPrefix(upper, "UNLOCK TABLES") {
+		return "data", ""
+	}
+	if name := extractTableName(text); name != "" {
+		switch {
+		case strings.HasPrefix(upper, "INSERT"), strings.HasPrefix(upper, "REPLACE"), strings.HasPrefix(upper, "LOCK"):
+			return "data", name
+		case strings.HasPrefix(upper, "ALTER") && (strings.Contains(upper, "DISABLE KEYS") || strings.Contains(upper, "ENABLE KEYS")):
+			return "data", name
+		default:
+			return "schema", name
+		}
+	}
+	if otherDDLRe.MatchString(text) {
+		return "ddl", ""
+	}
+	return "session", ""
+}
+
+// estimateInsertRows 粗略估算一条扩展插入语句包含的行数
+func estimateInsertRows(stmt string) int64 {
+	return int64(strings.Count(stmt, "),(")) + 1
+}
+
+// ScanDumpFile 流式扫描备份文件，列出其中包含的表及结构、数据大小
+func (a *App) ScanDumpFile(path string) (DumpScanResult, error) {
+	result := DumpScanResult{Path: path, Databases: []string{}, Tables: []DumpTableInfo{}}
+	if path == "" {
+		return result, fmt.Errorf("请选择备份文件")
+	}
+	src, _, _, closeSrc, err := openSQLSource(path)
+	if err != nil {
+		return result, err
+	}
+	defer closeSrc()
+
+	idx := map[string]int{}
+	seenDB := map[string]bool{}
+	current, currentDB := "", ""
+	reader := newSQLStatementReader(src)
+	for {
+		stmt, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("读取文件失败: %v", err)
+		}
+		result.Statements++
+		size := int64(len(stmt.SQL))
+		kind, table := classifyDumpStatement(stmt.SQL)
+		if kind == "database" {
+			name, use := dumpDatabaseName(stmt.SQL)
+			if name != "" && !seenDB[name] {
+				seenDB[name] = true
+				result.Databases = append(result.Databases, name)
+			}
+			if use {
+				currentDB, current = name, ""
+			}
+		}
+		if table != "" {
+			current = table
+		}
+		if table == "" || (kind != "schema" && kind != "data") {
+			result.OtherBytes += size
+			continue
+		}
+		// 不同库中的同名表分别统计
+		key := currentDB + "." + current
+		i, ok := idx[key]
+		if !ok {
+			i = len(result.Tables)
+			idx[key] = i
+			result.Tables = append(result.Tables, DumpTableInfo{Database: currentDB, Name: current})
+		}
+		info := &result.Tables[i]
+		if kind == "schema" {
+			info.SchemaBytes += size
+			continue
+		}
+		info.DataBytes += size
+		if upper := strings.ToUpper(stmt.SQL[:min(len(stmt.SQL), 16)]); strings.HasPrefix(upper, "INSERT") || strings.HasPrefix(upper, "REPLACE") {
+			info.Inserts++
+			info.EstimatedRows += estimateInsertRows(stmt.SQL)
+		}
+	}
+	return result, nil
+}
+
+// dumpRestoreFilter 按所选库、表和结构/数据选项过滤备份语句；
+// 建库与切库语句一律跳过，所选源库的语句恢复到连接的默认库。
+// 切库语句之前的语句视为属于备份的默认库（单库备份没有切库语句）
+type dumpRestoreFilter struct {
+	database   string
+	tables     map[string]bool
+	keepSchema bool
+	keepData   bool
+	currentDB  string
+	current    string
+}
+
+func newDumpRestoreFilter(opts RestoreOptions) *dumpRestoreFilter {
+	f := &dumpRestoreFilter{database: opts.Database, keepSchema: opts.KeepSchema, keepData: opts.KeepData}
+	if len(opts.Tables) > 0 {
+		f.tables = make(map[string]bool, len(opts.Tables))
+		for _, t := range opts.Tables {
+			f.tables[t] = true
+		}
+	}
+	return f
+}
+
+func (f *dumpRestoreFilter) selected(table string) bool {
+	return f.tables == nil || f.tables[table]
+}
+
+// inDatabase 判断当前语句是否属于所选源库
+func (f *dumpRestoreFilter) inDatabase() bool {
+	return f.database == "" || f.currentDB == "" || f.currentDB == f.database
+}
+
+func (f *dumpRestoreFilter) Filter(stmt string) (string, bool) {
+	kind, table := classifyDumpStatement(stmt)
+	if kind == "database" {
+		if name, use := dumpDatabaseName(stmt); use {
+			f.currentDB = name
+			f.current = ""
+		}
+		return stmt, false
+	}
+	if kind != "session" && !f.inDatabase() {
+		return stmt, false
+	}
+	switch kind {
+	case "schema":
+		f.current = table
+		return stmt, f.keepSchema && f.selected(table)
+	case "data":
+		if table != "" {
+			f.current = table
+		}
+		return stmt, f.keepData && f.current != "" && f.selected(f.current)
+	case "ddl":
+		// 视图、触发器、存储过程等无法可靠归属到表，仅在恢复全部表结构时执行
+		return stmt, f.keepSchema && f.tables == nil
+	default:
+		return stmt, true
+	}
+}
+
+// RestoreDumpFile 从备份文件中选择性恢复表到目标库，作为后台任务执行并等待其结束
+func (a *App) RestoreDumpFile(cfg DBConfig, targetDB string, path string, opts RestoreOptions) (ImportResult, error) {
+	job, err := a.SubmitRestoreJob(cfg, targetDB, path, opts)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	res, err := a.jobs.wait(job.ID)
+	result, _ := res.(ImportResult)
+	return result, err
+}
+
+// SubmitRestoreJob 提交选择性恢复任务，立即返回任务信息
+func (a *App) SubmitRestoreJob(cfg DBConfig, targetDB string, path string, opts RestoreOptions) (Job, error) {
+	if normalizeDBType(cfg.Type) != "mysql" {
+		return Job{}, fmt.Errorf("当前连接类型暂不支持SQL恢复")
+	}
+	if targetDB == "" {
+		return Job{}, fmt.Errorf("目标库不能为空")
+	}
+	if err := checkWritable(cfg); err != nil {
+		return Job{}, err
+	}
+	if !opts.KeepSchema && !opts.KeepData {
+		return Job{}, fmt.Errorf("请至少选择恢复结构或数据")
+	}
+	if opts.KeepSchema {
+		if err := a.checkDestructiveTask(cfg, "restore", targetDB, "恢复表结构将删除并重建目标库中的同名表", opts.ConfirmToken); err != nil {
+			return Job{}, err
+		}
+	}
+	if _, err := os.Stat(path); err != nil {
+		return Job{}, fmt.Errorf("无法读取文件: %v", err)
+	}
+	if err := ensureDatabase(cfg, targetDB); err != nil {
+		return Job{}, err
+	}
+	cfg.Database = targetDB
+	title := fmt.Sprintf("恢复 %s → %s", filepath.Base(path), targetDB)
+	job := a.jobs.submit("restore", title, func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		if opts.Database == "" {
+			// 多库备份恢复到单个目标库时同名表会互相覆盖，必须指定源库
+			dbs, err := scanDumpDatabases(ctx, path)
+			if err != nil {
+				return ImportResult{}, err
+			}
+			if len(dbs) > 1 {
+				return ImportResult{}, fmt.Errorf("备份包含多个库（%s），请指定要恢复的源库", strings.Join(dbs, ", "))
+			}
+		}
+		filter := newDumpRestoreFilter(opts)
+		return a.runImportSqlFile(ctx, r, cfg, path, opts.ImportOptions, filter.Filter)
+	})
+	return job, nil
+}
+
+// scanDumpDatabases 列出备份中切换过的库
+func scanDumpDatabases(ctx context.Context, path string) ([]string, error) {
+	src, _, _, closeSrc, err := openSQLSource(path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSrc()
+
+	var dbs []string
+	seen := map[string]bool{}
+	reader := newSQLStatementReader(src)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		stmt, err := reader.Next()
+		if err == io.EOF {
+			return dbs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取文件失败: %v", err)
+		}
+		if name, use := dumpDatabaseName(stmt.SQL); use && !seen[name] {
+			seen[name] = true
+			dbs = append(dbs, name)
+		}
+	}
+}
+
+// ensureDatabase 确保目标库存在（恢复到新库名时自动创建）
+func ensureDatabase(cfg DBConfig, database string) error {
+	cfg.Database = ""
+	driver, dsn, err := buildDriverAndDSN(cfg)
+	if err != nil {
+		return err
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	_, err = db.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`", database))
+	return err
+}
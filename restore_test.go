@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func TestDumpRestoreFilterMultiDatabase(t *testing.T) {
+	dump := []string{
+		"SET NAMES utf8mb4",
+		"CREATE DATABASE /*!32312 IF NOT EXISTS*/ `a`",
+		"USE `a`",
+		"CREATE TABLE `t` (`id` int)",
+		"INSERT INTO `t` VALUES (1)",
+		"CREATE VIEW `v` AS SELECT 1",
+		"CREATE DATABASE /*!32312 IF NOT EXISTS*/ `b`",
+		"USE `b`",
+		"CREATE TABLE `t` (`id` int)",
+		"INSERT INTO `t` VALUES (2)",
+		"CREATE TABLE `u` (`id` int)",
+		"INSERT INTO `u` VALUES (3)",
+	}
+	tests := []struct {
+		name string
+		opts RestoreOptions
+		want []int
+	}{
+		{"source a", RestoreOptions{Database: "a", KeepSchema: true, KeepData: true}, []int{0, 3, 4, 5}},
+		{"source b", RestoreOptions{Database: "b", KeepSchema: true, KeepData: true}, []int{0, 8, 9, 10, 11}},
+		{"source b table t data", RestoreOptions{Database: "b", Tables: []string{"t"}, KeepData: true}, []int{0, 9}},
+		{"source b table u", RestoreOptions{Database: "b", Tables: []string{"u"}, KeepSchema: true, KeepData: true}, []int{0, 10, 11}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDumpRestoreFilter(tt.opts)
+			var got []int
+			for i, stmt := range dump {
+				if _, keep := f.Filter(stmt); keep {
+					got = append(got, i)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("kept %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("kept %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDumpRestoreFilterSingleDatabase(t *testing.T) {
+	// 单库备份没有切库语句，指定源库时也应全部恢复
+	f := newDumpRestoreFilter(RestoreOptions{Database: "a", KeepSchema: true, KeepData: true})
+	for _, stmt := range []string{"CREATE TABLE `t` (`id` int)", "INSERT INTO `t` VALUES (1)"} {
+		if _, keep := f.Filter(stmt); !keep {
+			t.Errorf("statement %q skipped", stmt)
+		}
+	}
+}
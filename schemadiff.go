@@ -0,0 +1,852 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SchemaDiffOptions 结构比对选项
+type SchemaDiffOptions struct {
+	IgnoreAutoIncrement bool `json:"ignoreAutoIncrement"`
+	IgnoreComments      bool `json:"ignoreComments"`
+	IgnoreCharset       bool `json:"ignoreCharset"`
+	IgnoreCollation     bool `json:"ignoreCollation"`
+}
+
+// SchemaDiffItem 一处结构差异；Action 为 create（目标缺少）/ drop（目标多出）/ alter（定义不同）
+type SchemaDiffItem struct {
+	ObjectType string `json:"objectType"` // table / column / index / foreign_key / table_option / view / procedure / function / trigger
+	Table      string `json:"table"`
+	Name       string `json:"name"`
+	Action     string `json:"action"`
+	Source     string `json:"source"` // 源库中的定义
+	Target     string `json:"target"` // 目标库中的定义
+}
+
+// SchemaDiffResult 结构比对结果；Script 为使目标库与源库一致的有序脚本
+type SchemaDiffResult struct {
+	SourceDB string           `json:"sourceDb"`
+	TargetDB string           `json:"targetDb"`
+	Items    []SchemaDiffItem `json:"items"`
+	Script   string           `json:"script"`
+}
+
+type schemaColumn struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	Default    sql.NullString
+	Extra      string
+	Charset    string
+	Collation  string
+	Comment    string
+	Generation string
+}
+
+type schemaIndex struct {
+	Name    string
+	Unique  bool
+	Kind    string // BTREE / FULLTEXT / SPATIAL ...
+	Columns []string
+}
+
+type schemaForeignKey struct {
+	Name       string
+	Columns    []string
+	RefSchema  string
+	RefTable   string
+	RefColumns []string
+	OnUpdate   string
+	OnDelete   string
+}
+
+type schemaTable struct {
+	Name          string
+	Engine        string
+	Collation     string
+	Comment       string
+	AutoIncrement sql.NullInt64
+	CreateSQL     string
+	Columns       []schemaColumn
+	Indexes       map[string]*schemaIndex
+	ForeignKeys   map[string]*schemaForeignKey
+}
+
+// schemaRoutine 视图、存储过程、函数、触发器
+type schemaRoutine struct {
+	Kind       string
+	Name       string
+	Table      string // 触发器所属表
+	Definition string // 用于比较的规范化定义
+	CreateSQL  string
+}
+
+type schemaModel struct {
+	Database string
+	Tables   map[string]*schemaTable
+	Objects  map[string]*schemaRoutine // key: kind + "/" + name
+}
+
+// CompareSchemas 比对两个MySQL库的结构（表、列、索引、外键、视图、存储过程、函数、触发器），
+// 返回差异列表以及使目标库与源库一致的 ALTER/CREATE/DROP 脚本
+func (a *App) CompareSchemas(source DBConfig, sourceDB string, target DBConfig, targetDB string, opts SchemaDiffOptions) (SchemaDiffResult, error) {
+	if normalizeDBType(source.Type) != "mysql" || normalizeDBType(target.Type) != "mysql" {
+		return SchemaDiffResult{}, fmt.Errorf("当前仅支持MySQL之间比对")
+	}
+	if sourceDB == "" || targetDB == "" {
+		return SchemaDiffResult{}, fmt.Errorf("源库和目标库不能为空")
+	}
+	source.Database = sourceDB
+	target.Database = targetDB
+	src, err := loadSchemaForConfig(source, sourceDB)
+	if err != nil {
+		return SchemaDiffResult{}, fmt.Errorf("读取源库结构失败: %v", err)
+	}
+	tgt, err := loadSchemaForConfig(target, targetDB)
+	if err != nil {
+		return SchemaDiffResult{}, fmt.Errorf("读取目标库结构失败: %v", err)
+	}
+	return diffSchemas(src, tgt, opts), nil
+}
+
+func loadSchemaForConfig(cfg DBConfig, database string) (*schemaModel, error) {
+	dsn, err := buildDSN(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return loadSchemaModel(db, database)
+}
+
+// loadSchemaModel 从 information_schema 读取库结构
+func loadSchemaModel(db *sql.DB, database string) (*schemaModel, error) {
+	m := &schemaModel{Database: database, Tables: map[string]*schemaTable{}, Objects: map[string]*schemaRoutine{}}
+
+	rows, err := db.Query(
+		`SELECT TABLE_NAME, IFNULL(ENGINE, ''), IFNULL(TABLE_COLLATION, ''), IFNULL(TABLE_COMMENT, ''), AUTO_INCREMENT
+		 FROM information_schema.TABLES
+		 WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'`,
+		database,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		t := &schemaTable{Indexes: map[string]*schemaIndex{}, ForeignKeys: map[string]*schemaForeignKey{}}
+		if err := rows.Scan(&t.Name, &t.Engine, &t.Collation, &t.Comment, &t.AutoIncrement); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		m.Tables[t.Name] = t
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.Query(
+		`SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, EXTRA,
+		        IFNULL(CHARACTER_SET_NAME, ''), IFNULL(COLLATION_NAME, ''), IFNULL(COLUMN_COMMENT, ''),
+		        IFNULL(GENERATION_EXPRESSION, '')
+		 FROM information_schema.COLUMNS
+		 WHERE TABLE_SCHEMA = ?
+		 ORDER BY TABLE_NAME, ORDINAL_POSITION`,
+		database,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var table, nullable string
+		var c schemaColumn
+		if err := rows.Scan(&table, &c.Name, &c.Type, &nullable, &c.Default, &c.Extra, &c.Charset, &c.Collation, &c.Comment, &c.Generation); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		c.Nullable = nullable == "YES"
+		if t, ok := m.Tables[table]; ok {
+			t.Columns = append(t.Columns, c)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.Query(
+		`SELECT TABLE_NAME, INDEX_NAME, NON_UNIQUE, IFNULL(INDEX_TYPE, ''), COLUMN_NAME, SUB_PART
+		 FROM information_schema.STATISTICS
+		 WHERE TABLE_SCHEMA = ?
+		 ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX`,
+		database,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var table, name, kind string
+		var nonUnique int
+		var column sql.NullString
+		var subPart sql.NullInt64
+		if err := rows.Scan(&table, &name, &nonUnique, &kind, &column, &subPart); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		t, ok := m.Tables[table]
+		if !ok {
+			continue
+		}
+		idx, ok := t.Indexes[name]
+		if !ok {
+			idx = &schemaIndex{Name: name, Unique: nonUnique == 0, Kind: kind}
+			t.Indexes[name] = idx
+		}
+		col := fmt.Sprintf("`%s`", column.String)
+		if !column.Valid {
+			// 函数索引（MySQL 8）无法从 STATISTICS 还原表达式
+			col = "(expr)"
+		}
+		if subPart.Valid {
+			col += fmt.Sprintf("(%d)", subPart.Int64)
+		}
+		idx.Columns = append(idx.Columns, col)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.Query(
+		`SELECT k.TABLE_NAME, k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME,
+		        k.REFERENCED_COLUMN_NAME, r.UPDATE_RULE, r.DELETE_RULE
+		 FROM information_schema.KEY_COLUMN_USAGE k
+		 JOIN information_schema.REFERENTIAL_CONSTRAINTS r
+		   ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME AND r.TABLE_NAME = k.TABLE_NAME
+		 WHERE k.TABLE_SCHEMA = ? AND k.REFERENCED_TABLE_NAME IS NOT NULL
+		 ORDER BY k.TABLE_NAME, k.CONSTRAINT_NAME, k.ORDINAL_POSITION`,
+		database,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var table, name, column, refSchema, refTable, refColumn, onUpdate, onDelete string
+		if err := rows.Scan(&table, &name, &column, &refSchema, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		t, ok := m.Tables[table]
+		if !ok {
+			continue
+		}
+		fk, ok := t.ForeignKeys[name]
+		if !ok {
+			fk = &schemaForeignKey{Name: name, RefSchema: refSchema, RefTable: refTable, OnUpdate: onUpdate, OnDelete: onDelete}
+			t.ForeignKeys[name] = fk
+		}
+		fk.Columns = append(fk.Columns, column)
+		fk.RefColumns = append(fk.RefColumns, refColumn)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for name, t := range m.Tables {
+		createSQL, err := showCreateTable(db, database, name)
+		if err != nil {
+			return nil, err
+		}
+		t.CreateSQL = createSQL
+	}
+
+	if err := loadSchemaObjects(db, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var definerRe = regexp.MustCompile("(?i)\\s*DEFINER\\s*=\\s*(`[^`]*`|'[^']*'|[^\\s@]+)@(`[^`]*`|'[^']*'|\\S+)")
+
+// stripDefiner 去掉 DEFINER 子句，避免不同实例的账号差异造成误报和执行失败
+func stripDefiner(s string) string {
+	return definerRe.ReplaceAllString(s, "")
+}
+
+// normalizeDefinition 去掉库名限定并压缩空白，用于比较
+func normalizeDefinition(def string, database string) string {
+	def = strings.ReplaceAll(def, "`"+database+"`.", "")
+	return strings.Join(strings.Fields(def), " ")
+}
+
+// showCreateValue 执行 SHOW CREATE ... 并按列名取值
+func showCreateValue(db *sql.DB, query string, column string) (string, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if !rows.Next() {
+		return "", rows.Err()
+	}
+	values := make([]sql.NullString, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return "", err
+	}
+	for i, c := range cols {
+		if strings.EqualFold(c, column) {
+			return values[i].String, nil
+		}
+	}
+	return "", fmt.Errorf("未找到列 %s", column)
+}
+
+// loadSchemaObjects 读取视图、存储过程、函数与触发器
+func loadSchemaObjects(db *sql.DB, m *schemaModel) error {
+	database := m.Database
+	rows, err := db.Query(`SELECT TABLE_NAME, IFNULL(VIEW_DEFINITION, '') FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ?`, database)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var name, def string
+		if err := rows.Scan(&name, &def); err != nil {
+			rows.Close()
+			return err
+		}
+		def = normalizeDefinition(def, database)
+		m.Objects["view/"+name] = &schemaRoutine{
+			Kind:       "view",
+			Name:       name,
+			Definition: def,
+			CreateSQL:  fmt.Sprintf("CREATE OR REPLACE VIEW `%s` AS %s", name, def),
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = db.Query(`SELECT ROUTINE_TYPE, ROUTINE_NAME FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = ?`, database)
+	if err != nil {
+		return err
+	}
+	var routines [][2]string
+	for rows.Next() {
+		var kind, name string
+		if err := rows.Scan(&kind, &name); err != nil {
+			rows.Close()
+			return err
+		}
+		routines = append(routines, [2]string{strings.ToLower(kind), name})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, rt := range routines {
+		kind, name := rt[0], rt[1]
+		column := "Create Procedure"
+		if kind == "function" {
+			column = "Create Function"
+		}
+		createSQL, err := showCreateValue(db, fmt.Sprintf("SHOW CREATE %s `%s`.`%s`", strings.ToUpper(kind), database, name), column)
+		if err != nil {
+			return err
+		}
+		createSQL = stripDefiner(createSQL)
+		m.Objects[kind+"/"+name] = &schemaRoutine{
+			Kind:       kind,
+			Name:       name,
+			Definition: normalizeDefinition(createSQL, database),
+			CreateSQL:  createSQL,
+		}
+	}
+
+	rows, err = db.Query(`SELECT TRIGGER_NAME, EVENT_OBJECT_TABLE FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = ?`, database)
+	if err != nil {
+		return err
+	}
+	var triggers [][2]string
+	for rows.Next() {
+		var name, table string
+		if err := rows.Scan(&name, &table); err != nil {
+			rows.Close()
+			return err
+		}
+		triggers = append(triggers, [2]string{name, table})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, tr := range triggers {
+		createSQL, err := showCreateValue(db, fmt.Sprintf("SHOW CREATE TRIGGER `%s`.`%s`", database, tr[0]), "SQL Original Statement")
+		if err != nil {
+			return err
+		}
+		createSQL = stripDefiner(createSQL)
+		m.Objects["trigger/"+tr[0]] = &schemaRoutine{
+			Kind:       "trigger",
+			Name:       tr[0],
+			Table:      tr[1],
+			Definition: normalizeDefinition(createSQL, database),
+			CreateSQL:  createSQL,
+		}
+	}
+	return nil
+}
+
+// columnCompareOptions 比较一对同名列时使用的选项：忽略字符集且两列字符集不同时，
+// 排序规则也无从比较（排序规则隐含字符集），一并忽略
+func columnCompareOptions(sc, tc schemaColumn, opts SchemaDiffOptions) SchemaDiffOptions {
+	if opts.IgnoreCharset && !opts.IgnoreCollation && columnCharset(sc) != columnCharset(tc) {
+		opts.IgnoreCollation = true
+	}
+	return opts
+}
+
+// modifyColumnDefinition 生成 MODIFY COLUMN 使用的列定义：被忽略的字符集、排序规则沿用目标列，
+// 避免因其他差异修改列时顺带转换了字符集
+func modifyColumnDefinition(sc, tc schemaColumn, copts SchemaDiffOptions) string {
+	c := sc
+	if copts.IgnoreCharset {
+		c.Charset = tc.Charset
+	}
+	if copts.IgnoreCollation && columnCharset(c) == columnCharset(tc) {
+		c.Collation = tc.Collation
+	}
+	copts.IgnoreCharset, copts.IgnoreCollation = false, false
+	return columnDefinition(c, copts)
+}
+
+// columnCharset 列的字符集，未单独记录时从排序规则推断
+func columnCharset(c schemaColumn) string {
+	if c.Charset != "" {
+		return c.Charset
+	}
+	return collationCharset(c.Collation)
+}
+
+// columnDefinition 生成列定义（不含列名与位置）
+func columnDefinition(c schemaColumn, opts SchemaDiffOptions) string {
+	var b strings.Builder
+	b.WriteString(c.Type)
+	if c.Charset != "" && !opts.IgnoreCharset {
+		b.WriteString(" CHARACTER SET " + c.Charset)
+	}
+	if c.Collation != "" && !opts.IgnoreCollation {
+		b.WriteString(" COLLATE " + c.Collation)
+	}
+	extra := strings.TrimSpace(strings.ReplaceAll(c.Extra, "DEFAULT_GENERATED", ""))
+	lowerExtra := strings.ToLower(extra)
+	if c.Generation != "" && strings.Contains(lowerExtra, "generated") {
+		kind := "VIRTUAL"
+		if strings.Contains(lowerExtra, "stored") {
+			kind = "STORED"
+		}
+		b.WriteString(fmt.Sprintf(" GENERATED ALWAYS AS (%s) %s", c.Generation, kind))
+		extra = ""
+	}
+	if c.Nullable {
+		b.WriteString(" NULL")
+	} else {
+		b.WriteString(" NOT NULL")
+	}
+	if c.Default.Valid {
+		b.WriteString(" DEFAULT " + columnDefaultSQL(c))
+	} else if c.Nullable && c.Generation == "" && !strings.Contains(strings.ToLower(c.Type), "blob") && !strings.Contains(strings.ToLower(c.Type), "text") {
+		b.WriteString(" DEFAULT NULL")
+	}
+	if extra != "" {
+		b.WriteString(" " + extra)
+	}
+	if c.Comment != "" && !opts.IgnoreComments {
+		b.WriteString(" COMMENT '" + escapeSQLString(c.Comment) + "'")
+	}
+	return b.String()
+}
+
+// columnDefaultSQL 默认值：时间函数、表达式与位值不加引号
+func columnDefaultSQL(c schemaColumn) string {
+	v := c.Default.String
+	upper := strings.ToUpper(v)
+	switch {
+	case strings.HasPrefix(upper, "CURRENT_TIMESTAMP"), upper == "NOW()", strings.HasPrefix(upper, "LOCALTIMESTAMP"):
+		return v
+	case strings.Contains(c.Extra, "DEFAULT_GENERATED"):
+		return "(" + v + ")"
+	case strings.HasPrefix(v, "b'"):
+		return v
+	}
+	return "'" + escapeSQLString(v) + "'"
+}
+
+// indexDefinition 生成索引定义
+func indexDefinition(idx *schemaIndex) string {
+	cols := strings.Join(idx.Columns, ",")
+	switch {
+	case idx.Name == "PRIMARY":
+		return fmt.Sprintf("PRIMARY KEY (%s)", cols)
+	case idx.Kind == "FULLTEXT":
+		return fmt.Sprintf("FULLTEXT KEY `%s` (%s)", idx.Name, cols)
+	case idx.Kind == "SPATIAL":
+		return fmt.Sprintf("SPATIAL KEY `%s` (%s)", idx.Name, cols)
+	case idx.Unique:
+		return fmt.Sprintf("UNIQUE KEY `%s` (%s)", idx.Name, cols)
+	}
+	return fmt.Sprintf("KEY `%s` (%s)", idx.Name, cols)
+}
+
+// foreignKeyDefinition 生成外键定义；引用同库的表时不带库名
+func foreignKeyDefinition(fk *schemaForeignKey, database string) string {
+	ref := fmt.Sprintf("`%s`", fk.RefTable)
+	if fk.RefSchema != database {
+		ref = fmt.Sprintf("`%s`.`%s`", fk.RefSchema, fk.RefTable)
+	}
+	return fmt.Sprintf("CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES %s (%s) ON DELETE %s ON UPDATE %s",
+		fk.Name, quoteColumns(fk.Columns), ref, quoteColumns(fk.RefColumns), fk.OnDelete, fk.OnUpdate)
+}
+
+var autoIncrementRe = regexp.MustCompile(`\s*AUTO_INCREMENT=\d+`)
+
+// createTableSQL 源表建表语句，按选项去掉 AUTO_INCREMENT 值
+func createTableSQL(t *schemaTable, opts SchemaDiffOptions) string {
+	s := t.CreateSQL
+	if opts.IgnoreAutoIncrement {
+		s = autoIncrementRe.ReplaceAllString(s, "")
+	}
+	return s
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffSchemas 比较两个库结构并生成有序脚本：
+// 删除外键 → 删除触发器/视图/例程 → 删除表 → 建表 → 修改表 → 添加外键 → 创建视图/例程/触发器
+func diffSchemas(src, tgt *schemaModel, opts SchemaDiffOptions) SchemaDiffResult {
+	res := SchemaDiffResult{SourceDB: src.Database, TargetDB: tgt.Database, Items: []SchemaDiffItem{}}
+	var dropFKs, dropObjects, dropTables, createTables, alterTables, addFKs, createObjects []string
+
+	for _, name := range sortedKeys(src.Tables) {
+		st := src.Tables[name]
+		tt, ok := tgt.Tables[name]
+		if !ok {
+			res.Items = append(res.Items, SchemaDiffItem{ObjectType: "table", Table: name, Name: name, Action: "create", Source: createTableSQL(st, opts)})
+			// 外键在所有表创建完成后再添加，避免依赖顺序问题
+			createSQL := createTableSQL(st, opts)
+			for _, fkName := range sortedKeys(st.ForeignKeys) {
+				addFKs = append(addFKs, fmt.Sprintf("ALTER TABLE `%s` ADD %s", name, foreignKeyDefinition(st.ForeignKeys[fkName], src.Database)))
+			}
+			createTables = append(createTables, stripForeignKeys(createSQL))
+			continue
+		}
+		var clauses []string
+
+		// 列
+		tgtCols := map[string]schemaColumn{}
+		for _, c := range tt.Columns {
+			tgtCols[c.Name] = c
+		}
+		srcCols := map[string]bool{}
+		prev := ""
+		for _, c := range st.Columns {
+			srcCols[c.Name] = true
+			position := " FIRST"
+			if prev != "" {
+				position = fmt.Sprintf(" AFTER `%s`", prev)
+			}
+			prev = c.Name
+			sdef := columnDefinition(c, opts)
+			tc, ok := tgtCols[c.Name]
+			if !ok {
+				res.Items = append(res.Items, SchemaDiffItem{ObjectType: "column", Table: name, Name: c.Name, Action: "create", Source: sdef})
+				clauses = append(clauses, fmt.Sprintf("ADD COLUMN `%s` %s%s", c.Name, sdef, position))
+				continue
+			}
+			copts := columnCompareOptions(c, tc, opts)
+			if tdef := columnDefinition(tc, copts); tdef != columnDefinition(c, copts) {
+				res.Items = append(res.Items, SchemaDiffItem{ObjectType: "column", Table: name, Name: c.Name, Action: "alter", Source: sdef, Target: tdef})
+				clauses = append(clauses, fmt.Sprintf("MODIFY COLUMN `%s` %s%s", c.Name, modifyColumnDefinition(c, tc, copts), position))
+			}
+		}
+		for _, c := range tt.Columns {
+			if !srcCols[c.Name] {
+				res.Items = append(res.Items, SchemaDiffItem{ObjectType: "column", Table: name, Name: c.Name, Action: "drop", Target: columnDefinition(c, opts)})
+				clauses = append(clauses, fmt.Sprintf("DROP COLUMN `%s`", c.Name))
+			}
+		}
+
+		// 索引：先删后加
+		var dropIdx, addIdx []string
+		for _, idxName := range sortedKeys(st.Indexes) {
+			sdef := indexDefinition(st.Indexes[idxName])
+			ti, ok := tt.Indexes[idxName]
+			if !ok {
+				res.Items = append(res.Items, SchemaDiffItem{ObjectType: "index", Table: name, Name: idxName, Action: "create", Source: sdef})
+				addIdx = append(addIdx, "ADD "+sdef)
+				continue
+			}
+			if tdef := indexDefinition(ti); tdef != sdef {
+				res.Items = append(res.Items, SchemaDiffItem{ObjectType: "index", Table: name, Name: idxName, Action: "alter", Source: sdef, Target: tdef})
+				dropIdx = append(dropIdx, dropIndexClause(idxName))
+				addIdx = append(addIdx, "ADD "+sdef)
+			}
+		}
+		for _, idxName := range sortedKeys(tt.Indexes) {
+			if _, ok := st.Indexes[idxName]; !ok {
+				res.Items = append(res.Items, SchemaDiffItem{ObjectType: "index", Table: name, Name: idxName, Action: "drop", Target: indexDefinition(tt.Indexes[idxName])})
+				dropIdx = append(dropIdx, dropIndexClause(idxName))
+			}
+		}
+		clauses = append(dropIdx, append(clauses, addIdx...)...)
+
+		// 外键
+		for _, fkName := range sortedKeys(st.ForeignKeys) {
+			sdef := foreignKeyDefinition(st.ForeignKeys[fkName], src.Database)
+			tf, ok := tt.ForeignKeys[fkName]
+			if !ok {
+				res.Items = append(res.Items, SchemaDiffItem{ObjectType: "foreign_key", Table: name, Name: fkName, Action: "create", Source: sdef})
+				addFKs = append(addFKs, fmt.Sprintf("ALTER TABLE `%s` ADD %s", name, sdef))
+				continue
+			}
+			if tdef := foreignKeyDefinition(tf, tgt.Database); tdef != sdef {
+				res.Items = append(res.Items, SchemaDiffItem{ObjectType: "foreign_key", Table: name, Name: fkName, Action: "alter", Source: sdef, Target: tdef})
+				dropFKs = append(dropFKs, fmt.Sprintf("ALTER TABLE `%s` DROP FOREIGN KEY `%s`", name, fkName))
+				addFKs = append(addFKs, fmt.Sprintf("ALTER TABLE `%s` ADD %s", name, sdef))
+			}
+		}
+		for _, fkName := range sortedKeys(tt.ForeignKeys) {
+			if _, ok := st.ForeignKeys[fkName]; !ok {
+				res.Items = append(res.Items, SchemaDiffItem{ObjectType: "foreign_key", Table: name, Name: fkName, Action: "drop", Target: foreignKeyDefinition(tt.ForeignKeys[fkName], tgt.Database)})
+				dropFKs = append(dropFKs, fmt.Sprintf("ALTER TABLE `%s` DROP FOREIGN KEY `%s`", name, fkName))
+			}
+		}
+
+		// 表选项
+		var options []string
+		if st.Engine != tt.Engine && st.Engine != "" {
+			options = append(options, "ENGINE="+st.Engine)
+			res.Items = append(res.Items, SchemaDiffItem{ObjectType: "table_option", Table: name, Name: "ENGINE", Action: "alter", Source: st.Engine, Target: tt.Engine})
+		}
+		if st.Collation != tt.Collation && st.Collation != "" {
+			// 排序规则隐含字符集：忽略字符集时只比较同一字符集下的排序规则，忽略排序规则时只比较字符集
+			sameCharset := collationCharset(st.Collation) == collationCharset(tt.Collation)
+			differs := true
+			switch {
+			case opts.IgnoreCharset && opts.IgnoreCollation:
+				differs = false
+			case opts.IgnoreCharset:
+				differs = sameCharset
+			case opts.IgnoreCollation:
+				differs = !sameCharset
+			}
+			if differs {
+				options = append(options, "COLLATE="+st.Collation)
+				res.Items = append(res.Items, SchemaDiffItem{ObjectType: "table_option", Table: name, Name: "COLLATE", Action: "alter", Source: st.Collation, Target: tt.Collation})
+			}
+		}
+		if st.Comment != tt.Comment && !opts.IgnoreComments {
+			options = append(options, "COMMENT='"+escapeSQLString(st.Comment)+"'")
+			res.Items = append(res.Items, SchemaDiffItem{ObjectType: "table_option", Table: name, Name: "COMMENT", Action: "alter", Source: st.Comment, Target: tt.Comment})
+		}
+		if !opts.IgnoreAutoIncrement && st.AutoIncrement.Valid && st.AutoIncrement != tt.AutoIncrement {
+			options = append(options, fmt.Sprintf("AUTO_INCREMENT=%d", st.AutoIncrement.Int64))
+			res.Items = append(res.Items, SchemaDiffItem{ObjectType: "table_option", Table: name, Name: "AUTO_INCREMENT", Action: "alter",
+				Source: fmt.Sprint(st.AutoIncrement.Int64), Target: fmt.Sprint(tt.AutoIncrement.Int64)})
+		}
+		clauses = append(clauses, options...)
+
+		if len(clauses) > 0 {
+			alterTables = append(alterTables, fmt.Sprintf("ALTER TABLE `%s`\n  %s", name, strings.Join(clauses, ",\n  ")))
+		}
+	}
+	for _, name := range sortedKeys(tgt.Tables) {
+		if _, ok := src.Tables[name]; !ok {
+			res.Items = append(res.Items, SchemaDiffItem{ObjectType: "table", Table: name, Name: name, Action: "drop", Target: tgt.Tables[name].CreateSQL})
+			dropTables = append(dropTables, fmt.Sprintf("DROP TABLE IF EXISTS `%s`", name))
+		}
+	}
+
+	// 视图、存储过程、函数、触发器：定义不同则删除后重建
+	for _, key := range objectCreateOrder(src.Objects) {
+		so := src.Objects[key]
+		to, ok := tgt.Objects[key]
+		sdef := so.Definition
+		if ok && to.Definition == sdef {
+			continue
+		}
+		item := SchemaDiffItem{ObjectType: so.Kind, Table: so.Table, Name: so.Name, Action: "create", Source: so.CreateSQL}
+		if ok {
+			item.Action = "alter"
+			item.Target = to.CreateSQL
+			if so.Kind != "view" {
+				dropObjects = append(dropObjects, dropObjectSQL(to))
+			}
+		}
+		res.Items = append(res.Items, item)
+		createObjects = append(createObjects, createObjectSQL(so))
+	}
+	for _, key := range sortedKeys(tgt.Objects) {
+		if _, ok := src.Objects[key]; !ok {
+			to := tgt.Objects[key]
+			res.Items = append(res.Items, SchemaDiffItem{ObjectType: to.Kind, Table: to.Table, Name: to.Name, Action: "drop", Target: to.CreateSQL})
+			dropObjects = append(dropObjects, dropObjectSQL(to))
+		}
+	}
+
+	var b strings.Builder
+	if len(res.Items) == 0 {
+		res.Script = ""
+		return res
+	}
+	fmt.Fprintf(&b, "-- 结构同步脚本：%s → %s\n", src.Database, tgt.Database)
+	fmt.Fprintf(&b, "USE `%s`;\n", tgt.Database)
+	b.WriteString("SET FOREIGN_KEY_CHECKS = 0;\n")
+	writeSection := func(title string, stmts []string) {
+		if len(stmts) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n-- %s\n", title)
+		for _, s := range stmts {
+			b.WriteString(s)
+			if !strings.HasSuffix(strings.TrimSpace(s), "DELIMITER ;") {
+				b.WriteString(";")
+			}
+			b.WriteString("\n")
+		}
+	}
+	writeSection("删除外键", dropFKs)
+	writeSection("删除触发器、视图、存储过程与函数", dropObjects)
+	writeSection("删除表", dropTables)
+	writeSection("创建表", createTables)
+	writeSection("修改表", alterTables)
+	writeSection("添加外键", addFKs)
+	writeSection("创建视图、存储过程、函数与触发器", createObjects)
+	b.WriteString("\nSET FOREIGN_KEY_CHECKS = 1;\n")
+	res.Script = b.String()
+	return res
+}
+
+func dropIndexClause(name string) string {
+	if name == "PRIMARY" {
+		return "DROP PRIMARY KEY"
+	}
+	return fmt.Sprintf("DROP INDEX `%s`", name)
+}
+
+// collationCharset 从排序规则名取字符集
+func collationCharset(collation string) string {
+	if i := strings.Index(collation, "_"); i > 0 {
+		return collation[:i]
+	}
+	return collation
+}
+
+// stripForeignKeys 去掉建表语句中的外键约束，外键统一在建表之后添加
+func stripForeignKeys(createSQL string) string {
+	lines := strings.Split(createSQL, "\n")
+	kept := make([]string, 0, len(lines))
+	removed := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "CONSTRAINT ") && strings.Contains(trimmed, " FOREIGN KEY ") {
+			removed = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if !removed {
+		return createSQL
+	}
+	return strings.Join(fixDefinitionCommas(kept), "\n")
+}
+
+// fixDefinitionCommas 删除定义行后重新整理行末的逗号：最后一个定义行之后紧跟右括号
+func fixDefinitionCommas(lines []string) []string {
+	for i := 1; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], ")") {
+			continue
+		}
+		for j := 1; j < i; j++ {
+			line := strings.TrimRight(lines[j], ",")
+			if j < i-1 {
+				line += ","
+			}
+			lines[j] = line
+		}
+		break
+	}
+	return lines
+}
+
+func dropObjectSQL(o *schemaRoutine) string {
+	switch o.Kind {
+	case "view":
+		return fmt.Sprintf("DROP VIEW IF EXISTS `%s`", o.Name)
+	case "procedure":
+		return fmt.Sprintf("DROP PROCEDURE IF EXISTS `%s`", o.Name)
+	case "function":
+		return fmt.Sprintf("DROP FUNCTION IF EXISTS `%s`", o.Name)
+	default:
+		return fmt.Sprintf("DROP TRIGGER IF EXISTS `%s`", o.Name)
+	}
+}
+
+// createObjectSQL 例程与触发器体内含分号，用 DELIMITER 包裹
+// objectCreateOrder 返回视图、例程与触发器的创建顺序：按名称排序，
+// 视图排在它引用的其他视图之后（循环引用时保持名称顺序）
+func objectCreateOrder(objs map[string]*schemaRoutine) []string {
+	keys := sortedKeys(objs)
+	order := make([]string, 0, len(keys))
+	state := make(map[string]int, len(keys)) // 1 访问中，2 已完成
+	var visit func(key string)
+	visit = func(key string) {
+		if state[key] != 0 {
+			return
+		}
+		state[key] = 1
+		if o := objs[key]; o.Kind == "view" {
+			for _, dep := range keys {
+				d := objs[dep]
+				if dep != key && d.Kind == "view" && strings.Contains(o.Definition, "`"+d.Name+"`") {
+					visit(dep)
+				}
+			}
+		}
+		state[key] = 2
+		order = append(order, key)
+	}
+	for _, key := range keys {
+		visit(key)
+	}
+	return order
+}
+
+func createObjectSQL(o *schemaRoutine) string {
+	if o.Kind == "view" {
+		return o.CreateSQL
+	}
+	return fmt.Sprintf("DELIMITER ;;\n%s;;\nDELIMITER ;", o.CreateSQL)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffSchemasCharsetCollation(t *testing.T) {
+	table := func(collation string, col schemaColumn) *schemaTable {
+		return &schemaTable{Name: "t", Engine: "InnoDB", Collation: collation, Columns: []schemaColumn{col}}
+	}
+	col := func(charset, collation string) schemaColumn {
+		return schemaColumn{Name: "c", Type: "varchar(10)", Nullable: true, Charset: charset, Collation: collation}
+	}
+	tests := []struct {
+		name  string
+		opts  SchemaDiffOptions
+		src   *schemaTable
+		tgt   *schemaTable
+		items int
+	}{
+		{"charset differs, charset ignored", SchemaDiffOptions{IgnoreCharset: true},
+			table("utf8mb4_general_ci", col("utf8mb4", "utf8mb4_general_ci")), table("latin1_swedish_ci", col("latin1", "latin1_swedish_ci")), 0},
+		{"collation differs, charset ignored", SchemaDiffOptions{IgnoreCharset: true},
+			table("utf8mb4_general_ci", col("utf8mb4", "utf8mb4_general_ci")), table("utf8mb4_bin", col("utf8mb4", "utf8mb4_bin")), 2},
+		{"collation differs, collation ignored", SchemaDiffOptions{IgnoreCollation: true},
+			table("utf8mb4_general_ci", col("utf8mb4", "utf8mb4_general_ci")), table("utf8mb4_bin", col("utf8mb4", "utf8mb4_bin")), 0},
+		{"charset differs, collation ignored", SchemaDiffOptions{IgnoreCollation: true},
+			table("utf8mb4_general_ci", col("utf8mb4", "utf8mb4_general_ci")), table("latin1_swedish_ci", col("latin1", "latin1_swedish_ci")), 2},
+		{"both ignored", SchemaDiffOptions{IgnoreCharset: true, IgnoreCollation: true},
+			table("utf8mb4_general_ci", col("utf8mb4", "utf8mb4_general_ci")), table("latin1_bin", col("latin1", "latin1_bin")), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &schemaModel{Database: "s", Tables: map[string]*schemaTable{"t": tt.src}, Objects: map[string]*schemaRoutine{}}
+			tgt := &schemaModel{Database: "d", Tables: map[string]*schemaTable{"t": tt.tgt}, Objects: map[string]*schemaRoutine{}}
+			res := diffSchemas(src, tgt, tt.opts)
+			if len(res.Items) != tt.items {
+				t.Fatalf("got %d items %+v, want %d", len(res.Items), res.Items, tt.items)
+			}
+		})
+	}
+}
+
+func TestDiffSchemasModifyKeepsIgnoredCharset(t *testing.T) {
+	src := &schemaModel{Database: "s", Objects: map[string]*schemaRoutine{}, Tables: map[string]*schemaTable{"t": {Name: "t",
+		Columns: []schemaColumn{{Name: "c", Type: "varchar(20)", Charset: "utf8mb4", Collation: "utf8mb4_general_ci"}}}}}
+	tgt := &schemaModel{Database: "d", Objects: map[string]*schemaRoutine{}, Tables: map[string]*schemaTable{"t": {Name: "t",
+		Columns: []schemaColumn{{Name: "c", Type: "varchar(10)", Charset: "latin1", Collation: "latin1_swedish_ci"}}}}}
+	res := diffSchemas(src, tgt, SchemaDiffOptions{IgnoreCharset: true})
+	want := "MODIFY COLUMN `c` varchar(20) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL FIRST"
+	if !strings.Contains(res.Script, want) {
+		t.Fatalf("script does not contain %q:\n%s", want, res.Script)
+	}
+}
+
+func TestObjectCreateOrderViewDependencies(t *testing.T) {
+	objs := map[string]*schemaRoutine{
+		"view/a":      {Kind: "view", Name: "a", Definition: "CREATE VIEW `a` AS select `c`.`id` from `c`"},
+		"view/b":      {Kind: "view", Name: "b", Definition: "CREATE VIEW `b` AS select `t`.`id` from `t`"},
+		"view/c":      {Kind: "view", Name: "c", Definition: "CREATE VIEW `c` AS select `b`.`id` from `b`"},
+		"procedure/p": {Kind: "procedure", Name: "p", Definition: "CREATE PROCEDURE `p`() select 1"},
+	}
+	got := strings.Join(objectCreateOrder(objs), ",")
+	if want := "procedure/p,view/b,view/c,view/a"; got != want {
+		t.Fatalf("order = %s, want %s", got, want)
+	}
+}
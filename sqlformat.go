@@ -0,0 +1,876 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatOptions SQL 格式化选项
+type FormatOptions struct {
+	KeywordCase string `json:"keywordCase"` // upper / lower / preserve，默认 upper
+	IndentSize  int    `json:"indentSize"`  // 缩进空格数，默认 2
+	UseTabs     bool   `json:"useTabs"`     // 使用制表符缩进
+	Minify      bool   `json:"minify"`      // 压缩为一行：去掉多余空白，行注释改写为块注释
+}
+
+// FormatSQL 按方言（mysql / oracle）格式化 SQL：子句换行、列表逐项对齐、关键字统一大小写，
+// 注释与字符串原样保留。存储过程、触发器等过程体以及 DELIMITER 段原样输出
+func (a *App) FormatSQL(sql string, dialect string, opts FormatOptions) (string, error) {
+	return formatSQL(sql, normalizeDBType(dialect), opts)
+}
+
+func formatSQL(sql string, dialect string, opts FormatOptions) (string, error) {
+	switch opts.KeywordCase {
+	case "":
+		opts.KeywordCase = "upper"
+	case "upper", "lower", "preserve":
+	default:
+		return "", fmt.Errorf("不支持的关键字大小写: %s", opts.KeywordCase)
+	}
+	if opts.IndentSize <= 0 {
+		opts.IndentSize = 2
+	}
+	tokens, err := tokenizeSQL(sql, dialect)
+	if err != nil {
+		return "", err
+	}
+	f := &sqlFormatter{src: sql, dialect: dialect, opts: opts, tokens: tokens}
+	return f.run(), nil
+}
+
+type sqlTokenKind int
+
+const (
+	tokWord sqlTokenKind = iota
+	tokQuoted
+	tokString
+	tokNumber
+	tokVar
+	tokOp
+	tokComma
+	tokOpen
+	tokClose
+	tokSemi
+	tokDot
+	tokLineComment
+	tokBlockComment
+)
+
+// sqlToken 词法单元；nl 表示前面的空白中含换行
+type sqlToken struct {
+	kind       sqlTokenKind
+	text       string
+	start, end int
+	nl         bool
+	space      bool // 前面有空白
+}
+
+func isSQLWordByte(b byte) bool {
+	return b == '_' || b == '$' || b >= 0x80 || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+var sqlMultiOps = []string{"<=>", "->>", "<=", ">=", "<>", "!=", "||", "&&", ":=", "->", "<<", ">>", "=>", "**"}
+
+// tokenizeSQL 切分词法单元。MySQL：反引号标识符、双引号字符串、反斜杠转义、# 注释；
+// Oracle：双引号标识符、q'[...]' 字符串、:name 绑定变量
+func tokenizeSQL(s string, dialect string) ([]sqlToken, error) {
+	oracle := dialect == "oracle"
+	var tokens []sqlToken
+	nl, space := false, false
+	i := 0
+	add := func(kind sqlTokenKind, end int) {
+		tokens = append(tokens, sqlToken{kind: kind, text: s[i:end], start: i, end: end, nl: nl, space: space})
+		nl, space = false, false
+		i = end
+	}
+	// readQuoted 读到结束引号，返回结束位置
+	readQuoted := func(from int, q byte, backslash bool) (int, bool) {
+		for j := from; j < len(s); j++ {
+			switch {
+			case backslash && s[j] == '\\':
+				j++
+			case s[j] == q:
+				if j+1 < len(s) && s[j+1] == q {
+					j++
+					continue
+				}
+				return j + 1, true
+			}
+		}
+		return len(s), false
+	}
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case isSpaceByte(c):
+			if c == '\n' {
+				nl = true
+			}
+			space = true
+			i++
+		case c == '-' && i+1 < len(s) && s[i+1] == '-' && (oracle || i+2 >= len(s) || isSpaceByte(s[i+2])),
+			c == '#' && !oracle:
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				end = len(s)
+			} else {
+				end += i
+			}
+			if end > i && s[end-1] == '\r' {
+				end--
+			}
+			add(tokLineComment, end)
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("存在未闭合的注释")
+			}
+			add(tokBlockComment, i+2+end+2)
+		case c == '\'':
+			end, ok := readQuoted(i+1, '\'', !oracle)
+			if !ok {
+				return nil, fmt.Errorf("存在未闭合的字符串")
+			}
+			add(tokString, end)
+		case c == '"':
+			end, ok := readQuoted(i+1, '"', !oracle)
+			if !ok {
+				return nil, fmt.Errorf("存在未闭合的引号")
+			}
+			if oracle {
+				add(tokQuoted, end)
+			} else {
+				add(tokString, end)
+			}
+		case c == '`' && !oracle:
+			end, ok := readQuoted(i+1, '`', false)
+			if !ok {
+				return nil, fmt.Errorf("存在未闭合的反引号")
+			}
+			add(tokQuoted, end)
+		case c >= '0' && c <= '9' || (c == '.' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9' && (len(tokens) == 0 || tokens[len(tokens)-1].kind != tokWord && tokens[len(tokens)-1].kind != tokQuoted)):
+			j := i
+			if c == '0' && i+1 < len(s) && (s[i+1] == 'x' || s[i+1] == 'X' || s[i+1] == 'b' || s[i+1] == 'B') {
+				j += 2
+			}
+			for j < len(s) && (isSQLWordByte(s[j]) || s[j] == '.' ||
+				((s[j] == '+' || s[j] == '-') && (s[j-1] == 'e' || s[j-1] == 'E'))) {
+				j++
+			}
+			add(tokNumber, j)
+		case isSQLWordByte(c) || (oracle && c == '#'):
+			j := i
+			for j < len(s) && (isSQLWordByte(s[j]) || (oracle && s[j] == '#')) {
+				j++
+			}
+			word := s[i:j]
+			// 字符串前缀：X'..' B'..' N'..' _utf8mb4'..'，Oracle q'[..]'
+			if j < len(s) && s[j] == '\'' {
+				lw := strings.ToLower(word)
+				if oracle && (lw == "q" || lw == "nq") && j+1 < len(s) {
+					open := s[j+1]
+					closing := map[byte]byte{'[': ']', '(': ')', '{': '}', '<': '>'}[open]
+					if closing == 0 {
+						closing = open
+					}
+					end := strings.Index(s[j+2:], string(closing)+"'")
+					if end < 0 {
+						return nil, fmt.Errorf("存在未闭合的字符串")
+					}
+					add(tokString, j+2+end+2)
+					continue
+				}
+				if lw == "x" || lw == "b" || lw == "n" || (!oracle && strings.HasPrefix(word, "_")) {
+					end, ok := readQuoted(j+1, '\'', !oracle && lw != "x" && lw != "b")
+					if !ok {
+						return nil, fmt.Errorf("存在未闭合的字符串")
+					}
+					add(tokString, end)
+					continue
+				}
+			}
+			add(tokWord, j)
+		case c == '@' && !oracle:
+			j := i + 1
+			if j < len(s) && s[j] == '@' {
+				j++
+			}
+			if j < len(s) && (s[j] == '`' || s[j] == '\'' || s[j] == '"') {
+				end, ok := readQuoted(j+1, s[j], s[j] != '`')
+				if !ok {
+					return nil, fmt.Errorf("存在未闭合的引号")
+				}
+				j = end
+			} else {
+				for j < len(s) && (isSQLWordByte(s[j]) || s[j] == '.') {
+					j++
+				}
+			}
+			add(tokVar, j)
+		case c == ':' && i+1 < len(s) && isSQLWordByte(s[i+1]):
+			j := i + 1
+			for j < len(s) && isSQLWordByte(s[j]) {
+				j++
+			}
+			add(tokVar, j)
+		case c == '?':
+			add(tokVar, i+1)
+		case c == '(':
+			add(tokOpen, i+1)
+		case c == ')':
+			add(tokClose, i+1)
+		case c == ',':
+			add(tokComma, i+1)
+		case c == ';':
+			add(tokSemi, i+1)
+		case c == '.':
+			add(tokDot, i+1)
+		default:
+			n := 1
+			for _, op := range sqlMultiOps {
+				if strings.HasPrefix(s[i:], op) {
+					n = len(op)
+					break
+				}
+			}
+			add(tokOp, i+n)
+		}
+	}
+	return tokens, nil
+}
+
+// sqlKeywords 需要统一大小写的关键字
+var sqlKeywords = toKeywordSet(`ACCESSIBLE ADD AFTER ALGORITHM ALL ALTER ANALYZE AND ANY AS ASC AUTO_INCREMENT BEFORE BEGIN BETWEEN BIGINT
+BINARY BLOB BOOLEAN BOTH BY CALL CASCADE CASE CHANGE CHAR CHARACTER CHARSET CHECK COLLATE COLUMN COMMENT COMMIT CONNECT
+CONSTRAINT CREATE CROSS CURRENT_DATE CURRENT_TIMESTAMP DATABASE DATE DATETIME DECIMAL DECLARE DEFAULT DEFINER DELETE DESC
+DESCRIBE DISTINCT DIV DOUBLE DROP DUAL DUPLICATE EACH ELSE ELSEIF END ENGINE ENUM ESCAPE EXCEPT EXISTS EXPLAIN FALSE FETCH
+FIRST FLOAT FOR FORCE FOREIGN FROM FULL FULLTEXT FUNCTION GRANT GROUP HAVING IF IGNORE IN INDEX INNER INSERT INT INTEGER
+INTERSECT INTERVAL INTO IS JOIN JSON KEY KEYS KILL LEADING LEFT LIKE LIMIT LOCK LONGTEXT MATCHED MEDIUMINT MEDIUMTEXT MERGE
+MINUS MOD MODIFY NATURAL NEXT NOCOPY NOT NULL NULLS NUMBER NUMERIC OF OFFSET ON ONLY OR ORDER OUTER OVER PARTITION
+PRIMARY PROCEDURE PRIOR RANGE RECURSIVE REFERENCES REGEXP RENAME REPLACE RESTRICT RETURN RETURNING RETURNS REVOKE RIGHT
+RLIKE ROLLBACK ROW ROWNUM ROWS SCHEMA SELECT SEPARATOR SEQUENCE SET SHARE SHOW SMALLINT START STRAIGHT_JOIN SYSDATE TABLE
+TEMPORARY TEXT THEN TIME TIMESTAMP TINYINT TINYTEXT TO TRAILING TRIGGER TRUE TRUNCATE UNION UNIQUE UNSIGNED UPDATE USE USING
+VALUES VARCHAR VARCHAR2 VIEW WHEN WHERE WINDOW WITH XOR ZEROFILL`)
+
+func toKeywordSet(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(s) {
+		set[w] = true
+	}
+	return set
+}
+
+// sqlClause 子句关键字及其排版方式
+type sqlClause struct {
+	words []string
+	kind  int
+}
+
+const (
+	clauseBlock  = iota // 关键字独占一行，内容换行缩进，逗号分隔的列表逐项换行
+	clauseInline        // 关键字另起一行，内容跟在同一行
+	clauseSetOp         // UNION 等：前后各换行
+	clauseJoin          // JOIN：在内容缩进层级另起一行
+)
+
+var sqlClauses = func() []sqlClause {
+	defs := []struct {
+		kind  int
+		words string
+	}{
+		{clauseBlock, "ON DUPLICATE KEY UPDATE|SELECT DISTINCT|SELECT|FROM|WHERE|GROUP BY|HAVING|ORDER BY|SET|VALUES|" +
+			"DELETE FROM|INSERT INTO|INSERT IGNORE INTO|REPLACE INTO|MERGE INTO|UPDATE|CONNECT BY|START WITH|RETURNING"},
+		{clauseInline, "LIMIT|OFFSET|FETCH|FOR UPDATE|LOCK IN SHARE MODE|WITH RECURSIVE|WITH|USING|WHEN NOT MATCHED THEN|WHEN MATCHED THEN|WINDOW"},
+		{clauseSetOp, "UNION ALL|UNION DISTINCT|UNION|INTERSECT|EXCEPT|MINUS"},
+		{clauseJoin, "LEFT OUTER JOIN|RIGHT OUTER JOIN|FULL OUTER JOIN|LEFT JOIN|RIGHT JOIN|FULL JOIN|INNER JOIN|CROSS JOIN|" +
+			"NATURAL JOIN|STRAIGHT_JOIN|JOIN|CROSS APPLY|OUTER APPLY"},
+	}
+	var out []sqlClause
+	for _, d := range defs {
+		for _, w := range strings.Split(d.words, "|") {
+			out = append(out, sqlClause{words: strings.Fields(w), kind: d.kind})
+		}
+	}
+	return out
+}()
+
+// sqlFrame 括号或 CASE 的排版上下文
+type sqlFrame struct {
+	block  bool // 子查询或建表列定义：内容换行缩进
+	list   bool // 逗号逐项换行
+	isCase bool
+	outer  int // 开始时所在行的缩进
+	base   int // 子句关键字的缩进
+	clause string
+}
+
+type sqlFormatter struct {
+	src     string
+	dialect string
+	opts    FormatOptions
+	tokens  []sqlToken
+
+	b         strings.Builder
+	lineStart bool
+	level     int // 当前行缩进
+	prev      *sqlToken
+	prevPrev  *sqlToken
+	stack     []sqlFrame
+	first     string // 语句首个关键字
+	between   bool
+	created   bool // 建表语句的列定义括号已出现（只有第一个括号按列表排版）
+}
+
+func (f *sqlFormatter) frame() *sqlFrame { return &f.stack[len(f.stack)-1] }
+
+func (f *sqlFormatter) reset() {
+	f.stack = []sqlFrame{{block: true}}
+	f.prev, f.prevPrev = nil, nil
+	f.first = ""
+	f.between = false
+	f.created = false
+}
+
+// newline 另起一行；当前已在行首时只调整缩进
+func (f *sqlFormatter) newline(level int) {
+	if level < 0 {
+		level = 0
+	}
+	if !f.lineStart && f.b.Len() > 0 {
+		s := strings.TrimRight(f.b.String(), " ")
+		f.b.Reset()
+		f.b.WriteString(s)
+		f.b.WriteByte('\n')
+	}
+	f.lineStart = true
+	f.level = level
+}
+
+func (f *sqlFormatter) write(s string) {
+	if f.lineStart {
+		if f.opts.UseTabs {
+			f.b.WriteString(strings.Repeat("\t", f.level))
+		} else {
+			f.b.WriteString(strings.Repeat(" ", f.level*f.opts.IndentSize))
+		}
+		f.lineStart = false
+	}
+	f.b.WriteString(s)
+}
+
+func (f *sqlFormatter) keyword(t sqlToken) string {
+	if t.kind != tokWord || !sqlKeywords[strings.ToUpper(t.text)] || (f.prev != nil && f.prev.kind == tokDot) {
+		return t.text
+	}
+	switch f.opts.KeywordCase {
+	case "upper":
+		return strings.ToUpper(t.text)
+	case "lower":
+		return strings.ToLower(t.text)
+	}
+	return t.text
+}
+
+// isUnary 判断 + - 是否为正负号
+func (f *sqlFormatter) isUnary() bool {
+	if f.prev == nil || f.prev.kind != tokOp || (f.prev.text != "-" && f.prev.text != "+") {
+		return false
+	}
+	p := f.prevPrev
+	return p == nil || p.kind == tokOp || p.kind == tokOpen || p.kind == tokComma ||
+		(p.kind == tokWord && sqlKeywords[strings.ToUpper(p.text)])
+}
+
+// needSpace 当前单元前是否加空格
+func (f *sqlFormatter) needSpace(t sqlToken) bool {
+	if f.lineStart || f.prev == nil {
+		return false
+	}
+	switch t.kind {
+	case tokComma, tokClose, tokSemi, tokDot:
+		return false
+	}
+	switch f.prev.kind {
+	case tokOpen, tokDot:
+		return false
+	}
+	if f.isUnary() {
+		return false
+	}
+	if t.kind == tokOpen {
+		switch f.prev.kind {
+		case tokWord:
+			// 关键字后加空格；函数调用与表名后的列清单按原文
+			if sqlKeywords[strings.ToUpper(f.prev.text)] && !sqlFunctionKeywords[strings.ToUpper(f.prev.text)] {
+				return true
+			}
+			return t.space
+		case tokQuoted, tokVar:
+			return t.space
+		}
+	}
+	return true
+}
+
+// sqlFunctionKeywords 常作为函数调用的关键字，后面的括号不加空格
+var sqlFunctionKeywords = toKeywordSet(`CHAR CHECK COLUMN DATE DECIMAL DOUBLE ENUM FLOAT IF INSERT INT INTERVAL KEY LEFT MOD NUMBER
+NUMERIC REPLACE RIGHT SET TIME TIMESTAMP TINYINT SMALLINT MEDIUMINT BIGINT INTEGER VARCHAR VARCHAR2 VALUES TRUNCATE BINARY CHARSET`)
+
+// matchClause 在位置 i 处匹配子句关键字，返回子句与占用的单元数
+func (f *sqlFormatter) matchClause(i int) (*sqlClause, int) {
+	for ci := range sqlClauses {
+		c := &sqlClauses[ci]
+		if i+len(c.words) > len(f.tokens) {
+			continue
+		}
+		ok := true
+		for k, w := range c.words {
+			t := f.tokens[i+k]
+			if t.kind != tokWord || !strings.EqualFold(t.text, w) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return c, len(c.words)
+		}
+	}
+	return nil, 0
+}
+
+// clauseApplies 子句关键字是否按子句排版（排除同名的非子句用法）
+func (f *sqlFormatter) clauseApplies(c *sqlClause, i int) bool {
+	fr := f.frame()
+	if !fr.block || fr.isCase || f.prev != nil && f.prev.kind == tokDot {
+		return false
+	}
+	head := c.words[0]
+	switch head {
+	case "SET":
+		return f.first == "UPDATE" || f.first == "INSERT" || f.first == "REPLACE" || f.first == "MERGE"
+	case "VALUES":
+		return (f.first == "INSERT" || f.first == "REPLACE") && fr.clause != "ON DUPLICATE KEY UPDATE" &&
+			!(i+1 < len(f.tokens) && f.tokens[i+1].kind == tokOpen && !f.tokens[i+1].space && fr.clause == "SET")
+	case "UPDATE":
+		if len(c.words) == 1 {
+			return f.first == "UPDATE" && f.prev == nil || f.first == "MERGE"
+		}
+	case "USING":
+		return f.first == "MERGE" || f.first == "DELETE"
+	case "WITH":
+		return f.prev == nil || f.prev.kind == tokOpen
+	case "FETCH", "OFFSET", "LIMIT", "START", "CONNECT", "WINDOW", "RETURNING":
+		return f.first == "SELECT" || f.first == "WITH" || f.first == "UPDATE" || f.first == "DELETE" || f.first == "INSERT" || f.first == "("
+	}
+	switch f.first {
+	case "CREATE", "ALTER", "DROP", "GRANT", "REVOKE", "RENAME":
+		// DDL 中只有 AS SELECT 之后才出现查询子句
+		return fr.clause != ""
+	}
+	return true
+}
+
+// isBlockStart 判断语句是否为存储过程、触发器、匿名块等需原样保留的过程体
+func isBlockStart(tokens []sqlToken) bool {
+	var words []string
+	for _, t := range tokens {
+		if t.kind == tokLineComment || t.kind == tokBlockComment {
+			continue
+		}
+		if t.kind != tokWord {
+			if len(words) > 0 && words[0] == "CREATE" && (t.kind == tokOp || t.kind == tokQuoted || t.kind == tokVar || t.kind == tokDot || t.kind == tokString) {
+				// CREATE DEFINER = `u`@`h` PROCEDURE ...
+				continue
+			}
+			break
+		}
+		words = append(words, strings.ToUpper(t.text))
+		if len(words) >= 8 {
+			break
+		}
+	}
+	if len(words) == 0 {
+		return false
+	}
+	switch words[0] {
+	case "BEGIN", "DECLARE":
+		return true
+	case "CREATE":
+		for _, w := range words[1:] {
+			switch w {
+			case "PROCEDURE", "FUNCTION", "TRIGGER", "PACKAGE", "EVENT", "TYPE":
+				return true
+			case "TABLE", "VIEW", "INDEX", "DATABASE", "SCHEMA", "USER", "SEQUENCE":
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// verbatimEnd 过程体的结束位置（不含）：Oracle 以单独一行的 / 结束，
+// MySQL 按 BEGIN/END 配对到与之对应的分号
+func (f *sqlFormatter) verbatimEnd(i int) int {
+	tokens := f.tokens
+	if f.dialect == "oracle" {
+		for j := i; j < len(tokens); j++ {
+			t := tokens[j]
+			if t.kind == tokOp && t.text == "/" && t.nl && (j+1 == len(tokens) || tokens[j+1].nl) {
+				return j + 1
+			}
+		}
+		return len(tokens)
+	}
+	depth := 0
+	for j := i; j < len(tokens); j++ {
+		t := tokens[j]
+		if t.kind != tokWord && t.kind != tokSemi {
+			continue
+		}
+		switch {
+		case t.kind == tokWord && strings.EqualFold(t.text, "BEGIN"):
+			depth++
+		case t.kind == tokWord && strings.EqualFold(t.text, "END") && depth > 0 &&
+			(j+1 == len(tokens) || tokens[j+1].kind == tokSemi):
+			depth--
+		case t.kind == tokSemi && depth == 0:
+			return j + 1
+		}
+	}
+	return len(tokens)
+}
+
+// delimiterEnd 处理 MySQL DELIMITER 命令：返回原样输出的结束位置
+func (f *sqlFormatter) delimiterEnd(i int) int {
+	t := f.tokens[i]
+	lineEnd := strings.IndexByte(f.src[t.start:], '\n')
+	if lineEnd < 0 {
+		return len(f.tokens)
+	}
+	lineEnd += t.start
+	delim := strings.TrimSpace(f.src[t.end:lineEnd])
+	j := i + 1
+	for j < len(f.tokens) && f.tokens[j].start < lineEnd {
+		j++
+	}
+	if delim == ";" || delim == "" {
+		return j
+	}
+	// 自定义分隔符期间的内容原样保留，直到下一条 DELIMITER
+	for ; j < len(f.tokens); j++ {
+		if f.tokens[j].kind == tokWord && f.tokens[j].nl && strings.EqualFold(f.tokens[j].text, "DELIMITER") {
+			return j
+		}
+	}
+	return len(f.tokens)
+}
+
+func (f *sqlFormatter) run() string {
+	if f.opts.Minify {
+		return f.minify()
+	}
+	f.reset()
+	f.lineStart = true
+	statementStart := true
+	for i := 0; i < len(f.tokens); i++ {
+		t := f.tokens[i]
+		if statementStart && t.kind != tokLineComment && t.kind != tokBlockComment {
+			end := 0
+			if f.dialect != "oracle" && t.kind == tokWord && strings.EqualFold(t.text, "DELIMITER") {
+				end = f.delimiterEnd(i)
+			} else if isBlockStart(f.tokens[i:]) {
+				end = f.verbatimEnd(i)
+			}
+			if end > 0 {
+				f.verbatim(i, end)
+				i = end - 1
+				continue
+			}
+			statementStart = false
+		}
+		switch t.kind {
+		case tokLineComment, tokBlockComment:
+			f.comment(t, i)
+			continue
+		case tokSemi:
+			f.write(";")
+			f.b.WriteString("\n")
+			f.lineStart = true
+			f.level = 0
+			if i+1 < len(f.tokens) {
+				f.b.WriteString("\n")
+			}
+			f.reset()
+			statementStart = true
+			continue
+		}
+		i = f.token(i)
+	}
+	return strings.TrimRight(f.b.String(), " \n") + "\n"
+}
+
+// verbatim 原样输出 [i, end) 对应的原文
+func (f *sqlFormatter) verbatim(i, end int) {
+	f.newline(0)
+	if f.b.Len() > 0 && !strings.HasSuffix(f.b.String(), "\n\n") {
+		f.b.WriteString("\n")
+	}
+	f.b.WriteString(strings.TrimSpace(f.src[f.tokens[i].start:f.tokens[end-1].end]))
+	f.b.WriteString("\n\n")
+	f.lineStart = true
+	f.level = 0
+	f.reset()
+}
+
+func (f *sqlFormatter) comment(t sqlToken, i int) {
+	ownLine := t.nl || f.b.Len() == 0
+	if ownLine {
+		f.newline(f.level)
+	}
+	if !f.lineStart {
+		f.write(" ")
+	}
+	f.write(t.text)
+	next := i + 1
+	if t.kind == tokLineComment || (ownLine && (next >= len(f.tokens) || f.tokens[next].nl)) {
+		f.newline(f.level)
+	}
+}
+
+// token 输出一个单元（子句关键字可能占用多个），返回最后处理的位置
+func (f *sqlFormatter) token(i int) int {
+	t := f.tokens[i]
+	fr := f.frame()
+	upper := strings.ToUpper(t.text)
+	if f.first == "" {
+		if t.kind == tokWord {
+			f.first = upper
+		} else {
+			f.first = t.text
+		}
+	}
+
+	if t.kind == tokWord {
+		if c, n := f.matchClause(i); c != nil && f.clauseApplies(c, i) {
+			words := make([]string, n)
+			for k := 0; k < n; k++ {
+				words[k] = f.keyword(f.tokens[i+k])
+			}
+			text := strings.Join(words, " ")
+			name := strings.ToUpper(text)
+			switch c.kind {
+			case clauseBlock:
+				f.newline(fr.base)
+				f.write(text)
+				f.newline(fr.base + 1)
+				fr.clause = name
+				fr.list = true
+			case clauseInline:
+				f.newline(fr.base)
+				f.write(text)
+				fr.clause = name
+				fr.list = false
+			case clauseSetOp:
+				f.newline(fr.base)
+				f.write(text)
+				f.newline(fr.base)
+				fr.clause = ""
+				fr.list = false
+			case clauseJoin:
+				f.newline(fr.base + 1)
+				f.write(text)
+				fr.list = false
+			}
+			f.advance(f.tokens[i+n-1])
+			return i + n - 1
+		}
+		switch upper {
+		case "BETWEEN":
+			f.between = true
+		case "AND", "OR", "XOR":
+			if upper == "AND" && f.between {
+				f.between = false
+				break
+			}
+			if fr.block && !fr.isCase && (fr.clause == "WHERE" || fr.clause == "HAVING" || fr.clause == "CONNECT BY" || fr.clause == "START WITH") {
+				f.newline(fr.base + 1)
+			}
+		case "CASE":
+			f.emit(t)
+			f.stack = append(f.stack, sqlFrame{isCase: true, outer: f.level, block: fr.block})
+			return i
+		case "WHEN", "ELSE":
+			if fr.isCase {
+				f.newline(fr.outer + 1)
+			}
+		case "END":
+			if fr.isCase {
+				f.newline(fr.outer)
+				f.emit(t)
+				f.stack = f.stack[:len(f.stack)-1]
+				return i
+			}
+		case "AS":
+			// CREATE TABLE/VIEW ... AS SELECT：之后按查询排版
+			if fr.clause == "" && len(f.stack) == 1 && i+1 < len(f.tokens) && f.tokens[i+1].kind == tokWord {
+				switch strings.ToUpper(f.tokens[i+1].text) {
+				case "SELECT", "WITH":
+					fr.clause = "AS"
+				}
+			}
+		}
+	}
+
+	switch t.kind {
+	case tokOpen:
+		f.emit(t)
+		next := f.nextSignificant(i + 1)
+		sub := next != nil && next.kind == tokWord && (strings.EqualFold(next.text, "SELECT") || strings.EqualFold(next.text, "WITH"))
+		createList := len(f.stack) == 1 && fr.clause == "" && f.first == "CREATE" && !f.created
+		switch {
+		case sub:
+			f.stack = append(f.stack, sqlFrame{block: true, outer: f.level, base: f.level + 1})
+			f.newline(f.level + 1)
+		case createList:
+			f.created = true
+			f.stack = append(f.stack, sqlFrame{list: true, outer: f.level, base: f.level, clause: "("})
+			f.newline(f.level + 1)
+		default:
+			f.stack = append(f.stack, sqlFrame{outer: f.level, base: fr.base})
+		}
+		return i
+	case tokClose:
+		if len(f.stack) > 1 {
+			closing := f.stack[len(f.stack)-1]
+			// 未闭合的 CASE 一并弹出
+			for len(f.stack) > 1 && f.stack[len(f.stack)-1].isCase {
+				f.stack = f.stack[:len(f.stack)-1]
+				closing = f.stack[len(f.stack)-1]
+			}
+			if closing.block || closing.clause == "(" {
+				f.newline(closing.outer)
+			}
+			f.stack = f.stack[:len(f.stack)-1]
+		}
+		f.emit(t)
+		return i
+	case tokComma:
+		f.emit(t)
+		if fr.list && !fr.isCase {
+			if fr.clause == "(" {
+				f.newline(fr.base + 1)
+			} else if fr.block {
+				f.newline(fr.base + 1)
+			}
+		} else if len(f.stack) == 1 && f.first == "ALTER" {
+			f.newline(1)
+		}
+		return i
+	}
+	f.emit(t)
+	return i
+}
+
+func (f *sqlFormatter) nextSignificant(i int) *sqlToken {
+	for ; i < len(f.tokens); i++ {
+		if k := f.tokens[i].kind; k != tokLineComment && k != tokBlockComment {
+			return &f.tokens[i]
+		}
+	}
+	return nil
+}
+
+func (f *sqlFormatter) emit(t sqlToken) {
+	if f.needSpace(t) {
+		f.write(" ")
+	}
+	f.write(f.keyword(t))
+	f.advance(t)
+}
+
+func (f *sqlFormatter) advance(t sqlToken) {
+	tc := t
+	f.prevPrev, f.prev = f.prev, &tc
+}
+
+// minify 压缩为尽量少的空白；行注释改写为块注释（--+ 提示改写为 /*+ */，块注释原样保留），过程体与 DELIMITER 段保留换行
+func (f *sqlFormatter) minify() string {
+	var b strings.Builder
+	var prev *sqlToken
+	statementStart := true
+	for i := 0; i < len(f.tokens); i++ {
+		t := f.tokens[i]
+		if statementStart && t.kind != tokLineComment && t.kind != tokBlockComment {
+			end := 0
+			if f.dialect != "oracle" && t.kind == tokWord && strings.EqualFold(t.text, "DELIMITER") {
+				end = f.delimiterEnd(i)
+			} else if isBlockStart(f.tokens[i:]) {
+				end = f.verbatimEnd(i)
+			}
+			if end > 0 {
+				if b.Len() > 0 {
+					b.WriteString("\n")
+				}
+				b.WriteString(strings.TrimSpace(f.src[t.start:f.tokens[end-1].end]))
+				b.WriteString("\n")
+				prev = nil
+				i = end - 1
+				continue
+			}
+			statementStart = false
+		}
+		text := t.text
+		if t.kind == tokLineComment {
+			// Oracle 的 --+ 提示改写为 /*+ ... */，+ 须紧跟注释开头才仍是提示
+			hint := strings.HasPrefix(text, "--+")
+			body := strings.TrimSpace(strings.TrimLeft(text, "-#"))
+			if hint {
+				body = strings.TrimSpace(text[3:])
+			}
+			if strings.Contains(body, "*/") {
+				b.WriteString(text)
+				b.WriteString("\n")
+				prev = nil
+				continue
+			}
+			if hint {
+				text = "/*+ " + body + " */"
+			} else {
+				text = "/* " + body + " */"
+			}
+		}
+		if prev != nil && minifyNeedsSpace(*prev, t) {
+			b.WriteString(" ")
+		}
+		b.WriteString(text)
+		tc := t
+		prev = &tc
+		if t.kind == tokSemi {
+			statementStart = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// minifyNeedsSpace 两个单元紧挨着是否会改变含义
+func minifyNeedsSpace(prev, t sqlToken) bool {
+	wordish := func(k sqlTokenKind) bool {
+		return k == tokWord || k == tokNumber || k == tokVar || k == tokQuoted || k == tokString
+	}
+	switch {
+	case prev.kind == tokSemi:
+		return true
+	case wordish(prev.kind) && wordish(t.kind):
+		return true
+	case prev.kind == tokOp && t.kind == tokOp:
+		return true
+	case prev.kind == tokLineComment || prev.kind == tokBlockComment || t.kind == tokLineComment || t.kind == tokBlockComment:
+		return true
+	case t.kind == tokOpen && prev.kind == tokWord:
+		// 保留关键字与括号之间的空格（如 IN (...)），函数调用不加
+		return t.space
+	case prev.kind == tokDot && t.kind == tokNumber, prev.kind == tokNumber && t.kind == tokDot:
+		return true
+	}
+	return false
+}
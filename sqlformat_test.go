@@ -0,0 +1,166 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenizeSQL(t *testing.T) {
+	type tok struct {
+		kind sqlTokenKind
+		text string
+	}
+	tests := []struct {
+		name    string
+		dialect string
+		sql     string
+		want    []tok
+	}{
+		{"mysql quoting", "mysql", "SELECT `a``b`, \"x\\\"y\", 'it''s' FROM t", []tok{
+			{tokWord, "SELECT"}, {tokQuoted, "`a``b`"}, {tokComma, ","}, {tokString, `"x\"y"`}, {tokComma, ","},
+			{tokString, "'it''s'"}, {tokWord, "FROM"}, {tokWord, "t"},
+		}},
+		{"mysql comments", "mysql", "a -- c;\nb # d\nc --e", []tok{
+			{tokWord, "a"}, {tokLineComment, "-- c;"}, {tokWord, "b"}, {tokLineComment, "# d"},
+			{tokWord, "c"}, {tokOp, "-"}, {tokOp, "-"}, {tokWord, "e"},
+		}},
+		{"hints and conditional comments", "mysql", "SELECT /*+ NO_ICP(t) */ 1 /*!80000 , 2 */;", []tok{
+			{tokWord, "SELECT"}, {tokBlockComment, "/*+ NO_ICP(t) */"}, {tokNumber, "1"}, {tokBlockComment, "/*!80000 , 2 */"}, {tokSemi, ";"},
+		}},
+		{"mysql literals and variables", "mysql", "x'0F' _utf8mb4'a\\'b' 0x1F 1.5e-3 @v @@global.x @`q v` a.b", []tok{
+			{tokString, "x'0F'"}, {tokString, "_utf8mb4'a\\'b'"}, {tokNumber, "0x1F"}, {tokNumber, "1.5e-3"},
+			{tokVar, "@v"}, {tokVar, "@@global.x"}, {tokVar, "@`q v`"}, {tokWord, "a"}, {tokDot, "."}, {tokWord, "b"},
+		}},
+		{"operators", "mysql", "a<=>b->>'$.x' != c", []tok{
+			{tokWord, "a"}, {tokOp, "<=>"}, {tokWord, "b"}, {tokOp, "->>"}, {tokString, "'$.x'"}, {tokOp, "!="}, {tokWord, "c"},
+		}},
+		{"oracle q strings", "oracle", "q'[it's; ok]' Q'{a}' nq'<b>' q'!c!'", []tok{
+			{tokString, "q'[it's; ok]'"}, {tokString, "Q'{a}'"}, {tokString, "nq'<b>'"}, {tokString, "q'!c!'"},
+		}},
+		{"oracle identifiers and binds", "oracle", "SELECT \"Col\", a#b, :name FROM t --x", []tok{
+			{tokWord, "SELECT"}, {tokQuoted, "\"Col\""}, {tokComma, ","}, {tokWord, "a#b"}, {tokComma, ","},
+			{tokVar, ":name"}, {tokWord, "FROM"}, {tokWord, "t"}, {tokLineComment, "--x"},
+		}},
+		{"oracle backslash is literal", "oracle", `'a\' || 'b'`, []tok{
+			{tokString, `'a\'`}, {tokOp, "||"}, {tokString, "'b'"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := tokenizeSQL(tt.sql, tt.dialect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []tok
+			for _, tk := range tokens {
+				if tk.text != tt.sql[tk.start:tk.end] {
+					t.Errorf("token %q does not match its source range %q", tk.text, tt.sql[tk.start:tk.end])
+				}
+				got = append(got, tok{tk.kind, tk.text})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeSQLErrors(t *testing.T) {
+	tests := []struct{ dialect, sql string }{
+		{"mysql", "SELECT 'abc"},
+		{"mysql", "SELECT 'a\\'"},
+		{"mysql", "SELECT `a"},
+		{"mysql", "SELECT /* x"},
+		{"oracle", "SELECT \"a"},
+		{"oracle", "SELECT q'[a]"},
+	}
+	for _, tt := range tests {
+		if _, err := tokenizeSQL(tt.sql, tt.dialect); err == nil {
+			t.Errorf("%s %q tokenized without error", tt.dialect, tt.sql)
+		}
+	}
+}
+
+func TestFormatSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		sql     string
+		opts    FormatOptions
+		want    string
+	}{
+		{"clauses and subquery", "mysql", "select a, b from t where x = 1 and y in (select id from u) order by a desc limit 10", FormatOptions{},
+			"SELECT\n  a,\n  b\nFROM\n  t\nWHERE\n  x = 1\n  AND y IN (\n    SELECT\n      id\n    FROM\n      u\n  )\nORDER BY\n  a DESC\nLIMIT 10\n"},
+		{"comments hints and statements", "mysql", "SELECT /*+ BKA(t) */ a FROM t -- note\nWHERE b = 'x;y'; update t set a=1 where id=2", FormatOptions{},
+			"SELECT\n  /*+ BKA(t) */ a\nFROM\n  t -- note\nWHERE\n  b = 'x;y';\n\nUPDATE\n  t\nSET\n  a = 1\nWHERE\n  id = 2\n"},
+		{"oracle", "oracle", "select q'[a'b]' as s, :v from dual where rownum <= 1", FormatOptions{KeywordCase: "lower", IndentSize: 4},
+			"select\n    q'[a'b]' as s,\n    :v\nfrom\n    dual\nwhere\n    rownum <= 1\n"},
+		{"delimiter block kept verbatim", "mysql", "DELIMITER ;;\nCREATE PROCEDURE p() BEGIN SELECT 1; END;;\nDELIMITER ;\nselect 1", FormatOptions{},
+			"DELIMITER ;;\nCREATE PROCEDURE p() BEGIN SELECT 1; END;;\n\nDELIMITER ;\n\nSELECT\n  1\n"},
+		{"minify", "mysql", "SELECT /*+ BKA(t) */ a FROM t -- note\nWHERE b = 'x;y'; update t set a=1 where id=2", FormatOptions{Minify: true},
+			"SELECT /*+ BKA(t) */ a FROM t /* note */ WHERE b='x;y'; update t set a=1 where id=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatSQL(tt.sql, tt.dialect, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimRight(got, "\n") != strings.TrimRight(tt.want, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// 格式化与压缩只改变空白、关键字大小写和注释写法，语句本身的单元序列不变，且再次格式化结果相同
+func TestFormatSQLPreservesStatements(t *testing.T) {
+	corpus := []struct{ dialect, sql string }{
+		{"mysql", "select a.id, count(*) as n from `order` a left join u on u.id = a.uid where a.x between 1 and 2 group by a.id having n > 1"},
+		{"mysql", "insert into t (a, b) values (1, 'x'), (2, \"y\") on duplicate key update b = values(b)"},
+		{"mysql", "update t set a = a - -1, b = case when c then 1 else 2 end where d is not null -- tail"},
+		{"mysql", "with recursive c(n) as (select 1 union all select n + 1 from c where n < 5) select * from c"},
+		{"mysql", "SELECT 1 /*!80000 + 1 */; SET @@session.x = 1; SELECT @a := 1"},
+		{"oracle", "select e.* from emp e where e.deptno = :d and e.name like q'{%'%}' fetch first 10 rows only"},
+		{"oracle", "merge into t using s on (t.id = s.id) when matched then update set t.v = s.v when not matched then insert (id, v) values (s.id, s.v)"},
+	}
+	units := func(t *testing.T, sql, dialect string) []string {
+		t.Helper()
+		tokens, err := tokenizeSQL(sql, dialect)
+		if err != nil {
+			t.Fatalf("re-tokenize %q: %v", sql, err)
+		}
+		var out []string
+		for _, tk := range significantTokens(tokens) {
+			text := tk.text
+			if tk.kind == tokWord {
+				text = strings.ToUpper(text)
+			}
+			out = append(out, text)
+		}
+		return out
+	}
+	for _, c := range corpus {
+		want := units(t, c.sql, c.dialect)
+		for _, opts := range []FormatOptions{{}, {KeywordCase: "lower"}, {Minify: true}} {
+			got, err := formatSQL(c.sql, c.dialect, opts)
+			if err != nil {
+				t.Fatalf("%q: %v", c.sql, err)
+			}
+			if u := units(t, got, c.dialect); !reflect.DeepEqual(u, want) {
+				t.Errorf("%+v changed the statement:\n%s\ngot  %q\nwant %q", opts, got, u, want)
+			}
+			again, _ := formatSQL(got, c.dialect, opts)
+			if again != got {
+				t.Errorf("%+v is not idempotent:\n%s\n---\n%s", opts, got, again)
+			}
+		}
+	}
+}
+
+func TestFormatSQLRejectsUnknownKeywordCase(t *testing.T) {
+	if _, err := formatSQL("select 1", "mysql", FormatOptions{KeywordCase: "title"}); err == nil {
+		t.Error("unknown keyword case accepted")
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// sqlStatement 从SQL文件中切分出的一条语句
+type sqlStatement struct {
+	SQL    string
+	Line   int   // 语句起始行号（从1开始）
+	Offset int64 // 读完该语句后在文件中的字节偏移
+}
+
+// sqlStatementReader 流式切分SQL文件：
+// 跳过 -- / # / 普通块注释，保留 /*! */ 条件注释与 /*+ */ 提示，
+// 支持 DELIMITER 命令以及引号、反引号内的分隔符
+type sqlStatementReader struct {
+	r      *bufio.Reader
+	delim  string
+	offset int64
+	line   int
+	buf    bytes.Buffer
+}
+
+func newSQLStatementReader(r io.Reader) *sqlStatementReader {
+	return &sqlStatementReader{r: bufio.NewReaderSize(r, 1<<20), delim: ";", line: 1}
+}
+
+func (s *sqlStatementReader) readByte() (byte, error) {
+	b, err := s.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	s.offset++
+	if b == '\n' {
+		s.line++
+	}
+	return b, nil
+}
+
+func (s *sqlStatementReader) peek(n int) []byte {
+	b, _ := s.r.Peek(n)
+	return b
+}
+
+// skipLine 跳过到行尾（含换行符）
+func (s *sqlStatementReader) skipLine() error {
+	for {
+		b, err := s.readByte()
+		if err != nil {
+			return err
+		}
+		if b == '\n' {
+			return nil
+		}
+	}
+}
+
+// readLine 读取到行尾，返回不含换行符的内容
+func (s *sqlStatementReader) readLine() (string, error) {
+	var sb strings.Builder
+	for {
+		b, err := s.readByte()
+		if err != nil {
+			return sb.String(), err
+		}
+		if b == '\n' {
+			return sb.String(), nil
+		}
+		sb.WriteByte(b)
+	}
+}
+
+func isSpaceByte(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f' || b == '\v'
+}
+
+// Next 返回下一条语句，文件结束时返回 io.EOF
+func (s *sqlStatementReader) Next() (sqlStatement, error) {
+	s.buf.Reset()
+	startLine := 0
+	started := false
+	var quote byte
+
+	emit := func() (sqlStatement, bool) {
+		text := strings.TrimSpace(s.buf.String())
+		s.buf.Reset()
+		started = false
+		if text == "" {
+			return sqlStatement{}, false
+		}
+		return sqlStatement{SQL: text, Line: startLine, Offset: s.offset}, true
+	}
+
+	for {
+		b, err := s.readByte()
+		if err != nil {
+			if err == io.EOF {
+				if st, ok := emit(); ok {
+					return st, nil
+				}
+			}
+			return sqlStatement{}, err
+		}
+
+		if quote != 0 {
+			s.buf.WriteByte(b)
+			if b == '\\' && quote != '`' {
+				next, err := s.readByte()
+				if err != nil {
+					continue
+				}
+				s.buf.WriteByte(next)
+				continue
+			}
+			if b == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		empty := !started
+		if empty && isSpaceByte(b) {
+			continue
+		}
+
+		switch b {
+		case '\'', '"', '`':
+			quote = b
+		case '-':
+			if p := s.peek(2); len(p) >= 1 && p[0] == '-' && (len(p) < 2 || isSpaceByte(p[1])) {
+				if err := s.skipLine(); err != nil && err != io.EOF {
+					return sqlStatement{}, err
+				}
+				if !empty {
+					s.buf.WriteByte('\n')
+				}
+				continue
+			}
+		case '#':
+			if err := s.skipLine(); err != nil && err != io.EOF {
+				return sqlStatement{}, err
+			}
+			if !empty {
+				s.buf.WriteByte('\n')
+			}
+			continue
+		case '/':
+			if p := s.peek(2); len(p) >= 1 && p[0] == '*' {
+				keep := len(p) == 2 && (p[1] == '!' || p[1] == '+')
+				if keep && empty {
+					started = true
+					startLine = s.line
+				}
+				if err := s.readBlockComment(keep, !empty); err != nil {
+					return sqlStatement{}, err
+				}
+				continue
+			}
+		case 'D', 'd':
+			if empty {
+				if p := s.peek(9); len(p) == 9 && strings.EqualFold(string(p[:8]), "ELIMITER") && isSpaceByte(p[8]) {
+					line, err := s.readLine()
+					if err != nil && err != io.EOF {
+						return sqlStatement{}, err
+					}
+					if d := strings.TrimSpace(line[8:]); d != "" {
+						s.delim = d
+					}
+					continue
+				}
+			}
+		}
+
+		if empty {
+			started = true
+			startLine = s.line
+		}
+		s.buf.WriteByte(b)
+		if quote == 0 && b == s.delim[len(s.delim)-1] && bytes.HasSuffix(s.buf.Bytes(), []byte(s.delim)) {
+			s.buf.Truncate(s.buf.Len() - len(s.delim))
+			if st, ok := emit(); ok {
+				return st, nil
+			}
+		}
+	}
+}
+
+// readBlockComment 读取块注释（已读入开头的 '/'）；keep 为 true 时原样保留在语句中，
+// 否则在语句中间用一个空格代替
+func (s *sqlStatementReader) readBlockComment(keep bool, inStatement bool) error {
+	if keep {
+		s.buf.WriteByte('/')
+	}
+	var prev byte
+	first := true
+	for {
+		b, err := s.readByte()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if keep {
+			s.buf.WriteByte(b)
+		}
+		// 开头的 '*' 不能与后续字符组成结束符
+		if !first && prev == '*' && b == '/' {
+			if !keep && inStatement {
+				s.buf.WriteByte(' ')
+			}
+			return nil
+		}
+		if first {
+			first = false
+			prev = 0
+			continue
+		}
+		prev = b
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func splitSQLForTest(t *testing.T, script string) []sqlStatement {
+	t.Helper()
+	r := newSQLStatementReader(strings.NewReader(script))
+	var out []sqlStatement
+	for {
+		st, err := r.Next()
+		if err == io.EOF {
+			return out
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		out = append(out, st)
+	}
+}
+
+func TestSQLStatementReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"basic", "SELECT 1; SELECT 2;\n", []string{"SELECT 1", "SELECT 2"}},
+		{"no trailing delimiter", "SELECT 1;\nSELECT 2", []string{"SELECT 1", "SELECT 2"}},
+		{"empty statements", ";;\n ; SELECT 1;;", []string{"SELECT 1"}},
+		{"quoted delimiters", "INSERT INTO t VALUES ('a;b', \"c;d\");\nSELECT `e;f` FROM t;",
+			[]string{"INSERT INTO t VALUES ('a;b', \"c;d\")", "SELECT `e;f` FROM t"}},
+		{"backslash escape", `SELECT 'it\'s;'; SELECT "\\";`, []string{`SELECT 'it\'s;'`, `SELECT "\\"`}},
+		{"doubled quote", "SELECT 'a'';b'; SELECT 2;", []string{"SELECT 'a'';b'", "SELECT 2"}},
+		{"backslash in backtick", "SELECT `a\\`; SELECT 2;", []string{"SELECT `a\\`", "SELECT 2"}},
+		{"line comments", "-- drop;\nSELECT 1; # x;\nSELECT 2 -- y;\n;", []string{"SELECT 1", "SELECT 2"}},
+		{"double dash without space", "SELECT 1--1;", []string{"SELECT 1--1"}},
+		{"block comment", "/* a; */ SELECT /* b; */ 1;", []string{"SELECT   1"}},
+		{"block comment star", "SELECT /*/ ; */ 1;", []string{"SELECT   1"}},
+		{"conditional comment", "/*!40101 SET NAMES utf8mb4 */;\n/*!50503 SET character_set_client = utf8mb4 */;",
+			[]string{"/*!40101 SET NAMES utf8mb4 */", "/*!50503 SET character_set_client = utf8mb4 */"}},
+		{"optimizer hint", "SELECT /*+ MAX_EXECUTION_TIME(1000) */ id FROM t;", []string{"SELECT /*+ MAX_EXECUTION_TIME(1000) */ id FROM t"}},
+		{"delimiter", "DELIMITER ;;\nCREATE TRIGGER tr BEFORE INSERT ON t FOR EACH ROW BEGIN SET NEW.a = 1; SET NEW.b = ';;'; END ;;\nDELIMITER ;\nSELECT 1;",
+			[]string{"CREATE TRIGGER tr BEFORE INSERT ON t FOR EACH ROW BEGIN SET NEW.a = 1; SET NEW.b = ';;'; END", "SELECT 1"}},
+		{"multi-char delimiter", "delimiter $$\nCREATE PROCEDURE p() BEGIN SELECT 1; END$$\nCALL p()$$",
+			[]string{"CREATE PROCEDURE p() BEGIN SELECT 1; END", "CALL p()"}},
+		{"delimiter word inside statement", "SELECT delimiter FROM t;", []string{"SELECT delimiter FROM t"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, st := range splitSQLForTest(t, tt.script) {
+				got = append(got, st.SQL)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLStatementReaderPositions(t *testing.T) {
+	script := "-- header\n\nSELECT 1;\n/*!40101 SET a = 1 */;\nINSERT INTO t\nVALUES (1);"
+	got := splitSQLForTest(t, script)
+	wantLines := []int{3, 4, 5}
+	if len(got) != len(wantLines) {
+		t.Fatalf("got %d statements", len(got))
+	}
+	for i, st := range got {
+		if st.Line != wantLines[i] {
+			t.Errorf("statement %d line = %d, want %d", i, st.Line, wantLines[i])
+		}
+	}
+	if end := got[0].Offset; script[:end] != "-- header\n\nSELECT 1;" {
+		t.Errorf("first offset %d covers %q", end, script[:end])
+	}
+	if got[2].Offset != int64(len(script)) {
+		t.Errorf("last offset = %d, want %d", got[2].Offset, len(script))
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SyncMapping 同步时的表名、列名映射与值转换；为空时两端名称一致
+type SyncMapping struct {
+	TableRules  []NameRule              `json:"tableRules"`  // 表名改名规则，按顺序依次应用
+	ColumnRules []NameRule              `json:"columnRules"` // 列名改名规则，对所有表生效
+	Tables      map[string]TableMapping `json:"tables"`      // 按源表名单独指定，优先于规则
+}
+
+// NameRule 改名规则：Regex 为 true 时 Match 为正则、Replace 可引用分组（$1），
+// 否则名称等于 Match 时替换为 Replace；Match 为空表示对所有名称生效。
+// Case 为 lower / upper 时在替换后转换大小写
+type NameRule struct {
+	Match   string `json:"match"`
+	Replace string `json:"replace"`
+	Regex   bool   `json:"regex"`
+	Case    string `json:"case"`
+}
+
+// TableMapping 单表映射
+type TableMapping struct {
+	Target     string            `json:"target"`     // 目标表名，为空时按规则生成
+	Include    []string          `json:"include"`    // 只同步这些列，为空表示全部
+	Exclude    []string          `json:"exclude"`    // 不同步的列
+	Columns    map[string]string `json:"columns"`    // 源列名 → 目标列名
+	Transforms map[string]string `json:"transforms"` // 源列名 → trim / ltrim / rtrim / lower / upper
+}
+
+// validateSyncMapping 校验正则与值转换
+func validateSyncMapping(m SyncMapping) error {
+	for _, rules := range [][]NameRule{m.TableRules, m.ColumnRules} {
+		for _, r := range rules {
+			if r.Regex {
+				if _, err := regexp.Compile(r.Match); err != nil {
+					return fmt.Errorf("改名规则正则无效 %s: %v", r.Match, err)
+				}
+			}
+			switch r.Case {
+			case "", "lower", "upper":
+			default:
+				return fmt.Errorf("不支持的大小写转换: %s", r.Case)
+			}
+		}
+	}
+	for table, tm := range m.Tables {
+		for col, t := range tm.Transforms {
+			switch t {
+			case "", "trim", "ltrim", "rtrim", "lower", "upper":
+			default:
+				return fmt.Errorf("表 %s 列 %s 不支持的值转换: %s", table, col, t)
+			}
+		}
+	}
+	return nil
+}
+
+// applyNameRules 依次应用改名规则
+func applyNameRules(name string, rules []NameRule) string {
+	for _, r := range rules {
+		switch {
+		case r.Regex:
+			re, err := regexp.Compile(r.Match)
+			if err != nil {
+				continue
+			}
+			name = re.ReplaceAllString(name, r.Replace)
+		case r.Match == "":
+		case r.Match == name:
+			name = r.Replace
+		default:
+			continue
+		}
+		switch r.Case {
+		case "lower":
+			name = strings.ToLower(name)
+		case "upper":
+			name = strings.ToUpper(name)
+		}
+	}
+	return name
+}
+
+// tableMapper 单表生效的映射
+type tableMapper struct {
+	source     string
+	target     string
+	mapping    *SyncMapping
+	table      TableMapping
+	include    map[string]bool
+	exclude    map[string]bool
+	transforms map[string]string
+	masks      *maskTableRules // 脱敏规则，未启用脱敏时为 nil
+}
+
+// forTable 返回源表的映射
+func (m *SyncMapping) forTable(table string) *tableMapper {
+	tm := &tableMapper{source: table, mapping: m, table: m.Tables[table], exclude: map[string]bool{}, transforms: map[string]string{}}
+	tm.target = tm.table.Target
+	if tm.target == "" {
+		tm.target = applyNameRules(table, m.TableRules)
+	}
+	if len(tm.table.Include) > 0 {
+		tm.include = map[string]bool{}
+		for _, c := range tm.table.Include {
+			tm.include[strings.ToLower(c)] = true
+		}
+	}
+	for _, c := range tm.table.Exclude {
+		tm.exclude[strings.ToLower(c)] = true
+	}
+	for c, t := range tm.table.Transforms {
+		tm.transforms[strings.ToLower(c)] = t
+	}
+	return tm
+}
+
+// empty 是否未配置任何映射
+func (m *SyncMapping) empty() bool {
+	return len(m.TableRules) == 0 && len(m.ColumnRules) == 0 && len(m.Tables) == 0
+}
+
+// identity 映射是否不改变表结构与数据
+func (tm *tableMapper) identity() bool {
+	return tm.target == tm.source && len(tm.mapping.ColumnRules) == 0 && len(tm.table.Columns) == 0 &&
+		tm.include == nil && len(tm.exclude) == 0 && len(tm.transforms) == 0 && tm.masks == nil
+}
+
+// column 返回源列对应的目标列名，列被排除时 ok 为 false
+func (tm *tableMapper) column(name string) (string, bool) {
+	key := strings.ToLower(name)
+	if tm.exclude[key] || (tm.include != nil && !tm.include[key]) {
+		return "", false
+	}
+	for src, dst := range tm.table.Columns {
+		if strings.EqualFold(src, name) && dst != "" {
+			return dst, true
+		}
+	}
+	return applyNameRules(name, tm.mapping.ColumnRules), true
+}
+
+// transform 对写入目标的值做转换（仅作用于文本），再按脱敏规则处理
+func (tm *tableMapper) transform(column string, v interface{}) interface{} {
+	return tm.masks.apply(column, tm.convert(column, v))
+}
+
+func (tm *tableMapper) convert(column string, v interface{}) interface{} {
+	t := tm.transforms[strings.ToLower(column)]
+	if t == "" {
+		return v
+	}
+	var s string
+	switch x := v.(type) {
+	case []byte:
+		s = string(x)
+	case string:
+		s = x
+	default:
+		return v
+	}
+	switch t {
+	case "trim":
+		s = strings.TrimSpace(s)
+	case "ltrim":
+		s = strings.TrimLeft(s, " \t\r\n")
+	case "rtrim":
+		s = strings.TrimRight(s, " \t\r\n")
+	case "lower":
+		s = strings.ToLower(s)
+	case "upper":
+		s = strings.ToUpper(s)
+	}
+	return s
+}
+
+var (
+	ddlIdentRe      = regexp.MustCompile("`((?:[^`]|``)*)`")
+	ddlForeignKeyRe = regexp.MustCompile("^(\\s*CONSTRAINT `(?:[^`]|``)*` FOREIGN KEY )\\(([^)]*)\\)( REFERENCES )(?:`((?:[^`]|``)*)`\\.)?`((?:[^`]|``)*)` \\(([^)]*)\\)(.*)$")
+)
+
+// mapIdentList 替换标识符列表中的列名，有列被排除时 ok 为 false
+func mapIdentList(list string, tm *tableMapper) (string, bool) {
+	ok := true
+	out := ddlIdentRe.ReplaceAllStringFunc(list, func(ident string) string {
+		name := strings.ReplaceAll(ident[1:len(ident)-1], "``", "`")
+		dst, keep := tm.column(name)
+		if !keep {
+			ok = false
+			return ident
+		}
+		return "`" + strings.ReplaceAll(dst, "`", "``") + "`"
+	})
+	return out, ok
+}
+
+// rewriteCreateTable 把源表建表语句改写为目标表：替换表名与列名，删除被排除的列
+// 以及引用了这些列的索引和约束；外键引用的表与列按被引用表的映射改写
+func (tm *tableMapper) rewriteCreateTable(ddl string) string {
+	if tm.mapping.empty() {
+		return ddl
+	}
+	lines := strings.Split(ddl, "\n")
+	kept := make([]string, 0, len(lines))
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case i == 0:
+			if loc := ddlIdentRe.FindStringIndex(line); loc != nil {
+				line = line[:loc[0]] + "`" + strings.ReplaceAll(tm.target, "`", "``") + "`" + line[loc[1]:]
+			}
+		case strings.HasPrefix(trimmed, ")"):
+		case strings.HasPrefix(trimmed, "`"):
+			// 列定义：只替换行首的列名，生成列表达式中的引用一并替换
+			loc := ddlIdentRe.FindStringIndex(line)
+			name := strings.ReplaceAll(line[loc[0]+1:loc[1]-1], "``", "`")
+			dst, ok := tm.column(name)
+			if !ok {
+				continue
+			}
+			rest := line[loc[1]:]
+			if strings.Contains(rest, " AS (") {
+				if mapped, ok := mapIdentList(rest, tm); ok {
+					rest = mapped
+				} else {
+					continue
+				}
+			}
+			line = line[:loc[0]] + "`" + strings.ReplaceAll(dst, "`", "``") + "`" + rest
+		case ddlForeignKeyRe.MatchString(line):
+			m := ddlForeignKeyRe.FindStringSubmatch(line)
+			cols, ok := mapIdentList(m[2], tm)
+			if !ok {
+				continue
+			}
+			if m[4] != "" {
+				// 引用其他库的表，保持原样
+				line = m[1] + "(" + cols + ")" + line[len(m[1])+len(m[2])+2:]
+				break
+			}
+			ref := tm.mapping.forTable(strings.ReplaceAll(m[5], "``", "`"))
+			refCols, ok := mapIdentList(m[6], ref)
+			if !ok {
+				continue
+			}
+			line = m[1] + "(" + cols + ")" + m[3] + "`" + strings.ReplaceAll(ref.target, "`", "``") + "` (" + refCols + ")" + m[7]
+		default:
+			// 索引与 CHECK 约束：名称在括号之前，括号内为列引用
+			idx := strings.Index(line, "(")
+			if idx < 0 {
+				break
+			}
+			rest, ok := mapIdentList(line[idx:], tm)
+			if !ok {
+				continue
+			}
+			line = line[:idx] + rest
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(fixDefinitionCommas(kept), "\n")
+}
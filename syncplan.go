@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SyncPlan 同步演练结果：每张表的检查结果、计划操作与语句，以及可供审阅的 SQL 脚本
+type SyncPlan struct {
+	SourceDB       string              `json:"sourceDb"`
+	TargetDB       string              `json:"targetDb"`
+	Mode           string              `json:"mode"`
+	CreateDatabase bool                `json:"createDatabase"` // 目标库不存在，同步时会自动创建
+	Rows           []MigrationCheckRow `json:"rows"`
+	Script         string              `json:"script"`
+}
+
+// DryRunSync 演练同步：执行与 SyncDatabase 相同的检查（表是否存在、行数、结构与数据冲突），
+// 返回每张表的计划操作与将执行的语句，不写入目标库
+func (a *App) DryRunSync(source DBConfig, sourceDB string, target DBConfig, targetDB string, mode string, tables []string, opts SyncOptions) (SyncPlan, error) {
+	job, err := a.SubmitDryRunSyncJob(source, sourceDB, target, targetDB, mode, tables, opts)
+	if err != nil {
+		return SyncPlan{}, err
+	}
+	res, err := a.jobs.wait(job.ID)
+	if err != nil {
+		return SyncPlan{}, err
+	}
+	plan, _ := res.(SyncPlan)
+	return plan, nil
+}
+
+// SubmitDryRunSyncJob 提交同步演练任务，立即返回任务信息
+func (a *App) SubmitDryRunSyncJob(source DBConfig, sourceDB string, target DBConfig, targetDB string, mode string, tables []string, opts SyncOptions) (Job, error) {
+	if normalizeDBType(target.Type) != "mysql" {
+		return Job{}, fmt.Errorf("当前仅支持同步到MySQL")
+	}
+	if sourceDB == "" || targetDB == "" {
+		return Job{}, fmt.Errorf("源库和目标库不能为空")
+	}
+	if err := validateSyncPolicies(opts); err != nil {
+		return Job{}, err
+	}
+	if err := validateSyncMapping(opts.Mapping); err != nil {
+		return Job{}, err
+	}
+	title := fmt.Sprintf("演练同步 %s → %s", sourceDB, targetDB)
+	job := a.jobs.submit("check", title, func(ctx context.Context, r *jobReporter) (interface{}, error) {
+		plan := SyncPlan{SourceDB: sourceDB, TargetDB: targetDB, Mode: mode}
+		rows, err := runSyncDatabase(ctx, r, source, sourceDB, target, targetDB, mode, tables, opts, nil, &plan)
+		if err != nil {
+			return nil, err
+		}
+		plan.Rows = rows
+		plan.Script = buildSyncPlanScript(plan)
+		return plan, nil
+	})
+	return job, nil
+}
+
+// databaseExists 检查目标实例上是否已有该库
+func databaseExists(cfg DBConfig, database string) (bool, error) {
+	cfg.Database = ""
+	dsn, err := buildDSN(cfg)
+	if err != nil {
+		return false, err
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+	var cnt int
+	if err := db.QueryRow("SELECT COUNT(1) FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?", database).Scan(&cnt); err != nil {
+		return false, err
+	}
+	return cnt > 0, nil
+}
+
+// buildSyncPlanScript 把演练计划整理为 SQL 脚本：结构语句按表顺序排列，
+// 数据复制与检查失败的表以注释说明
+func buildSyncPlanScript(plan SyncPlan) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- 同步演练：%s → %s（模式 %s）\n", plan.SourceDB, plan.TargetDB, plan.Mode)
+	fmt.Fprintf(&b, "-- 生成时间：%s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+	if plan.CreateDatabase {
+		fmt.Fprintf(&b, "CREATE DATABASE IF NOT EXISTS `%s`;\n", plan.TargetDB)
+	}
+	fmt.Fprintf(&b, "USE `%s`;\n", plan.TargetDB)
+	b.WriteString("SET FOREIGN_KEY_CHECKS = 0;\n")
+	for _, row := range plan.Rows {
+		target := row.Name
+		if row.Target != "" {
+			target = row.Target
+		}
+		fmt.Fprintf(&b, "\n-- 表 %s → %s：源 %d 行，目标 %d 行\n", row.Name, target, row.SourceRows, row.TargetRows)
+		if strings.HasPrefix(row.Status, "failed") {
+			fmt.Fprintf(&b, "-- 检查不通过，将跳过：%s\n", strings.TrimPrefix(row.Status, "failed: "))
+			continue
+		}
+		for _, action := range row.Actions {
+			fmt.Fprintf(&b, "-- %s\n", action)
+		}
+		for _, stmt := range row.DDL {
+			b.WriteString(strings.TrimRight(stmt, "; \n"))
+			b.WriteString(";\n")
+		}
+	}
+	b.WriteString("\nSET FOREIGN_KEY_CHECKS = 1;\n")
+	return b.String()
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// syncSource 同步任务的数据源：负责列出表、统计行数、给出目标库（MySQL）的建表语句、
+// 规划分片以及按分片读取数据；读取结果的列名即目标表列名，值可直接写入 MySQL
+type syncSource interface {
+	tables() ([]string, error)
+	countRows(table string) (int64, error)
+	createTableSQL(table string) (string, error)
+	planChunks(table string, rows int64, chunkRows int64) ([]tableChunk, error)
+	queryChunk(ctx context.Context, table string, chunk tableChunk) (*sourceRows, error)
+	close() error
+}
+
+// sourceRows 分片读取结果；Close 时归还读取占用的连接
+type sourceRows struct {
+	*sql.Rows
+	release func()
+}
+
+func (r *sourceRows) Close() error {
+	err := r.Rows.Close()
+	if r.release != nil {
+		r.release()
+		r.release = nil
+	}
+	return err
+}
+
+// openSyncSource 按源连接类型打开数据源
+func openSyncSource(cfg DBConfig, database string, opts SyncOptions, poolSize int) (syncSource, error) {
+	switch normalizeDBType(cfg.Type) {
+	case "oracle":
+		return openOracleSyncSource(cfg, database, opts.TypeMappings, poolSize)
+	default:
+		cfg.Database = database
+		dsn, err := buildDSNWithParams(cfg, map[string]string{"time_zone": syncSessionTimeZone})
+		if err != nil {
+			return nil, err
+		}
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return nil, err
+		}
+		src := &mysqlSyncSource{db: db, database: database}
+		if opts.onSnapshot == nil {
+			db.SetMaxOpenConns(poolSize)
+			db.SetMaxIdleConns(poolSize)
+			return src, nil
+		}
+		// 快照连接在任务期间一直占用，连接池需额外留出
+		db.SetMaxOpenConns(poolSize + opts.Workers)
+		db.SetMaxIdleConns(poolSize)
+		snap, err := openMySQLSnapshotSource(src, opts.Workers, opts.onSnapshot)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		return snap, nil
+	}
+}
+
+// mysqlSyncSource MySQL 源，表结构与数据原样复制
+type mysqlSyncSource struct {
+	db       *sql.DB
+	database string
+}
+
+func (s *mysqlSyncSource) tables() ([]string, error) {
+	return fetchAllTableNames(s.db, s.database)
+}
+
+func (s *mysqlSyncSource) countRows(table string) (int64, error) {
+	return countRows(s.db, s.database, table)
+}
+
+func (s *mysqlSyncSource) createTableSQL(table string) (string, error) {
+	return showCreateTable(s.db, s.database, table)
+}
+
+func (s *mysqlSyncSource) planChunks(table string, rows int64, chunkRows int64) ([]tableChunk, error) {
+	return planTableChunks(s.db, s.database, table, rows, chunkRows)
+}
+
+func (s *mysqlSyncSource) queryChunk(ctx context.Context, table string, chunk tableChunk) (*sourceRows, error) {
+	where, whereArgs := chunk.where()
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM `%s`.`%s`%s", s.database, table, where), whereArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return &sourceRows{Rows: rows}, nil
+}
+
+func (s *mysqlSyncSource) close() error {
+	return s.db.Close()
+}
+
+// mysqlSnapshotSource 在同一一致性快照中读取数据的 MySQL 源，用于增量同步的全量复制：
+// 持有全局读锁期间为每个读取连接开启 START TRANSACTION WITH CONSISTENT SNAPSHOT 并记录 binlog 位点，
+// 随后立即释放读锁，复制结果与该位点的源库状态一致
+type mysqlSnapshotSource struct {
+	*mysqlSyncSource
+	conns chan *sql.Conn
+	all   []*sql.Conn
+}
+
+// openMySQLSnapshotSource 建立 n 个处于同一快照的读取连接，并通过 onSnapshot 返回快照对应的位点
+func openMySQLSnapshotSource(src *mysqlSyncSource, n int, onSnapshot func(binlogPosition)) (*mysqlSnapshotSource, error) {
+	ctx := context.Background()
+	s := &mysqlSnapshotSource{mysqlSyncSource: src, conns: make(chan *sql.Conn, n)}
+	lock, err := src.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Close()
+	if _, err := lock.ExecContext(ctx, "FLUSH TABLES WITH READ LOCK"); err != nil {
+		return nil, fmt.Errorf("获取全局读锁失败（需要 RELOAD 权限）: %v", err)
+	}
+	defer lock.ExecContext(ctx, "UNLOCK TABLES")
+	for i := 0; i < n; i++ {
+		c, err := src.db.Conn(ctx)
+		if err != nil {
+			s.closeConns()
+			return nil, err
+		}
+		s.all = append(s.all, c)
+		if _, err := c.ExecContext(ctx, "SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
+			s.closeConns()
+			return nil, err
+		}
+		if _, err := c.ExecContext(ctx, "START TRANSACTION WITH CONSISTENT SNAPSHOT"); err != nil {
+			s.closeConns()
+			return nil, fmt.Errorf("开启一致性快照失败: %v", err)
+		}
+		s.conns <- c
+	}
+	pos, err := readBinlogPosition(ctx, lock)
+	if err != nil {
+		s.closeConns()
+		return nil, err
+	}
+	onSnapshot(pos)
+	return s, nil
+}
+
+func (s *mysqlSnapshotSource) queryChunk(ctx context.Context, table string, chunk tableChunk) (*sourceRows, error) {
+	var c *sql.Conn
+	select {
+	case c = <-s.conns:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	where, whereArgs := chunk.where()
+	rows, err := c.QueryContext(ctx, fmt.Sprintf("SELECT * FROM `%s`.`%s`%s", s.database, table, where), whereArgs...)
+	if err != nil {
+		s.conns <- c
+		return nil, err
+	}
+	return &sourceRows{Rows: rows, release: func() { s.conns <- c }}, nil
+}
+
+func (s *mysqlSnapshotSource) closeConns() {
+	for _, c := range s.all {
+		c.Close()
+	}
+	s.all = nil
+}
+
+func (s *mysqlSnapshotSource) close() error {
+	s.closeConns()
+	return s.mysqlSyncSource.close()
+}
@@ -0,0 +1,1682 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableModel 表设计器使用的表结构；OriginalName 为空表示新建表，与 Name 不同表示改名
+type TableModel struct {
+	Schema                string             `json:"schema"`
+	Name                  string             `json:"name"`
+	OriginalName          string             `json:"originalName"`
+	Engine                string             `json:"engine"`                // MySQL 存储引擎
+	Charset               string             `json:"charset"`               // MySQL 默认字符集
+	Collation             string             `json:"collation"`             // MySQL 默认排序规则
+	AutoIncrement         int64              `json:"autoIncrement"`         // MySQL 自增值，0 表示不指定
+	OriginalAutoIncrement int64              `json:"originalAutoIncrement"` // 打开设计器时的自增值，与 AutoIncrement 相同表示未修改
+	Comment               string             `json:"comment"`
+	Partition             string             `json:"partition"` // 分区子句原文，如 PARTITION BY HASH (`id`) PARTITIONS 4
+	Columns               []DesignColumn     `json:"columns"`
+	Indexes               []DesignIndex      `json:"indexes"`
+	ForeignKeys           []DesignForeignKey `json:"foreignKeys"`
+	Checks                []DesignCheck      `json:"checks"`
+}
+
+// DesignColumn 列定义；OriginalName 为空表示新增列，与 Name 不同表示改名
+type DesignColumn struct {
+	Name          string  `json:"name"`
+	OriginalName  string  `json:"originalName"`
+	Type          string  `json:"type"` // 完整类型，如 varchar(64)、int unsigned、NUMBER(10,2)
+	Nullable      bool    `json:"nullable"`
+	Default       *string `json:"default"`       // nil 表示没有默认值
+	DefaultIsExpr bool    `json:"defaultIsExpr"` // 默认值是表达式（如 CURRENT_TIMESTAMP），原样输出不加引号
+	AutoIncrement bool    `json:"autoIncrement"` // MySQL AUTO_INCREMENT / Oracle IDENTITY
+	OnUpdate      string  `json:"onUpdate"`      // MySQL ON UPDATE 表达式
+	Charset       string  `json:"charset"`
+	Collation     string  `json:"collation"`
+	Generated     string  `json:"generated"` // 生成列表达式
+	Stored        bool    `json:"stored"`
+	Comment       string  `json:"comment"`
+}
+
+// DesignIndex 索引或主键、唯一约束；Kind 为 primary / unique / index / fulltext / spatial
+type DesignIndex struct {
+	Name         string              `json:"name"`
+	OriginalName string              `json:"originalName"`
+	Kind         string              `json:"kind"`
+	Columns      []DesignIndexColumn `json:"columns"`
+	Comment      string              `json:"comment"`
+
+	standalone bool // Oracle 中不属于约束的唯一索引
+}
+
+// DesignIndexColumn 索引列；Length 为前缀长度（仅 MySQL），0 表示整列
+type DesignIndexColumn struct {
+	Name   string `json:"name"`
+	Length int    `json:"length"`
+	Desc   bool   `json:"desc"`
+}
+
+// DesignForeignKey 外键；引用本库的表时 RefSchema 可为空
+type DesignForeignKey struct {
+	Name       string   `json:"name"`
+	Columns    []string `json:"columns"`
+	RefSchema  string   `json:"refSchema"`
+	RefTable   string   `json:"refTable"`
+	RefColumns []string `json:"refColumns"`
+	OnDelete   string   `json:"onDelete"`
+	OnUpdate   string   `json:"onUpdate"`
+}
+
+// DesignCheck CHECK 约束；Expr 为 CHECK 后括号内的表达式原文，NotEnforced 对应 MySQL 的 NOT ENFORCED 与 Oracle 的 DISABLE
+type DesignCheck struct {
+	Name        string `json:"name"`
+	Expr        string `json:"expr"`
+	NotEnforced bool   `json:"notEnforced"`
+}
+
+// TableDDLOptions 生成选项
+type TableDDLOptions struct {
+	// Online MySQL 的 ALTER 追加 ALGORITHM=INPLACE, LOCK=NONE；Oracle 建索引使用 ONLINE
+	Online bool `json:"online"`
+}
+
+// TableDDL 生成的语句；Create 表示建表，否则为针对当前结构的最小修改
+type TableDDL struct {
+	Create     bool     `json:"create"`
+	Statements []string `json:"statements"`
+	Script     string   `json:"script"`
+	Warnings   []string `json:"warnings"`
+}
+
+// LoadTableModel 读取当前连接中已有表的结构：MySQL 解析 SHOW CREATE TABLE，Oracle 读取数据字典
+func (a *App) LoadTableModel(schema string, table string) (TableModel, error) {
+	if a.db == nil {
+		return TableModel{}, fmt.Errorf("数据库未连接")
+	}
+	schema = a.designSchema(schema)
+	if schema == "" {
+		return TableModel{}, fmt.Errorf("请先选择数据库")
+	}
+	if a.currentDBType == "oracle" {
+		return loadOracleTableModel(a.db, schema, table)
+	}
+	ddl, err := showCreateTable(a.db, schema, table)
+	if err != nil {
+		return TableModel{}, err
+	}
+	m, err := parseMySQLCreateTable(ddl)
+	if err != nil {
+		return TableModel{}, fmt.Errorf("解析建表语句失败: %v", err)
+	}
+	m.Schema = schema
+	m.OriginalName = m.Name
+	m.OriginalAutoIncrement = m.AutoIncrement
+	for i := range m.Columns {
+		m.Columns[i].OriginalName = m.Columns[i].Name
+	}
+	for i := range m.Indexes {
+		m.Indexes[i].OriginalName = m.Indexes[i].Name
+	}
+	if m.Charset == "" && m.Collation != "" {
+		m.Charset = collationCharset(m.Collation)
+	}
+	return m, nil
+}
+
+// PreviewTableDDL 生成建表语句，或与当前结构比较后生成最小的 ALTER TABLE
+func (a *App) PreviewTableDDL(model TableModel, opts TableDDLOptions) (TableDDL, error) {
+	if a.db == nil {
+		return TableDDL{}, fmt.Errorf("数据库未连接")
+	}
+	return a.tableDDL(model, opts)
+}
+
+// ExecuteTableDDL 重新生成并依次执行语句；DDL 会隐式提交，失败时之前的语句已经生效
+func (a *App) ExecuteTableDDL(model TableModel, opts TableDDLOptions, confirmToken string) (res TableDDL, err error) {
+	if a.db == nil {
+		return TableDDL{}, fmt.Errorf("数据库未连接")
+	}
+	start := time.Now()
+	defer func() {
+		if res.Script != "" {
+			a.auditStatement("ddl", res.Script, start, 0, err)
+		}
+	}()
+	res, err = a.tableDDL(model, opts)
+	if err != nil || len(res.Statements) == 0 {
+		return res, err
+	}
+	if _, err := a.guardQuery(strings.Join(res.Statements, ";\n"), confirmToken); err != nil {
+		return res, err
+	}
+	defer a.meta.invalidate(a.currentCfg, a.designSchema(model.Schema))
+	for i, stmt := range res.Statements {
+		if _, err := a.db.Exec(stmt); err != nil {
+			if i > 0 {
+				return res, fmt.Errorf("第 %d 条语句执行失败（之前的 %d 条已生效）: %v", i+1, i, err)
+			}
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// designSchema 未指定库时使用当前库
+func (a *App) designSchema(schema string) string {
+	if schema == "" {
+		schema = a.currentSchema
+	}
+	if schema == "" && a.currentDBType == "oracle" {
+		_ = a.db.QueryRow("SELECT SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') FROM DUAL").Scan(&schema)
+	}
+	if a.currentDBType == "oracle" {
+		schema = strings.ToUpper(schema)
+	}
+	return schema
+}
+
+func (a *App) tableDDL(model TableModel, opts TableDDLOptions) (TableDDL, error) {
+	dialect := a.currentDBType
+	model.Schema = a.designSchema(model.Schema)
+	if model.Schema == "" {
+		return TableDDL{}, fmt.Errorf("请先选择数据库")
+	}
+	if dialect == "oracle" {
+		normalizeOracleModel(&model)
+	}
+	if err := validateTableModel(&model); err != nil {
+		return TableDDL{}, err
+	}
+	g := &tableDDLGen{dialect: dialect, opts: opts}
+	if model.OriginalName == "" {
+		g.create(&model)
+	} else {
+		old, err := a.LoadTableModel(model.Schema, model.OriginalName)
+		if err != nil {
+			return TableDDL{}, fmt.Errorf("读取表 %s 的当前结构失败: %v", model.OriginalName, err)
+		}
+		if dialect == "oracle" {
+			g.alterOracle(&old, &model)
+		} else {
+			g.alterMySQL(&old, &model)
+		}
+	}
+	res := TableDDL{Create: model.OriginalName == "", Statements: g.stmts, Warnings: g.warnings}
+	if len(res.Statements) > 0 {
+		res.Script = strings.Join(res.Statements, ";\n\n") + ";\n"
+	}
+	return res, nil
+}
+
+// validateTableModel 检查名称、重复列与索引引用的列
+func validateTableModel(m *TableModel) error {
+	if strings.TrimSpace(m.Name) == "" {
+		return fmt.Errorf("表名不能为空")
+	}
+	if len(m.Columns) == 0 {
+		return fmt.Errorf("表至少需要一列")
+	}
+	cols := map[string]bool{}
+	for _, c := range m.Columns {
+		if strings.TrimSpace(c.Name) == "" {
+			return fmt.Errorf("列名不能为空")
+		}
+		if strings.TrimSpace(c.Type) == "" && c.Generated == "" {
+			return fmt.Errorf("列 %s 未指定类型", c.Name)
+		}
+		if cols[strings.ToLower(c.Name)] {
+			return fmt.Errorf("列名 %s 重复", c.Name)
+		}
+		cols[strings.ToLower(c.Name)] = true
+	}
+	primary := 0
+	names := map[string]bool{}
+	for _, idx := range m.Indexes {
+		switch idx.Kind {
+		case "primary":
+			primary++
+		case "unique", "index", "fulltext", "spatial":
+			if strings.TrimSpace(idx.Name) == "" {
+				return fmt.Errorf("索引名不能为空")
+			}
+		default:
+			return fmt.Errorf("索引 %s 的类型 %s 无效", idx.Name, idx.Kind)
+		}
+		if idx.Name != "" {
+			if names[strings.ToLower(idx.Name)] {
+				return fmt.Errorf("索引名 %s 重复", idx.Name)
+			}
+			names[strings.ToLower(idx.Name)] = true
+		}
+		if len(idx.Columns) == 0 {
+			return fmt.Errorf("索引 %s 没有列", idx.Name)
+		}
+		for _, c := range idx.Columns {
+			if !cols[strings.ToLower(c.Name)] {
+				return fmt.Errorf("索引 %s 引用了不存在的列 %s", idx.Name, c.Name)
+			}
+		}
+	}
+	if primary > 1 {
+		return fmt.Errorf("只能有一个主键")
+	}
+	for _, fk := range m.ForeignKeys {
+		if fk.Name == "" || fk.RefTable == "" {
+			return fmt.Errorf("外键的名称和引用表不能为空")
+		}
+		if len(fk.Columns) == 0 || len(fk.Columns) != len(fk.RefColumns) {
+			return fmt.Errorf("外键 %s 的列与引用列数量不一致", fk.Name)
+		}
+		for _, c := range fk.Columns {
+			if !cols[strings.ToLower(c)] {
+				return fmt.Errorf("外键 %s 引用了不存在的列 %s", fk.Name, c)
+			}
+		}
+	}
+	checks := map[string]bool{}
+	for _, ck := range m.Checks {
+		if strings.TrimSpace(ck.Expr) == "" {
+			return fmt.Errorf("CHECK 约束 %s 的表达式不能为空", ck.Name)
+		}
+		if ck.Name != "" {
+			if checks[strings.ToLower(ck.Name)] {
+				return fmt.Errorf("CHECK 约束名 %s 重复", ck.Name)
+			}
+			checks[strings.ToLower(ck.Name)] = true
+		}
+	}
+	return nil
+}
+
+var oraclePlainIdentRe = regexp.MustCompile(`^[a-z][a-z0-9_$#]*$`)
+
+// oracleObjectName 全小写的普通名称按 Oracle 习惯转为大写，避免建出需要引号访问的对象
+func oracleObjectName(s string) string {
+	if oraclePlainIdentRe.MatchString(s) {
+		return strings.ToUpper(s)
+	}
+	return s
+}
+
+// normalizeOracleModel 统一模型中各名称的大小写
+func normalizeOracleModel(m *TableModel) {
+	up := oracleObjectName
+	m.Name, m.OriginalName = up(m.Name), up(m.OriginalName)
+	for i := range m.Columns {
+		c := &m.Columns[i]
+		c.Name, c.OriginalName = up(c.Name), up(c.OriginalName)
+	}
+	for i := range m.Indexes {
+		idx := &m.Indexes[i]
+		idx.Name, idx.OriginalName = up(idx.Name), up(idx.OriginalName)
+		for j := range idx.Columns {
+			idx.Columns[j].Name = up(idx.Columns[j].Name)
+		}
+	}
+	for i := range m.ForeignKeys {
+		fk := &m.ForeignKeys[i]
+		fk.Name, fk.RefSchema, fk.RefTable = up(fk.Name), up(fk.RefSchema), up(fk.RefTable)
+		for j := range fk.Columns {
+			fk.Columns[j] = up(fk.Columns[j])
+		}
+		for j := range fk.RefColumns {
+			fk.RefColumns[j] = up(fk.RefColumns[j])
+		}
+	}
+	for i := range m.Checks {
+		m.Checks[i].Name = up(m.Checks[i].Name)
+	}
+}
+
+// tableDDLGen 收集生成的语句与提示
+type tableDDLGen struct {
+	dialect  string
+	opts     TableDDLOptions
+	stmts    []string
+	warnings []string
+}
+
+func (g *tableDDLGen) warn(format string, args ...interface{}) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+func (g *tableDDLGen) quote(name string) string {
+	if g.dialect == "oracle" {
+		return quoteOracleIdent(name)
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (g *tableDDLGen) qualified(schema, name string) string {
+	return g.quote(schema) + "." + g.quote(name)
+}
+
+func (g *tableDDLGen) stringLiteral(s string) string {
+	if g.dialect == "oracle" {
+		return "'" + escapeSQLLiteral(s) + "'"
+	}
+	return "'" + escapeSQLString(s) + "'"
+}
+
+func (g *tableDDLGen) defaultSQL(c *DesignColumn) string {
+	if c.Default == nil {
+		return ""
+	}
+	if c.DefaultIsExpr {
+		return *c.Default
+	}
+	return g.stringLiteral(*c.Default)
+}
+
+// columnDef 列定义（不含列名）；MySQL 与 SHOW CREATE TABLE 的顺序一致，便于比较
+func (g *tableDDLGen) columnDef(c *DesignColumn) string {
+	var b strings.Builder
+	b.WriteString(c.Type)
+	if g.dialect == "oracle" {
+		if c.Generated != "" {
+			b.WriteString(" GENERATED ALWAYS AS (" + c.Generated + ") VIRTUAL")
+		} else if c.AutoIncrement {
+			b.WriteString(" GENERATED BY DEFAULT AS IDENTITY")
+		} else if d := g.defaultSQL(c); d != "" {
+			b.WriteString(" DEFAULT " + d)
+		}
+		if !c.Nullable {
+			b.WriteString(" NOT NULL")
+		}
+		return b.String()
+	}
+	if c.Charset != "" {
+		b.WriteString(" CHARACTER SET " + c.Charset)
+	}
+	if c.Collation != "" {
+		b.WriteString(" COLLATE " + c.Collation)
+	}
+	if c.Generated != "" {
+		kind := "VIRTUAL"
+		if c.Stored {
+			kind = "STORED"
+		}
+		b.WriteString(" GENERATED ALWAYS AS (" + c.Generated + ") " + kind)
+	}
+	if !c.Nullable {
+		b.WriteString(" NOT NULL")
+	} else if c.Generated == "" {
+		b.WriteString(" NULL")
+	}
+	if d := g.defaultSQL(c); d != "" && c.Generated == "" {
+		b.WriteString(" DEFAULT " + d)
+	}
+	if c.OnUpdate != "" {
+		b.WriteString(" ON UPDATE " + c.OnUpdate)
+	}
+	if c.AutoIncrement {
+		b.WriteString(" AUTO_INCREMENT")
+	}
+	if c.Comment != "" {
+		b.WriteString(" COMMENT " + g.stringLiteral(c.Comment))
+	}
+	return b.String()
+}
+
+func (g *tableDDLGen) indexColumns(cols []DesignIndexColumn) string {
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		s := g.quote(c.Name)
+		if c.Length > 0 && g.dialect != "oracle" {
+			s += fmt.Sprintf("(%d)", c.Length)
+		}
+		if c.Desc {
+			s += " DESC"
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, ", ")
+}
+
+// indexDef MySQL 的索引定义（用于建表与 ADD）
+func (g *tableDDLGen) indexDef(idx *DesignIndex) string {
+	cols := g.indexColumns(idx.Columns)
+	var s string
+	switch idx.Kind {
+	case "primary":
+		s = fmt.Sprintf("PRIMARY KEY (%s)", cols)
+	case "unique":
+		s = fmt.Sprintf("UNIQUE KEY %s (%s)", g.quote(idx.Name), cols)
+	case "fulltext":
+		s = fmt.Sprintf("FULLTEXT KEY %s (%s)", g.quote(idx.Name), cols)
+	case "spatial":
+		s = fmt.Sprintf("SPATIAL KEY %s (%s)", g.quote(idx.Name), cols)
+	default:
+		s = fmt.Sprintf("KEY %s (%s)", g.quote(idx.Name), cols)
+	}
+	if idx.Comment != "" && g.dialect != "oracle" {
+		s += " COMMENT " + g.stringLiteral(idx.Comment)
+	}
+	return s
+}
+
+// oracleConstraintDef Oracle 的主键、唯一约束定义
+func (g *tableDDLGen) oracleConstraintDef(idx *DesignIndex) string {
+	kind := "UNIQUE"
+	if idx.Kind == "primary" {
+		kind = "PRIMARY KEY"
+	}
+	s := fmt.Sprintf("%s (%s)", kind, g.indexColumns(idx.Columns))
+	if idx.Name != "" {
+		s = "CONSTRAINT " + g.quote(idx.Name) + " " + s
+	}
+	return s
+}
+
+// oracleIsConstraint Oracle 中主键与唯一键按约束处理，普通索引单独创建
+func oracleIsConstraint(idx *DesignIndex) bool {
+	return idx.Kind == "primary" || (idx.Kind == "unique" && !idx.standalone)
+}
+
+func (g *tableDDLGen) oracleCreateIndex(schema, table string, idx *DesignIndex) string {
+	unique := ""
+	if idx.Kind == "unique" {
+		unique = "UNIQUE "
+	}
+	s := fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", unique, g.qualified(schema, idx.Name), g.qualified(schema, table), g.indexColumns(idx.Columns))
+	if g.opts.Online {
+		s += " ONLINE"
+	}
+	return s
+}
+
+func (g *tableDDLGen) foreignKeyDef(fk *DesignForeignKey, schema string) string {
+	ref := g.quote(fk.RefTable)
+	if g.dialect == "oracle" {
+		refSchema := fk.RefSchema
+		if refSchema == "" {
+			refSchema = schema
+		}
+		ref = g.qualified(refSchema, fk.RefTable)
+	} else if fk.RefSchema != "" && fk.RefSchema != schema {
+		ref = g.qualified(fk.RefSchema, fk.RefTable)
+	}
+	cols := make([]string, len(fk.Columns))
+	for i, c := range fk.Columns {
+		cols[i] = g.quote(c)
+	}
+	refCols := make([]string, len(fk.RefColumns))
+	for i, c := range fk.RefColumns {
+		refCols[i] = g.quote(c)
+	}
+	s := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)", g.quote(fk.Name), strings.Join(cols, ", "), ref, strings.Join(refCols, ", "))
+	onDelete, onUpdate := strings.ToUpper(strings.TrimSpace(fk.OnDelete)), strings.ToUpper(strings.TrimSpace(fk.OnUpdate))
+	if g.dialect == "oracle" {
+		// Oracle 只支持 ON DELETE CASCADE / SET NULL
+		if onDelete == "CASCADE" || onDelete == "SET NULL" {
+			s += " ON DELETE " + onDelete
+		}
+		return s
+	}
+	if onDelete != "" && onDelete != "RESTRICT" && onDelete != "NO ACTION" {
+		s += " ON DELETE " + onDelete
+	}
+	if onUpdate != "" && onUpdate != "RESTRICT" && onUpdate != "NO ACTION" {
+		s += " ON UPDATE " + onUpdate
+	}
+	return s
+}
+
+// checkDef CHECK 约束定义；未命名时由数据库生成名称
+func (g *tableDDLGen) checkDef(ck *DesignCheck) string {
+	s := "CHECK (" + strings.TrimSpace(ck.Expr) + ")"
+	if ck.Name != "" {
+		s = "CONSTRAINT " + g.quote(ck.Name) + " " + s
+	}
+	if ck.NotEnforced {
+		if g.dialect == "oracle" {
+			s += " DISABLE"
+		} else {
+			s += " NOT ENFORCED"
+		}
+	}
+	return s
+}
+
+// diffChecks 比较 CHECK 约束，返回需要删除的旧约束与需要添加的新约束：有名称的按名称对应，定义变化的先删后加；
+// 未命名的新约束与定义相同的旧约束对应
+func (g *tableDDLGen) diffChecks(old, m *TableModel) (drop []*DesignCheck, add []*DesignCheck) {
+	kept := map[*DesignCheck]bool{}
+	find := func(ck *DesignCheck) *DesignCheck {
+		for i := range old.Checks {
+			o := &old.Checks[i]
+			if kept[o] {
+				continue
+			}
+			if ck.Name != "" && strings.EqualFold(o.Name, ck.Name) {
+				return o
+			}
+			if ck.Name == "" && normalizeSpace(o.Expr) == normalizeSpace(ck.Expr) && o.NotEnforced == ck.NotEnforced {
+				return o
+			}
+		}
+		return nil
+	}
+	for i := range m.Checks {
+		ck := &m.Checks[i]
+		if o := find(ck); o != nil {
+			kept[o] = true
+			if ck.Name == "" || g.checkDef(o) == g.checkDef(ck) {
+				continue
+			}
+			drop = append(drop, o)
+		}
+		add = append(add, ck)
+	}
+	for i := range old.Checks {
+		if o := &old.Checks[i]; !kept[o] {
+			drop = append(drop, o)
+		}
+	}
+	return drop, add
+}
+
+// create 生成建表语句；Oracle 的普通索引与注释单独成句
+func (g *tableDDLGen) create(m *TableModel) {
+	table := g.qualified(m.Schema, m.Name)
+	var defs []string
+	for i := range m.Columns {
+		c := &m.Columns[i]
+		defs = append(defs, g.quote(c.Name)+" "+g.columnDef(c))
+	}
+	var indexes []string
+	for i := range m.Indexes {
+		idx := &m.Indexes[i]
+		switch {
+		case g.dialect != "oracle":
+			defs = append(defs, g.indexDef(idx))
+		case oracleIsConstraint(idx):
+			defs = append(defs, g.oracleConstraintDef(idx))
+		default:
+			indexes = append(indexes, g.oracleCreateIndex(m.Schema, m.Name, idx))
+		}
+	}
+	for i := range m.ForeignKeys {
+		defs = append(defs, g.foreignKeyDef(&m.ForeignKeys[i], m.Schema))
+	}
+	for i := range m.Checks {
+		defs = append(defs, g.checkDef(&m.Checks[i]))
+	}
+	stmt := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", table, strings.Join(defs, ",\n  "))
+	if g.dialect == "oracle" {
+		if p := strings.TrimSpace(m.Partition); p != "" {
+			stmt += "\n" + p
+		}
+		g.stmts = append(g.stmts, stmt)
+		g.stmts = append(g.stmts, indexes...)
+		if m.Comment != "" {
+			g.stmts = append(g.stmts, fmt.Sprintf("COMMENT ON TABLE %s IS %s", table, g.stringLiteral(m.Comment)))
+		}
+		for _, c := range m.Columns {
+			if c.Comment != "" {
+				g.stmts = append(g.stmts, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", table, g.quote(c.Name), g.stringLiteral(c.Comment)))
+			}
+		}
+		return
+	}
+	if opts := g.tableOptions(nil, m); len(opts) > 0 {
+		stmt += " " + strings.Join(opts, " ")
+	}
+	if p := strings.TrimSpace(m.Partition); p != "" {
+		stmt += "\n" + p
+	}
+	g.stmts = append(g.stmts, stmt)
+}
+
+// tableOptions MySQL 表选项；old 为 nil 时输出全部已指定的选项，否则只输出变化的
+func (g *tableDDLGen) tableOptions(old, m *TableModel) []string {
+	var opts []string
+	if m.Engine != "" && (old == nil || !strings.EqualFold(old.Engine, m.Engine)) {
+		opts = append(opts, "ENGINE="+m.Engine)
+		if old != nil {
+			g.warn("修改存储引擎会重建整张表")
+		}
+	}
+	// 已有表的自增值随写入变化，只有用户改动过才输出，避免把计数器改回打开设计器时的值
+	if m.AutoIncrement > 0 && (old == nil || m.OriginalAutoIncrement != m.AutoIncrement) {
+		opts = append(opts, fmt.Sprintf("AUTO_INCREMENT=%d", m.AutoIncrement))
+	}
+	charsetChanged := m.Charset != "" && (old == nil || !strings.EqualFold(old.Charset, m.Charset))
+	collationChanged := m.Collation != "" && (old == nil || !strings.EqualFold(old.Collation, m.Collation))
+	if charsetChanged || collationChanged {
+		if m.Charset != "" {
+			opts = append(opts, "DEFAULT CHARSET="+m.Charset)
+		}
+		if m.Collation != "" {
+			opts = append(opts, "COLLATE="+m.Collation)
+		}
+		if old != nil {
+			g.warn("修改表的默认字符集只影响之后新增的列，已有列的字符集不变")
+		}
+	}
+	if (old == nil && m.Comment != "") || (old != nil && old.Comment != m.Comment) {
+		opts = append(opts, "COMMENT="+g.stringLiteral(m.Comment))
+	}
+	return opts
+}
+
+// matchColumns 按原列名把新模型的列对应到旧结构，返回 新列下标 → 旧列
+func matchColumns(old, m *TableModel) map[int]*DesignColumn {
+	byName := map[string]*DesignColumn{}
+	for i := range old.Columns {
+		byName[strings.ToLower(old.Columns[i].Name)] = &old.Columns[i]
+	}
+	out := map[int]*DesignColumn{}
+	for i, c := range m.Columns {
+		if c.OriginalName == "" {
+			continue
+		}
+		if oc, ok := byName[strings.ToLower(c.OriginalName)]; ok {
+			out[i] = oc
+		}
+	}
+	return out
+}
+
+// renamedColumns 改名的列：旧名（小写） → 新名
+func renamedColumns(matched map[int]*DesignColumn, m *TableModel) map[string]string {
+	out := map[string]string{}
+	for i, oc := range matched {
+		if oc.Name != m.Columns[i].Name {
+			out[strings.ToLower(oc.Name)] = m.Columns[i].Name
+		}
+	}
+	return out
+}
+
+// followRenames 索引与外键随列改名自动更新，比较前先按新列名改写旧定义
+func followRenames(idx DesignIndex, renamed map[string]string) DesignIndex {
+	cols := make([]DesignIndexColumn, len(idx.Columns))
+	for i, c := range idx.Columns {
+		if n, ok := renamed[strings.ToLower(c.Name)]; ok {
+			c.Name = n
+		}
+		cols[i] = c
+	}
+	idx.Columns = cols
+	return idx
+}
+
+func followRenamesFK(fk DesignForeignKey, renamed map[string]string) DesignForeignKey {
+	cols := make([]string, len(fk.Columns))
+	for i, c := range fk.Columns {
+		if n, ok := renamed[strings.ToLower(c)]; ok {
+			c = n
+		}
+		cols[i] = c
+	}
+	fk.Columns = cols
+	return fk
+}
+
+// matchIndex 在旧结构中查找对应的索引：主键按类型，其余按原名
+func matchIndex(old *TableModel, idx *DesignIndex) *DesignIndex {
+	for i := range old.Indexes {
+		o := &old.Indexes[i]
+		if idx.Kind == "primary" && o.Kind == "primary" {
+			return o
+		}
+		if idx.Kind != "primary" && o.Kind != "primary" && idx.OriginalName != "" && strings.EqualFold(o.Name, idx.OriginalName) {
+			return o
+		}
+	}
+	return nil
+}
+
+// stableColumns 最长递增子序列：保持相对顺序不动的列，其余列需要调整位置
+func stableColumns(oldPos []int) map[int]bool {
+	n := len(oldPos)
+	length := make([]int, n)
+	prev := make([]int, n)
+	best := -1
+	for i := 0; i < n; i++ {
+		if oldPos[i] < 0 {
+			continue
+		}
+		length[i], prev[i] = 1, -1
+		for j := 0; j < i; j++ {
+			if oldPos[j] >= 0 && oldPos[j] < oldPos[i] && length[j]+1 > length[i] {
+				length[i], prev[i] = length[j]+1, j
+			}
+		}
+		if best < 0 || length[i] > length[best] {
+			best = i
+		}
+	}
+	stable := map[int]bool{}
+	for i := best; i >= 0; i = prev[i] {
+		stable[i] = true
+	}
+	return stable
+}
+
+// alterMySQL 生成最小的 ALTER TABLE：先删外键与 CHECK 约束，再在一条 ALTER 中修改列、索引、CHECK 约束与表选项，最后调整分区并添加外键
+func (g *tableDDLGen) alterMySQL(old, m *TableModel) {
+	table := g.qualified(m.Schema, old.Name)
+	var clauses []string
+	matched := matchColumns(old, m)
+	oldIndex := map[*DesignColumn]int{}
+	for i := range old.Columns {
+		oldIndex[&old.Columns[i]] = i
+	}
+
+	// 删除列
+	kept := map[*DesignColumn]bool{}
+	for _, oc := range matched {
+		kept[oc] = true
+	}
+	for i := range old.Columns {
+		if oc := &old.Columns[i]; !kept[oc] {
+			clauses = append(clauses, "DROP COLUMN "+g.quote(oc.Name))
+			g.warn("删除列 %s 会丢失该列的数据", oc.Name)
+		}
+	}
+
+	// 新增、改名与修改列；位置变化的列通过 FIRST / AFTER 调整
+	oldPos := make([]int, len(m.Columns))
+	for i := range m.Columns {
+		oldPos[i] = -1
+		if oc, ok := matched[i]; ok {
+			oldPos[i] = oldIndex[oc]
+		}
+	}
+	stable := stableColumns(oldPos)
+	renamed := renamedColumns(matched, m)
+	for i := range m.Columns {
+		c := &m.Columns[i]
+		position := " FIRST"
+		if i > 0 {
+			position = " AFTER " + g.quote(m.Columns[i-1].Name)
+		}
+		oc, ok := matched[i]
+		if !ok {
+			if i == len(m.Columns)-1 {
+				position = ""
+			}
+			clauses = append(clauses, "ADD COLUMN "+g.quote(c.Name)+" "+g.columnDef(c)+position)
+			continue
+		}
+		def := g.columnDef(c)
+		changed := def != g.columnDef(oc)
+		if stable[i] {
+			position = ""
+		}
+		if !changed && position == "" && oc.Name == c.Name {
+			continue
+		}
+		if changed && !strings.EqualFold(oc.Type, c.Type) {
+			g.warn("列 %s 的类型由 %s 改为 %s，可能截断数据，且通常需要复制整表", c.Name, oc.Type, c.Type)
+		}
+		if oc.Name != c.Name {
+			clauses = append(clauses, "CHANGE COLUMN "+g.quote(oc.Name)+" "+g.quote(c.Name)+" "+def+position)
+		} else {
+			clauses = append(clauses, "MODIFY COLUMN "+g.quote(c.Name)+" "+def+position)
+		}
+	}
+
+	// 索引：定义不同则先删后加，只改名时使用 RENAME INDEX
+	var dropIdx, addIdx []string
+	used := map[*DesignIndex]bool{}
+	for i := range m.Indexes {
+		idx := &m.Indexes[i]
+		o := matchIndex(old, idx)
+		if o == nil {
+			addIdx = append(addIdx, "ADD "+g.indexDef(idx))
+			if idx.Kind == "fulltext" && g.opts.Online {
+				g.warn("添加全文索引不支持 LOCK=NONE")
+			}
+			continue
+		}
+		used[o] = true
+		nameChanged := idx.Kind != "primary" && o.Name != idx.Name
+		cur := followRenames(*o, renamed)
+		cur.Name = idx.Name
+		same := g.indexDef(&cur) == g.indexDef(idx)
+		switch {
+		case same && nameChanged:
+			clauses = append(clauses, fmt.Sprintf("RENAME INDEX %s TO %s", g.quote(o.Name), g.quote(idx.Name)))
+		case !same:
+			dropIdx = append(dropIdx, g.dropIndexClause(o))
+			addIdx = append(addIdx, "ADD "+g.indexDef(idx))
+		}
+	}
+	for i := range old.Indexes {
+		if o := &old.Indexes[i]; !used[o] {
+			dropIdx = append(dropIdx, g.dropIndexClause(o))
+			if o.Kind == "primary" {
+				g.warn("删除主键后表将没有主键")
+			}
+		}
+	}
+	clauses = append(dropIdx, append(clauses, addIdx...)...)
+
+	// 外键：修改的外键需要先删后加，分两条语句执行
+	var dropFK, addFK []string
+	oldFKs := map[string]*DesignForeignKey{}
+	for i := range old.ForeignKeys {
+		oldFKs[strings.ToLower(old.ForeignKeys[i].Name)] = &old.ForeignKeys[i]
+	}
+	newFKs := map[string]bool{}
+	for i := range m.ForeignKeys {
+		fk := &m.ForeignKeys[i]
+		newFKs[strings.ToLower(fk.Name)] = true
+		o, ok := oldFKs[strings.ToLower(fk.Name)]
+		def := g.foreignKeyDef(fk, m.Schema)
+		if ok {
+			if cur := followRenamesFK(*o, renamed); g.foreignKeyDef(&cur, m.Schema) == def {
+				continue
+			}
+			dropFK = append(dropFK, "DROP FOREIGN KEY "+g.quote(o.Name))
+		}
+		addFK = append(addFK, "ADD "+def)
+	}
+	for i := range old.ForeignKeys {
+		if o := &old.ForeignKeys[i]; !newFKs[strings.ToLower(o.Name)] {
+			dropFK = append(dropFK, "DROP FOREIGN KEY "+g.quote(o.Name))
+		}
+	}
+
+	// CHECK 约束与外键一起先删除，避免删除或修改被引用的列时报错；新的约束在修改列之后添加
+	dropCheck, addCheck := g.diffChecks(old, m)
+	for _, ck := range dropCheck {
+		dropFK = append(dropFK, "DROP CHECK "+g.quote(ck.Name))
+	}
+	for _, ck := range addCheck {
+		clauses = append(clauses, "ADD "+g.checkDef(ck))
+	}
+
+	clauses = append(clauses, g.tableOptions(old, m)...)
+	if old.Name != m.Name {
+		clauses = append(clauses, "RENAME TO "+g.qualified(m.Schema, m.Name))
+	}
+
+	if len(dropFK) > 0 {
+		g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s\n  %s", table, strings.Join(dropFK, ",\n  ")))
+	}
+	if len(clauses) > 0 {
+		if g.opts.Online {
+			clauses = append(clauses, "ALGORITHM=INPLACE", "LOCK=NONE")
+		}
+		g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s\n  %s", table, strings.Join(clauses, ",\n  ")))
+	}
+	table = g.qualified(m.Schema, m.Name)
+	if p := strings.TrimSpace(m.Partition); normalizeSpace(p) != normalizeSpace(old.Partition) {
+		if p == "" {
+			g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s REMOVE PARTITIONING", table))
+		} else {
+			g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s\n%s", table, p))
+		}
+		g.warn("调整分区会重建整张表")
+	}
+	if len(addFK) > 0 {
+		g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s\n  %s", table, strings.Join(addFK, ",\n  ")))
+	}
+}
+
+func (g *tableDDLGen) dropIndexClause(idx *DesignIndex) string {
+	if idx.Kind == "primary" {
+		return "DROP PRIMARY KEY"
+	}
+	return "DROP INDEX " + g.quote(idx.Name)
+}
+
+// alterOracle Oracle 每项修改单独成句；MODIFY 只带变化的部分，避免重复设置 NOT NULL 报错
+func (g *tableDDLGen) alterOracle(old, m *TableModel) {
+	table := g.qualified(m.Schema, old.Name)
+	matched := matchColumns(old, m)
+	renamed := renamedColumns(matched, m)
+
+	// 先删外键与约束，避免删除列或主键时被引用
+	oldFKs := map[string]*DesignForeignKey{}
+	for i := range old.ForeignKeys {
+		oldFKs[old.ForeignKeys[i].Name] = &old.ForeignKeys[i]
+	}
+	newFKs := map[string]bool{}
+	var addFK []string
+	for i := range m.ForeignKeys {
+		fk := &m.ForeignKeys[i]
+		newFKs[fk.Name] = true
+		o, ok := oldFKs[fk.Name]
+		def := g.foreignKeyDef(fk, m.Schema)
+		if ok {
+			if cur := followRenamesFK(*o, renamed); g.foreignKeyDef(&cur, m.Schema) == def {
+				continue
+			}
+			g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", table, g.quote(o.Name)))
+		}
+		if strings.TrimSpace(fk.OnUpdate) != "" && !strings.EqualFold(fk.OnUpdate, "NO ACTION") && !strings.EqualFold(fk.OnUpdate, "RESTRICT") {
+			g.warn("Oracle 不支持外键 %s 的 ON UPDATE 规则，已忽略", fk.Name)
+		}
+		addFK = append(addFK, def)
+	}
+	for i := range old.ForeignKeys {
+		if o := &old.ForeignKeys[i]; !newFKs[o.Name] {
+			g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", table, g.quote(o.Name)))
+		}
+	}
+	dropCheck, addCheck := g.diffChecks(old, m)
+	for _, ck := range dropCheck {
+		g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", table, g.quote(ck.Name)))
+	}
+
+	used := map[*DesignIndex]bool{}
+	var addIdx []*DesignIndex
+	dropIndex := func(o *DesignIndex) {
+		if oracleIsConstraint(o) {
+			g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", table, g.quote(o.Name)))
+		} else {
+			g.stmts = append(g.stmts, "DROP INDEX "+g.qualified(m.Schema, o.Name))
+		}
+	}
+	for i := range m.Indexes {
+		idx := &m.Indexes[i]
+		o := matchIndex(old, idx)
+		if o == nil {
+			addIdx = append(addIdx, idx)
+			continue
+		}
+		used[o] = true
+		idx.standalone = o.standalone
+		same := o.Kind == idx.Kind && g.indexColumns(followRenames(*o, renamed).Columns) == g.indexColumns(idx.Columns)
+		switch {
+		case same && o.Name != idx.Name && idx.Name != "":
+			if oracleIsConstraint(o) {
+				g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s RENAME CONSTRAINT %s TO %s", table, g.quote(o.Name), g.quote(idx.Name)))
+			} else {
+				g.stmts = append(g.stmts, fmt.Sprintf("ALTER INDEX %s RENAME TO %s", g.qualified(m.Schema, o.Name), g.quote(idx.Name)))
+			}
+		case !same:
+			dropIndex(o)
+			idx.standalone = false
+			addIdx = append(addIdx, idx)
+		}
+	}
+	for i := range old.Indexes {
+		if o := &old.Indexes[i]; !used[o] {
+			dropIndex(o)
+		}
+	}
+
+	// 列
+	kept := map[*DesignColumn]bool{}
+	for _, oc := range matched {
+		kept[oc] = true
+	}
+	for i := range old.Columns {
+		if oc := &old.Columns[i]; !kept[oc] {
+			g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, g.quote(oc.Name)))
+			g.warn("删除列 %s 会丢失该列的数据", oc.Name)
+		}
+	}
+	reordered := false
+	lastOld := -1
+	for i := range m.Columns {
+		c := &m.Columns[i]
+		oc, ok := matched[i]
+		if !ok {
+			g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s ADD (%s %s)", table, g.quote(c.Name), g.columnDef(c)))
+			if c.Comment != "" {
+				g.stmts = append(g.stmts, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", table, g.quote(c.Name), g.stringLiteral(c.Comment)))
+			}
+			continue
+		}
+		pos := -1
+		for j := range old.Columns {
+			if &old.Columns[j] == oc {
+				pos = j
+			}
+		}
+		if pos < lastOld {
+			reordered = true
+		}
+		lastOld = pos
+		if oc.Name != c.Name {
+			g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", table, g.quote(oc.Name), g.quote(c.Name)))
+		}
+		var parts []string
+		if !strings.EqualFold(normalizeSpace(oc.Type), normalizeSpace(c.Type)) {
+			parts = append(parts, c.Type)
+			g.warn("列 %s 的类型由 %s 改为 %s，已有数据不兼容时会执行失败", c.Name, oc.Type, c.Type)
+		}
+		if od, nd := g.defaultSQL(oc), g.defaultSQL(c); normalizeSpace(od) != normalizeSpace(nd) && !c.AutoIncrement {
+			if nd == "" {
+				nd = "NULL"
+			}
+			parts = append(parts, "DEFAULT "+nd)
+		}
+		if oc.Nullable != c.Nullable {
+			if c.Nullable {
+				parts = append(parts, "NULL")
+			} else {
+				parts = append(parts, "NOT NULL")
+			}
+		}
+		if len(parts) > 0 {
+			g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s MODIFY (%s %s)", table, g.quote(c.Name), strings.Join(parts, " ")))
+		}
+		if oc.AutoIncrement != c.AutoIncrement || oc.Generated != c.Generated {
+			g.warn("Oracle 不支持直接修改列 %s 的自增（IDENTITY）或虚拟列属性，已忽略", c.Name)
+		}
+		if oc.Comment != c.Comment {
+			g.stmts = append(g.stmts, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", table, g.quote(c.Name), g.stringLiteral(c.Comment)))
+		}
+	}
+	if reordered {
+		g.warn("Oracle 不支持调整列顺序，列顺序保持不变")
+	}
+
+	for _, idx := range addIdx {
+		if oracleIsConstraint(idx) {
+			g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s ADD %s", table, g.oracleConstraintDef(idx)))
+		} else {
+			g.stmts = append(g.stmts, g.oracleCreateIndex(m.Schema, old.Name, idx))
+		}
+	}
+	for _, def := range addFK {
+		g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s ADD %s", table, def))
+	}
+	for _, ck := range addCheck {
+		g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s ADD %s", table, g.checkDef(ck)))
+	}
+	if old.Comment != m.Comment {
+		g.stmts = append(g.stmts, fmt.Sprintf("COMMENT ON TABLE %s IS %s", table, g.stringLiteral(m.Comment)))
+	}
+	if p := strings.TrimSpace(m.Partition); normalizeSpace(p) != normalizeSpace(old.Partition) {
+		if p == "" {
+			g.warn("Oracle 不支持直接取消分区，已忽略")
+		} else {
+			stmt := fmt.Sprintf("ALTER TABLE %s MODIFY %s", table, p)
+			if g.opts.Online {
+				stmt += " ONLINE"
+			}
+			g.stmts = append(g.stmts, stmt)
+			g.warn("修改分区方式需要 Oracle 12.2 及以上版本")
+		}
+	}
+	if old.Name != m.Name {
+		g.stmts = append(g.stmts, fmt.Sprintf("ALTER TABLE %s RENAME TO %s", table, g.quote(m.Name)))
+	}
+}
+
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// parseMySQLCreateTable 把 SHOW CREATE TABLE 的结果解析为模型（含 /*!50100 ... */ 中的分区子句）
+func parseMySQLCreateTable(ddl string) (TableModel, error) {
+	tokens, err := tokenizeSQL(ddl, "mysql")
+	if err != nil {
+		return TableModel{}, err
+	}
+	sig := significantTokens(expandConditionalComments(tokens))
+	if len(sig) < 4 || !tokenIs(sig[0], "CREATE") {
+		return TableModel{}, fmt.Errorf("不是建表语句")
+	}
+	i := skipWords(sig, 1, "TEMPORARY", "TABLE", "IF", "NOT", "EXISTS")
+	_, name, i := readObjectName(sig, i)
+	if name == "" || i >= len(sig) || sig[i].kind != tokOpen {
+		return TableModel{}, fmt.Errorf("无法识别表名")
+	}
+	m := TableModel{Name: name}
+
+	// 按顶层逗号切分括号内的定义
+	var items [][]sqlToken
+	var cur []sqlToken
+	depth := 0
+	i++
+	for ; i < len(sig); i++ {
+		t := sig[i]
+		if t.kind == tokOpen {
+			depth++
+		} else if t.kind == tokClose {
+			if depth == 0 {
+				break
+			}
+			depth--
+		} else if t.kind == tokComma && depth == 0 {
+			items = append(items, cur)
+			cur = nil
+			continue
+		}
+		cur = append(cur, t)
+	}
+	if len(cur) > 0 {
+		items = append(items, cur)
+	}
+	for _, item := range items {
+		if err := parseMySQLDefinition(ddl, item, &m); err != nil {
+			return TableModel{}, err
+		}
+	}
+
+	// 表选项与分区
+	for i++; i < len(sig); i++ {
+		t := sig[i]
+		if tokenIs(t, "PARTITION") {
+			m.Partition = strings.TrimSpace(ddl[t.start:sig[len(sig)-1].end])
+			break
+		}
+		if tokenIs(t, "DEFAULT") || (t.kind == tokOp && t.text == "=") {
+			continue
+		}
+		key := strings.ToUpper(t.text)
+		if tokenIs(t, "CHARACTER") && i+1 < len(sig) && tokenIs(sig[i+1], "SET") {
+			key = "CHARSET"
+			i++
+		}
+		j := i + 1
+		if j < len(sig) && sig[j].kind == tokOp && sig[j].text == "=" {
+			j++
+		}
+		if j >= len(sig) {
+			break
+		}
+		value := sig[j].text
+		switch key {
+		case "ENGINE":
+			m.Engine = value
+		case "CHARSET":
+			m.Charset = value
+		case "COLLATE":
+			m.Collation = value
+		case "AUTO_INCREMENT":
+			m.AutoIncrement, _ = strconv.ParseInt(value, 10, 64)
+		case "COMMENT":
+			m.Comment = unquoteSQLString(value)
+		default:
+			// ROW_FORMAT、STATS_PERSISTENT 等其余选项不在设计器中维护
+		}
+		i = j
+	}
+	return m, nil
+}
+
+// parseMySQLDefinition 解析一项列、索引或约束定义
+func parseMySQLDefinition(ddl string, item []sqlToken, m *TableModel) error {
+	if len(item) == 0 {
+		return nil
+	}
+	first := item[0]
+	switch {
+	case tokenIs(first, "PRIMARY"):
+		idx, err := parseMySQLIndex(item, skipWords(item, 1, "KEY"), "primary")
+		if err != nil {
+			return err
+		}
+		idx.Name = ""
+		m.Indexes = append(m.Indexes, idx)
+	case tokenIs(first, "UNIQUE", "KEY", "INDEX", "FULLTEXT", "SPATIAL"):
+		kind := "index"
+		switch strings.ToUpper(first.text) {
+		case "UNIQUE":
+			kind = "unique"
+		case "FULLTEXT":
+			kind = "fulltext"
+		case "SPATIAL":
+			kind = "spatial"
+		}
+		i := 0
+		if kind != "index" {
+			i = 1
+		}
+		idx, err := parseMySQLIndex(item, skipWords(item, i, "KEY", "INDEX"), kind)
+		if err != nil {
+			return err
+		}
+		m.Indexes = append(m.Indexes, idx)
+	case tokenIs(first, "CONSTRAINT") && len(item) > 2 && tokenIs(item[2], "CHECK"):
+		ck, err := parseMySQLCheck(ddl, item, 2)
+		if err != nil {
+			return err
+		}
+		ck.Name = unquoteIdent(item[1].text)
+		m.Checks = append(m.Checks, ck)
+	case tokenIs(first, "CONSTRAINT"):
+		if len(item) < 3 || !tokenIs(item[2], "FOREIGN") {
+			return fmt.Errorf("无法识别的约束定义: %s", ddl[first.start:item[len(item)-1].end])
+		}
+		fk := DesignForeignKey{Name: unquoteIdent(item[1].text)}
+		i := skipWords(item, 3, "KEY")
+		var err error
+		if fk.Columns, i, err = parseNameList(item, i); err != nil {
+			return err
+		}
+		if i >= len(item) || !tokenIs(item[i], "REFERENCES") {
+			return fmt.Errorf("外键 %s 缺少 REFERENCES", fk.Name)
+		}
+		fk.RefSchema, fk.RefTable, i = readObjectName(item, i+1)
+		if fk.RefColumns, i, err = parseNameList(item, i); err != nil {
+			return err
+		}
+		for ; i+2 < len(item); i++ {
+			if !tokenIs(item[i], "ON") {
+				continue
+			}
+			action := strings.ToUpper(item[i+2].text)
+			if tokenIs(item[i+2], "SET", "NO") && i+3 < len(item) {
+				action += " " + strings.ToUpper(item[i+3].text)
+			}
+			if tokenIs(item[i+1], "DELETE") {
+				fk.OnDelete = action
+			} else {
+				fk.OnUpdate = action
+			}
+		}
+		m.ForeignKeys = append(m.ForeignKeys, fk)
+	case tokenIs(first, "CHECK"):
+		ck, err := parseMySQLCheck(ddl, item, 0)
+		if err != nil {
+			return err
+		}
+		m.Checks = append(m.Checks, ck)
+	default:
+		c, err := parseMySQLColumn(ddl, item)
+		if err != nil {
+			return err
+		}
+		m.Columns = append(m.Columns, c)
+	}
+	return nil
+}
+
+// parseMySQLCheck 读取 item[i] 处的 CHECK (expr) [NOT ENFORCED]，表达式保留原文
+func parseMySQLCheck(ddl string, item []sqlToken, i int) (DesignCheck, error) {
+	if i+1 >= len(item) || item[i+1].kind != tokOpen {
+		return DesignCheck{}, fmt.Errorf("CHECK 约束缺少表达式")
+	}
+	depth := 0
+	for j := i + 1; j < len(item); j++ {
+		switch item[j].kind {
+		case tokOpen:
+			depth++
+		case tokClose:
+			if depth--; depth == 0 {
+				ck := DesignCheck{Expr: strings.TrimSpace(ddl[item[i+1].end:item[j].start])}
+				ck.NotEnforced = j+2 < len(item) && tokenIs(item[j+1], "NOT") && tokenIs(item[j+2], "ENFORCED")
+				return ck, nil
+			}
+		}
+	}
+	return DesignCheck{}, fmt.Errorf("CHECK 约束的括号不匹配")
+}
+
+// parseNameList 读取 (`a`, `b`)，返回名称与下一个位置
+func parseNameList(item []sqlToken, i int) ([]string, int, error) {
+	if i >= len(item) || item[i].kind != tokOpen {
+		return nil, i, fmt.Errorf("缺少列清单")
+	}
+	var names []string
+	for i++; i < len(item) && item[i].kind != tokClose; i++ {
+		if item[i].kind == tokWord || item[i].kind == tokQuoted {
+			names = append(names, unquoteIdent(item[i].text))
+		}
+	}
+	return names, i + 1, nil
+}
+
+func parseMySQLIndex(item []sqlToken, i int, kind string) (DesignIndex, error) {
+	idx := DesignIndex{Kind: kind}
+	if i < len(item) && (item[i].kind == tokQuoted || item[i].kind == tokWord) {
+		idx.Name = unquoteIdent(item[i].text)
+		i++
+	}
+	if i >= len(item) || item[i].kind != tokOpen {
+		return idx, fmt.Errorf("索引 %s 缺少列清单", idx.Name)
+	}
+	// 列：`a`(10) DESC，函数索引 ((expr)) 无法在设计器中表示，按原文保留列名
+	depth := 0
+	var col *DesignIndexColumn
+	for i++; i < len(item); i++ {
+		t := item[i]
+		if t.kind == tokClose && depth == 0 {
+			i++
+			break
+		}
+		switch {
+		case t.kind == tokOpen:
+			depth++
+		case t.kind == tokClose:
+			depth--
+		case depth == 0 && t.kind == tokComma:
+			col = nil
+		case depth == 0 && tokenIs(t, "DESC") && col != nil:
+			col.Desc = true
+		case depth == 0 && tokenIs(t, "ASC"):
+		case depth == 0 && (t.kind == tokQuoted || t.kind == tokWord):
+			idx.Columns = append(idx.Columns, DesignIndexColumn{Name: unquoteIdent(t.text)})
+			col = &idx.Columns[len(idx.Columns)-1]
+		case depth == 1 && t.kind == tokNumber && col != nil:
+			col.Length, _ = strconv.Atoi(t.text)
+		}
+	}
+	for ; i+1 < len(item); i++ {
+		if tokenIs(item[i], "COMMENT") {
+			idx.Comment = unquoteSQLString(item[i+1].text)
+		}
+	}
+	return idx, nil
+}
+
+// parseMySQLColumn 解析列定义：类型取原文，其余属性逐个识别
+func parseMySQLColumn(ddl string, item []sqlToken) (DesignColumn, error) {
+	c := DesignColumn{Name: unquoteIdent(item[0].text), Nullable: true}
+	if len(item) < 2 {
+		return c, fmt.Errorf("列 %s 缺少类型", c.Name)
+	}
+	// 类型：名称、可选的括号参数以及 UNSIGNED / ZEROFILL 等修饰
+	i := 2
+	if i < len(item) && item[i].kind == tokOpen {
+		depth := 0
+		for ; i < len(item); i++ {
+			if item[i].kind == tokOpen {
+				depth++
+			} else if item[i].kind == tokClose {
+				depth--
+				if depth == 0 {
+					i++
+					break
+				}
+			}
+		}
+	}
+	for i < len(item) && tokenIs(item[i], "UNSIGNED", "SIGNED", "ZEROFILL") {
+		i++
+	}
+	c.Type = ddl[item[1].start:item[i-1].end]
+
+	// group 读取括号表达式的原文（不含外层括号），返回下一个位置
+	group := func(i int) (string, int) {
+		depth := 0
+		for j := i; j < len(item); j++ {
+			if item[j].kind == tokOpen {
+				depth++
+			} else if item[j].kind == tokClose {
+				depth--
+				if depth == 0 {
+					return ddl[item[i].end:item[j].start], j + 1
+				}
+			}
+		}
+		return "", len(item)
+	}
+	for i < len(item) {
+		t := item[i]
+		switch {
+		case tokenIs(t, "NOT") && i+1 < len(item) && tokenIs(item[i+1], "NULL"):
+			c.Nullable = false
+			i += 2
+		case tokenIs(t, "NULL"):
+			i++
+		case (tokenIs(t, "CHARACTER") || tokenIs(t, "CHARSET")) && i+1 < len(item):
+			i = skipWords(item, i+1, "SET")
+			if i < len(item) {
+				c.Charset = item[i].text
+			}
+			i++
+		case tokenIs(t, "COLLATE") && i+1 < len(item):
+			c.Collation = item[i+1].text
+			i += 2
+		case tokenIs(t, "AUTO_INCREMENT"):
+			c.AutoIncrement = true
+			i++
+		case tokenIs(t, "COMMENT") && i+1 < len(item):
+			c.Comment = unquoteSQLString(item[i+1].text)
+			i += 2
+		case tokenIs(t, "GENERATED", "AS"):
+			for i < len(item) && item[i].kind != tokOpen {
+				i++
+			}
+			c.Generated, i = group(i)
+		case tokenIs(t, "STORED"):
+			c.Stored = true
+			i++
+		case tokenIs(t, "DEFAULT") && i+1 < len(item):
+			v, next := mysqlDefaultValue(ddl, item, i+1, group)
+			c.Default, c.DefaultIsExpr = v.value, v.expr
+			i = next
+		case tokenIs(t, "ON") && i+2 < len(item) && tokenIs(item[i+1], "UPDATE"):
+			end := i + 3
+			if end < len(item) && item[end].kind == tokOpen {
+				_, end = group(end)
+			}
+			c.OnUpdate = ddl[item[i+2].start:item[end-1].end]
+			i = end
+		default:
+			// VIRTUAL、INVISIBLE、SRID、COLUMN_FORMAT 等
+			i++
+		}
+	}
+	return c, nil
+}
+
+type mysqlDefault struct {
+	value *string
+	expr  bool
+}
+
+// mysqlDefaultValue 默认值：字符串取值，NULL 视为无默认值，其余（函数、数字、位值、括号表达式）按表达式原文保留
+func mysqlDefaultValue(ddl string, item []sqlToken, i int, group func(int) (string, int)) (mysqlDefault, int) {
+	t := item[i]
+	switch {
+	case tokenIs(t, "NULL"):
+		return mysqlDefault{}, i + 1
+	case t.kind == tokString && (t.text[0] == '\'' || t.text[0] == '"' || t.text[0] == '_'):
+		v := unquoteSQLString(t.text)
+		return mysqlDefault{value: &v}, i + 1
+	case t.kind == tokOpen:
+		inner, next := group(i)
+		v := "(" + inner + ")"
+		return mysqlDefault{value: &v, expr: true}, next
+	}
+	end := i + 1
+	if t.kind == tokOp && end < len(item) && item[end].kind == tokNumber {
+		end++ // 负数
+	}
+	if end < len(item) && item[end].kind == tokOpen && !item[end].space {
+		_, end = group(end) // CURRENT_TIMESTAMP(3)
+	}
+	v := ddl[t.start:item[end-1].end]
+	return mysqlDefault{value: &v, expr: true}, end
+}
+
+// unquoteSQLString 去掉字符串字面量的引号与字符集前缀，还原成对的引号与反斜杠转义
+func unquoteSQLString(s string) string {
+	if i := strings.IndexAny(s, `'"`); i > 0 && s[0] == '_' {
+		s = s[i:]
+	}
+	if len(s) < 2 || (s[0] != '\'' && s[0] != '"') || s[len(s)-1] != s[0] {
+		return s
+	}
+	q := s[0]
+	body := s[1 : len(s)-1]
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		ch := body[i]
+		switch {
+		case ch == q && i+1 < len(body) && body[i+1] == q:
+			b.WriteByte(q)
+			i++
+		case ch == '\\' && i+1 < len(body):
+			i++
+			switch body[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '0':
+				b.WriteByte(0)
+			case 'Z':
+				b.WriteByte(26)
+			default:
+				b.WriteByte(body[i])
+			}
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+var oraclePartitionRe = regexp.MustCompile(`(?is)\bPARTITION\s+BY\b.*`)
+
+var oracleNotNullCheckRe = regexp.MustCompile(`(?i)^"[^"]+" IS NOT NULL$`)
+
+// loadOracleTableModel 从数据字典读取 Oracle 表结构：列、默认值、IDENTITY、约束、普通索引与注释
+func loadOracleTableModel(db *sql.DB, owner, table string) (TableModel, error) {
+	src := &oracleSyncSource{db: db, owner: owner, cache: map[string]*oracleTable{}}
+	t, err := src.table(table)
+	if err != nil {
+		return TableModel{}, err
+	}
+	m := TableModel{Schema: owner, Name: table, OriginalName: table, Comment: t.Comment}
+	for _, oc := range t.Columns {
+		m.Columns = append(m.Columns, DesignColumn{
+			Name:         oc.Name,
+			OriginalName: oc.Name,
+			Type:         oracleFullType(oc),
+			Nullable:     oc.Nullable,
+			Comment:      oc.Comment,
+		})
+	}
+
+	// 默认值（LONG）、IDENTITY 与虚拟列；IDENTITY_COLUMN 为 12c 起才有的列
+	extra := func(withIdentity bool) error {
+		identity := "'NO'"
+		if withIdentity {
+			identity = "IDENTITY_COLUMN"
+		}
+		rows, err := db.Query(fmt.Sprintf(`SELECT COLUMN_NAME, DATA_DEFAULT, %s, VIRTUAL_COLUMN FROM ALL_TAB_COLS
+			WHERE OWNER = :1 AND TABLE_NAME = :2 AND HIDDEN_COLUMN = 'NO'`, identity), owner, table)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var name string
+			var def, ident, virtual sql.NullString
+			if err := rows.Scan(&name, &def, &ident, &virtual); err != nil {
+				return err
+			}
+			for i := range m.Columns {
+				c := &m.Columns[i]
+				if c.Name != name {
+					continue
+				}
+				expr := strings.TrimSpace(def.String)
+				switch {
+				case ident.String == "YES":
+					c.AutoIncrement = true
+				case virtual.String == "YES":
+					c.Generated = expr
+				case expr != "" && !strings.EqualFold(expr, "NULL"):
+					c.Default, c.DefaultIsExpr = &expr, true
+				}
+			}
+		}
+		return rows.Err()
+	}
+	if err := extra(true); err != nil {
+		if err := extra(false); err != nil {
+			return TableModel{}, err
+		}
+	}
+
+	for _, k := range t.Constraints {
+		switch k.Type {
+		case "P", "U":
+			idx := DesignIndex{Name: k.Name, OriginalName: k.Name, Kind: "unique"}
+			if k.Type == "P" {
+				idx.Kind = "primary"
+			}
+			for _, col := range k.Columns {
+				idx.Columns = append(idx.Columns, DesignIndexColumn{Name: col})
+			}
+			m.Indexes = append(m.Indexes, idx)
+		case "R":
+			fk := DesignForeignKey{Name: k.Name, Columns: k.Columns, RefTable: k.RefTable, RefColumns: k.RefColumns}
+			if k.DeleteRule != "NO ACTION" {
+				fk.OnDelete = k.DeleteRule
+			}
+			m.ForeignKeys = append(m.ForeignKeys, fk)
+		}
+	}
+
+	// CHECK 约束（SEARCH_CONDITION 为 LONG）；列的 NOT NULL 也以 CHECK 约束存储，已体现在 Nullable 中，跳过
+	ckRows, err := db.Query(`SELECT CONSTRAINT_NAME, SEARCH_CONDITION, STATUS FROM ALL_CONSTRAINTS
+		WHERE OWNER = :1 AND TABLE_NAME = :2 AND CONSTRAINT_TYPE = 'C' ORDER BY CONSTRAINT_NAME`, owner, table)
+	if err != nil {
+		return TableModel{}, err
+	}
+	defer ckRows.Close()
+	for ckRows.Next() {
+		var name, status string
+		var cond sql.NullString
+		if err := ckRows.Scan(&name, &cond, &status); err != nil {
+			return TableModel{}, err
+		}
+		expr := strings.TrimSpace(cond.String)
+		if oracleNotNullCheckRe.MatchString(expr) {
+			continue
+		}
+		m.Checks = append(m.Checks, DesignCheck{Name: name, Expr: expr, NotEnforced: status == "DISABLED"})
+	}
+	if err := ckRows.Err(); err != nil {
+		return TableModel{}, err
+	}
+
+	// 不属于约束的普通索引；函数索引无法在设计器中表示，跳过
+	rows, err := db.Query(`SELECT i.INDEX_NAME, i.UNIQUENESS, c.COLUMN_NAME, c.DESCEND
+		FROM ALL_INDEXES i
+		JOIN ALL_IND_COLUMNS c ON c.INDEX_OWNER = i.OWNER AND c.INDEX_NAME = i.INDEX_NAME
+		WHERE i.TABLE_OWNER = :1 AND i.TABLE_NAME = :2 AND i.INDEX_TYPE = 'NORMAL'
+		AND NOT EXISTS (SELECT 1 FROM ALL_CONSTRAINTS k WHERE k.OWNER = i.TABLE_OWNER AND k.TABLE_NAME = i.TABLE_NAME
+			AND k.INDEX_NAME = i.INDEX_NAME AND k.CONSTRAINT_TYPE IN ('P', 'U'))
+		ORDER BY i.INDEX_NAME, c.COLUMN_POSITION`, owner, table)
+	if err != nil {
+		return TableModel{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, uniqueness, col string
+		var descend sql.NullString
+		if err := rows.Scan(&name, &uniqueness, &col, &descend); err != nil {
+			return TableModel{}, err
+		}
+		n := len(m.Indexes)
+		if n == 0 || m.Indexes[n-1].Name != name || m.Indexes[n-1].Kind == "primary" {
+			idx := DesignIndex{Name: name, OriginalName: name, Kind: "index"}
+			if uniqueness == "UNIQUE" {
+				idx.Kind, idx.standalone = "unique", true
+			}
+			m.Indexes = append(m.Indexes, idx)
+			n++
+		}
+		m.Indexes[n-1].Columns = append(m.Indexes[n-1].Columns, DesignIndexColumn{Name: col, Desc: descend.String == "DESC"})
+	}
+	if err := rows.Err(); err != nil {
+		return TableModel{}, err
+	}
+
+	var partitioned sql.NullString
+	_ = db.QueryRow("SELECT PARTITIONED FROM ALL_TABLES WHERE OWNER = :1 AND TABLE_NAME = :2", owner, table).Scan(&partitioned)
+	if partitioned.String == "YES" {
+		var ddl sql.NullString
+		if err := db.QueryRow("SELECT DBMS_METADATA.GET_DDL('TABLE', :1, :2) FROM DUAL", table, owner).Scan(&ddl); err == nil {
+			m.Partition = strings.TrimSpace(oraclePartitionRe.FindString(ddl.String))
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const designSampleDDL = "CREATE TABLE `orders` (\n" +
+	"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+	"  `user_id` int NOT NULL,\n" +
+	"  `code` varchar(32) CHARACTER SET ascii COLLATE ascii_bin NOT NULL COMMENT '单号',\n" +
+	"  `note` varchar(255) DEFAULT NULL,\n" +
+	"  `amount` decimal(10,2) NOT NULL DEFAULT '0.00',\n" +
+	"  `status` enum('new','paid') NOT NULL DEFAULT 'new',\n" +
+	"  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+	"  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+	"  `total` decimal(12,2) GENERATED ALWAYS AS ((`amount` * 2)) STORED,\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  UNIQUE KEY `uk_code` (`code`),\n" +
+	"  KEY `idx_user` (`user_id`,`created_at` DESC),\n" +
+	"  KEY `idx_note` (`note`(16)) COMMENT '前缀索引',\n" +
+	"  CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,\n" +
+	"  CONSTRAINT `chk_amount` CHECK ((`amount` >= 0)),\n" +
+	"  CONSTRAINT `chk_status` CHECK ((`status` <> _utf8mb4'paid') or (`amount` > 0)) /*!80016 NOT ENFORCED */\n" +
+	") ENGINE=InnoDB AUTO_INCREMENT=120 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='订单'\n" +
+	"/*!50100 PARTITION BY HASH (`id`)\nPARTITIONS 4 */"
+
+// loadedDesignModel 模拟 LoadTableModel 对解析结果的处理
+func loadedDesignModel(t *testing.T, ddl string) TableModel {
+	t.Helper()
+	m, err := parseMySQLCreateTable(ddl)
+	if err != nil {
+		t.Fatalf("parse: %v\n%s", err, ddl)
+	}
+	m.Schema = "shop"
+	m.OriginalName = m.Name
+	m.OriginalAutoIncrement = m.AutoIncrement
+	for i := range m.Columns {
+		m.Columns[i].OriginalName = m.Columns[i].Name
+	}
+	for i := range m.Indexes {
+		m.Indexes[i].OriginalName = m.Indexes[i].Name
+	}
+	return m
+}
+
+func alterStatements(old, m TableModel) []string {
+	g := &tableDDLGen{dialect: "mysql"}
+	g.alterMySQL(&old, &m)
+	return g.stmts
+}
+
+func TestParseMySQLCreateTable(t *testing.T) {
+	m := loadedDesignModel(t, designSampleDDL)
+	if m.Name != "orders" || m.Engine != "InnoDB" || m.Charset != "utf8mb4" || m.AutoIncrement != 120 || m.Comment != "订单" {
+		t.Errorf("table options = %+v", m)
+	}
+	if !strings.HasPrefix(m.Partition, "PARTITION BY HASH") {
+		t.Errorf("partition = %q", m.Partition)
+	}
+	if len(m.Columns) != 9 || len(m.Indexes) != 4 || len(m.ForeignKeys) != 1 {
+		t.Fatalf("columns %d, indexes %d, foreign keys %d", len(m.Columns), len(m.Indexes), len(m.ForeignKeys))
+	}
+	wantChecks := []DesignCheck{
+		{Name: "chk_amount", Expr: "(`amount` >= 0)"},
+		{Name: "chk_status", Expr: "(`status` <> _utf8mb4'paid') or (`amount` > 0)", NotEnforced: true},
+	}
+	if !reflect.DeepEqual(m.Checks, wantChecks) {
+		t.Errorf("checks = %+v, want %+v", m.Checks, wantChecks)
+	}
+	code := m.Columns[2]
+	if code.Charset != "ascii" || code.Collation != "ascii_bin" || code.Comment != "单号" || code.Nullable {
+		t.Errorf("code column = %+v", code)
+	}
+	if note := m.Columns[3]; note.Default != nil || !note.Nullable {
+		t.Errorf("note column = %+v", note)
+	}
+	if idx := m.Indexes[3]; idx.Columns[0].Length != 16 || idx.Comment != "前缀索引" {
+		t.Errorf("prefix index = %+v", idx)
+	}
+	if !m.Indexes[2].Columns[1].Desc {
+		t.Errorf("descending index column not parsed: %+v", m.Indexes[2])
+	}
+}
+
+func TestParseMySQLCreateTableRejectsUnknownConstraint(t *testing.T) {
+	ddl := "CREATE TABLE `t` (\n  `id` int NOT NULL,\n  CONSTRAINT `x` SOMETHING (`id`)\n) ENGINE=InnoDB"
+	if _, err := parseMySQLCreateTable(ddl); err == nil {
+		t.Error("unknown constraint parsed without error")
+	}
+}
+
+// 解析后原样生成的 ALTER 不应包含任何语句；生成的建表语句再解析回来也应如此
+func TestTableDesignRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+	}{
+		{"sample", designSampleDDL},
+		{"minimal", "CREATE TABLE `t` (\n  `id` int NOT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=latin1"},
+		{"unnamed check", "CREATE TABLE `t` (\n  `a` int DEFAULT NULL,\n  CHECK ((`a` > 0))\n) ENGINE=InnoDB"},
+		{"quoted names", "CREATE TABLE `we``ird` (\n  `col;1` varchar(8) NOT NULL DEFAULT 'a''b',\n  KEY `i,1` (`col;1`)\n) ENGINE=InnoDB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded := loadedDesignModel(t, tt.ddl)
+			if stmts := alterStatements(loaded, loaded); len(stmts) != 0 {
+				t.Errorf("unchanged model produced %q", stmts)
+			}
+
+			g := &tableDDLGen{dialect: "mysql"}
+			g.create(&loaded)
+			if len(g.stmts) != 1 {
+				t.Fatalf("create produced %d statements", len(g.stmts))
+			}
+			reparsed := loadedDesignModel(t, g.stmts[0])
+			reparsed.Name, reparsed.OriginalName = loaded.Name, loaded.Name
+			if stmts := alterStatements(reparsed, loaded); len(stmts) != 0 {
+				t.Errorf("create -> parse -> alter produced %q\ncreate: %s", stmts, g.stmts[0])
+			}
+		})
+	}
+}
+
+func TestTableDesignAutoIncrement(t *testing.T) {
+	opened := loadedDesignModel(t, designSampleDDL)
+	// 打开设计器之后表中又写入了数据，当前计数器已经前进
+	current := opened
+	current.AutoIncrement = 500
+
+	if stmts := alterStatements(current, opened); len(stmts) != 0 {
+		t.Errorf("untouched AUTO_INCREMENT produced %q", stmts)
+	}
+
+	opened.Comment = "订单表"
+	stmts := alterStatements(current, opened)
+	if len(stmts) != 1 || strings.Contains(stmts[0], "AUTO_INCREMENT=") {
+		t.Errorf("unrelated change produced %q", stmts)
+	}
+
+	opened.AutoIncrement = 1000
+	stmts = alterStatements(current, opened)
+	if len(stmts) != 1 || !strings.Contains(stmts[0], "AUTO_INCREMENT=1000") {
+		t.Errorf("explicit AUTO_INCREMENT change produced %q", stmts)
+	}
+}
+
+func TestTableDesignCheckChanges(t *testing.T) {
+	old := loadedDesignModel(t, designSampleDDL)
+	tests := []struct {
+		name string
+		edit func(m *TableModel)
+		want []string
+	}{
+		{"drop", func(m *TableModel) { m.Checks = m.Checks[:1] }, []string{"DROP CHECK `chk_status`"}},
+		{"change expression", func(m *TableModel) { m.Checks[0].Expr = "`amount` > 0" }, []string{
+			"DROP CHECK `chk_amount`", "ADD CONSTRAINT `chk_amount` CHECK (`amount` > 0)",
+		}},
+		{"enforce", func(m *TableModel) { m.Checks[1].NotEnforced = false }, []string{
+			"DROP CHECK `chk_status`", "ADD CONSTRAINT `chk_status` CHECK ((`status` <> _utf8mb4'paid') or (`amount` > 0))",
+		}},
+		{"add", func(m *TableModel) { m.Checks = append(m.Checks, DesignCheck{Name: "chk_user", Expr: "`user_id` > 0"}) }, []string{
+			"ADD CONSTRAINT `chk_user` CHECK (`user_id` > 0)",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := old
+			m.Checks = append([]DesignCheck(nil), old.Checks...)
+			tt.edit(&m)
+			script := strings.Join(alterStatements(old, m), "\n")
+			for _, want := range tt.want {
+				if !strings.Contains(script, want) {
+					t.Errorf("script missing %q:\n%s", want, script)
+				}
+			}
+			if i, j := strings.Index(script, "DROP CHECK"), strings.Index(script, "ADD CONSTRAINT"); i >= 0 && j >= 0 && i > j {
+				t.Errorf("check is added before the old one is dropped:\n%s", script)
+			}
+		})
+	}
+}